@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+var _ ports.OutboxArchivePort = (*JSONLArchive)(nil)
+
+// JSONLArchive appends archived outbox events to daily JSON Lines files.
+// Files are synced before returning, so the events can be safely deleted afterwards.
+type JSONLArchive struct {
+	mx  sync.Mutex
+	dir string
+}
+
+type jsonlEvent struct {
+	ID          int64      `json:"id"`
+	EventType   string     `json:"event_type"`
+	Payload     string     `json:"payload"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+	ArchivedAt  time.Time  `json:"archived_at"`
+}
+
+func NewJSONLArchive(dir string) (*JSONLArchive, error) {
+	if dir == "" {
+		return nil, errors.New("archive directory is empty")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrap(err, "create archive directory")
+	}
+	return &JSONLArchive{dir: dir}, nil
+}
+
+func (a *JSONLArchive) ArchiveEvents(_ context.Context, events []model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	now := time.Now().UTC()
+	path := filepath.Join(a.dir, "outbox_events_"+now.Format(time.DateOnly)+".jsonl")
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640) //nolint:gosec // path is built from config
+	if err != nil {
+		return errors.Wrap(err, "open archive file")
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range events {
+		line := jsonlEvent{
+			ID:          event.ID,
+			EventType:   string(event.EventType),
+			Payload:     string(event.Payload),
+			CreatedAt:   event.CreatedAt,
+			ProcessedAt: event.ProcessedAt,
+			ArchivedAt:  now,
+		}
+		if err = encoder.Encode(&line); err != nil {
+			return errors.Wrap(err, "encode event")
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return errors.Wrap(err, "flush archive file")
+	}
+
+	if err = file.Sync(); err != nil {
+		return errors.Wrap(err, "sync archive file")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func readArchive(t *testing.T, dir string) []jsonlEvent {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "outbox_events_*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	file, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer file.Close()
+
+	var events []jsonlEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event jsonlEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestJSONLArchive_ArchiveEvents(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	archive, err := NewJSONLArchive(dir)
+	require.NoError(t, err)
+
+	processedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := []model.OutboxEvent{
+		{ID: 1, EventType: model.AccountCreated, Payload: []byte(`{"account_id":"1"}`), CreatedAt: processedAt, ProcessedAt: &processedAt},
+		{ID: 2, EventType: model.AccountClosed, Payload: []byte(`{"account_id":"1"}`), CreatedAt: processedAt, ProcessedAt: &processedAt},
+	}
+	second := []model.OutboxEvent{{ID: 3, EventType: model.DepositDetectedEvent, Payload: []byte(`{}`), CreatedAt: processedAt}}
+
+	require.NoError(t, archive.ArchiveEvents(context.Background(), first))
+	require.NoError(t, archive.ArchiveEvents(context.Background(), second))
+	require.NoError(t, archive.ArchiveEvents(context.Background(), nil))
+
+	events := readArchive(t, dir)
+	require.Len(t, events, 3, "the batches are appended to the daily file")
+
+	require.Equal(t, int64(1), events[0].ID)
+	require.Equal(t, string(model.AccountCreated), events[0].EventType)
+	require.JSONEq(t, `{"account_id":"1"}`, events[0].Payload)
+	require.Equal(t, processedAt, *events[0].ProcessedAt)
+	require.False(t, events[0].ArchivedAt.IsZero())
+
+	require.Equal(t, int64(3), events[2].ID)
+	require.Nil(t, events[2].ProcessedAt)
+}
+
+func TestNewJSONLArchive_EmptyDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewJSONLArchive("")
+	require.Error(t, err)
+}
@@ -2,41 +2,168 @@ package consumer
 
 import (
 	"context"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/go-faster/errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
+	"github.com/kriuchkov/tonbeacon/pkg/retrier"
+)
+
+const (
+	// defaultRetryDelay is the default delay before a message from the retry topic is handled again.
+	defaultRetryDelay = 30 * time.Second
+
+	// defaultMaxRetries is the default number of rounds through the retry topic.
+	defaultMaxRetries = 3
+
+	// defaultConcurrency is the default number of workers per partition.
+	defaultConcurrency = 1
+
+	// defaultConsumerBatchSize is the default number of messages handled at once.
+	defaultConsumerBatchSize = 1
+)
+
+// Headers added to messages forwarded to the retry and dead-letter topics.
+const (
+	HeaderRetryCount        = "x-retry-count"
+	HeaderRetryAt           = "x-retry-at"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
 )
 
+var errNoDeadLetterTopic = errors.New("dead-letter topic is not configured")
+
+var defaultRetryPolicy = retrier.RetryPolicy{
+	MaxAttempts:        3,
+	StartDelay:         100 * time.Millisecond,
+	MaxDelay:           lo.ToPtr(time.Second),
+	BackoffCoefficient: 2,
+}
+
 type KafkaHandler interface {
 	Handle(ctx context.Context, message []byte) error
 }
 
+// KafkaBatchHandler is implemented by handlers that can handle several messages at once.
+type KafkaBatchHandler interface {
+	HandleBatch(ctx context.Context, messages [][]byte) error
+}
+
+// KafkaKeyHandler is implemented by handlers that define the ordering key of a message.
+// Messages with the same key are handled in order, the message key is used otherwise.
+type KafkaKeyHandler interface {
+	Key(message []byte) string
+}
+
 type KafkaOptions struct {
 	Brokers []string `validate:"required"`
 	Topic   string   `validate:"required"`
 	GroupID string   `validate:"required"`
 	Handler KafkaHandler
+
+	// RetryPolicy configures in-place retries of a failed message.
+	RetryPolicy *retrier.RetryPolicy
+
+	// RetryTopic receives messages that failed in-place retries, they are handled again after RetryDelay.
+	RetryTopic string
+	RetryDelay time.Duration
+	MaxRetries int
+
+	// DeadLetterTopic receives messages that exhausted all retries together with error headers.
+	// Without it a failed message blocks its partition until it is handled successfully.
+	DeadLetterTopic string
+
+	// SkipErrors are handler errors after which a message is committed without retries.
+	SkipErrors []error
+
+	// Concurrency is the number of workers handling messages of a partition.
+	Concurrency int `validate:"gte=0"`
+
+	// BatchSize is the maximal number of messages passed to KafkaBatchHandler at once.
+	BatchSize int `validate:"gte=0"`
+
+	// Client holds the connection settings shared by the Kafka adapters.
+	Client kafka.Config
+}
+
+func (o *KafkaOptions) SetDefaults() {
+	if o.RetryPolicy == nil {
+		o.RetryPolicy = lo.ToPtr(defaultRetryPolicy)
+	}
+	if o.RetryDelay == 0 {
+		o.RetryDelay = defaultRetryDelay
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.SkipErrors == nil {
+		o.SkipErrors = []error{model.ErrAccountNotFound}
+	}
+	if o.Concurrency == 0 {
+		o.Concurrency = defaultConcurrency
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultConsumerBatchSize
+	}
 }
 
 type Kafka struct {
 	consumer sarama.ConsumerGroup
-	handler  KafkaHandler
-	topic    string
+	producer sarama.SyncProducer
+	handler  *consumerGroupHandler
+	topics   []string
 }
 
 func NewKafka(opts KafkaOptions) *Kafka {
+	opts.SetDefaults()
+
 	if err := validator.New().Struct(opts); err != nil {
 		panic(err.Error())
 	}
 
-	cfg := sarama.NewConfig()
+	cfg, err := opts.Client.SaramaConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+
 	consumer, err := sarama.NewConsumerGroup(opts.Brokers, opts.GroupID, cfg)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	return &Kafka{consumer: consumer, handler: opts.Handler, topic: opts.Topic}
+	var producer sarama.SyncProducer
+	if opts.RetryTopic != "" || opts.DeadLetterTopic != "" {
+		producerCfg, _ := opts.Client.SaramaConfig()
+		producerCfg.Producer.RequiredAcks = sarama.WaitForAll
+		producerCfg.Producer.Return.Successes = true
+
+		if producer, err = sarama.NewSyncProducer(opts.Brokers, producerCfg); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	topics := []string{opts.Topic}
+	if opts.RetryTopic != "" {
+		topics = append(topics, opts.RetryTopic)
+	}
+
+	return &Kafka{
+		consumer: consumer,
+		producer: producer,
+		handler:  newConsumerGroupHandler(opts, producer),
+		topics:   topics,
+	}
 }
 
 func (c *Kafka) Consume(ctx context.Context) {
@@ -46,14 +173,12 @@ func (c *Kafka) Consume(ctx context.Context) {
 		}
 	}()
 
-	handler := &consumerGroupHandler{ctx: ctx, handler: c.handler}
-	topics := []string{c.topic}
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			if err := c.consumer.Consume(ctx, topics, handler); err != nil {
+			if err := c.consumer.Consume(ctx, c.topics, c.handler); err != nil {
 				log.Error().Err(err).Msg("error from consumer")
 			}
 		}
@@ -61,17 +186,71 @@ func (c *Kafka) Consume(ctx context.Context) {
 }
 
 func (c *Kafka) Close() error {
+	if c.producer != nil {
+		if err := c.producer.Close(); err != nil {
+			return errors.Wrap(err, "close producer")
+		}
+	}
 	return c.consumer.Close()
 }
 
 type consumerGroupHandler struct {
-	ctx     context.Context
-	handler KafkaHandler
+	handler  KafkaHandler
+	retrier  *retrier.Retrier
+	producer sarama.SyncProducer
+
+	retryTopic      string
+	retryDelay      time.Duration
+	maxRetries      int
+	deadLetterTopic string
+	skipErrors      []error
+	concurrency     int
+	batchSize       int
+}
+
+func newConsumerGroupHandler(opts KafkaOptions, producer sarama.SyncProducer) *consumerGroupHandler {
+	return &consumerGroupHandler{
+		handler: opts.Handler,
+		retrier: retrier.NewRetrier(
+			retrier.WithRetryPolicy(*opts.RetryPolicy),
+			retrier.WithExcludedErrors(opts.SkipErrors...),
+		),
+		producer:        producer,
+		retryTopic:      opts.RetryTopic,
+		retryDelay:      opts.RetryDelay,
+		maxRetries:      opts.MaxRetries,
+		deadLetterTopic: opts.DeadLetterTopic,
+		skipErrors:      opts.SkipErrors,
+		concurrency:     opts.Concurrency,
+		batchSize:       opts.BatchSize,
+	}
 }
 
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	tracker := newOffsetTracker(session)
+
+	// Messages with the same key are sent to the same worker and handled in order.
+	workers := make([]chan *sarama.ConsumerMessage, h.concurrency)
+	wg := sync.WaitGroup{}
+	for i := range workers {
+		workers[i] = make(chan *sarama.ConsumerMessage, h.batchSize)
+
+		wg.Add(1)
+		go func(messages <-chan *sarama.ConsumerMessage) {
+			defer wg.Done()
+			h.worker(session.Context(), messages, tracker)
+		}(workers[i])
+	}
+
+	defer func() {
+		for _, worker := range workers {
+			close(worker)
+		}
+		wg.Wait()
+	}()
+
 	for message := range claim.Messages() {
-		if h.ctx.Err() != nil {
+		if session.Context().Err() != nil {
 			return nil //nolint:nilerr
 		}
 
@@ -79,12 +258,12 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 			Str("topic", message.Topic).Int32("partition", message.Partition).Int64("offset", message.Offset).
 			Msg("received message")
 
-		if err := h.handler.Handle(h.ctx, message.Value); err != nil {
-			log.Error().Err(err).
-				Str("topic", message.Topic).Int32("partition", message.Partition).Int64("offset", message.Offset).
-				Msg("handle message")
-		} else {
-			session.MarkMessage(message, "")
+		tracker.add(message)
+
+		select {
+		case workers[h.workerIndex(message)] <- message:
+		case <-session.Context().Done():
+			return nil
 		}
 	}
 	return nil
@@ -93,6 +272,140 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 func (h *consumerGroupHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
 func (h *consumerGroupHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
 
+// process handles the message and reports whether its offset can be committed.
+func (h *consumerGroupHandler) process(ctx context.Context, message *sarama.ConsumerMessage) bool {
+	if !h.waitRetryAt(ctx, message) {
+		return false
+	}
+
+	err := h.retrier.Wrap(ctx, "handle message", func() error { return h.handler.Handle(ctx, message.Value) })
+	if err == nil {
+		return true
+	}
+	if ctx.Err() != nil {
+		// the session ended, the message is consumed again by the next session
+		return false
+	}
+
+	logger := log.With().
+		Str("topic", message.Topic).Int32("partition", message.Partition).Int64("offset", message.Offset).
+		Logger()
+
+	if h.isSkipped(err) {
+		logger.Debug().Err(err).Msg("skip message")
+		return true
+	}
+
+	logger.Error().Err(err).Msg("handle message")
+
+	if err = h.forward(message, err); err != nil {
+		logger.Error().Err(err).Msg("forward failed message")
+		return false
+	}
+	return true
+}
+
+// waitRetryAt delays a message from the retry topic until its retry time.
+func (h *consumerGroupHandler) waitRetryAt(ctx context.Context, message *sarama.ConsumerMessage) bool {
+	value, ok := header(message, HeaderRetryAt)
+	if !ok {
+		return true
+	}
+
+	retryAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		log.Warn().Err(err).Str("retry_at", value).Msg("parse retry time")
+		return true
+	}
+
+	timer := time.NewTimer(time.Until(retryAt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (h *consumerGroupHandler) isSkipped(err error) bool {
+	return lo.ContainsBy(h.skipErrors, func(item error) bool { return errors.Is(err, item) })
+}
+
+// forward sends the failed message to the retry topic or, once retries are exhausted, to the dead-letter topic.
+func (h *consumerGroupHandler) forward(message *sarama.ConsumerMessage, cause error) error {
+	retryCount := 0
+	if value, ok := header(message, HeaderRetryCount); ok {
+		retryCount, _ = strconv.Atoi(value)
+	}
+
+	now := time.Now().UTC()
+	headers := map[string]string{
+		HeaderError:    cause.Error(),
+		HeaderFailedAt: now.Format(time.RFC3339Nano),
+	}
+
+	if _, ok := header(message, HeaderOriginalTopic); !ok {
+		headers[HeaderOriginalTopic] = message.Topic
+		headers[HeaderOriginalPartition] = strconv.FormatInt(int64(message.Partition), 10)
+		headers[HeaderOriginalOffset] = strconv.FormatInt(message.Offset, 10)
+	}
+
+	topic := h.deadLetterTopic
+	if h.retryTopic != "" && retryCount < h.maxRetries {
+		topic = h.retryTopic
+		headers[HeaderRetryCount] = strconv.Itoa(retryCount + 1)
+		headers[HeaderRetryAt] = now.Add(h.retryDelay).Format(time.RFC3339Nano)
+	}
+
+	if topic == "" {
+		return errNoDeadLetterTopic
+	}
+
+	msg := &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: mergeHeaders(message.Headers, headers),
+	}
+
+	if _, _, err := h.producer.SendMessage(msg); err != nil {
+		return errors.Wrapf(err, "send message to %s", topic)
+	}
+
+	log.Warn().Str("topic", topic).Int("retry_count", retryCount).Msg("failed message forwarded")
+	return nil
+}
+
+func header(message *sarama.ConsumerMessage, key string) (string, bool) {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+// mergeHeaders keeps the original headers and overrides the ones set by the consumer.
+func mergeHeaders(original []*sarama.RecordHeader, headers map[string]string) []sarama.RecordHeader {
+	result := make([]sarama.RecordHeader, 0, len(original)+len(headers))
+	for _, h := range original {
+		if h == nil {
+			continue
+		}
+
+		if _, ok := headers[string(h.Key)]; !ok {
+			result = append(result, *h)
+		}
+	}
+
+	for key, value := range headers {
+		result = append(result, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return result
+}
+
 type StdOutHandler struct{}
 
 func (h *StdOutHandler) Handle(_ context.Context, message []byte) error {
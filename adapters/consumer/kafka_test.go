@@ -0,0 +1,144 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/retrier"
+)
+
+var errHandle = errors.New("handle failed")
+
+type fakeKafkaHandler struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeKafkaHandler) Handle(context.Context, []byte) error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func headersOf(msg *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[string(h.Key)] = string(h.Value)
+	}
+	return headers
+}
+
+func newTestHandler(t *testing.T, handler KafkaHandler, producer sarama.SyncProducer, opts KafkaOptions) *consumerGroupHandler {
+	t.Helper()
+
+	opts.Handler = handler
+	opts.RetryPolicy = &retrier.RetryPolicy{MaxAttempts: 2, StartDelay: time.Millisecond, BackoffCoefficient: 1}
+	opts.SetDefaults()
+	return newConsumerGroupHandler(opts, producer)
+}
+
+func TestConsumerGroupHandler_Process(t *testing.T) {
+	message := &sarama.ConsumerMessage{
+		Topic:     "transactions",
+		Partition: 1,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}},
+	}
+
+	t.Run("retries in place", func(t *testing.T) {
+		handler := &fakeKafkaHandler{errs: []error{errHandle}}
+		h := newTestHandler(t, handler, nil, KafkaOptions{})
+
+		require.True(t, h.process(context.Background(), message))
+		require.Equal(t, 2, handler.calls)
+	})
+
+	t.Run("skips account not found", func(t *testing.T) {
+		handler := &fakeKafkaHandler{errs: []error{model.ErrAccountNotFound}}
+		h := newTestHandler(t, handler, nil, KafkaOptions{})
+
+		require.True(t, h.process(context.Background(), message))
+		require.Equal(t, 1, handler.calls)
+	})
+
+	t.Run("forwards to retry topic", func(t *testing.T) {
+		producer := mocks.NewSyncProducer(t, nil)
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			headers := headersOf(msg)
+			require.Equal(t, "transactions.retry", msg.Topic)
+			require.Equal(t, "1", headers[HeaderRetryCount])
+			require.Equal(t, "transactions", headers[HeaderOriginalTopic])
+			require.Equal(t, "42", headers[HeaderOriginalOffset])
+			require.Equal(t, errHandle.Error(), headers[HeaderError])
+			require.Equal(t, "abc", headers["trace"])
+			require.NotEmpty(t, headers[HeaderRetryAt])
+			return nil
+		})
+
+		handler := &fakeKafkaHandler{errs: []error{errHandle, errHandle}}
+		h := newTestHandler(t, handler, producer, KafkaOptions{RetryTopic: "transactions.retry", DeadLetterTopic: "transactions.dlt"})
+
+		require.True(t, h.process(context.Background(), message))
+		require.NoError(t, producer.Close())
+	})
+
+	t.Run("forwards to dead-letter topic after retries", func(t *testing.T) {
+		retried := &sarama.ConsumerMessage{
+			Topic: "transactions.retry",
+			Value: []byte("value"),
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte(HeaderRetryCount), Value: []byte("3")},
+				{Key: []byte(HeaderOriginalTopic), Value: []byte("transactions")},
+				{Key: []byte(HeaderRetryAt), Value: []byte(time.Now().Format(time.RFC3339Nano))},
+			},
+		}
+
+		producer := mocks.NewSyncProducer(t, nil)
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			headers := headersOf(msg)
+			require.Equal(t, "transactions.dlt", msg.Topic)
+			require.Equal(t, "3", headers[HeaderRetryCount])
+			require.Equal(t, "transactions", headers[HeaderOriginalTopic])
+			require.Equal(t, errHandle.Error(), headers[HeaderError])
+			return nil
+		})
+
+		handler := &fakeKafkaHandler{errs: []error{errHandle, errHandle}}
+		h := newTestHandler(t, handler, producer, KafkaOptions{RetryTopic: "transactions.retry", DeadLetterTopic: "transactions.dlt"})
+
+		require.True(t, h.process(context.Background(), retried))
+		require.NoError(t, producer.Close())
+	})
+
+	t.Run("keeps message when the session ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		handler := &fakeKafkaHandler{errs: []error{errHandle, errHandle}}
+		h := newTestHandler(t, handler, nil, KafkaOptions{RetryTopic: "transactions.retry", DeadLetterTopic: "transactions.dlt"})
+
+		require.False(t, h.process(ctx, message))
+		require.Equal(t, 1, handler.calls)
+	})
+
+	t.Run("keeps message without dead-letter topic", func(t *testing.T) {
+		handler := &fakeKafkaHandler{errs: []error{errHandle, errHandle}}
+		h := newTestHandler(t, handler, nil, KafkaOptions{})
+
+		require.False(t, h.process(context.Background(), message))
+	})
+}
@@ -0,0 +1,132 @@
+package consumer
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rs/zerolog/log"
+)
+
+// offsetTracker commits offsets of a partition only up to the lowest message that is not processed yet.
+type offsetTracker struct {
+	mx      sync.Mutex
+	session sarama.ConsumerGroupSession
+	pending []*sarama.ConsumerMessage
+	done    map[int64]bool
+}
+
+func newOffsetTracker(session sarama.ConsumerGroupSession) *offsetTracker {
+	return &offsetTracker{session: session, done: make(map[int64]bool)}
+}
+
+// add registers a message in offset order before it is dispatched to a worker.
+func (t *offsetTracker) add(message *sarama.ConsumerMessage) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.pending = append(t.pending, message)
+}
+
+// markDone marks the message as processed and commits all contiguous processed offsets.
+func (t *offsetTracker) markDone(message *sarama.ConsumerMessage) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.done[message.Offset] = true
+	for len(t.pending) > 0 && t.done[t.pending[0].Offset] {
+		head := t.pending[0]
+		t.session.MarkMessage(head, "")
+
+		delete(t.done, head.Offset)
+		t.pending = t.pending[1:]
+	}
+}
+
+func (h *consumerGroupHandler) workerIndex(message *sarama.ConsumerMessage) int {
+	if h.concurrency <= 1 {
+		return 0
+	}
+
+	key := string(message.Key)
+	if keyHandler, ok := h.handler.(KafkaKeyHandler); ok {
+		key = keyHandler.Key(message.Value)
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key)) //nolint:errcheck
+	return int(hash.Sum32() % uint32(h.concurrency))
+}
+
+// worker handles messages in the order they were received, collecting up to batchSize queued messages at once.
+func (h *consumerGroupHandler) worker(ctx context.Context, messages <-chan *sarama.ConsumerMessage, tracker *offsetTracker) {
+	for message := range messages {
+		batch := []*sarama.ConsumerMessage{message}
+
+	collect:
+		for len(batch) < h.batchSize {
+			select {
+			case next, ok := <-messages:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, next)
+			default:
+				break collect
+			}
+		}
+
+		if ctx.Err() != nil {
+			continue
+		}
+
+		for _, processed := range h.processBatch(ctx, batch) {
+			tracker.markDone(processed)
+		}
+	}
+}
+
+// processBatch handles the batch and returns the messages whose offsets can be committed.
+// A failed batch is handled message by message so retries and dead-lettering apply to the failed message only.
+func (h *consumerGroupHandler) processBatch(ctx context.Context, batch []*sarama.ConsumerMessage) []*sarama.ConsumerMessage {
+	if batchHandler, ok := h.handler.(KafkaBatchHandler); ok && len(batch) > 1 && !hasRetryAt(batch) {
+		values := make([][]byte, 0, len(batch))
+		for _, message := range batch {
+			values = append(values, message.Value)
+		}
+
+		err := h.retrier.Wrap(ctx, "handle batch", func() error { return batchHandler.HandleBatch(ctx, values) })
+		if err == nil {
+			return batch
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Warn().Err(err).Int("size", len(batch)).Msg("handle batch, falling back to single messages")
+	}
+
+	processed := make([]*sarama.ConsumerMessage, 0, len(batch))
+	for _, message := range batch {
+		for !h.process(ctx, message) {
+			select {
+			case <-ctx.Done():
+				return processed
+			case <-time.After(h.retryDelay):
+			}
+		}
+		processed = append(processed, message)
+	}
+	return processed
+}
+
+// hasRetryAt reports whether the batch contains messages from the retry topic that must wait for their retry time.
+func hasRetryAt(batch []*sarama.ConsumerMessage) bool {
+	for _, message := range batch {
+		if _, ok := header(message, HeaderRetryAt); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+
+	mx     sync.Mutex
+	marked []int64
+}
+
+func (f *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.marked = append(f.marked, msg.Offset)
+}
+
+type fakeBatchHandler struct {
+	fakeKafkaHandler
+
+	mx      sync.Mutex
+	batches [][]string
+}
+
+func (f *fakeBatchHandler) HandleBatch(_ context.Context, messages [][]byte) error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	batch := make([]string, 0, len(messages))
+	for _, message := range messages {
+		batch = append(batch, string(message))
+	}
+	f.batches = append(f.batches, batch)
+	return nil
+}
+
+func (f *fakeBatchHandler) Key(message []byte) string {
+	return string(message[:1])
+}
+
+func TestOffsetTracker_CommitsContiguousOffsets(t *testing.T) {
+	session := &fakeSession{}
+	tracker := newOffsetTracker(session)
+
+	messages := make([]*sarama.ConsumerMessage, 0, 4)
+	for offset := range int64(4) {
+		message := &sarama.ConsumerMessage{Offset: offset}
+		messages = append(messages, message)
+		tracker.add(message)
+	}
+
+	tracker.markDone(messages[1])
+	tracker.markDone(messages[3])
+	require.Empty(t, session.marked)
+
+	tracker.markDone(messages[0])
+	require.Equal(t, []int64{0, 1}, session.marked)
+
+	tracker.markDone(messages[2])
+	require.Equal(t, []int64{0, 1, 2, 3}, session.marked)
+}
+
+func TestConsumerGroupHandler_WorkerIndex(t *testing.T) {
+	h := newTestHandler(t, &fakeBatchHandler{}, nil, KafkaOptions{Concurrency: 8})
+
+	first := h.workerIndex(&sarama.ConsumerMessage{Value: []byte("a-1")})
+	require.Equal(t, first, h.workerIndex(&sarama.ConsumerMessage{Value: []byte("a-2")}))
+	require.Less(t, first, 8)
+}
+
+func TestConsumerGroupHandler_Worker(t *testing.T) {
+	handler := &fakeBatchHandler{}
+	h := newTestHandler(t, handler, nil, KafkaOptions{BatchSize: 3})
+
+	session := &fakeSession{}
+	tracker := newOffsetTracker(session)
+
+	messages := make(chan *sarama.ConsumerMessage, 5)
+	for offset, value := range []string{"a-1", "b-1", "a-2", "b-2", "a-3"} {
+		message := &sarama.ConsumerMessage{Offset: int64(offset), Value: []byte(value)}
+		tracker.add(message)
+		messages <- message
+	}
+	close(messages)
+
+	h.worker(context.Background(), messages, tracker)
+
+	require.Equal(t, [][]string{{"a-1", "b-1", "a-2"}, {"b-2", "a-3"}}, handler.batches)
+	require.Equal(t, []int64{0, 1, 2, 3, 4}, session.marked)
+}
@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/go-faster/errors"
@@ -14,30 +15,53 @@ import (
 
 const (
 	defaultInterval = 10 * time.Millisecond
+
+	// defaultBatchSize is the default number of events relayed in a single Kafka transaction.
+	defaultBatchSize = 100
 )
 
 type OutboxWriter interface {
 	SendMessage(key string, value []byte) (partition int32, offset int64, err error)
 }
 
+// OutboxTxnWriter sends messages in a Kafka transaction together with the ids of the delivered outbox events.
+type OutboxTxnWriter interface {
+	DeliveredIDs() ([]int64, error)
+	// DeliveredIDsFit reports whether the ids can be recorded by a single transaction.
+	DeliveredIDsFit(ids []int64) bool
+	SendMessages(keys []string, values [][]byte, deliveredIDs []int64) error
+}
+
 type OutboxOptions struct {
 	OutboxManager ports.OutboxServicePort             `validate:"required"`
 	TxManager     ports.DatabaseWithinTransactionPort `validate:"required"`
-	Writer        OutboxWriter                        `validate:"required"`
-	Interval      time.Duration
+	Writer        OutboxWriter                        `validate:"required_without=TxnWriter"`
+
+	// TxnWriter enables the exactly-once mode, Writer is ignored when it is set.
+	TxnWriter OutboxTxnWriter
+	BatchSize int
+	Interval  time.Duration
 }
 
 func (o *OutboxOptions) SetDefaults() {
 	if o.Interval == 0 {
 		o.Interval = defaultInterval
 	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultBatchSize
+	}
 }
 
 type Outbox struct {
 	tx        ports.DatabaseWithinTransactionPort
 	outboxSvc ports.OutboxServicePort
 	writer    OutboxWriter
+	txnWriter OutboxTxnWriter
+	batchSize int
 	interval  time.Duration
+
+	// delivered holds ids committed to Kafka but not yet marked as processed in the database.
+	delivered map[int64]struct{}
 }
 
 func NewOutbox(options OutboxOptions) *Outbox {
@@ -51,6 +75,8 @@ func NewOutbox(options OutboxOptions) *Outbox {
 		tx:        options.TxManager,
 		outboxSvc: options.OutboxManager,
 		writer:    options.Writer,
+		txnWriter: options.TxnWriter,
+		batchSize: options.BatchSize,
 		interval:  options.Interval,
 	}
 }
@@ -74,6 +100,10 @@ func (o *Outbox) Consumer(ctx context.Context) {
 }
 
 func (o *Outbox) process(ctx context.Context) error {
+	if o.txnWriter != nil {
+		return o.processTxn(ctx)
+	}
+
 	err := o.tx.WithInTransaction(ctx, func(ctx context.Context) error {
 		event, err := o.outboxSvc.GetPendingEvent(ctx)
 		if err != nil {
@@ -84,7 +114,12 @@ func (o *Outbox) process(ctx context.Context) error {
 			return errors.Wrap(err, "get pending event")
 		}
 
-		if _, _, err = o.writer.SendMessage(event.Key(), event.Payload); err != nil {
+		message, err := event.Message()
+		if err != nil {
+			return errors.Wrap(err, "event message")
+		}
+
+		if _, _, err = o.writer.SendMessage(event.Key(), message); err != nil {
 			return errors.Wrap(err, "send message")
 		}
 
@@ -100,3 +135,96 @@ func (o *Outbox) process(ctx context.Context) error {
 	}
 	return nil
 }
+
+// processTxn relays a batch of events in a Kafka transaction.
+// Kafka is the source of truth for delivered events: the transaction records their ids,
+// so events delivered before a crash but not marked as processed are not sent again.
+// The batch is cut where its ids would no longer fit in the transaction.
+func (o *Outbox) processTxn(ctx context.Context) error {
+	if o.delivered == nil {
+		ids, err := o.txnWriter.DeliveredIDs()
+		if err != nil {
+			return errors.Wrap(err, "get delivered ids")
+		}
+
+		o.delivered = make(map[int64]struct{}, len(ids))
+		for _, id := range ids {
+			o.delivered[id] = struct{}{}
+		}
+	}
+
+	var processed []int64
+	err := o.tx.WithInTransaction(ctx, func(ctx context.Context) error {
+		events, err := o.outboxSvc.GetPendingEvents(ctx, o.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "get pending events")
+		}
+
+		keys := make([]string, 0, len(events))
+		values := make([][]byte, 0, len(events))
+		ids := make([]int64, 0, len(events))
+
+		for _, event := range events {
+			if !o.txnWriter.DeliveredIDsFit(o.deliveredWith(append(ids, event.ID))) {
+				if len(ids) == 0 {
+					return errors.Errorf("delivered ids do not fit in a transaction with event %d", event.ID)
+				}
+				break
+			}
+			ids = append(ids, event.ID)
+
+			if _, ok := o.delivered[event.ID]; ok {
+				log.Debug().Int64("event_id", event.ID).Msg("event already delivered")
+				continue
+			}
+
+			message, err := event.Message()
+			if err != nil {
+				return errors.Wrap(err, "event message")
+			}
+
+			keys = append(keys, event.Key())
+			values = append(values, message)
+		}
+
+		if len(keys) > 0 {
+			if err = o.txnWriter.SendMessages(keys, values, o.deliveredWith(ids)); err != nil {
+				return errors.Wrap(err, "send messages")
+			}
+
+			for _, id := range ids {
+				o.delivered[id] = struct{}{}
+			}
+		}
+
+		for _, id := range ids {
+			if err = o.outboxSvc.MarkEventAsProcessed(ctx, id); err != nil {
+				return errors.Wrap(err, "mark event as processed")
+			}
+		}
+
+		processed = ids
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "process transaction")
+	}
+
+	for _, id := range processed {
+		delete(o.delivered, id)
+	}
+	return nil
+}
+
+// deliveredWith returns the sorted ids of the batch and of the events delivered earlier but not yet marked as processed.
+func (o *Outbox) deliveredWith(ids []int64) []int64 {
+	all := make([]int64, 0, len(o.delivered)+len(ids))
+	for id := range o.delivered {
+		all = append(all, id)
+	}
+
+	all = append(all, ids...)
+	slices.Sort(all)
+	return slices.Compact(all)
+}
@@ -0,0 +1,159 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+var errCommit = errors.New("commit failed")
+
+type fakeTx struct {
+	commitErr error
+}
+
+func (f *fakeTx) WithInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return f.commitErr
+}
+
+type fakeOutboxService struct {
+	events    []model.OutboxEvent
+	processed map[int64]bool
+	marked    []int64
+}
+
+func (f *fakeOutboxService) GetPendingEvent(context.Context) (*model.OutboxEvent, error) {
+	return nil, model.ErrNoPendingEvents
+}
+
+func (f *fakeOutboxService) GetPendingEvents(_ context.Context, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	for _, event := range f.events {
+		if !f.processed[event.ID] && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeOutboxService) MarkEventAsProcessed(_ context.Context, eventID int64) error {
+	f.marked = append(f.marked, eventID)
+	return nil
+}
+
+// commit applies the marks of a committed database transaction.
+func (f *fakeOutboxService) commit() {
+	for _, id := range f.marked {
+		f.processed[id] = true
+	}
+	f.marked = nil
+}
+
+type fakeTxnWriter struct {
+	delivered []int64
+	sent      []string
+	// maxIDs limits the number of the delivered ids of a transaction, 0 for no limit.
+	maxIDs int
+}
+
+func (f *fakeTxnWriter) DeliveredIDs() ([]int64, error) {
+	return f.delivered, nil
+}
+
+func (f *fakeTxnWriter) DeliveredIDsFit(ids []int64) bool {
+	return f.maxIDs == 0 || len(ids) <= f.maxIDs
+}
+
+func (f *fakeTxnWriter) SendMessages(keys []string, _ [][]byte, deliveredIDs []int64) error {
+	f.sent = append(f.sent, keys...)
+	f.delivered = deliveredIDs
+	return nil
+}
+
+func newTestEvents(ids ...int64) []model.OutboxEvent {
+	events := make([]model.OutboxEvent, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, model.OutboxEvent{ID: id, EventType: model.AccountCreated, Payload: []byte(`{}`)})
+	}
+	return events
+}
+
+func TestOutbox_ProcessTxn(t *testing.T) {
+	tests := []struct {
+		name          string
+		events        []model.OutboxEvent
+		delivered     []int64
+		maxIDs        int
+		commitErrs    []error
+		expectedSent  []string
+		expectedState []int64
+	}{
+		{
+			name:          "delivers pending events once",
+			events:        newTestEvents(1, 2),
+			commitErrs:    []error{nil, nil},
+			expectedSent:  []string{"account_created:1", "account_created:2"},
+			expectedState: []int64{1, 2},
+		},
+		{
+			name:          "skips events delivered before restart",
+			events:        newTestEvents(1, 2, 3),
+			delivered:     []int64{1, 2},
+			commitErrs:    []error{nil},
+			expectedSent:  []string{"account_created:3"},
+			expectedState: []int64{1, 2, 3},
+		},
+		{
+			name:          "cuts the batch to the delivered ids limit",
+			events:        newTestEvents(1, 2, 3),
+			maxIDs:        2,
+			commitErrs:    []error{nil, nil},
+			expectedSent:  []string{"account_created:1", "account_created:2", "account_created:3"},
+			expectedState: []int64{3},
+		},
+		{
+			name:          "does not resend after database commit failure",
+			events:        newTestEvents(1, 2),
+			commitErrs:    []error{errCommit, nil},
+			expectedSent:  []string{"account_created:1", "account_created:2"},
+			expectedState: []int64{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &fakeTx{}
+			svc := &fakeOutboxService{events: tt.events, processed: map[int64]bool{}}
+			writer := &fakeTxnWriter{delivered: tt.delivered, maxIDs: tt.maxIDs}
+
+			relay := NewOutbox(OutboxOptions{OutboxManager: svc, TxManager: tx, TxnWriter: writer})
+
+			for _, commitErr := range tt.commitErrs {
+				tx.commitErr = commitErr
+
+				err := relay.process(context.Background())
+				if commitErr != nil {
+					require.ErrorIs(t, err, commitErr)
+					svc.marked = nil
+					continue
+				}
+
+				require.NoError(t, err)
+				svc.commit()
+			}
+
+			require.Equal(t, tt.expectedSent, writer.sent)
+			require.Equal(t, tt.expectedState, writer.delivered)
+			for _, event := range tt.events {
+				require.True(t, svc.processed[event.ID], "event %d is not processed", event.ID)
+			}
+		})
+	}
+}
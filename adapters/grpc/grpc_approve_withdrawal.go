@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) ApproveWithdrawal(ctx context.Context, req *pb.ApproveWithdrawalRequest) (*pb.ApproveWithdrawalResponse, error) {
+	if s.withdrawalSvc == nil {
+		return approveWithdrawalPbError(codes.Unimplemented, errors.New("withdrawals are not available")), nil
+	}
+
+	// the operator of the request is not trusted, the decision belongs to the authenticated caller
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return approveWithdrawalPbError(codes.Unauthenticated, err), nil
+	}
+	log.Debug().Int64("id", req.GetId()).Str("operator", operator).Msg("approve withdrawal")
+
+	approvals, err := s.withdrawalSvc.ApproveWithdrawal(ctx, &model.WithdrawalApproval{
+		WithdrawalID: req.GetId(),
+		Operator:     operator,
+		Comment:      req.GetComment(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidWithdrawal):
+			return approveWithdrawalPbError(codes.InvalidArgument, err), nil
+		case errors.Is(err, model.ErrWithdrawalNotFound):
+			return approveWithdrawalPbError(codes.NotFound, err), nil
+		case errors.Is(err, model.ErrWithdrawalApprovalExists):
+			return approveWithdrawalPbError(codes.AlreadyExists, err), nil
+		case errors.Is(err, model.ErrWithdrawalNotPending):
+			return approveWithdrawalPbError(codes.FailedPrecondition, err), nil
+		}
+		return approveWithdrawalPbError(codes.Internal, errors.Wrap(err, "approve withdrawal")), nil
+	}
+
+	response := &pb.ApproveWithdrawalResponse{Approvals: make([]*pb.WithdrawalApproval, 0, len(approvals))}
+	for _, approval := range approvals {
+		response.Approvals = append(response.Approvals, toPbWithdrawalApproval(approval))
+	}
+	return response, nil
+}
+
+func toPbWithdrawalApproval(approval *model.WithdrawalApproval) *pb.WithdrawalApproval {
+	return &pb.WithdrawalApproval{
+		Operator:  approval.Operator,
+		Decision:  string(approval.Decision),
+		Comment:   approval.Comment,
+		CreatedAt: timestamppb.New(approval.CreatedAt),
+	}
+}
+
+func approveWithdrawalPbError(code codes.Code, err error) *pb.ApproveWithdrawalResponse {
+	return &pb.ApproveWithdrawalResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
@@ -0,0 +1,159 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_ApproveWithdrawal(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		approvals        []*model.WithdrawalApproval
+		serviceError     error
+		expectedResponse *pb.ApproveWithdrawalResponse
+	}{
+		{
+			name: "approvals of the withdrawal",
+			approvals: []*model.WithdrawalApproval{
+				{ID: 1, WithdrawalID: 5, Operator: "bob", Decision: model.ApprovalApproved, CreatedAt: at},
+				{ID: 2, WithdrawalID: 5, Operator: "alice", Decision: model.ApprovalApproved, Comment: "checked", CreatedAt: at},
+			},
+			expectedResponse: &pb.ApproveWithdrawalResponse{Approvals: []*pb.WithdrawalApproval{
+				{Operator: "bob", Decision: "approved", CreatedAt: timestamppb.New(at)},
+				{Operator: "alice", Decision: "approved", Comment: "checked", CreatedAt: timestamppb.New(at)},
+			}},
+		},
+		{
+			name:         "operator already decided",
+			serviceError: model.ErrWithdrawalApprovalExists,
+			expectedResponse: &pb.ApproveWithdrawalResponse{Error: &pb.Error{
+				Code: uint32(codes.AlreadyExists), Message: model.ErrWithdrawalApprovalExists.Error(),
+			}},
+		},
+		{
+			name:         "withdrawal is not requested",
+			serviceError: errors.Wrap(model.ErrWithdrawalNotPending, "withdrawal 5 is signed"),
+			expectedResponse: &pb.ApproveWithdrawalResponse{Error: &pb.Error{
+				Code: uint32(codes.FailedPrecondition), Message: "withdrawal 5 is signed: " + model.ErrWithdrawalNotPending.Error(),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWithdrawalSvc := portsmocks.NewMockWithdrawalServicePort(t)
+			mockWithdrawalSvc.On("ApproveWithdrawal", mock.Anything, mock.MatchedBy(func(a *model.WithdrawalApproval) bool {
+				return a.WithdrawalID == 5 && a.Operator == "alice" && a.Comment == "checked"
+			})).Return(tt.approvals, tt.serviceError).Once()
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+				Account:        portsmocks.NewMockAccountServicePort(t),
+				Withdrawals:    mockWithdrawalSvc,
+				TLS:            &tls.Config{MinVersion: tls.VersionTLS12},
+				OperatorTokens: map[string]string{"alice": "alice-token", "bob": "bob-token"},
+			})
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer alice-token"))
+			resp, err := server.ApproveWithdrawal(ctx, &pb.ApproveWithdrawalRequest{
+				Id: 5, Operator: "bob", Comment: "checked",
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
+
+func TestTonBeacon_ApproveWithdrawalOperator(t *testing.T) {
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+	tlsPeer := &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+	}}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		approved bool
+	}{
+		{
+			name:     "client certificate",
+			ctx:      peer.NewContext(context.Background(), tlsPeer),
+			approved: true,
+		},
+		{
+			name: "no credentials",
+			ctx:  context.Background(),
+		},
+		{
+			name: "unknown token",
+			ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer bob-token")),
+		},
+		{
+			name: "certificate of another operator",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "mallory"}}}}},
+			}}),
+		},
+		{
+			name: "unverified certificate",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWithdrawalSvc := portsmocks.NewMockWithdrawalServicePort(t)
+			if tt.approved {
+				mockWithdrawalSvc.On("ApproveWithdrawal", mock.Anything, mock.MatchedBy(func(a *model.WithdrawalApproval) bool {
+					return a.Operator == "alice"
+				})).Return([]*model.WithdrawalApproval{}, nil).Once()
+			}
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+				Account:        portsmocks.NewMockAccountServicePort(t),
+				Withdrawals:    mockWithdrawalSvc,
+				TLS:            &tls.Config{MinVersion: tls.VersionTLS12},
+				Operators:      []string{"alice"},
+				OperatorTokens: map[string]string{"alice": "alice-token"},
+			})
+
+			resp, err := server.ApproveWithdrawal(tt.ctx, &pb.ApproveWithdrawalRequest{Id: 5, Operator: "alice"})
+			require.NoError(t, err)
+			if tt.approved {
+				require.Nil(t, resp.GetError())
+				return
+			}
+			require.Equal(t, uint32(codes.Unauthenticated), resp.GetError().GetCode())
+		})
+	}
+}
+
+func TestNewTonBeacon_OperatorTokensWithoutTLS(t *testing.T) {
+	require.Panics(t, func() {
+		grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+			Account:        portsmocks.NewMockAccountServicePort(t),
+			OperatorTokens: map[string]string{"alice": "alice-token"},
+		})
+	})
+}
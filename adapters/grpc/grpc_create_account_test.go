@@ -92,7 +92,7 @@ func TestTonBeacon_CreateAccount(t *testing.T) {
 					Times(param.calls)
 			}
 
-			tonBeacon := grpcAdapter.NewTonBeacon(mockAccountSvc)
+			tonBeacon := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: mockAccountSvc})
 
 			resp, err := tonBeacon.CreateAccount(context.Background(), tt.req)
 			require.ErrorIs(t, err, tt.expectedError)
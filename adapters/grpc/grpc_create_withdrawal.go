@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) CreateWithdrawal(ctx context.Context, req *pb.CreateWithdrawalRequest) (*pb.CreateWithdrawalResponse, error) {
+	log.Debug().Str("account_id", req.GetAccountId()).Str("idempotency_key", req.GetIdempotencyKey()).Msg("create withdrawal")
+
+	if s.withdrawalSvc == nil {
+		return createWithdrawalPbError(codes.Unimplemented, errors.New("withdrawals are not available")), nil
+	}
+
+	amount, err := model.ParseUnits(req.GetAmount())
+	if err != nil {
+		return createWithdrawalPbError(codes.InvalidArgument, errors.Wrap(err, "amount")), nil
+	}
+
+	withdrawal, err := s.withdrawalSvc.CreateWithdrawal(ctx, &model.Withdrawal{
+		AccountID:      req.GetAccountId(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+		To:             req.GetTo(),
+		Currency:       model.Currency(req.GetCurrency()),
+		Amount:         amount,
+		Comment:        req.GetComment(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidWithdrawal):
+			return createWithdrawalPbError(codes.InvalidArgument, err), nil
+		case errors.Is(err, model.ErrAccountNotFound):
+			return createWithdrawalPbError(codes.NotFound, err), nil
+		case errors.Is(err, model.ErrWithdrawalExists):
+			return createWithdrawalPbError(codes.AlreadyExists, err), nil
+		case errors.Is(err, model.ErrInsufficientFunds):
+			return createWithdrawalPbError(codes.FailedPrecondition, err), nil
+		}
+		return createWithdrawalPbError(codes.Internal, errors.Wrap(err, "create withdrawal")), nil
+	}
+	return &pb.CreateWithdrawalResponse{Withdrawal: toPbWithdrawal(withdrawal)}, nil
+}
+
+func toPbWithdrawal(withdrawal *model.Withdrawal) *pb.Withdrawal {
+	return &pb.Withdrawal{
+		Id:             withdrawal.ID,
+		AccountId:      withdrawal.AccountID,
+		IdempotencyKey: withdrawal.IdempotencyKey,
+		To:             withdrawal.To,
+		Currency:       withdrawal.Currency.String(),
+		Amount:         withdrawal.Amount.String(),
+		Comment:        withdrawal.Comment,
+		Status:         string(withdrawal.Status),
+		MessageHash:    withdrawal.MessageHash,
+		TxHash:         withdrawal.TxHash,
+		Error:          withdrawal.Error,
+		HoldReason:     withdrawal.HoldReason,
+		CreatedAt:      timestamppb.New(withdrawal.CreatedAt),
+		UpdatedAt:      timestamppb.New(withdrawal.UpdatedAt),
+	}
+}
+
+func createWithdrawalPbError(code codes.Code, err error) *pb.CreateWithdrawalResponse {
+	return &pb.CreateWithdrawalResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
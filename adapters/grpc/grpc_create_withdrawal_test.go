@@ -0,0 +1,98 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_CreateWithdrawal(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	request := &pb.CreateWithdrawalRequest{
+		AccountId: "acc", IdempotencyKey: "payout-1", To: "EQ...", Currency: "USDT", Amount: "1.5", Comment: "invoice 42",
+	}
+
+	tests := []struct {
+		name             string
+		request          *pb.CreateWithdrawalRequest
+		withdrawal       *model.Withdrawal
+		serviceError     error
+		expectedResponse *pb.CreateWithdrawalResponse
+	}{
+		{
+			name:    "amount is in whole units",
+			request: request,
+			withdrawal: &model.Withdrawal{
+				ID: 5, AccountID: "acc", IdempotencyKey: "payout-1", To: "EQ...", Currency: model.CurrencyUSDT,
+				Amount: model.NewAmount(1_500_000_000), Comment: "invoice 42", Status: model.WithdrawalRequested,
+				CreatedAt: at, UpdatedAt: at,
+			},
+			expectedResponse: &pb.CreateWithdrawalResponse{Withdrawal: &pb.Withdrawal{
+				Id: 5, AccountId: "acc", IdempotencyKey: "payout-1", To: "EQ...", Currency: "USDT", Amount: "1.5",
+				Comment: "invoice 42", Status: "requested", CreatedAt: timestamppb.New(at), UpdatedAt: timestamppb.New(at),
+			}},
+		},
+		{
+			name:         "insufficient funds",
+			request:      request,
+			serviceError: model.ErrInsufficientFunds,
+			expectedResponse: &pb.CreateWithdrawalResponse{Error: &pb.Error{
+				Code: uint32(codes.FailedPrecondition), Message: model.ErrInsufficientFunds.Error(),
+			}},
+		},
+		{
+			name:         "idempotency key of another withdrawal",
+			request:      request,
+			serviceError: errors.Wrap(model.ErrWithdrawalExists, "idempotency key payout-1"),
+			expectedResponse: &pb.CreateWithdrawalResponse{Error: &pb.Error{
+				Code: uint32(codes.AlreadyExists), Message: "idempotency key payout-1: " + model.ErrWithdrawalExists.Error(),
+			}},
+		},
+		{
+			name:    "invalid amount",
+			request: &pb.CreateWithdrawalRequest{AccountId: "acc", Amount: "0.0000000001"},
+			expectedResponse: &pb.CreateWithdrawalResponse{Error: &pb.Error{
+				Code: uint32(codes.InvalidArgument), Message: `amount: invalid amount "0.0000000001", more than 9 decimals`,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWithdrawalSvc := portsmocks.NewMockWithdrawalServicePort(t)
+			if tt.withdrawal != nil || tt.serviceError != nil {
+				mockWithdrawalSvc.On("CreateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Amount.Nano() == "1500000000" && w.Currency == model.CurrencyUSDT && w.IdempotencyKey == "payout-1"
+				})).Return(tt.withdrawal, tt.serviceError).Once()
+			}
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+				Account:     portsmocks.NewMockAccountServicePort(t),
+				Withdrawals: mockWithdrawalSvc,
+			})
+
+			resp, err := server.CreateWithdrawal(context.Background(), tt.request)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
+
+func TestTonBeacon_Withdrawals_Unavailable(t *testing.T) {
+	server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: portsmocks.NewMockAccountServicePort(t)})
+
+	resp, err := server.ListWithdrawals(context.Background(), &pb.ListWithdrawalsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(codes.Unimplemented), resp.GetError().GetCode())
+}
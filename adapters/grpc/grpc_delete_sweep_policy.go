@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) DeleteSweepPolicy(ctx context.Context, req *pb.DeleteSweepPolicyRequest) (*pb.DeleteSweepPolicyResponse, error) {
+	log.Debug().Str("account_id", req.GetAccountId()).Str("currency", req.GetCurrency()).Msg("delete sweep policy")
+
+	if s.policySvc == nil {
+		return deleteSweepPolicyPbError(codes.Unimplemented, errors.New("sweep policies are not available")), nil
+	}
+
+	err := s.policySvc.DeleteSweepPolicy(ctx, req.GetAccountId(), model.Currency(req.GetCurrency()))
+	if err != nil {
+		if errors.Is(err, model.ErrSweepPolicyNotFound) {
+			return deleteSweepPolicyPbError(codes.NotFound, err), nil
+		}
+		return deleteSweepPolicyPbError(codes.Internal, errors.Wrap(err, "delete sweep policy")), nil
+	}
+	return &pb.DeleteSweepPolicyResponse{}, nil
+}
+
+func deleteSweepPolicyPbError(code codes.Code, err error) *pb.DeleteSweepPolicyResponse {
+	return &pb.DeleteSweepPolicyResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
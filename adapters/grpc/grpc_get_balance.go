@@ -21,11 +21,15 @@ func (s *TonBeacon) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (
 		return getBalancePbError(codes.Internal, errors.Wrap(err, "get balance")), nil
 	}
 
-	var balances []*pb.Tokens
-	for _, b := range balance {
-		balances = append(balances, &pb.Tokens{Symbol: b.Currency.String(), Amount: b.Amount.String()})
+	return &pb.GetBalanceResponse{Tokens: toPbTokens(balance.Ledger), OnChain: toPbTokens(balance.OnChain)}, nil
+}
+
+func toPbTokens(balances []model.Balance) []*pb.Tokens {
+	tokens := make([]*pb.Tokens, 0, len(balances))
+	for _, b := range balances {
+		tokens = append(tokens, &pb.Tokens{Symbol: b.Currency.String(), Amount: b.Amount.String()})
 	}
-	return &pb.GetBalanceResponse{Tokens: balances}, nil
+	return tokens
 }
 
 func getBalancePbError(code codes.Code, err error) *pb.GetBalanceResponse {
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.GetTransactionResponse, error) {
+	log.Debug().Str("hash", req.GetHash()).Str("account_id", req.GetAccountId()).Int64("lt", req.GetLt()).Msg("get transaction")
+
+	tx, err := s.accountSvc.GetTransaction(ctx, model.GetTransactionQuery{
+		Hash:      req.GetHash(),
+		AccountID: req.GetAccountId(),
+		LT:        req.GetLt(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrTransactionNotFound):
+			return getTransactionPbError(codes.NotFound, err), nil
+		case errors.Is(err, model.ErrInvalidTransactionQuery):
+			return getTransactionPbError(codes.InvalidArgument, err), nil
+		}
+		return getTransactionPbError(codes.Internal, errors.Wrap(err, "get transaction")), nil
+	}
+	return &pb.GetTransactionResponse{Transaction: toPbTransaction(tx)}, nil
+}
+
+func getTransactionPbError(code codes.Code, err error) *pb.GetTransactionResponse {
+	return &pb.GetTransactionResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
@@ -0,0 +1,75 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_GetTransaction(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		req              *pb.GetTransactionRequest
+		query            model.GetTransactionQuery
+		tx               *model.Transaction
+		serviceError     error
+		expectedResponse *pb.GetTransactionResponse
+	}{
+		{
+			name:  "by account and logical time",
+			req:   &pb.GetTransactionRequest{AccountId: "acc", Lt: 11},
+			query: model.GetTransactionQuery{AccountID: "acc", LT: 11},
+			tx: &model.Transaction{
+				Hash: "0102", AccountID: "acc", LT: 11, Direction: model.DirectionIn, Currency: model.CurrencyTON,
+				Amount: model.NewAmount(1_500_000_000), Kind: model.TxKindDeposit, Comment: "memo", CreatedAt: createdAt,
+			},
+			expectedResponse: &pb.GetTransactionResponse{
+				Transaction: &pb.Transaction{
+					Hash: "0102", AccountId: "acc", Lt: 11, Direction: "in", Currency: "TON", Amount: "1.5", TotalFees: "0",
+					Kind: "deposit", Comment: "memo", CreatedAt: timestamppb.New(createdAt),
+				},
+			},
+		},
+		{
+			name:         "not found",
+			req:          &pb.GetTransactionRequest{Hash: "0303"},
+			query:        model.GetTransactionQuery{Hash: "0303"},
+			serviceError: model.ErrTransactionNotFound,
+			expectedResponse: &pb.GetTransactionResponse{
+				Error: &pb.Error{Code: uint32(codes.NotFound), Message: model.ErrTransactionNotFound.Error()},
+			},
+		},
+		{
+			name:         "invalid query",
+			req:          &pb.GetTransactionRequest{Lt: 11},
+			query:        model.GetTransactionQuery{LT: 11},
+			serviceError: model.ErrInvalidTransactionQuery,
+			expectedResponse: &pb.GetTransactionResponse{
+				Error: &pb.Error{Code: uint32(codes.InvalidArgument), Message: model.ErrInvalidTransactionQuery.Error()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAccountSvc := portsmocks.NewMockAccountServicePort(t)
+			mockAccountSvc.On("GetTransaction", mock.Anything, tt.query).Return(tt.tx, tt.serviceError).Once()
+
+			resp, err := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: mockAccountSvc}).GetTransaction(context.Background(), tt.req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
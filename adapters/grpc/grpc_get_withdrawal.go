@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) GetWithdrawal(ctx context.Context, req *pb.GetWithdrawalRequest) (*pb.GetWithdrawalResponse, error) {
+	log.Debug().Int64("id", req.GetId()).Msg("get withdrawal")
+
+	if s.withdrawalSvc == nil {
+		return getWithdrawalPbError(codes.Unimplemented, errors.New("withdrawals are not available")), nil
+	}
+
+	withdrawal, err := s.withdrawalSvc.GetWithdrawal(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, model.ErrWithdrawalNotFound) {
+			return getWithdrawalPbError(codes.NotFound, err), nil
+		}
+		return getWithdrawalPbError(codes.Internal, errors.Wrap(err, "get withdrawal")), nil
+	}
+	return &pb.GetWithdrawalResponse{Withdrawal: toPbWithdrawal(withdrawal)}, nil
+}
+
+func getWithdrawalPbError(code codes.Code, err error) *pb.GetWithdrawalResponse {
+	return &pb.GetWithdrawalResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) ListSweepPolicies(ctx context.Context, req *pb.ListSweepPoliciesRequest) (*pb.ListSweepPoliciesResponse, error) {
+	log.Debug().Str("account_id", req.GetAccountId()).Msg("list sweep policies")
+
+	if s.policySvc == nil {
+		return listSweepPoliciesPbError(codes.Unimplemented, errors.New("sweep policies are not available")), nil
+	}
+
+	policies, err := s.policySvc.ListSweepPolicies(ctx, req.AccountId)
+	if err != nil {
+		return listSweepPoliciesPbError(codes.Internal, errors.Wrap(err, "list sweep policies")), nil
+	}
+
+	response := &pb.ListSweepPoliciesResponse{Policies: make([]*pb.SweepPolicy, 0, len(policies))}
+	for _, policy := range policies {
+		response.Policies = append(response.Policies, toPbSweepPolicy(policy))
+	}
+	return response, nil
+}
+
+func listSweepPoliciesPbError(code codes.Code, err error) *pb.ListSweepPoliciesResponse {
+	return &pb.ListSweepPoliciesResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
+
+func toPbSweepPolicy(policy *model.SweepPolicy) *pb.SweepPolicy {
+	return &pb.SweepPolicy{
+		AccountId:          policy.AccountID,
+		Currency:           string(policy.Currency),
+		Disabled:           policy.Disabled,
+		Threshold:          policy.Threshold.String(),
+		Reserve:            policy.Reserve.String(),
+		MinIntervalSeconds: uint64(policy.MinInterval / time.Second),
+		Schedule:           policy.Schedule,
+		UpdatedAt:          timestamppb.New(policy.UpdatedAt),
+	}
+}
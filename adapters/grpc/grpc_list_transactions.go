@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) ListTransactions(ctx context.Context, req *pb.ListTransactionsRequest) (*pb.ListTransactionsResponse, error) {
+	log.Debug().Str("account_id", req.GetAccountId()).Str("cursor", req.GetCursor()).Msg("list transactions")
+
+	filter := model.ListTransactionsFilter{
+		AccountID: req.AccountId,
+		Address:   req.Address,
+		FromLT:    req.FromLt,
+		ToLT:      req.ToLt,
+		Cursor:    req.GetCursor(),
+		Limit:     int(req.GetLimit()),
+	}
+
+	if req.Direction != nil {
+		direction := model.TransactionDirection(req.GetDirection())
+		if direction != model.DirectionIn && direction != model.DirectionOut {
+			return listTransactionsPbError(codes.InvalidArgument, errors.Errorf("unknown direction %q", req.GetDirection())), nil
+		}
+		filter.Direction = &direction
+	}
+
+	if req.Currency != nil {
+		filter.Currency = lo.ToPtr(model.Currency(req.GetCurrency()))
+	}
+
+	if req.Status != nil {
+		switch req.GetStatus() {
+		case "success":
+			filter.Success = lo.ToPtr(true)
+		case "failed":
+			filter.Success = lo.ToPtr(false)
+		default:
+			return listTransactionsPbError(codes.InvalidArgument, errors.Errorf("unknown status %q", req.GetStatus())), nil
+		}
+	}
+
+	if req.FromTime != nil {
+		filter.FromTime = lo.ToPtr(req.GetFromTime().AsTime())
+	}
+
+	if req.ToTime != nil {
+		filter.ToTime = lo.ToPtr(req.GetToTime().AsTime())
+	}
+
+	page, err := s.accountSvc.ListTransactions(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrAccountNotFound):
+			return listTransactionsPbError(codes.NotFound, err), nil
+		case errors.Is(err, model.ErrInvalidCursor):
+			return listTransactionsPbError(codes.InvalidArgument, err), nil
+		}
+		return listTransactionsPbError(codes.Internal, errors.Wrap(err, "list transactions")), nil
+	}
+
+	pbTransactions := make([]*pb.Transaction, 0, len(page.Transactions))
+	for _, tx := range page.Transactions {
+		pbTransactions = append(pbTransactions, toPbTransaction(tx))
+	}
+	return &pb.ListTransactionsResponse{Transactions: pbTransactions, NextCursor: page.NextCursor}, nil
+}
+
+func toPbTransaction(tx *model.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Hash:      tx.Hash,
+		AccountId: tx.AccountID,
+		Lt:        tx.LT,
+		Sender:    tx.Sender,
+		Receiver:  tx.Receiver,
+		Direction: string(tx.Direction),
+		Currency:  tx.Currency.String(),
+		Amount:    tx.Amount.String(),
+		TotalFees: tx.TotalFees.String(),
+		Success:   tx.Success,
+		ExitCode:  int32(tx.ExitCode), //nolint:gosec // exit codes are 32-bit
+		Kind:      string(tx.Kind),
+		Bounced:   tx.Bounced,
+		OpCode:    tx.OpCode,
+		QueryId:   tx.QueryID,
+		Comment:   tx.Comment,
+		CreatedAt: timestamppb.New(tx.CreatedAt),
+
+		EncryptedComment: tx.EncryptedComment,
+	}
+}
+
+func listTransactionsPbError(code codes.Code, err error) *pb.ListTransactionsResponse {
+	return &pb.ListTransactionsResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
@@ -0,0 +1,97 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_ListTransactions(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		req              *pb.ListTransactionsRequest
+		filter           func(filter model.ListTransactionsFilter) bool
+		page             *model.TransactionPage
+		serviceError     error
+		expectedResponse *pb.ListTransactionsResponse
+	}{
+		{
+			name: "filters are mapped",
+			req: &pb.ListTransactionsRequest{
+				AccountId: lo.ToPtr("acc"),
+				Direction: lo.ToPtr("in"),
+				Status:    lo.ToPtr("failed"),
+				FromTime:  timestamppb.New(createdAt),
+				FromLt:    lo.ToPtr(int64(10)),
+				Cursor:    "cursor",
+				Limit:     20,
+			},
+			filter: func(filter model.ListTransactionsFilter) bool {
+				return *filter.AccountID == "acc" && *filter.Direction == model.DirectionIn && !*filter.Success &&
+					filter.FromTime.Equal(createdAt) && *filter.FromLT == 10 && filter.Cursor == "cursor" && filter.Limit == 20
+			},
+			page: &model.TransactionPage{
+				Transactions: []*model.Transaction{{
+					Hash: "0102", AccountID: "acc", LT: 11, Direction: model.DirectionIn, Currency: model.CurrencyTON,
+					Amount: model.NewAmount(1_500_000_000), Kind: model.TxKindDeposit, Comment: "memo", CreatedAt: createdAt,
+				}, {
+					Hash: "0103", AccountID: "acc", LT: 12, Direction: model.DirectionIn, Currency: model.CurrencyTON,
+					Kind: model.TxKindDeposit, EncryptedComment: true, CreatedAt: createdAt,
+				}},
+				NextCursor: "next",
+			},
+			expectedResponse: &pb.ListTransactionsResponse{
+				Transactions: []*pb.Transaction{{
+					Hash: "0102", AccountId: "acc", Lt: 11, Direction: "in", Currency: "TON", Amount: "1.5", TotalFees: "0",
+					Kind: "deposit", Comment: "memo", CreatedAt: timestamppb.New(createdAt),
+				}, {
+					Hash: "0103", AccountId: "acc", Lt: 12, Direction: "in", Currency: "TON", Amount: "0", TotalFees: "0",
+					Kind: "deposit", EncryptedComment: true, CreatedAt: timestamppb.New(createdAt),
+				}},
+				NextCursor: "next",
+			},
+		},
+		{
+			name: "unknown status",
+			req:  &pb.ListTransactionsRequest{Status: lo.ToPtr("pending")},
+			expectedResponse: &pb.ListTransactionsResponse{
+				Error: &pb.Error{Code: uint32(codes.InvalidArgument), Message: `unknown status "pending"`},
+			},
+		},
+		{
+			name:         "invalid cursor",
+			req:          &pb.ListTransactionsRequest{Cursor: "!"},
+			filter:       func(model.ListTransactionsFilter) bool { return true },
+			serviceError: model.ErrInvalidCursor,
+			expectedResponse: &pb.ListTransactionsResponse{
+				Error: &pb.Error{Code: uint32(codes.InvalidArgument), Message: model.ErrInvalidCursor.Error()},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAccountSvc := portsmocks.NewMockAccountServicePort(t)
+			if tt.filter != nil {
+				mockAccountSvc.On("ListTransactions", mock.Anything, mock.MatchedBy(tt.filter)).Return(tt.page, tt.serviceError).Once()
+			}
+
+			resp, err := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: mockAccountSvc}).ListTransactions(context.Background(), tt.req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) ListWithdrawals(ctx context.Context, req *pb.ListWithdrawalsRequest) (*pb.ListWithdrawalsResponse, error) {
+	log.Debug().Str("account_id", req.GetAccountId()).Str("cursor", req.GetCursor()).Msg("list withdrawals")
+
+	if s.withdrawalSvc == nil {
+		return listWithdrawalsPbError(codes.Unimplemented, errors.New("withdrawals are not available")), nil
+	}
+
+	filter := model.ListWithdrawalsFilter{
+		AccountID: req.AccountId,
+		Cursor:    req.GetCursor(),
+		Limit:     int(req.GetLimit()),
+	}
+
+	if req.Status != nil {
+		status := model.WithdrawalStatus(req.GetStatus())
+		switch status {
+		case model.WithdrawalRequested, model.WithdrawalApproved, model.WithdrawalSigned,
+			model.WithdrawalBroadcast, model.WithdrawalConfirmed, model.WithdrawalFailed, model.WithdrawalUnconfirmed:
+		default:
+			return listWithdrawalsPbError(codes.InvalidArgument, errors.Errorf("unknown status %q", req.GetStatus())), nil
+		}
+		filter.Status = &status
+	}
+
+	page, err := s.withdrawalSvc.ListWithdrawals(ctx, filter)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidCursor) {
+			return listWithdrawalsPbError(codes.InvalidArgument, err), nil
+		}
+		return listWithdrawalsPbError(codes.Internal, errors.Wrap(err, "list withdrawals")), nil
+	}
+
+	pbWithdrawals := make([]*pb.Withdrawal, 0, len(page.Withdrawals))
+	for _, withdrawal := range page.Withdrawals {
+		pbWithdrawals = append(pbWithdrawals, toPbWithdrawal(withdrawal))
+	}
+	return &pb.ListWithdrawalsResponse{Withdrawals: pbWithdrawals, NextCursor: page.NextCursor}, nil
+}
+
+func listWithdrawalsPbError(code codes.Code, err error) *pb.ListWithdrawalsResponse {
+	return &pb.ListWithdrawalsResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
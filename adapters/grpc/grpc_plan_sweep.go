@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) PlanSweep(ctx context.Context, req *pb.PlanSweepRequest) (*pb.PlanSweepResponse, error) {
+	log.Debug().Strs("account_ids", req.GetAccountIds()).Msg("plan sweep")
+
+	if s.collectorSvc == nil {
+		return planSweepPbError(codes.Unimplemented, errors.New("sweep planning is not available")), nil
+	}
+
+	sweeps, err := s.collectorSvc.PlanSweeps(ctx, req.GetAccountIds())
+	if err != nil {
+		if errors.Is(err, model.ErrAccountNotFound) {
+			return planSweepPbError(codes.NotFound, err), nil
+		}
+		return planSweepPbError(codes.Internal, errors.Wrap(err, "plan sweeps")), nil
+	}
+
+	response := &pb.PlanSweepResponse{Sweeps: make([]*pb.PlannedSweep, 0, len(sweeps))}
+	for _, sweep := range sweeps {
+		response.Sweeps = append(response.Sweeps, toPbPlannedSweep(sweep))
+	}
+	return response, nil
+}
+
+func planSweepPbError(code codes.Code, err error) *pb.PlanSweepResponse {
+	return &pb.PlanSweepResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
+
+func toPbPlannedSweep(sweep *model.PlannedSweep) *pb.PlannedSweep {
+	return &pb.PlannedSweep{
+		AccountId: sweep.AccountID,
+		WalletId:  sweep.WalletID,
+		Address:   sweep.Address.String(),
+		Currency:  string(sweep.Currency),
+		Balance:   sweep.Balance.String(),
+		Amount:    sweep.Amount.String(),
+		Fee:       sweep.Fee.String(),
+		TopUp:     sweep.TopUp.String(),
+		Reserve:   sweep.Reserve.String(),
+
+		UnableToPayFee: sweep.UnableToPayFee,
+	}
+}
@@ -0,0 +1,81 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_PlanSweep(t *testing.T) {
+	tests := []struct {
+		name             string
+		sweeps           []*model.PlannedSweep
+		serviceError     error
+		expectedResponse *pb.PlanSweepResponse
+	}{
+		{
+			name: "amounts are whole units",
+			sweeps: []*model.PlannedSweep{{
+				AccountID: "acc", WalletID: 3, Address: "0:01", Currency: model.CurrencyUSDT,
+				Balance: model.NewAmount(2_000_000_000), Amount: model.NewAmount(1_500_000_000),
+				Fee: model.NewAmount(55_000_000), TopUp: model.NewAmount(40_000_000), Reserve: model.NewAmount(500_000_000),
+			}},
+			expectedResponse: &pb.PlanSweepResponse{Sweeps: []*pb.PlannedSweep{{
+				AccountId: "acc", WalletId: 3, Address: "0:01", Currency: "USDT",
+				Balance: "2", Amount: "1.5", Fee: "0.055", TopUp: "0.04", Reserve: "0.5",
+			}}},
+		},
+		{
+			name: "fee above the reserve",
+			sweeps: []*model.PlannedSweep{{
+				AccountID: "acc", WalletID: 3, Address: "0:01", Currency: model.CurrencyTON,
+				Balance: model.NewAmount(2_000_000_000), Amount: model.NewAmount(1_990_000_000),
+				Fee: model.NewAmount(20_000_000), Reserve: model.NewAmount(0), UnableToPayFee: true,
+			}},
+			expectedResponse: &pb.PlanSweepResponse{Sweeps: []*pb.PlannedSweep{{
+				AccountId: "acc", WalletId: 3, Address: "0:01", Currency: "TON",
+				Balance: "2", Amount: "1.99", Fee: "0.02", TopUp: "0", Reserve: "0", UnableToPayFee: true,
+			}}},
+		},
+		{
+			name:         "unknown account",
+			serviceError: fmt.Errorf("account acc: %w", model.ErrAccountNotFound),
+			expectedResponse: &pb.PlanSweepResponse{Error: &pb.Error{
+				Code: uint32(codes.NotFound), Message: "account acc: account not found",
+			}},
+		},
+		{
+			name:         "collector failure",
+			serviceError: errors.New("liteserver"),
+			expectedResponse: &pb.PlanSweepResponse{Error: &pb.Error{
+				Code: uint32(codes.Internal), Message: "plan sweeps: liteserver",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCollectorSvc := portsmocks.NewMockCollectorServicePort(t)
+			mockCollectorSvc.On("PlanSweeps", mock.Anything, []model.AccountID{"acc"}).Return(tt.sweeps, tt.serviceError).Once()
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+				Account:   portsmocks.NewMockAccountServicePort(t),
+				Collector: mockCollectorSvc,
+			})
+
+			resp, err := server.PlanSweep(context.Background(), &pb.PlanSweepRequest{AccountIds: []string{"acc"}})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) RejectWithdrawal(ctx context.Context, req *pb.RejectWithdrawalRequest) (*pb.RejectWithdrawalResponse, error) {
+	if s.withdrawalSvc == nil {
+		return rejectWithdrawalPbError(codes.Unimplemented, errors.New("withdrawals are not available")), nil
+	}
+
+	// the operator of the request is not trusted, the decision belongs to the authenticated caller
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return rejectWithdrawalPbError(codes.Unauthenticated, err), nil
+	}
+	log.Debug().Int64("id", req.GetId()).Str("operator", operator).Msg("reject withdrawal")
+
+	withdrawal, err := s.withdrawalSvc.RejectWithdrawal(ctx, &model.WithdrawalApproval{
+		WithdrawalID: req.GetId(),
+		Operator:     operator,
+		Comment:      req.GetReason(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidWithdrawal):
+			return rejectWithdrawalPbError(codes.InvalidArgument, err), nil
+		case errors.Is(err, model.ErrWithdrawalNotFound):
+			return rejectWithdrawalPbError(codes.NotFound, err), nil
+		case errors.Is(err, model.ErrWithdrawalApprovalExists):
+			return rejectWithdrawalPbError(codes.AlreadyExists, err), nil
+		case errors.Is(err, model.ErrWithdrawalNotPending):
+			return rejectWithdrawalPbError(codes.FailedPrecondition, err), nil
+		}
+		return rejectWithdrawalPbError(codes.Internal, errors.Wrap(err, "reject withdrawal")), nil
+	}
+	return &pb.RejectWithdrawalResponse{Withdrawal: toPbWithdrawal(withdrawal)}, nil
+}
+
+func rejectWithdrawalPbError(code codes.Code, err error) *pb.RejectWithdrawalResponse {
+	return &pb.RejectWithdrawalResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) SetSweepPolicy(ctx context.Context, req *pb.SetSweepPolicyRequest) (*pb.SetSweepPolicyResponse, error) {
+	log.Debug().Str("account_id", req.GetPolicy().GetAccountId()).Str("currency", req.GetPolicy().GetCurrency()).Msg("set sweep policy")
+
+	if s.policySvc == nil {
+		return setSweepPolicyPbError(codes.Unimplemented, errors.New("sweep policies are not available")), nil
+	}
+
+	policy, err := fromPbSweepPolicy(req.GetPolicy())
+	if err != nil {
+		return setSweepPolicyPbError(codes.InvalidArgument, err), nil
+	}
+
+	policy, err = s.policySvc.SetSweepPolicy(ctx, policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidSweepPolicy):
+			return setSweepPolicyPbError(codes.InvalidArgument, err), nil
+		case errors.Is(err, model.ErrAccountNotFound):
+			return setSweepPolicyPbError(codes.NotFound, err), nil
+		}
+		return setSweepPolicyPbError(codes.Internal, errors.Wrap(err, "set sweep policy")), nil
+	}
+	return &pb.SetSweepPolicyResponse{Policy: toPbSweepPolicy(policy)}, nil
+}
+
+func setSweepPolicyPbError(code codes.Code, err error) *pb.SetSweepPolicyResponse {
+	return &pb.SetSweepPolicyResponse{Error: &pb.Error{Code: uint32(code), Message: err.Error()}}
+}
+
+// fromPbSweepPolicy parses the whole unit amounts of the policy, the empty amounts are zero.
+func fromPbSweepPolicy(policy *pb.SweepPolicy) (*model.SweepPolicy, error) {
+	if policy == nil {
+		return nil, errors.Wrap(model.ErrInvalidSweepPolicy, "policy is required")
+	}
+
+	var amounts [2]model.Amount
+	for i, units := range []string{policy.GetThreshold(), policy.GetReserve()} {
+		if units == "" {
+			continue
+		}
+
+		amount, err := model.ParseUnits(units)
+		if err != nil {
+			return nil, errors.Wrapf(model.ErrInvalidSweepPolicy, "%v", err)
+		}
+		amounts[i] = amount
+	}
+
+	if policy.GetMinIntervalSeconds() > math.MaxInt64/uint64(time.Second) {
+		return nil, errors.Wrap(model.ErrInvalidSweepPolicy, "min interval is too long")
+	}
+
+	return &model.SweepPolicy{
+		AccountID:   policy.GetAccountId(),
+		Currency:    model.Currency(policy.GetCurrency()),
+		Disabled:    policy.GetDisabled(),
+		Threshold:   amounts[0],
+		Reserve:     amounts[1],
+		MinInterval: time.Duration(policy.GetMinIntervalSeconds()) * time.Second,
+		Schedule:    policy.GetSchedule(),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTonBeacon_SetSweepPolicy(t *testing.T) {
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		req              *pb.SetSweepPolicyRequest
+		policy           func(policy *model.SweepPolicy) bool
+		serviceError     error
+		expectedResponse *pb.SetSweepPolicyResponse
+	}{
+		{
+			name: "amounts are whole units",
+			req: &pb.SetSweepPolicyRequest{Policy: &pb.SweepPolicy{
+				AccountId: "acc", Currency: "TON", Threshold: "1.5", MinIntervalSeconds: 3600, Schedule: "@daily",
+			}},
+			policy: func(policy *model.SweepPolicy) bool {
+				return policy.AccountID == "acc" && policy.Currency == model.CurrencyTON && policy.Threshold.Nano() == "1500000000" &&
+					policy.Reserve.Sign() == 0 && policy.MinInterval == time.Hour && policy.Schedule == "@daily"
+			},
+			expectedResponse: &pb.SetSweepPolicyResponse{Policy: &pb.SweepPolicy{
+				AccountId: "acc", Currency: "TON", Threshold: "1.5", Reserve: "0", MinIntervalSeconds: 3600, Schedule: "@daily",
+				UpdatedAt: timestamppb.New(updatedAt),
+			}},
+		},
+		{
+			name: "invalid amount",
+			req:  &pb.SetSweepPolicyRequest{Policy: &pb.SweepPolicy{Reserve: "0.0000000001"}},
+			expectedResponse: &pb.SetSweepPolicyResponse{Error: &pb.Error{
+				Code:    uint32(codes.InvalidArgument),
+				Message: `invalid amount "0.0000000001", more than 9 decimals: invalid sweep policy`,
+			}},
+		},
+		{
+			name:         "unknown account",
+			req:          &pb.SetSweepPolicyRequest{Policy: &pb.SweepPolicy{AccountId: "unknown", Disabled: true}},
+			policy:       func(policy *model.SweepPolicy) bool { return policy.Disabled },
+			serviceError: model.ErrAccountNotFound,
+			expectedResponse: &pb.SetSweepPolicyResponse{Error: &pb.Error{
+				Code: uint32(codes.NotFound), Message: model.ErrAccountNotFound.Error(),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPolicySvc := portsmocks.NewMockSweepPolicyServicePort(t)
+			if tt.policy != nil {
+				mockPolicySvc.On("SetSweepPolicy", mock.Anything, mock.MatchedBy(tt.policy)).
+					Return(func(_ context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error) {
+						if tt.serviceError != nil {
+							return nil, tt.serviceError
+						}
+						policy.UpdatedAt = updatedAt
+						return policy, nil
+					}).Once()
+			}
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{
+				Account:  portsmocks.NewMockAccountServicePort(t),
+				Policies: mockPolicySvc,
+			})
+
+			resp, err := server.SetSweepPolicy(context.Background(), tt.req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResponse.String(), resp.String())
+		})
+	}
+}
+
+func TestTonBeacon_SweepPoliciesUnavailable(t *testing.T) {
+	server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: portsmocks.NewMockAccountServicePort(t)})
+
+	resp, err := server.ListSweepPolicies(context.Background(), &pb.ListSweepPoliciesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(codes.Unimplemented), resp.GetError().GetCode())
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (s *TonBeacon) SubscribeAccountEvents(req *pb.SubscribeAccountEventsRequest, stream pb.TonBeacon_SubscribeAccountEventsServer) error {
+	log.Debug().Strs("account_ids", req.GetAccountIds()).Str("cursor", req.GetCursor()).Msg("subscribe account events")
+
+	if s.eventsSvc == nil {
+		return status.Error(codes.Unimplemented, "account events are not available")
+	}
+
+	subscription := model.EventSubscription{AccountIDs: req.GetAccountIds()}
+	if req.Cursor != nil {
+		afterID, err := strconv.ParseInt(req.GetCursor(), 10, 64)
+		if err != nil || afterID < 0 {
+			return status.Error(codes.InvalidArgument, model.ErrInvalidCursor.Error())
+		}
+		subscription.AfterID = &afterID
+	}
+
+	err := s.eventsSvc.Subscribe(stream.Context(), subscription, func(event model.OutboxEvent) error {
+		return stream.Send(&pb.AccountEvent{
+			Cursor:    strconv.FormatInt(event.ID, 10),
+			EventType: string(event.EventType),
+			AccountId: event.AggregateID,
+			Payload:   string(event.Payload),
+			CreatedAt: timestamppb.New(event.CreatedAt),
+		})
+	})
+	if errors.Is(err, model.ErrCursorExpired) {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		log.Err(err).Msg("subscribe account events")
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcAdapter "github.com/kriuchkov/tonbeacon/adapters/grpc"
+	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+type testEventStream struct {
+	grpc.ServerStream
+	events []*pb.AccountEvent
+}
+
+func (s *testEventStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testEventStream) Send(event *pb.AccountEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestTonBeacon_SubscribeAccountEvents(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		req          *pb.SubscribeAccountEventsRequest
+		subscription *model.EventSubscription
+		err          error
+		cursors      []string
+		code         codes.Code
+	}{
+		{
+			name:         "resumes after the cursor",
+			req:          &pb.SubscribeAccountEventsRequest{AccountIds: []string{"acc"}, Cursor: lo.ToPtr("41")},
+			subscription: &model.EventSubscription{AccountIDs: []string{"acc"}, AfterID: lo.ToPtr(int64(41))},
+			cursors:      []string{"42"},
+		},
+		{
+			name:         "new events of all accounts",
+			req:          &pb.SubscribeAccountEventsRequest{},
+			subscription: &model.EventSubscription{AccountIDs: []string{}},
+			cursors:      []string{"42"},
+		},
+		{
+			name:         "expired cursor",
+			req:          &pb.SubscribeAccountEventsRequest{Cursor: lo.ToPtr("1")},
+			subscription: &model.EventSubscription{AfterID: lo.ToPtr(int64(1))},
+			err:          model.ErrCursorExpired,
+			code:         codes.OutOfRange,
+		},
+		{
+			name: "invalid cursor",
+			req:  &pb.SubscribeAccountEventsRequest{Cursor: lo.ToPtr("latest")},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := portsmocks.NewMockEventStreamServicePort(t)
+			if tt.subscription != nil {
+				events.On("Subscribe", mock.Anything, mock.MatchedBy(func(subscription model.EventSubscription) bool {
+					return len(subscription.AccountIDs) == len(tt.subscription.AccountIDs) &&
+						lo.FromPtr(subscription.AfterID) == lo.FromPtr(tt.subscription.AfterID) &&
+						(subscription.AfterID == nil) == (tt.subscription.AfterID == nil)
+				}), mock.Anything).Return(func(_ context.Context, _ model.EventSubscription, send func(model.OutboxEvent) error) error {
+					if tt.err != nil {
+						return tt.err
+					}
+					return send(model.OutboxEvent{
+						ID: 42, EventType: model.DepositCreditedEvent, AggregateID: "acc", Payload: []byte(`{"deposit_id":1}`), CreatedAt: createdAt,
+					})
+				}).Once()
+			}
+
+			server := grpcAdapter.NewTonBeacon(&grpcAdapter.Options{Account: portsmocks.NewMockAccountServicePort(t), Events: events})
+
+			stream := &testEventStream{}
+			err := server.SubscribeAccountEvents(tt.req, stream)
+			require.Equal(t, tt.code, status.Code(err))
+
+			cursors := lo.Map(stream.events, func(event *pb.AccountEvent, _ int) string { return event.GetCursor() })
+			require.ElementsMatch(t, tt.cursors, cursors)
+			if len(stream.events) > 0 {
+				require.Equal(t, "deposit_credited", stream.events[0].GetEventType())
+				require.Equal(t, "acc", stream.events[0].GetAccountId())
+				require.JSONEq(t, `{"deposit_id":1}`, stream.events[0].GetPayload())
+			}
+		})
+	}
+}
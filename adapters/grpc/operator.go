@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+var errUnauthenticated = errors.New("operator is not authenticated")
+
+// operator returns the operator authenticated by the call: the allowed common name of the verified client
+// certificate of a mutual TLS connection or the operator of the bearer token in the authorization metadata.
+func (s *TonBeacon) operator(ctx context.Context) (string, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			chain := info.State.VerifiedChains[0]
+			if len(chain) > 0 && slices.Contains(s.operators, chain[0].Subject.CommonName) {
+				return chain[0].Subject.CommonName, nil
+			}
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if !ok || token == "" {
+			continue
+		}
+
+		// every token is compared, so the time of the check does not reveal the operators
+		var operator string
+		for name, operatorToken := range s.operatorTokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(operatorToken)) == 1 {
+				operator = name
+			}
+		}
+
+		if operator != "" {
+			return operator, nil
+		}
+	}
+	return "", errUnauthenticated
+}
@@ -1,23 +1,76 @@
 package grpc
 
 import (
+	"crypto/tls"
 	"net"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/kriuchkov/tonbeacon/api/grpc/v1"
 	"github.com/kriuchkov/tonbeacon/core/ports"
 )
 
+type Options struct {
+	Account ports.AccountServicePort `validate:"required"`
+	// Events streams the account events, SubscribeAccountEvents is unavailable if it is not set.
+	Events ports.EventStreamServicePort
+	// Policies manages the sweep policies, the sweep policy RPCs are unavailable if it is not set.
+	Policies ports.SweepPolicyServicePort
+	// Collector plans the sweeps, PlanSweep is unavailable if it is not set.
+	Collector ports.CollectorServicePort
+	// Withdrawals accepts the withdrawals, the withdrawal RPCs are unavailable if it is not set.
+	Withdrawals ports.WithdrawalServicePort
+
+	// TLS secures the connections, the operators are authenticated by their client certificates if it verifies them.
+	TLS *tls.Config
+	// Operators are the common names of the client certificates accepted as operators.
+	Operators []string
+	// OperatorTokens are the bearer tokens of the operators by their names, the operators without a client
+	// certificate are authenticated by them. The tokens require TLS.
+	OperatorTokens map[string]string
+}
+
 type TonBeacon struct {
 	pb.UnimplementedTonBeaconServer
-	accountSvc ports.AccountServicePort
-	server     *grpc.Server
+	accountSvc    ports.AccountServicePort
+	eventsSvc     ports.EventStreamServicePort
+	policySvc     ports.SweepPolicyServicePort
+	collectorSvc  ports.CollectorServicePort
+	withdrawalSvc ports.WithdrawalServicePort
+	// operators are the common names of the client certificates accepted as operators.
+	operators []string
+	// operatorTokens are the bearer tokens of the operators by their names.
+	operatorTokens map[string]string
+	server         *grpc.Server
 }
 
-func NewTonBeacon(account ports.AccountServicePort) *TonBeacon {
-	return &TonBeacon{accountSvc: account, server: grpc.NewServer()}
+func NewTonBeacon(opts *Options) *TonBeacon {
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+	if len(opts.OperatorTokens) > 0 && opts.TLS == nil {
+		log.Panic().Msg("operator tokens require TLS")
+	}
+
+	var serverOpts []grpc.ServerOption
+	if opts.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+
+	return &TonBeacon{
+		accountSvc:     opts.Account,
+		eventsSvc:      opts.Events,
+		policySvc:      opts.Policies,
+		collectorSvc:   opts.Collector,
+		withdrawalSvc:  opts.Withdrawals,
+		operators:      opts.Operators,
+		operatorTokens: opts.OperatorTokens,
+		server:         grpc.NewServer(serverOpts...),
+	}
 }
 
 func (s *TonBeacon) Run(lis net.Listener) error {
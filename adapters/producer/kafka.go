@@ -7,6 +7,8 @@ import (
 	"github.com/go-faster/errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
 )
 
 const (
@@ -34,6 +36,9 @@ type ProducerOptions struct {
 	ReqAcks sarama.RequiredAcks
 	Retries int
 	Backoff time.Duration
+
+	// Client holds the connection settings shared by the Kafka adapters.
+	Client kafka.Config
 }
 
 func (c *ProducerOptions) SetDefaults() {
@@ -67,7 +72,11 @@ func NewKafkaProducer(opt *ProducerOptions) (*KafkaProducer, error) {
 		return nil, errors.Wrap(err, "validating producer options")
 	}
 
-	saramaConfig := sarama.NewConfig()
+	saramaConfig, err := opt.Client.SaramaConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka client config")
+	}
+
 	saramaConfig.Producer.Return.Successes = *opt.Succeed
 	saramaConfig.Producer.Return.Errors = *opt.Error
 	saramaConfig.Producer.RequiredAcks = opt.ReqAcks
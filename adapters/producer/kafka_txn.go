@@ -0,0 +1,289 @@
+package producer
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
+)
+
+const (
+	// defaultTxnVersion is the minimal Kafka version with transactional offset fetches.
+	defaultTxnVersion = "2.5.0"
+
+	// defaultTxnTimeout is the default timeout of a Kafka transaction.
+	defaultTxnTimeout = time.Minute
+
+	// txnOffsetsPartition is the partition the relay progress is committed to.
+	txnOffsetsPartition int32 = 0
+
+	// defaultMaxMetadataBytes is the default offset.metadata.max.bytes of the brokers.
+	defaultMaxMetadataBytes = 4096
+)
+
+type TxnProducerOptions struct {
+	Brokers []string `validate:"required"`
+	Topic   string   `validate:"required"`
+
+	// TransactionalID identifies the relay across restarts and fences out zombie instances.
+	TransactionalID string `validate:"required"`
+
+	// OffsetsGroup is the consumer group the delivered outbox ids are committed to.
+	// It defaults to the transactional id.
+	OffsetsGroup string
+
+	// MaxMetadataBytes is the offset.metadata.max.bytes of the brokers, the encoded delivered ids must fit in it.
+	MaxMetadataBytes int
+
+	Retries int
+	Backoff time.Duration
+	Timeout time.Duration
+
+	// Client holds the connection settings shared by the Kafka adapters.
+	Client kafka.Config
+}
+
+func (c *TxnProducerOptions) SetDefaults() {
+	if c.OffsetsGroup == "" {
+		c.OffsetsGroup = c.TransactionalID
+	}
+	if c.Client.Version == "" {
+		c.Client.Version = defaultTxnVersion
+	}
+	if c.MaxMetadataBytes == 0 {
+		c.MaxMetadataBytes = defaultMaxMetadataBytes
+	}
+	if c.Retries == 0 {
+		c.Retries = defaultRetries
+	}
+	if c.Backoff == 0 {
+		c.Backoff = defaultBackoff
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTxnTimeout
+	}
+}
+
+// KafkaTxnProducer sends batches of messages in Kafka transactions.
+// The ids of the delivered outbox events are committed as consumer group offset metadata
+// in the same transaction, so they become visible only together with the messages.
+type KafkaTxnProducer struct {
+	client   sarama.Client
+	admin    sarama.ClusterAdmin
+	producer sarama.SyncProducer
+	topic    string
+	group    string
+	// maxMetadata is the size limit of the encoded delivered ids.
+	maxMetadata int
+}
+
+func NewKafkaTxnProducer(opt *TxnProducerOptions) (*KafkaTxnProducer, error) {
+	opt.SetDefaults()
+
+	if err := validator.New().Struct(opt); err != nil {
+		return nil, errors.Wrap(err, "validating producer options")
+	}
+
+	saramaConfig, err := opt.Client.SaramaConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka client config")
+	}
+
+	saramaConfig.Net.MaxOpenRequests = 1
+	saramaConfig.Producer.Idempotent = true
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	saramaConfig.Producer.Retry.Max = opt.Retries
+	saramaConfig.Producer.Retry.Backoff = opt.Backoff
+	saramaConfig.Producer.Transaction.ID = opt.TransactionalID
+	saramaConfig.Producer.Transaction.Timeout = opt.Timeout
+	saramaConfig.Consumer.IsolationLevel = sarama.ReadCommitted
+
+	client, err := sarama.NewClient(opt.Brokers, saramaConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating kafka client")
+	}
+
+	// Initializing the transactional producer aborts transactions left open by a previous instance.
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		client.Close() //nolint:errcheck
+		return nil, errors.Wrap(err, "creating kafka producer")
+	}
+
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		producer.Close() //nolint:errcheck
+		client.Close()   //nolint:errcheck
+		return nil, errors.Wrap(err, "creating kafka admin")
+	}
+
+	return &KafkaTxnProducer{
+		client:      client,
+		admin:       admin,
+		producer:    producer,
+		topic:       opt.Topic,
+		group:       opt.OffsetsGroup,
+		maxMetadata: opt.MaxMetadataBytes,
+	}, nil
+}
+
+// DeliveredIDs returns the outbox ids recorded by the last committed transaction.
+func (p *KafkaTxnProducer) DeliveredIDs() ([]int64, error) {
+	resp, err := p.admin.ListConsumerGroupOffsets(p.group, map[string][]int32{p.topic: {txnOffsetsPartition}})
+	if err != nil {
+		return nil, errors.Wrap(err, "list group offsets")
+	}
+
+	block := resp.GetBlock(p.topic, txnOffsetsPartition)
+	if block == nil || block.Offset < 0 {
+		return nil, nil
+	}
+
+	if !errors.Is(block.Err, sarama.ErrNoError) {
+		return nil, errors.Wrap(block.Err, "offset block")
+	}
+
+	ids, err := DecodeIDs(block.Metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode delivered ids")
+	}
+	return ids, nil
+}
+
+// DeliveredIDsFit reports whether the delivered outbox ids fit in the offset metadata of a transaction.
+func (p *KafkaTxnProducer) DeliveredIDsFit(ids []int64) bool {
+	return len(EncodeIDs(ids)) <= p.maxMetadata
+}
+
+// SendMessages atomically sends the messages and records the delivered outbox ids.
+// On failure the transaction is aborted and none of the messages become visible to read-committed consumers.
+func (p *KafkaTxnProducer) SendMessages(keys []string, values [][]byte, deliveredIDs []int64) (err error) {
+	if len(keys) != len(values) {
+		return errors.New("keys and values length mismatch")
+	}
+
+	metadata := EncodeIDs(deliveredIDs)
+	if len(metadata) > p.maxMetadata {
+		return errors.Errorf("delivered ids take %d bytes of offset metadata, the limit is %d", len(metadata), p.maxMetadata)
+	}
+
+	if err = p.producer.BeginTxn(); err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if errAbort := p.producer.AbortTxn(); errAbort != nil {
+			err = errors.Wrapf(err, "abort transaction: %v", errAbort)
+		}
+	}()
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(keys))
+	for i := range keys {
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: p.topic,
+			Key:   sarama.StringEncoder(keys[i]),
+			Value: sarama.ByteEncoder(values[i]),
+		})
+	}
+
+	if err = p.producer.SendMessages(msgs); err != nil {
+		return errors.Wrap(err, "sending messages")
+	}
+
+	var lastID int64
+	if len(deliveredIDs) > 0 {
+		lastID = deliveredIDs[len(deliveredIDs)-1]
+	}
+
+	offsets := map[string][]*sarama.PartitionOffsetMetadata{
+		p.topic: {{Partition: txnOffsetsPartition, Offset: lastID, LeaderEpoch: -1, Metadata: &metadata}},
+	}
+
+	if err = p.producer.AddOffsetsToTxn(offsets, p.group); err != nil {
+		return errors.Wrap(err, "add offsets to transaction")
+	}
+
+	if err = p.producer.CommitTxn(); err != nil {
+		return errors.Wrap(err, "commit transaction")
+	}
+	return nil
+}
+
+func (p *KafkaTxnProducer) Close() error {
+	if err := p.producer.Close(); err != nil {
+		return errors.Wrap(err, "closing producer")
+	}
+
+	if err := p.admin.Close(); err != nil {
+		return errors.Wrap(err, "closing admin")
+	}
+	return nil
+}
+
+// EncodeIDs encodes sorted ids as comma separated ranges, e.g. "1-3,7".
+func EncodeIDs(ids []int64) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(ids); {
+		j := i
+		for j+1 < len(ids) && ids[j+1] == ids[j]+1 {
+			j++
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(strconv.FormatInt(ids[i], 10))
+		if j > i {
+			sb.WriteByte('-')
+			sb.WriteString(strconv.FormatInt(ids[j], 10))
+		}
+		i = j + 1
+	}
+	return sb.String()
+}
+
+// DecodeIDs decodes ids encoded by EncodeIDs.
+func DecodeIDs(s string) ([]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+
+		first, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse id %q", from)
+		}
+
+		last := first
+		if isRange {
+			if last, err = strconv.ParseInt(to, 10, 64); err != nil {
+				return nil, errors.Wrapf(err, "parse id %q", to)
+			}
+		}
+
+		if last < first {
+			return nil, errors.Errorf("invalid range %q", part)
+		}
+
+		for id := first; id <= last; id++ {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,39 @@
+package producer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/adapters/producer"
+)
+
+func TestEncodeDecodeIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []int64
+		encoded string
+	}{
+		{name: "empty", ids: nil, encoded: ""},
+		{name: "single", ids: []int64{7}, encoded: "7"},
+		{name: "range", ids: []int64{1, 2, 3}, encoded: "1-3"},
+		{name: "ranges with gaps", ids: []int64{1, 2, 3, 7, 10, 11}, encoded: "1-3,7,10-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.encoded, producer.EncodeIDs(tt.ids))
+
+			ids, err := producer.DecodeIDs(tt.encoded)
+			require.NoError(t, err)
+			require.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+func TestDecodeIDs_Invalid(t *testing.T) {
+	for _, s := range []string{"a", "1-b", "5-3"} {
+		_, err := producer.DecodeIDs(s)
+		require.Error(t, err, s)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
 )
 
 var _ ports.PublisherPort = (*KafkaPublisher)(nil)
@@ -20,6 +21,9 @@ type KafkaOptions struct {
 	Topic        string   `required:"true"`
 	RequiredAcks sarama.RequiredAcks
 	MaxRetries   int
+
+	// Client holds the connection settings shared by the Kafka adapters.
+	Client kafka.Config
 }
 
 func (k *KafkaOptions) SetDefaults() {
@@ -41,7 +45,11 @@ func NewKafkaPublisher(opt *KafkaOptions) (*KafkaPublisher, error) {
 		log.Panic().Err(err).Msg("kafka options")
 	}
 
-	cfg := sarama.NewConfig()
+	cfg, err := opt.Client.SaramaConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "kafka client config")
+	}
+
 	cfg.Producer.RequiredAcks = opt.RequiredAcks
 	cfg.Producer.Retry.Max = opt.MaxRetries
 	cfg.Producer.Return.Successes = true
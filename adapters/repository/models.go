@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/go-faster/errors"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
@@ -44,30 +47,58 @@ func fromModelAccount(account *model.Account) *Account {
 type OutboxEvent struct {
 	bun.BaseModel `bun:"table:outbox_events"`
 
-	ID        int64     `bun:"id,pk,autoincrement"`
-	EventType string    `bun:"event_type"`
-	Payload   string    `bun:"payload"`
-	CreatedAt time.Time `bun:"created_at"`
-	Processed bool      `bun:"processed"`
+	ID          int64      `bun:"id,pk,autoincrement"`
+	EventType   string     `bun:"event_type"`
+	AggregateID string     `bun:"aggregate_id,nullzero"`
+	Payload     string     `bun:"payload"`
+	CreatedAt   time.Time  `bun:"created_at"`
+	Processed   bool       `bun:"processed"`
+	ProcessedAt *time.Time `bun:"processed_at"`
 }
 
 func (e *OutboxEvent) toModel() model.OutboxEvent {
 	return model.OutboxEvent{
-		ID:        e.ID,
-		EventType: model.EventType(e.EventType),
-		Payload:   []byte(e.Payload),
-		CreatedAt: e.CreatedAt,
-		Processed: e.Processed,
+		ID:          e.ID,
+		EventType:   model.EventType(e.EventType),
+		AggregateID: e.AggregateID,
+		Payload:     []byte(e.Payload),
+		CreatedAt:   e.CreatedAt,
+		Processed:   e.Processed,
+		ProcessedAt: e.ProcessedAt,
 	}
 }
 
 func fromModelOutboxEvent(event model.OutboxEvent) *OutboxEvent {
 	return &OutboxEvent{
-		ID:        event.ID,
-		EventType: string(event.EventType),
-		Payload:   string(event.Payload),
-		CreatedAt: event.CreatedAt,
-		Processed: event.Processed,
+		ID:          event.ID,
+		EventType:   string(event.EventType),
+		AggregateID: event.AggregateID,
+		Payload:     string(event.Payload),
+		CreatedAt:   event.CreatedAt,
+		Processed:   event.Processed,
+		ProcessedAt: event.ProcessedAt,
+	}
+}
+
+type OutboxEventArchive struct {
+	bun.BaseModel `bun:"table:outbox_events_archive"`
+
+	ID          int64      `bun:"id,pk"`
+	EventType   string     `bun:"event_type"`
+	AggregateID string     `bun:"aggregate_id,nullzero"`
+	Payload     string     `bun:"payload"`
+	CreatedAt   time.Time  `bun:"created_at,pk"`
+	ProcessedAt *time.Time `bun:"processed_at"`
+}
+
+func fromModelOutboxEventArchive(event model.OutboxEvent) *OutboxEventArchive {
+	return &OutboxEventArchive{
+		ID:          event.ID,
+		EventType:   string(event.EventType),
+		AggregateID: event.AggregateID,
+		Payload:     string(event.Payload),
+		CreatedAt:   event.CreatedAt,
+		ProcessedAt: event.ProcessedAt,
 	}
 }
 
@@ -80,22 +111,37 @@ type Transaction struct {
 	LT          int64  `bun:"lt"`
 	PrevTxHash  string `bun:"prev_tx_hash"`
 	PrevTxLT    int64  `bun:"prev_tx_lt"`
+	Hash        string `bun:"hash"`
 
 	// Address information
 	Sender   string `bun:"sender"`
 	Receiver string `bun:"receiver"`
 
 	// Financial information
-	Amount    float64 `bun:"amount"`
-	TotalFees float64 `bun:"total_fees"`
-	ExitCode  int     `bun:"exit_code"`
-	Success   bool    `bun:"success"`
+	Amount    string `bun:"amount,type:numeric"`
+	TotalFees string `bun:"total_fees,type:numeric"`
+	ExitCode  int    `bun:"exit_code"`
+	Success   bool   `bun:"success"`
 
 	// Message information
 	MessageType string `bun:"message_type"`
 	Bounce      bool   `bun:"bounce"`
 	Bounced     bool   `bun:"bounced"`
 	Body        string `bun:"body"`
+	InMsgHash   string `bun:"in_msg_hash"`
+
+	// Decoded message body
+	OpCode           *int64  `bun:"op_code"`
+	QueryID          *string `bun:"query_id,type:numeric"`
+	Comment          string  `bun:"comment"`
+	EncryptedComment bool    `bun:"encrypted_comment"`
+
+	// Classification
+	AccountID  string `bun:"account_id"`
+	Direction  string `bun:"direction"`
+	Currency   string `bun:"currency"`
+	Kind       string `bun:"kind"`
+	TransferID *int64 `bun:"transfer_id"`
 
 	// State information
 	BlockID       string    `bun:"block_id"`
@@ -107,28 +153,52 @@ type Transaction struct {
 	Description    string `bun:"description"`
 }
 
-func (t *Transaction) toModel() *model.Transaction {
-	return &model.Transaction{
+func (t *Transaction) toModel() (*model.Transaction, error) {
+	transaction := &model.Transaction{
+		ID:             t.ID,
 		AccountAddr:    t.AccountAddr,
 		LT:             t.LT,
 		PrevTxHash:     t.PrevTxHash,
+		Hash:           t.Hash,
 		PrevTxLT:       t.PrevTxLT,
 		Sender:         t.Sender,
 		Receiver:       t.Receiver,
-		Amount:         t.Amount,
-		TotalFees:      t.TotalFees,
 		ExitCode:       t.ExitCode,
 		Success:        t.Success,
 		MessageType:    t.MessageType,
 		Bounce:         t.Bounce,
 		Bounced:        t.Bounced,
 		Body:           t.Body,
+		InMsgHash:      t.InMsgHash,
+		OpCode:         toModelOpCode(t.OpCode),
+		Comment:        t.Comment,
+		AccountID:      t.AccountID,
+		Direction:      model.TransactionDirection(t.Direction),
+		Currency:       model.Currency(t.Currency),
+		Kind:           model.TransactionKind(t.Kind),
+		TransferID:     t.TransferID,
 		BlockID:        t.BlockID,
 		CreatedAt:      t.CreatedAt,
 		AccountStatus:  t.AccountStatus,
 		ComputeGasUsed: t.ComputeGasUsed,
 		Description:    t.Description,
+
+		EncryptedComment: t.EncryptedComment,
 	}
+
+	var err error
+	if transaction.Amount, err = toModelAmount(t.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of transaction %d", t.ID)
+	}
+
+	if transaction.TotalFees, err = toModelAmount(t.TotalFees); err != nil {
+		return nil, errors.Wrapf(err, "fees of transaction %d", t.ID)
+	}
+
+	if transaction.QueryID, err = toModelQueryID(t.QueryID); err != nil {
+		return nil, errors.Wrapf(err, "query id of transaction %d", t.ID)
+	}
+	return transaction, nil
 }
 
 func fromModelTransaction(transaction *model.Transaction) *Transaction {
@@ -137,20 +207,702 @@ func fromModelTransaction(transaction *model.Transaction) *Transaction {
 		LT:             transaction.LT,
 		PrevTxHash:     transaction.PrevTxHash,
 		PrevTxLT:       transaction.PrevTxLT,
+		Hash:           transaction.Hash,
 		Sender:         transaction.Sender,
 		Receiver:       transaction.Receiver,
-		Amount:         transaction.Amount,
-		TotalFees:      transaction.TotalFees,
+		Amount:         transaction.Amount.Nano(),
+		TotalFees:      transaction.TotalFees.Nano(),
 		ExitCode:       transaction.ExitCode,
 		Success:        transaction.Success,
 		MessageType:    transaction.MessageType,
 		Bounce:         transaction.Bounce,
 		Bounced:        transaction.Bounced,
 		Body:           transaction.Body,
+		InMsgHash:      transaction.InMsgHash,
+		OpCode:         fromModelOpCode(transaction.OpCode),
+		QueryID:        fromModelQueryID(transaction.QueryID),
+		Comment:        transaction.Comment,
+		AccountID:      transaction.AccountID,
+		Direction:      string(transaction.Direction),
+		Currency:       string(transaction.Currency),
+		Kind:           string(transaction.Kind),
+		TransferID:     transaction.TransferID,
 		BlockID:        transaction.BlockID,
 		CreatedAt:      transaction.CreatedAt,
 		AccountStatus:  transaction.AccountStatus,
 		ComputeGasUsed: transaction.ComputeGasUsed,
 		Description:    transaction.Description,
+
+		EncryptedComment: transaction.EncryptedComment,
+	}
+}
+
+func toModelOpCode(value *int64) *uint32 {
+	if value == nil {
+		return nil
+	}
+
+	op := uint32(*value)
+	return &op
+}
+
+func fromModelOpCode(op *uint32) *int64 {
+	if op == nil {
+		return nil
+	}
+
+	value := int64(*op)
+	return &value
+}
+
+// toModelQueryID converts a NUMERIC query id, it does not fit into BIGINT.
+func toModelQueryID(value *string) (*uint64, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	queryID, err := strconv.ParseUint(*value, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse query id")
+	}
+	return &queryID, nil
+}
+
+func fromModelQueryID(queryID *uint64) *string {
+	if queryID == nil {
+		return nil
+	}
+
+	value := strconv.FormatUint(*queryID, 10)
+	return &value
+}
+
+// toModelAmount converts a NUMERIC value of nano units, the column is constrained to integers.
+func toModelAmount(value string) (model.Amount, error) {
+	amount, err := model.ParseAmount(value)
+	if err != nil {
+		return amount, errors.Wrap(err, "parse amount")
+	}
+	return amount, nil
+}
+
+type LedgerEntry struct {
+	bun.BaseModel `bun:"table:ledger_entries"`
+
+	ID          int64     `bun:"id,pk,autoincrement"`
+	EntryType   string    `bun:"entry_type"`
+	Reference   string    `bun:"reference"`
+	Description string    `bun:"description"`
+	CreatedAt   time.Time `bun:"created_at"`
+}
+
+type LedgerPosting struct {
+	bun.BaseModel `bun:"table:ledger_postings"`
+
+	ID            int64  `bun:"id,pk,autoincrement"`
+	EntryID       int64  `bun:"entry_id"`
+	LedgerAccount string `bun:"ledger_account"`
+	Currency      string `bun:"currency"`
+	Amount        string `bun:"amount,type:numeric"`
+}
+
+func fromModelLedgerEntry(entry *model.LedgerEntry) (*LedgerEntry, []*LedgerPosting) {
+	postings := make([]*LedgerPosting, 0, len(entry.Postings))
+	for _, posting := range entry.Postings {
+		postings = append(postings, &LedgerPosting{
+			LedgerAccount: string(posting.Account),
+			Currency:      string(posting.Currency),
+			Amount:        posting.Amount.Nano(),
+		})
+	}
+
+	return &LedgerEntry{
+		ID:          entry.ID,
+		EntryType:   string(entry.Type),
+		Reference:   entry.Reference,
+		Description: entry.Description,
+		CreatedAt:   entry.CreatedAt,
+	}, postings
+}
+
+func (e *LedgerEntry) toModel(postings []*LedgerPosting) (*model.LedgerEntry, error) {
+	entry := &model.LedgerEntry{
+		ID:          e.ID,
+		Type:        model.LedgerEntryType(e.EntryType),
+		Reference:   e.Reference,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt,
+	}
+
+	for _, posting := range postings {
+		amount, err := toModelAmount(posting.Amount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "amount of posting %d", posting.ID)
+		}
+
+		entry.Postings = append(entry.Postings, model.LedgerPosting{
+			Account:  model.LedgerAccount(posting.LedgerAccount),
+			Currency: model.Currency(posting.Currency),
+			Amount:   amount,
+		})
+	}
+	return entry, nil
+}
+
+type Deposit struct {
+	bun.BaseModel `bun:"table:deposits"`
+
+	ID          int64      `bun:"id,pk,autoincrement"`
+	AccountID   string     `bun:"account_id"`
+	AccountAddr string     `bun:"account_addr"`
+	LT          int64      `bun:"lt"`
+	TxHash      string     `bun:"tx_hash"`
+	Sender      string     `bun:"sender"`
+	Currency    string     `bun:"currency"`
+	Amount      string     `bun:"amount,type:numeric"`
+	Comment     string     `bun:"comment"`
+	Status      string     `bun:"status"`
+	Reason      string     `bun:"reason"`
+	DetectedAt  time.Time  `bun:"detected_at"`
+	ConfirmedAt *time.Time `bun:"confirmed_at"`
+	CreditedAt  *time.Time `bun:"credited_at"`
+	SweptAt     *time.Time `bun:"swept_at"`
+	BouncedAt   *time.Time `bun:"bounced_at"`
+	RejectedAt  *time.Time `bun:"rejected_at"`
+	UpdatedAt   time.Time  `bun:"updated_at"`
+}
+
+func (d *Deposit) toModel() (*model.Deposit, error) {
+	deposit := &model.Deposit{
+		ID:          d.ID,
+		AccountID:   d.AccountID,
+		AccountAddr: d.AccountAddr,
+		LT:          d.LT,
+		TxHash:      d.TxHash,
+		Sender:      d.Sender,
+		Currency:    model.Currency(d.Currency),
+		Comment:     d.Comment,
+		Status:      model.DepositStatus(d.Status),
+		Reason:      d.Reason,
+		DetectedAt:  d.DetectedAt,
+		ConfirmedAt: d.ConfirmedAt,
+		CreditedAt:  d.CreditedAt,
+		SweptAt:     d.SweptAt,
+		BouncedAt:   d.BouncedAt,
+		RejectedAt:  d.RejectedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+
+	var err error
+	if deposit.Amount, err = toModelAmount(d.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of deposit %d", d.ID)
+	}
+	return deposit, nil
+}
+
+func fromModelDeposit(deposit *model.Deposit) *Deposit {
+	return &Deposit{
+		ID:          deposit.ID,
+		AccountID:   deposit.AccountID,
+		AccountAddr: deposit.AccountAddr,
+		LT:          deposit.LT,
+		TxHash:      deposit.TxHash,
+		Sender:      deposit.Sender,
+		Currency:    string(deposit.Currency),
+		Amount:      deposit.Amount.Nano(),
+		Comment:     deposit.Comment,
+		Status:      string(deposit.Status),
+		Reason:      deposit.Reason,
+		DetectedAt:  deposit.DetectedAt,
+		ConfirmedAt: deposit.ConfirmedAt,
+		CreditedAt:  deposit.CreditedAt,
+		SweptAt:     deposit.SweptAt,
+		BouncedAt:   deposit.BouncedAt,
+		RejectedAt:  deposit.RejectedAt,
+		UpdatedAt:   deposit.UpdatedAt,
+	}
+}
+
+type OutgoingTransfer struct {
+	bun.BaseModel `bun:"table:outgoing_transfers"`
+
+	ID            int64     `bun:"id,pk,autoincrement"`
+	Kind          string    `bun:"kind"`
+	Reference     string    `bun:"reference"`
+	AccountID     string    `bun:"account_id"`
+	From          string    `bun:"from_addr"`
+	To            string    `bun:"to_addr"`
+	Currency      string    `bun:"currency"`
+	Amount        string    `bun:"amount,type:numeric"`
+	Gas           string    `bun:"gas,type:numeric"`
+	MessageHash   string    `bun:"message_hash"`
+	BounceHash    string    `bun:"bounce_hash"`
+	JettonQueryID uint64    `bun:"jetton_query_id"`
+	Status        string    `bun:"status"`
+	TxHash        string    `bun:"tx_hash"`
+	Fee           string    `bun:"fee,type:numeric"`
+	CreatedAt     time.Time `bun:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at"`
+}
+
+func (t *OutgoingTransfer) toModel() (*model.OutgoingTransfer, error) {
+	transfer := &model.OutgoingTransfer{
+		ID:            t.ID,
+		Kind:          model.TransferKind(t.Kind),
+		Reference:     t.Reference,
+		AccountID:     t.AccountID,
+		From:          t.From,
+		To:            t.To,
+		Currency:      model.Currency(t.Currency),
+		MessageHash:   t.MessageHash,
+		BounceHash:    t.BounceHash,
+		JettonQueryID: t.JettonQueryID,
+		Status:        model.TransferStatus(t.Status),
+		TxHash:        t.TxHash,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+
+	var err error
+	if transfer.Amount, err = toModelAmount(t.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of transfer %d", t.ID)
+	}
+
+	if transfer.Gas, err = toModelAmount(t.Gas); err != nil {
+		return nil, errors.Wrapf(err, "gas of transfer %d", t.ID)
+	}
+
+	if transfer.Fee, err = toModelAmount(t.Fee); err != nil {
+		return nil, errors.Wrapf(err, "fee of transfer %d", t.ID)
+	}
+	return transfer, nil
+}
+
+func fromModelOutgoingTransfer(transfer *model.OutgoingTransfer) *OutgoingTransfer {
+	return &OutgoingTransfer{
+		ID:            transfer.ID,
+		Kind:          string(transfer.Kind),
+		Reference:     transfer.Reference,
+		AccountID:     transfer.AccountID,
+		From:          transfer.From,
+		To:            transfer.To,
+		Currency:      string(transfer.Currency),
+		Amount:        transfer.Amount.Nano(),
+		Gas:           transfer.Gas.Nano(),
+		MessageHash:   transfer.MessageHash,
+		BounceHash:    transfer.BounceHash,
+		JettonQueryID: transfer.JettonQueryID,
+		Status:        string(transfer.Status),
+		TxHash:        transfer.TxHash,
+		Fee:           transfer.Fee.Nano(),
+		CreatedAt:     transfer.CreatedAt,
+		UpdatedAt:     transfer.UpdatedAt,
+	}
+}
+
+type SweepJob struct {
+	bun.BaseModel `bun:"table:sweep_jobs"`
+
+	ID            int64      `bun:"id,pk,autoincrement"`
+	AccountID     string     `bun:"account_id"`
+	WalletID      uint32     `bun:"wallet_id"`
+	From          string     `bun:"from_addr"`
+	To            string     `bun:"to_addr"`
+	Currency      string     `bun:"currency"`
+	Amount        string     `bun:"amount,type:numeric"`
+	Gas           string     `bun:"gas,type:numeric"`
+	Seqno         uint32     `bun:"seqno"`
+	MessageHash   string     `bun:"message_hash,nullzero"`
+	JettonQueryID uint64     `bun:"jetton_query_id"`
+	BOC           []byte     `bun:"boc,type:bytea,nullzero"`
+	ExpiresAt     time.Time  `bun:"expires_at,nullzero"`
+	Status        string     `bun:"status"`
+	Attempts      int        `bun:"attempts"`
+	Error         string     `bun:"error"`
+	FundedAt      *time.Time `bun:"funded_at"`
+	SentAt        *time.Time `bun:"sent_at"`
+	ConfirmedAt   *time.Time `bun:"confirmed_at"`
+	FailedAt      *time.Time `bun:"failed_at"`
+	CreatedAt     time.Time  `bun:"created_at"`
+	UpdatedAt     time.Time  `bun:"updated_at"`
+
+	TopUpAmount      *string    `bun:"top_up_amount,type:numeric"`
+	TopUpFrom        *string    `bun:"top_up_from"`
+	TopUpTo          *string    `bun:"top_up_to"`
+	TopUpSeqno       *uint32    `bun:"top_up_seqno"`
+	TopUpMessageHash *string    `bun:"top_up_message_hash"`
+	TopUpBOC         []byte     `bun:"top_up_boc,type:bytea,nullzero"`
+	TopUpExpiresAt   *time.Time `bun:"top_up_expires_at"`
+}
+
+func (j *SweepJob) toModel() (*model.SweepJob, error) {
+	job := &model.SweepJob{
+		ID:            j.ID,
+		AccountID:     j.AccountID,
+		WalletID:      j.WalletID,
+		From:          j.From,
+		To:            j.To,
+		Currency:      model.Currency(j.Currency),
+		Seqno:         j.Seqno,
+		MessageHash:   j.MessageHash,
+		JettonQueryID: j.JettonQueryID,
+		BOC:           j.BOC,
+		ExpiresAt:     j.ExpiresAt,
+		Status:        model.SweepStatus(j.Status),
+		Attempts:      j.Attempts,
+		Error:         j.Error,
+		FundedAt:      j.FundedAt,
+		SentAt:        j.SentAt,
+		ConfirmedAt:   j.ConfirmedAt,
+		FailedAt:      j.FailedAt,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+	}
+
+	var err error
+	if job.Amount, err = toModelAmount(j.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of sweep job %d", j.ID)
+	}
+
+	if job.Gas, err = toModelAmount(j.Gas); err != nil {
+		return nil, errors.Wrapf(err, "gas of sweep job %d", j.ID)
+	}
+
+	if j.TopUpMessageHash != nil {
+		job.TopUp = &model.WalletMessage{
+			From:        lo.FromPtr(j.TopUpFrom),
+			To:          lo.FromPtr(j.TopUpTo),
+			Currency:    model.CurrencyTON,
+			Seqno:       lo.FromPtr(j.TopUpSeqno),
+			MessageHash: *j.TopUpMessageHash,
+			BOC:         j.TopUpBOC,
+			ExpiresAt:   lo.FromPtr(j.TopUpExpiresAt),
+		}
+
+		if job.TopUp.Amount, err = toModelAmount(lo.FromPtr(j.TopUpAmount)); err != nil {
+			return nil, errors.Wrapf(err, "top-up amount of sweep job %d", j.ID)
+		}
+	}
+	return job, nil
+}
+
+func fromModelSweepJob(job *model.SweepJob) *SweepJob {
+	jobModel := &SweepJob{
+		ID:            job.ID,
+		AccountID:     job.AccountID,
+		WalletID:      job.WalletID,
+		From:          job.From,
+		To:            job.To,
+		Currency:      string(job.Currency),
+		Amount:        job.Amount.Nano(),
+		Gas:           job.Gas.Nano(),
+		Seqno:         job.Seqno,
+		MessageHash:   job.MessageHash,
+		JettonQueryID: job.JettonQueryID,
+		BOC:           job.BOC,
+		ExpiresAt:     job.ExpiresAt,
+		Status:        string(job.Status),
+		Attempts:      job.Attempts,
+		Error:         job.Error,
+		FundedAt:      job.FundedAt,
+		SentAt:        job.SentAt,
+		ConfirmedAt:   job.ConfirmedAt,
+		FailedAt:      job.FailedAt,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+	}
+
+	if topUp := job.TopUp; topUp != nil {
+		jobModel.TopUpAmount = lo.ToPtr(topUp.Amount.Nano())
+		jobModel.TopUpFrom, jobModel.TopUpTo = &topUp.From, &topUp.To
+		jobModel.TopUpSeqno, jobModel.TopUpMessageHash = &topUp.Seqno, &topUp.MessageHash
+		jobModel.TopUpBOC, jobModel.TopUpExpiresAt = topUp.BOC, &topUp.ExpiresAt
+	}
+	return jobModel
+}
+
+type SweepPolicy struct {
+	bun.BaseModel `bun:"table:sweep_policies"`
+
+	ID                 int64     `bun:"id,pk,autoincrement"`
+	AccountID          string    `bun:"account_id"`
+	Currency           string    `bun:"currency"`
+	Disabled           bool      `bun:"disabled"`
+	Threshold          string    `bun:"threshold,type:numeric"`
+	Reserve            string    `bun:"reserve,type:numeric"`
+	MinIntervalSeconds int64     `bun:"min_interval_seconds"`
+	Schedule           string    `bun:"schedule"`
+	CreatedAt          time.Time `bun:"created_at"`
+	UpdatedAt          time.Time `bun:"updated_at"`
+}
+
+func (p *SweepPolicy) toModel() (*model.SweepPolicy, error) {
+	policy := &model.SweepPolicy{
+		ID:          p.ID,
+		AccountID:   p.AccountID,
+		Currency:    model.Currency(p.Currency),
+		Disabled:    p.Disabled,
+		MinInterval: time.Duration(p.MinIntervalSeconds) * time.Second,
+		Schedule:    p.Schedule,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+
+	var err error
+	if policy.Threshold, err = toModelAmount(p.Threshold); err != nil {
+		return nil, errors.Wrapf(err, "threshold of sweep policy %d", p.ID)
+	}
+
+	if policy.Reserve, err = toModelAmount(p.Reserve); err != nil {
+		return nil, errors.Wrapf(err, "reserve of sweep policy %d", p.ID)
+	}
+	return policy, nil
+}
+
+func fromModelSweepPolicy(policy *model.SweepPolicy) *SweepPolicy {
+	return &SweepPolicy{
+		ID:                 policy.ID,
+		AccountID:          policy.AccountID,
+		Currency:           string(policy.Currency),
+		Disabled:           policy.Disabled,
+		Threshold:          policy.Threshold.Nano(),
+		Reserve:            policy.Reserve.Nano(),
+		MinIntervalSeconds: int64(policy.MinInterval / time.Second),
+		Schedule:           policy.Schedule,
+		CreatedAt:          policy.CreatedAt,
+		UpdatedAt:          policy.UpdatedAt,
+	}
+}
+
+type Withdrawal struct {
+	bun.BaseModel `bun:"table:withdrawals"`
+
+	ID             int64      `bun:"id,pk,autoincrement"`
+	AccountID      string     `bun:"account_id"`
+	IdempotencyKey string     `bun:"idempotency_key"`
+	To             string     `bun:"to_addr"`
+	Currency       string     `bun:"currency"`
+	Amount         string     `bun:"amount,type:numeric"`
+	Comment        string     `bun:"comment"`
+	Gas            string     `bun:"gas,type:numeric"`
+	Status         string     `bun:"status"`
+	From           string     `bun:"from_addr,nullzero"`
+	Seqno          uint32     `bun:"seqno"`
+	QueryID        uint32     `bun:"query_id,nullzero"`
+	MessageHash    string     `bun:"message_hash,nullzero"`
+	BounceHash     string     `bun:"bounce_hash"`
+	JettonQueryID  uint64     `bun:"jetton_query_id"`
+	BOC            []byte     `bun:"boc,type:bytea,nullzero"`
+	ExpiresAt      time.Time  `bun:"expires_at,nullzero"`
+	TxHash         string     `bun:"tx_hash,nullzero"`
+	Attempts       int        `bun:"attempts"`
+	Error          string     `bun:"error"`
+	HoldReason     string     `bun:"hold_reason"`
+	ApprovedAt     *time.Time `bun:"approved_at"`
+	SignedAt       *time.Time `bun:"signed_at"`
+	BroadcastAt    *time.Time `bun:"broadcast_at"`
+	ConfirmedAt    *time.Time `bun:"confirmed_at"`
+	FailedAt       *time.Time `bun:"failed_at"`
+	CreatedAt      time.Time  `bun:"created_at"`
+	UpdatedAt      time.Time  `bun:"updated_at"`
+}
+
+func (w *Withdrawal) toModel() (*model.Withdrawal, error) {
+	withdrawal := &model.Withdrawal{
+		ID:             w.ID,
+		AccountID:      w.AccountID,
+		IdempotencyKey: w.IdempotencyKey,
+		To:             w.To,
+		Currency:       model.Currency(w.Currency),
+		Comment:        w.Comment,
+		Status:         model.WithdrawalStatus(w.Status),
+		From:           w.From,
+		Seqno:          w.Seqno,
+		QueryID:        w.QueryID,
+		MessageHash:    w.MessageHash,
+		BounceHash:     w.BounceHash,
+		JettonQueryID:  w.JettonQueryID,
+		BOC:            w.BOC,
+		ExpiresAt:      w.ExpiresAt,
+		TxHash:         w.TxHash,
+		Attempts:       w.Attempts,
+		Error:          w.Error,
+		HoldReason:     w.HoldReason,
+		ApprovedAt:     w.ApprovedAt,
+		SignedAt:       w.SignedAt,
+		BroadcastAt:    w.BroadcastAt,
+		ConfirmedAt:    w.ConfirmedAt,
+		FailedAt:       w.FailedAt,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+	}
+
+	var err error
+	if withdrawal.Amount, err = toModelAmount(w.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of withdrawal %d", w.ID)
+	}
+
+	if withdrawal.Gas, err = toModelAmount(w.Gas); err != nil {
+		return nil, errors.Wrapf(err, "gas of withdrawal %d", w.ID)
+	}
+	return withdrawal, nil
+}
+
+func fromModelWithdrawal(withdrawal *model.Withdrawal) *Withdrawal {
+	return &Withdrawal{
+		ID:             withdrawal.ID,
+		AccountID:      withdrawal.AccountID,
+		IdempotencyKey: withdrawal.IdempotencyKey,
+		To:             withdrawal.To,
+		Currency:       string(withdrawal.Currency),
+		Amount:         withdrawal.Amount.Nano(),
+		Comment:        withdrawal.Comment,
+		Gas:            withdrawal.Gas.Nano(),
+		Status:         string(withdrawal.Status),
+		From:           withdrawal.From,
+		Seqno:          withdrawal.Seqno,
+		QueryID:        withdrawal.QueryID,
+		MessageHash:    withdrawal.MessageHash,
+		BounceHash:     withdrawal.BounceHash,
+		JettonQueryID:  withdrawal.JettonQueryID,
+		BOC:            withdrawal.BOC,
+		ExpiresAt:      withdrawal.ExpiresAt,
+		TxHash:         withdrawal.TxHash,
+		Attempts:       withdrawal.Attempts,
+		Error:          withdrawal.Error,
+		HoldReason:     withdrawal.HoldReason,
+		ApprovedAt:     withdrawal.ApprovedAt,
+		SignedAt:       withdrawal.SignedAt,
+		BroadcastAt:    withdrawal.BroadcastAt,
+		ConfirmedAt:    withdrawal.ConfirmedAt,
+		FailedAt:       withdrawal.FailedAt,
+		CreatedAt:      withdrawal.CreatedAt,
+		UpdatedAt:      withdrawal.UpdatedAt,
+	}
+}
+
+type WithdrawalApproval struct {
+	bun.BaseModel `bun:"table:withdrawal_approvals"`
+
+	ID           int64     `bun:"id,pk,autoincrement"`
+	WithdrawalID int64     `bun:"withdrawal_id"`
+	Operator     string    `bun:"operator"`
+	Decision     string    `bun:"decision"`
+	Comment      string    `bun:"comment"`
+	CreatedAt    time.Time `bun:"created_at"`
+}
+
+func (a *WithdrawalApproval) toModel() *model.WithdrawalApproval {
+	return &model.WithdrawalApproval{
+		ID:           a.ID,
+		WithdrawalID: a.WithdrawalID,
+		Operator:     a.Operator,
+		Decision:     model.ApprovalDecision(a.Decision),
+		Comment:      a.Comment,
+		CreatedAt:    a.CreatedAt,
+	}
+}
+
+func fromModelWithdrawalApproval(approval *model.WithdrawalApproval) *WithdrawalApproval {
+	return &WithdrawalApproval{
+		ID:           approval.ID,
+		WithdrawalID: approval.WithdrawalID,
+		Operator:     approval.Operator,
+		Decision:     string(approval.Decision),
+		Comment:      approval.Comment,
+		CreatedAt:    approval.CreatedAt,
+	}
+}
+
+type WithdrawalLimitCounter struct {
+	bun.BaseModel `bun:"table:withdrawal_limit_counters,alias:counter"`
+
+	Scope     string    `bun:"scope,pk"`
+	Currency  string    `bun:"currency,pk"`
+	Day       time.Time `bun:"day,pk,type:date"`
+	Amount    string    `bun:"amount,type:numeric"`
+	UpdatedAt time.Time `bun:"updated_at"`
+}
+
+type Rebalance struct {
+	bun.BaseModel `bun:"table:rebalances"`
+
+	ID            int64      `bun:"id,pk,autoincrement"`
+	Currency      string     `bun:"currency"`
+	From          string     `bun:"from_addr"`
+	To            string     `bun:"to_addr"`
+	Amount        string     `bun:"amount,type:numeric"`
+	Gas           string     `bun:"gas,type:numeric"`
+	Seqno         uint32     `bun:"seqno"`
+	MessageHash   string     `bun:"message_hash"`
+	BounceHash    string     `bun:"bounce_hash"`
+	JettonQueryID uint64     `bun:"jetton_query_id"`
+	BOC           []byte     `bun:"boc,type:bytea"`
+	ExpiresAt     time.Time  `bun:"expires_at"`
+	Status        string     `bun:"status"`
+	Attempts      int        `bun:"attempts"`
+	Error         string     `bun:"error"`
+	SentAt        *time.Time `bun:"sent_at"`
+	ConfirmedAt   *time.Time `bun:"confirmed_at"`
+	FailedAt      *time.Time `bun:"failed_at"`
+	CreatedAt     time.Time  `bun:"created_at"`
+	UpdatedAt     time.Time  `bun:"updated_at"`
+}
+
+func (r *Rebalance) toModel() (*model.Rebalance, error) {
+	rebalance := &model.Rebalance{
+		ID:            r.ID,
+		Currency:      model.Currency(r.Currency),
+		From:          r.From,
+		To:            r.To,
+		Seqno:         r.Seqno,
+		MessageHash:   r.MessageHash,
+		BounceHash:    r.BounceHash,
+		JettonQueryID: r.JettonQueryID,
+		BOC:           r.BOC,
+		ExpiresAt:     r.ExpiresAt,
+		Status:        model.RebalanceStatus(r.Status),
+		Attempts:      r.Attempts,
+		Error:         r.Error,
+		SentAt:        r.SentAt,
+		ConfirmedAt:   r.ConfirmedAt,
+		FailedAt:      r.FailedAt,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+
+	var err error
+	if rebalance.Amount, err = toModelAmount(r.Amount); err != nil {
+		return nil, errors.Wrapf(err, "amount of rebalance %d", r.ID)
+	}
+
+	if rebalance.Gas, err = toModelAmount(r.Gas); err != nil {
+		return nil, errors.Wrapf(err, "gas of rebalance %d", r.ID)
+	}
+	return rebalance, nil
+}
+
+func fromModelRebalance(rebalance *model.Rebalance) *Rebalance {
+	return &Rebalance{
+		ID:            rebalance.ID,
+		Currency:      string(rebalance.Currency),
+		From:          rebalance.From,
+		To:            rebalance.To,
+		Amount:        rebalance.Amount.Nano(),
+		Gas:           rebalance.Gas.Nano(),
+		Seqno:         rebalance.Seqno,
+		MessageHash:   rebalance.MessageHash,
+		BounceHash:    rebalance.BounceHash,
+		JettonQueryID: rebalance.JettonQueryID,
+		BOC:           rebalance.BOC,
+		ExpiresAt:     rebalance.ExpiresAt,
+		Status:        string(rebalance.Status),
+		Attempts:      rebalance.Attempts,
+		Error:         rebalance.Error,
+		SentAt:        rebalance.SentAt,
+		ConfirmedAt:   rebalance.ConfirmedAt,
+		FailedAt:      rebalance.FailedAt,
+		CreatedAt:     rebalance.CreatedAt,
+		UpdatedAt:     rebalance.UpdatedAt,
 	}
 }
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToModelAmount(t *testing.T) {
+	amount, err := toModelAmount("1500000000")
+	require.NoError(t, err)
+	assert.Equal(t, "1500000000", amount.Nano())
+
+	_, err = toModelAmount("1.5")
+	require.Error(t, err)
+}
+
+func TestOutgoingTransferToModel_InvalidAmount(t *testing.T) {
+	transfer := &OutgoingTransfer{ID: 7, Amount: "1000", Gas: "not a number", Fee: "0"}
+
+	_, err := transfer.toModel()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gas of transfer 7")
+}
+
+func TestTransactionToModel_InvalidQueryID(t *testing.T) {
+	queryID := "-1"
+	transaction := &Transaction{ID: 3, Amount: "0", TotalFees: "0", QueryID: &queryID}
+
+	_, err := transaction.toModel()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query id of transaction 3")
+}
@@ -77,7 +77,7 @@ func (d *DatabaseAdapter) ListAccounts(ctx context.Context, filter model.ListAcc
 		query.Where("ton_address = ?", filter.Address)
 	}
 
-	query.Offset(filter.Offset).Limit(filter.Limit)
+	query.Order("wallet_id").Offset(filter.Offset).Limit(filter.Limit)
 
 	if err := query.Scan(ctx); err != nil {
 		return nil, errors.Wrap(err, "list accounts")
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// InsertDeposit inserts the deposit, model.ErrDepositExists is returned if the deposit of the transaction is already stored.
+func (d *DatabaseAdapter) InsertDeposit(ctx context.Context, deposit *model.Deposit) (*model.Deposit, error) {
+	depositModel := fromModelDeposit(deposit)
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(depositModel).
+		On("CONFLICT (account_addr, lt, tx_hash) DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrDepositExists
+	}
+	return depositModel.toModel()
+}
+
+// UpdateDepositStatus stores the status and the timestamps of the deposit if it is still in the previous status,
+// model.ErrInvalidDepositTransition is returned if it was moved by someone else.
+func (d *DatabaseAdapter) UpdateDepositStatus(ctx context.Context, deposit *model.Deposit, from model.DepositStatus) error {
+	depositModel := fromModelDeposit(deposit)
+
+	res, err := d.GetTxOrConn(ctx).NewUpdate().Model(depositModel).
+		Column("status", "reason", "confirmed_at", "credited_at", "swept_at", "bounced_at", "rejected_at", "updated_at").
+		Where("id = ?", deposit.ID).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrInvalidDepositTransition, "deposit %d is not %s", deposit.ID, from)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/ports/deposit"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+)
+
+func (suite *RepositoryTestSuite) TestDepositLifecycle() {
+	ctx := context.Background()
+
+	deposits := deposit.New(&deposit.Options{
+		Database: suite.adapter,
+		Events:   outbox.New(suite.adapter),
+		Ledger:   ledger.New(&ledger.Options{Database: suite.adapter, Withdrawals: suite.adapter, TxManager: suite.adapter}),
+	})
+
+	tx := &model.Transaction{
+		AccountAddr: "deposit-wallet",
+		LT:          900,
+		Hash:        "deposit-lifecycle-hash",
+		Sender:      "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt",
+		Amount:      model.NewAmount(2_000_000_000),
+	}
+
+	var d *model.Deposit
+	err := suite.adapter.WithInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if d, err = deposits.Detect(ctx, "deposit-account", tx); err != nil {
+			return err
+		}
+
+		if err = deposits.Confirm(ctx, d); err != nil {
+			return err
+		}
+		return deposits.Credit(ctx, d)
+	})
+	suite.Require().NoError(err)
+
+	_, err = deposits.Detect(ctx, "deposit-account", tx)
+	suite.Require().ErrorIs(err, model.ErrDepositExists)
+
+	var stored Deposit
+	suite.Require().NoError(suite.db.NewSelect().Model(&stored).Where("id = ?", d.ID).Scan(ctx))
+	suite.Equal(string(model.DepositCredited), stored.Status)
+	suite.NotNil(stored.ConfirmedAt)
+	suite.NotNil(stored.CreditedAt)
+
+	stale := *d
+	stale.Status = model.DepositDetected
+	suite.Require().ErrorIs(deposits.Confirm(ctx, &stale), model.ErrInvalidDepositTransition)
+
+	events, err := suite.db.NewSelect().Model((*OutboxEvent)(nil)).
+		Where("event_type LIKE 'deposit_%'").
+		Where("(payload::jsonb->>'deposit_id')::BIGINT = ?", d.ID).
+		Count(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(3, events)
+
+	balances, err := suite.adapter.GetLedgerBalances(ctx, model.CustomerLedgerAccount("deposit-account"))
+	suite.Require().NoError(err)
+	suite.Equal("-2", balances[0].Amount.String())
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// InsertLedgerEntry inserts the entry with its postings, model.ErrLedgerEntryExists is returned
+// if an entry of the same type and reference is already stored.
+func (d *DatabaseAdapter) InsertLedgerEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+	entryModel, postingModels := fromModelLedgerEntry(entry)
+
+	// a savepoint is used inside of the transaction from the context
+	err := d.GetTxOrConn(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewInsert().Model(entryModel).
+			On("CONFLICT (entry_type, reference) DO NOTHING").
+			Returning("id").Exec(ctx)
+		if err != nil {
+			return errors.Wrap(err, "insert entry")
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return model.ErrLedgerEntryExists
+		}
+
+		for _, posting := range postingModels {
+			posting.EntryID = entryModel.ID
+		}
+
+		if _, err = tx.NewInsert().Model(&postingModels).Returning("id").Exec(ctx); err != nil {
+			return errors.Wrap(err, "insert postings")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "insert ledger entry")
+	}
+	return entryModel.toModel(postingModels)
+}
+
+// GetLedgerBalances returns the sums of the postings of the ledger account per currency.
+func (d *DatabaseAdapter) GetLedgerBalances(ctx context.Context, account model.LedgerAccount) ([]model.Balance, error) {
+	var rows []struct {
+		Currency string `bun:"currency"`
+		Amount   string `bun:"amount"`
+	}
+
+	err := d.GetTxOrConn(ctx).NewSelect().Model((*LedgerPosting)(nil)).
+		Column("currency").
+		ColumnExpr("SUM(amount)::TEXT AS amount").
+		Where("ledger_account = ?", account).
+		Group("currency").
+		Order("currency").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "select ledger balances")
+	}
+
+	balances := make([]model.Balance, 0, len(rows))
+	for _, row := range rows {
+		amount, err := toModelAmount(row.Amount)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, model.Balance{Currency: model.Currency(row.Currency), Amount: amount})
+	}
+	return balances, nil
+}
+
+// LockLedgerAccount serializes the balance checks of the ledger account until the end of the transaction.
+func (d *DatabaseAdapter) LockLedgerAccount(ctx context.Context, account model.LedgerAccount) error {
+	tx, err := d.GetTxDB(ctx)
+	if err != nil {
+		return errors.Wrap(err, "lock ledger account")
+	}
+
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", string(account)); err != nil {
+		return errors.Wrap(err, "advisory lock")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
+)
+
+func (suite *RepositoryTestSuite) TestLedgerBalances() {
+	ctx := context.Background()
+	ledgerService := ledger.New(&ledger.Options{Database: suite.adapter, Withdrawals: suite.adapter, TxManager: suite.adapter})
+
+	ton := func(nano int64) model.Balance {
+		return model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}
+	}
+
+	_, err := ledgerService.RecordDeposit(ctx, "ledger-a", ton(5_000_000_000), "ledger-deposit-1")
+	suite.Require().NoError(err)
+
+	_, err = ledgerService.RecordDeposit(ctx, "ledger-a", ton(5_000_000_000), "ledger-deposit-1")
+	suite.Require().ErrorIs(err, model.ErrLedgerEntryExists)
+
+	_, err = ledgerService.RecordSweep(ctx, "ledger-a", ton(4_900_000_000), model.NewAmount(10_000_000), "ledger-sweep-1")
+	suite.Require().NoError(err)
+
+	_, err = ledgerService.Transfer(ctx, "ledger-a", "ledger-b", ton(1_000_000_000), "ledger-transfer-1")
+	suite.Require().NoError(err)
+
+	_, err = ledgerService.Transfer(ctx, "ledger-a", "ledger-b", ton(5_000_000_000), "ledger-transfer-2")
+	suite.Require().ErrorIs(err, model.ErrInsufficientFunds)
+
+	balances, err := ledgerService.GetBalance(ctx, "ledger-a")
+	suite.Require().NoError(err)
+	suite.Equal("4", balances[0].Amount.String(), "the sweep keeps the custodial balance")
+
+	balances, err = ledgerService.GetBalance(ctx, "ledger-b")
+	suite.Require().NoError(err)
+	suite.Equal("1", balances[0].Amount.String())
+
+	walletBalances, err := suite.adapter.GetLedgerBalances(ctx, model.WalletLedgerAccount("ledger-a"))
+	suite.Require().NoError(err)
+	suite.Equal("0.09", walletBalances[0].Amount.String())
+}
+
+func (suite *RepositoryTestSuite) TestLedgerRejectsUnbalancedEntry() {
+	ctx := context.Background()
+
+	_, err := suite.adapter.InsertLedgerEntry(ctx, &model.LedgerEntry{
+		Type:      model.LedgerDeposit,
+		Reference: "ledger-unbalanced",
+		Postings: []model.LedgerPosting{
+			{Account: model.WalletLedgerAccount("ledger-c"), Currency: model.CurrencyTON, Amount: model.NewAmount(1)},
+		},
+	})
+	suite.Require().Error(err)
+}
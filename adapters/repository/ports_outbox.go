@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-faster/errors"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
 )
 
+var _ ports.OutboxArchivePort = (*DatabaseAdapter)(nil)
+
 func (d *DatabaseAdapter) SaveEvent(ctx context.Context, event model.OutboxEvent) error {
 	idb := d.GetTxOrConn(ctx)
 
@@ -23,7 +28,18 @@ func (d *DatabaseAdapter) GetEvents(ctx context.Context, limit int64) ([]model.O
 
 	idb := d.GetTxOrConn(ctx)
 
-	err := idb.NewSelect().Model(&events).Limit(int(limit)).For("UPDATE").Scan(ctx)
+	// An event is not returned while an earlier event of the same aggregate is pending,
+	// including one locked by another relay, so aggregates are delivered in order.
+	err := idb.NewSelect().Model(&events).
+		Where("processed = ?", false).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_events AS prev
+			WHERE prev.processed = FALSE AND prev.aggregate_id = ?TableAlias.aggregate_id AND prev.id < ?TableAlias.id
+		)`).
+		Order("id ASC").
+		Limit(int(limit)).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "get events")
 	}
@@ -38,9 +54,111 @@ func (d *DatabaseAdapter) GetEvents(ctx context.Context, limit int64) ([]model.O
 
 func (d *DatabaseAdapter) MarkEventAsProcessed(ctx context.Context, eventID uint64) error {
 	idb := d.GetTxOrConn(ctx)
-	if _, err := idb.NewUpdate().Model((*OutboxEvent)(nil)).Set("processed = ?", true).
+	if _, err := idb.NewUpdate().Model((*OutboxEvent)(nil)).
+		Set("processed = ?", true).
+		Set("processed_at = ?", time.Now().UTC()).
 		Where("id = ?", eventID).Exec(ctx); err != nil {
 		return errors.Wrap(err, "mark event as processed")
 	}
 	return nil
 }
+
+// ListEventsAfter returns up to limit events with ids greater than afterID ordered by id, processed or not.
+func (d *DatabaseAdapter) ListEventsAfter(ctx context.Context, afterID int64, limit int) ([]model.OutboxEvent, error) {
+	var events []OutboxEvent
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&events).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list events")
+	}
+
+	result := make([]model.OutboxEvent, 0, len(events))
+	for i := range events {
+		result = append(result, events[i].toModel())
+	}
+	return result, nil
+}
+
+// GetFirstEventID returns the id of the oldest stored event, 0 if the outbox is empty.
+func (d *DatabaseAdapter) GetFirstEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := d.GetTxOrConn(ctx).NewSelect().Model((*OutboxEvent)(nil)).ColumnExpr("COALESCE(MIN(id), 0)").Scan(ctx, &id)
+	if err != nil {
+		return 0, errors.Wrap(err, "get first event id")
+	}
+	return id, nil
+}
+
+// GetLastEventID returns the id of the newest stored event, 0 if the outbox is empty.
+func (d *DatabaseAdapter) GetLastEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := d.GetTxOrConn(ctx).NewSelect().Model((*OutboxEvent)(nil)).ColumnExpr("COALESCE(MAX(id), 0)").Scan(ctx, &id)
+	if err != nil {
+		return 0, errors.Wrap(err, "get last event id")
+	}
+	return id, nil
+}
+
+// DeleteProcessedEvents removes up to limit processed events older than before and returns them,
+// so the caller can archive them within the same transaction.
+func (d *DatabaseAdapter) DeleteProcessedEvents(ctx context.Context, before time.Time, limit int) ([]model.OutboxEvent, error) {
+	idb := d.GetTxOrConn(ctx)
+
+	batch := idb.NewSelect().Model((*OutboxEvent)(nil)).Column("id").
+		Where("processed = ?", true).
+		Where("processed_at < ?", before).
+		Order("id ASC").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED")
+
+	var events []OutboxEvent
+	_, err := idb.NewDelete().Model((*OutboxEvent)(nil)).
+		Where("id IN (?)", batch).
+		Returning("*").
+		Exec(ctx, &events)
+	if err != nil {
+		return nil, errors.Wrap(err, "delete processed events")
+	}
+
+	result := make([]model.OutboxEvent, 0, len(events))
+	for i := range events {
+		result = append(result, events[i].toModel())
+	}
+	return result, nil
+}
+
+// ArchiveEvents copies events into the partitioned archive table, creating the monthly partitions on demand.
+func (d *DatabaseAdapter) ArchiveEvents(ctx context.Context, events []model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	idb := d.GetTxOrConn(ctx)
+
+	archived := make([]*OutboxEventArchive, 0, len(events))
+	months := make(map[time.Time]struct{})
+	for _, event := range events {
+		archived = append(archived, fromModelOutboxEventArchive(event))
+
+		month := time.Date(event.CreatedAt.Year(), event.CreatedAt.Month(), 1, 0, 0, 0, 0, time.UTC)
+		months[month] = struct{}{}
+	}
+
+	for month := range months {
+		query := fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS outbox_events_archive_y%04dm%02d PARTITION OF outbox_events_archive FOR VALUES FROM (?) TO (?)",
+			month.Year(), month.Month(),
+		)
+		if _, err := idb.ExecContext(ctx, query, month, month.AddDate(0, 1, 0)); err != nil {
+			return errors.Wrap(err, "create archive partition")
+		}
+	}
+
+	if _, err := idb.NewInsert().Model(&archived).On("CONFLICT DO NOTHING").Returning("NULL").Exec(ctx); err != nil {
+		return errors.Wrap(err, "archive events")
+	}
+	return nil
+}
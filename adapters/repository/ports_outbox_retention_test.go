@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/google/uuid"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+)
+
+type failingArchive struct {
+	err error
+}
+
+func (a failingArchive) ArchiveEvents(context.Context, []model.OutboxEvent) error {
+	return a.err
+}
+
+// saveRetentionEvents stores an old processed, a recent processed and an old unpublished event of the aggregate.
+func (suite *RepositoryTestSuite) saveRetentionEvents(ctx context.Context, aggregateID string) []OutboxEvent {
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	for range 3 {
+		suite.Require().NoError(suite.adapter.SaveEvent(ctx, model.OutboxEvent{
+			EventType:   model.DepositDetectedEvent,
+			AggregateID: aggregateID,
+			Payload:     []byte(`{}`),
+			CreatedAt:   old,
+		}))
+	}
+
+	events := suite.aggregateEvents(ctx, aggregateID)
+	suite.Require().Len(events, 3)
+
+	suite.Require().NoError(suite.adapter.MarkEventAsProcessed(ctx, uint64(events[0].ID)))
+	suite.Require().NoError(suite.adapter.MarkEventAsProcessed(ctx, uint64(events[1].ID)))
+
+	_, err := suite.db.NewUpdate().Model((*OutboxEvent)(nil)).
+		Set("processed_at = ?", old).
+		Where("id = ?", events[0].ID).
+		Exec(ctx)
+	suite.Require().NoError(err)
+	return events
+}
+
+func (suite *RepositoryTestSuite) aggregateEvents(ctx context.Context, aggregateID string) []OutboxEvent {
+	var events []OutboxEvent
+	err := suite.db.NewSelect().Model(&events).Where("aggregate_id = ?", aggregateID).Order("id").Scan(ctx)
+	suite.Require().NoError(err)
+	return events
+}
+
+func (suite *RepositoryTestSuite) TestRetentionArchivesThenPurges() {
+	ctx := context.Background()
+	aggregateID := uuid.NewString()
+	events := suite.saveRetentionEvents(ctx, aggregateID)
+
+	retention := outbox.NewRetention(&outbox.RetentionOptions{
+		Database:  suite.adapter,
+		TxManager: suite.adapter,
+		Archive:   suite.adapter,
+		MaxAge:    24 * time.Hour,
+		BatchSize: 1,
+	})
+
+	purged, err := retention.Purge(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, purged)
+
+	remaining := suite.aggregateEvents(ctx, aggregateID)
+	suite.Require().Len(remaining, 2, "the recent and the unpublished events are kept")
+	suite.Equal(events[1].ID, remaining[0].ID)
+	suite.Equal(events[2].ID, remaining[1].ID)
+	suite.False(remaining[1].Processed)
+
+	var archived []OutboxEventArchive
+	suite.Require().NoError(suite.db.NewSelect().Model(&archived).Where("aggregate_id = ?", aggregateID).Scan(ctx))
+	suite.Require().Len(archived, 1)
+	suite.Equal(events[0].ID, archived[0].ID)
+	suite.NotNil(archived[0].ProcessedAt)
+}
+
+func (suite *RepositoryTestSuite) TestRetentionKeepsEventsIfArchiveFails() {
+	ctx := context.Background()
+	aggregateID := uuid.NewString()
+	suite.saveRetentionEvents(ctx, aggregateID)
+
+	errArchive := errors.New("archive unavailable")
+	retention := outbox.NewRetention(&outbox.RetentionOptions{
+		Database:  suite.adapter,
+		TxManager: suite.adapter,
+		Archive:   failingArchive{err: errArchive},
+		MaxAge:    24 * time.Hour,
+	})
+
+	_, err := retention.Purge(ctx)
+	suite.Require().ErrorIs(err, errArchive)
+	suite.Len(suite.aggregateEvents(ctx, aggregateID), 3, "the deletion is rolled back with the failed archive")
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+	"github.com/kriuchkov/tonbeacon/ports/account"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+)
+
+type testWalletWrapper struct {
+	address model.Address
+}
+
+func (w *testWalletWrapper) WalletAddress() model.Address {
+	return w.address
+}
+
+func (w *testWalletWrapper) ToAccount() *model.Account {
+	return &model.Account{Address: w.address}
+}
+
+func (suite *RepositoryTestSuite) accountEvents(ctx context.Context, accountID string) []OutboxEvent {
+	var events []OutboxEvent
+	err := suite.db.NewSelect().Model(&events).
+		Where("payload::jsonb->>'account_id' = ?", accountID).
+		Order("id").
+		Scan(ctx)
+	suite.Require().NoError(err)
+	return events
+}
+
+func (suite *RepositoryTestSuite) TestOutboxPersistsAccountEvents() {
+	ctx := context.Background()
+	accountID := uuid.NewString()
+
+	wallet := portsmocks.NewMockWalletPort(suite.T())
+	wallet.On("CreateWallet", mock.Anything, mock.Anything).
+		Return(&testWalletWrapper{address: "EQBAGi6wUF6SvjQtWyP5OvniQfYSI3Q-eTvM4BLiJztcRahv"}, nil).Once()
+
+	service := account.New(account.Options{
+		WalletManager:   wallet,
+		TxManager:       suite.adapter,
+		DatabaseManager: suite.adapter,
+		EventManager:    outbox.New(suite.adapter),
+	})
+
+	created, err := service.CreateAccount(ctx, accountID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(service.CloseAccount(ctx, accountID))
+
+	events := suite.accountEvents(ctx, accountID)
+	suite.Require().Len(events, 2)
+
+	suite.Equal(string(model.AccountCreated), events[0].EventType)
+	suite.Equal(accountID, events[0].AggregateID)
+	suite.False(events[0].Processed)
+
+	var createdPayload model.AccountCreatedPayload
+	suite.Require().NoError(json.Unmarshal([]byte(events[0].Payload), &createdPayload))
+	suite.Equal(model.NewAccountCreatedPayload(created), createdPayload)
+
+	suite.Equal(string(model.AccountClosed), events[1].EventType)
+
+	var closedPayload model.AccountClosedPayload
+	suite.Require().NoError(json.Unmarshal([]byte(events[1].Payload), &closedPayload))
+	suite.Equal(accountID, closedPayload.AccountID)
+}
+
+func (suite *RepositoryTestSuite) TestOutboxRollsBackWithAccount() {
+	ctx := context.Background()
+	accountID := uuid.NewString()
+
+	wallet := portsmocks.NewMockWalletPort(suite.T())
+	wallet.On("CreateWallet", mock.Anything, mock.Anything).
+		Return(&testWalletWrapper{address: "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt"}, nil).Once()
+
+	errPublish := errors.New("publish failed")
+	events := portsmocks.NewMockOutboxMessagePort(suite.T())
+	events.On("Publish", mock.Anything, model.AccountCreated, mock.Anything).
+		Return(func(ctx context.Context, eventType model.EventType, payload any) error {
+			// The event is stored in the account transaction before the failure.
+			if err := outbox.New(suite.adapter).Publish(ctx, eventType, payload); err != nil {
+				return err
+			}
+			return errPublish
+		}).Once()
+
+	service := account.New(account.Options{
+		WalletManager:   wallet,
+		TxManager:       suite.adapter,
+		DatabaseManager: suite.adapter,
+		EventManager:    events,
+	})
+
+	_, err := service.CreateAccount(ctx, accountID)
+	suite.Require().ErrorIs(err, errPublish)
+
+	exists, err := suite.adapter.IsAccountExists(ctx, accountID)
+	suite.Require().NoError(err)
+	suite.False(exists)
+	suite.Empty(suite.accountEvents(ctx, accountID))
+}
+
+func (suite *RepositoryTestSuite) TestGetEventsKeepsAggregateOrder() {
+	ctx := context.Background()
+	first, second := uuid.NewString(), uuid.NewString()
+
+	for _, event := range []model.OutboxEvent{
+		{EventType: model.AccountCreated, AggregateID: first, Payload: []byte(`{}`), CreatedAt: time.Now()},
+		{EventType: model.AccountClosed, AggregateID: first, Payload: []byte(`{}`), CreatedAt: time.Now()},
+		{EventType: model.AccountCreated, AggregateID: second, Payload: []byte(`{}`), CreatedAt: time.Now()},
+	} {
+		suite.Require().NoError(suite.adapter.SaveEvent(ctx, event))
+	}
+
+	aggregateEvents := func() []model.OutboxEvent {
+		events, err := suite.adapter.GetEvents(ctx, 100)
+		suite.Require().NoError(err)
+
+		var result []model.OutboxEvent
+		for _, event := range events {
+			if event.AggregateID == first || event.AggregateID == second {
+				result = append(result, event)
+			}
+		}
+		return result
+	}
+
+	// The closing event of the first aggregate waits for the creation event.
+	events := aggregateEvents()
+	suite.Require().Len(events, 2)
+	suite.Equal(first, events[0].AggregateID)
+	suite.Equal(model.AccountCreated, events[0].EventType)
+	suite.Equal(second, events[1].AggregateID)
+
+	suite.Require().NoError(suite.adapter.MarkEventAsProcessed(ctx, uint64(events[0].ID)))
+	suite.Require().NoError(suite.adapter.MarkEventAsProcessed(ctx, uint64(events[1].ID)))
+
+	events = aggregateEvents()
+	suite.Require().Len(events, 1)
+	suite.Equal(first, events[0].AggregateID)
+	suite.Equal(model.AccountClosed, events[0].EventType)
+}
+
+func (suite *RepositoryTestSuite) TestListEventsAfter() {
+	ctx := context.Background()
+	aggregateID := uuid.NewString()
+
+	last, err := suite.adapter.GetLastEventID(ctx)
+	suite.Require().NoError(err)
+
+	for _, eventType := range []model.EventType{model.DepositDetectedEvent, model.DepositCreditedEvent} {
+		suite.Require().NoError(suite.adapter.SaveEvent(ctx, model.OutboxEvent{
+			EventType:   eventType,
+			AggregateID: aggregateID,
+			Payload:     []byte(`{}`),
+			CreatedAt:   time.Now().UTC(),
+		}))
+	}
+
+	events, err := suite.adapter.ListEventsAfter(ctx, last, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 2)
+	suite.Equal(model.DepositDetectedEvent, events[0].EventType)
+	suite.Equal(aggregateID, events[1].AggregateID)
+
+	suite.Require().NoError(suite.adapter.MarkEventAsProcessed(ctx, uint64(events[0].ID)))
+
+	events, err = suite.adapter.ListEventsAfter(ctx, last, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 1, "processed events are streamed until they are purged")
+
+	newest, err := suite.adapter.GetLastEventID(ctx)
+	suite.Require().NoError(err)
+	suite.GreaterOrEqual(newest, events[0].ID+1)
+
+	oldest, err := suite.adapter.GetFirstEventID(ctx)
+	suite.Require().NoError(err)
+	suite.LessOrEqual(oldest, events[0].ID)
+}
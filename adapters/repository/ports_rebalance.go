@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// InsertRebalance inserts the rebalance, the addresses are stored in the raw form.
+// model.ErrRebalanceExists is returned if the currency already has an active rebalance.
+func (d *DatabaseAdapter) InsertRebalance(ctx context.Context, rebalance *model.Rebalance) (*model.Rebalance, error) {
+	rebalanceModel := fromModelRebalance(rebalance)
+	rebalanceModel.From = common.NormalizeAddress(rebalanceModel.From)
+	rebalanceModel.To = common.NormalizeAddress(rebalanceModel.To)
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(rebalanceModel).
+		On("CONFLICT DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrRebalanceExists
+	}
+	return rebalanceModel.toModel()
+}
+
+// ListActiveRebalances returns the rebalances in flight from the oldest.
+func (d *DatabaseAdapter) ListActiveRebalances(ctx context.Context) ([]*model.Rebalance, error) {
+	var rebalances []Rebalance
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&rebalances).
+		Where("status IN (?)", bun.In(model.ActiveRebalanceStatuses)).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.Rebalance, 0, len(rebalances))
+	for i := range rebalances {
+		item, err := rebalances[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// UpdateRebalance stores the status, the attempts and the timestamps of the rebalance if it is still
+// in the previous status, model.ErrInvalidRebalanceTransition is returned if it was moved by someone else.
+func (d *DatabaseAdapter) UpdateRebalance(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus) error {
+	res, err := d.GetTxOrConn(ctx).NewUpdate().Model(fromModelRebalance(rebalance)).
+		Column("status", "attempts", "error", "sent_at", "confirmed_at", "failed_at", "updated_at").
+		Where("id = ?", rebalance.ID).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrInvalidRebalanceTransition, "rebalance %d is not %s", rebalance.ID, from)
+	}
+	return nil
+}
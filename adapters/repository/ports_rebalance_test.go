@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (suite *RepositoryTestSuite) TestRebalances() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	message := &model.WalletMessage{
+		From:        "0:02",
+		To:          "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		Currency:    model.CurrencyTON,
+		Amount:      model.NewAmount(7000),
+		Seqno:       21,
+		MessageHash: "rebalance-message-hash",
+		BOC:         []byte{7, 8},
+		ExpiresAt:   now.Add(3 * time.Minute),
+	}
+
+	rebalance, err := suite.adapter.InsertRebalance(ctx, model.NewRebalance(message, now))
+	suite.Require().NoError(err)
+	suite.NotZero(rebalance.ID)
+	suite.Equal("0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b", rebalance.To)
+
+	second := *message
+	second.MessageHash = "rebalance-message-hash-2"
+	_, err = suite.adapter.InsertRebalance(ctx, model.NewRebalance(&second, now))
+	suite.Require().ErrorIs(err, model.ErrRebalanceExists)
+
+	active, err := suite.adapter.ListActiveRebalances(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 1)
+	suite.Equal([]byte{7, 8}, active[0].BOC)
+	suite.Equal(uint32(21), active[0].Seqno)
+	suite.Equal("7000", active[0].Amount.Nano())
+
+	suite.Require().NoError(rebalance.Transition(model.RebalanceSent, now))
+	rebalance.Attempts = 1
+	suite.Require().NoError(suite.adapter.UpdateRebalance(ctx, rebalance, model.RebalancePlanned))
+	suite.Require().ErrorIs(suite.adapter.UpdateRebalance(ctx, rebalance, model.RebalancePlanned), model.ErrInvalidRebalanceTransition)
+
+	suite.Require().NoError(rebalance.Transition(model.RebalanceConfirmed, now))
+	suite.Require().NoError(suite.adapter.UpdateRebalance(ctx, rebalance, model.RebalanceSent))
+
+	active, err = suite.adapter.ListActiveRebalances(ctx)
+	suite.Require().NoError(err)
+	suite.Empty(active)
+
+	parked, err := suite.adapter.InsertRebalance(ctx, model.NewRebalance(&second, now))
+	suite.Require().NoError(err)
+
+	// an unconfirmed rebalance waits for its transfer without holding the currency
+	suite.Require().NoError(parked.Transition(model.RebalanceUnconfirmed, now))
+	suite.Require().NoError(suite.adapter.UpdateRebalance(ctx, parked, model.RebalancePlanned))
+
+	third := *message
+	third.MessageHash = "rebalance-message-hash-3"
+	_, err = suite.adapter.InsertRebalance(ctx, model.NewRebalance(&third, now))
+	suite.Require().NoError(err)
+
+	active, err = suite.adapter.ListActiveRebalances(ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 2)
+	suite.Equal(model.RebalanceUnconfirmed, active[0].Status)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-faster/errors"
+	"github.com/samber/lo"
+	"github.com/uptrace/bun"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// InsertSweepJob inserts the job, the addresses are stored in the raw form.
+// model.ErrSweepJobExists is returned if the account already has an active job.
+func (d *DatabaseAdapter) InsertSweepJob(ctx context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+	jobModel := fromModelSweepJob(job)
+	jobModel.From = common.NormalizeAddress(jobModel.From)
+	jobModel.To = common.NormalizeAddress(jobModel.To)
+	if jobModel.TopUpMessageHash != nil {
+		jobModel.TopUpFrom = lo.ToPtr(common.NormalizeAddress(*jobModel.TopUpFrom))
+		jobModel.TopUpTo = lo.ToPtr(common.NormalizeAddress(*jobModel.TopUpTo))
+	}
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(jobModel).
+		On("CONFLICT DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrSweepJobExists
+	}
+	return jobModel.toModel()
+}
+
+func (d *DatabaseAdapter) ListActiveSweepJobs(ctx context.Context, afterID int64, limit int) ([]*model.SweepJob, error) {
+	var jobs []SweepJob
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&jobs).
+		Where("status IN (?)", bun.In(model.ActiveSweepStatuses)).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.SweepJob, 0, len(jobs))
+	for _, job := range jobs {
+		item, err := job.toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetLastSweepJob returns the latest job of the account in the currency which did not fail,
+// model.ErrSweepJobNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetLastSweepJob(ctx context.Context, accountID model.AccountID, currency model.Currency) (*model.SweepJob, error) {
+	var job SweepJob
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&job).
+		Where("account_id = ?", accountID).
+		Where("currency = ?", currency).
+		Where("status != ?", model.SweepFailed).
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrSweepJobNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return job.toModel()
+}
+
+// UpdateSweepJob stores the status, the attempts, the timestamps and the message of the job if it is still
+// in the previous status, model.ErrInvalidSweepTransition is returned if it was moved by someone else.
+func (d *DatabaseAdapter) UpdateSweepJob(ctx context.Context, job *model.SweepJob, from model.SweepStatus) error {
+	jobModel := fromModelSweepJob(job)
+	jobModel.From = common.NormalizeAddress(jobModel.From)
+	jobModel.To = common.NormalizeAddress(jobModel.To)
+
+	res, err := d.GetTxOrConn(ctx).NewUpdate().Model(jobModel).
+		Column("status", "attempts", "error", "funded_at", "sent_at", "confirmed_at", "failed_at", "updated_at").
+		Column("from_addr", "to_addr", "seqno", "message_hash", "jetton_query_id", "boc", "expires_at").
+		Where("id = ?", job.ID).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrInvalidSweepTransition, "sweep job %d is not %s", job.ID, from)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// UpsertSweepPolicy stores the policy of its account and currency, the previous policy of them is replaced
+// and keeps its id and creation time.
+func (d *DatabaseAdapter) UpsertSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error) {
+	policyModel := fromModelSweepPolicy(policy)
+
+	_, err := d.GetTxOrConn(ctx).NewInsert().Model(policyModel).
+		On("CONFLICT (account_id, currency) DO UPDATE").
+		Set("disabled = EXCLUDED.disabled").
+		Set("threshold = EXCLUDED.threshold").
+		Set("reserve = EXCLUDED.reserve").
+		Set("min_interval_seconds = EXCLUDED.min_interval_seconds").
+		Set("schedule = EXCLUDED.schedule").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("id, created_at").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "upsert exec")
+	}
+	return policyModel.toModel()
+}
+
+// ListSweepPolicies returns the policies of the account, of all the accounts if it is nil.
+func (d *DatabaseAdapter) ListSweepPolicies(ctx context.Context, accountID *model.AccountID) ([]*model.SweepPolicy, error) {
+	var policies []SweepPolicy
+
+	query := d.GetTxOrConn(ctx).NewSelect().Model(&policies).Order("account_id", "currency")
+	if accountID != nil {
+		query = query.Where("account_id = ?", *accountID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.SweepPolicy, 0, len(policies))
+	for _, policy := range policies {
+		item, err := policy.toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// DeleteSweepPolicy deletes the policy of the account and the currency, model.ErrSweepPolicyNotFound
+// is returned if there is none.
+func (d *DatabaseAdapter) DeleteSweepPolicy(ctx context.Context, accountID model.AccountID, currency model.Currency) error {
+	res, err := d.GetTxOrConn(ctx).NewDelete().Model((*SweepPolicy)(nil)).
+		Where("account_id = ?", accountID).
+		Where("currency = ?", currency).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "delete exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.ErrSweepPolicyNotFound
+	}
+	return nil
+}
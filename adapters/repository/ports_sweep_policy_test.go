@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (suite *RepositoryTestSuite) TestSweepPolicies() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	policy, err := suite.adapter.UpsertSweepPolicy(ctx, &model.SweepPolicy{
+		AccountID: "policy-account", Currency: model.CurrencyTON, Threshold: model.NewAmount(1000),
+		Reserve: model.NewAmount(10), MinInterval: time.Hour, Schedule: "@daily", CreatedAt: now, UpdatedAt: now,
+	})
+	suite.Require().NoError(err)
+	suite.NotZero(policy.ID)
+
+	updated, err := suite.adapter.UpsertSweepPolicy(ctx, &model.SweepPolicy{
+		AccountID: "policy-account", Currency: model.CurrencyTON, Disabled: true,
+		CreatedAt: now.Add(time.Minute), UpdatedAt: now.Add(time.Minute),
+	})
+	suite.Require().NoError(err)
+	suite.Equal(policy.ID, updated.ID)
+	suite.Equal(now, updated.CreatedAt)
+
+	_, err = suite.adapter.UpsertSweepPolicy(ctx, &model.SweepPolicy{
+		Currency: model.CurrencyUSDT, Threshold: model.NewAmount(5), CreatedAt: now, UpdatedAt: now,
+	})
+	suite.Require().NoError(err)
+
+	accountID := model.AccountID("policy-account")
+	policies, err := suite.adapter.ListSweepPolicies(ctx, &accountID)
+	suite.Require().NoError(err)
+	suite.Require().Len(policies, 1)
+	suite.True(policies[0].Disabled)
+	suite.Equal("0", policies[0].Threshold.Nano())
+	suite.Zero(policies[0].MinInterval)
+	suite.Empty(policies[0].Schedule)
+
+	policies, err = suite.adapter.ListSweepPolicies(ctx, nil)
+	suite.Require().NoError(err)
+	suite.Len(policies, 2)
+
+	suite.Require().NoError(suite.adapter.DeleteSweepPolicy(ctx, "policy-account", model.CurrencyTON))
+	suite.Require().ErrorIs(suite.adapter.DeleteSweepPolicy(ctx, "policy-account", model.CurrencyTON), model.ErrSweepPolicyNotFound)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (suite *RepositoryTestSuite) TestSweepJobs() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	message := &model.WalletMessage{
+		WalletID:    3,
+		From:        "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		To:          "0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63c",
+		Amount:      model.NewAmount(900),
+		Seqno:       4,
+		MessageHash: "sweep-message-hash",
+		BOC:         []byte{1, 2, 3},
+		ExpiresAt:   now.Add(3 * time.Minute),
+	}
+
+	job, err := suite.adapter.InsertSweepJob(ctx, model.NewSweepJob("sweep-account", message, now))
+	suite.Require().NoError(err)
+	suite.NotZero(job.ID)
+	suite.Equal("0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b", job.From)
+
+	second := *message
+	second.MessageHash = "sweep-message-hash-2"
+	_, err = suite.adapter.InsertSweepJob(ctx, model.NewSweepJob("sweep-account", &second, now))
+	suite.Require().ErrorIs(err, model.ErrSweepJobExists)
+
+	active, err := suite.adapter.ListActiveSweepJobs(ctx, 0, 100)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 1)
+	suite.Equal([]byte{1, 2, 3}, active[0].BOC)
+	suite.Equal(uint32(4), active[0].Seqno)
+	suite.Equal("900", active[0].Amount.Nano())
+
+	suite.Require().NoError(job.Transition(model.SweepSent, now))
+	job.Attempts = 1
+	suite.Require().NoError(suite.adapter.UpdateSweepJob(ctx, job, model.SweepPlanned))
+	suite.Require().ErrorIs(suite.adapter.UpdateSweepJob(ctx, job, model.SweepPlanned), model.ErrInvalidSweepTransition)
+
+	suite.Require().NoError(job.Transition(model.SweepConfirmed, now))
+	suite.Require().NoError(suite.adapter.UpdateSweepJob(ctx, job, model.SweepSent))
+
+	active, err = suite.adapter.ListActiveSweepJobs(ctx, 0, 100)
+	suite.Require().NoError(err)
+	suite.Empty(active)
+
+	last, err := suite.adapter.GetLastSweepJob(ctx, "sweep-account", model.CurrencyTON)
+	suite.Require().NoError(err)
+	suite.Equal(job.ID, last.ID)
+
+	_, err = suite.adapter.GetLastSweepJob(ctx, "sweep-account", model.CurrencyUSDT)
+	suite.Require().ErrorIs(err, model.ErrSweepJobNotFound)
+
+	_, err = suite.adapter.InsertSweepJob(ctx, model.NewSweepJob("sweep-account", &second, now))
+	suite.Require().NoError(err)
+}
+
+func (suite *RepositoryTestSuite) TestJettonSweepJobs() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	account := model.Account{ID: "jetton-sweep-account", WalletID: 5, Address: "0:01"}
+	amount := model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}
+	topUp := &model.WalletMessage{
+		From: "0:02", To: "0:01", Currency: model.CurrencyTON, Amount: model.NewAmount(350), Seqno: 9,
+		MessageHash: "top-up-message-hash", BOC: []byte{4, 5}, ExpiresAt: now.Add(3 * time.Minute),
+	}
+
+	job, err := suite.adapter.InsertSweepJob(ctx, model.NewJettonSweepJob(account, "0:02", amount, model.NewAmount(300), topUp, now))
+	suite.Require().NoError(err)
+
+	active, err := suite.adapter.ListActiveSweepJobs(ctx, job.ID-1, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 1)
+	suite.False(active[0].Signed())
+	suite.Require().NotNil(active[0].TopUp)
+	suite.Equal("top-up-message-hash", active[0].TopUp.MessageHash)
+	suite.Equal("350", active[0].TopUp.Amount.Nano())
+	suite.Equal("300", active[0].Gas.Nano())
+	suite.Equal(model.CurrencyUSDT, active[0].Currency)
+
+	suite.Require().NoError(job.Transition(model.SweepFunding, now))
+	suite.Require().NoError(job.Transition(model.SweepFunded, now))
+	suite.Require().NoError(suite.adapter.UpdateSweepJob(ctx, job, model.SweepPlanned))
+
+	job.SetMessage(&model.WalletMessage{
+		From: "0:01", To: "0:02", Seqno: 2, MessageHash: "jetton-message-hash", BOC: []byte{6}, ExpiresAt: now.Add(time.Minute),
+	})
+	suite.Require().NoError(suite.adapter.UpdateSweepJob(ctx, job, model.SweepFunded))
+
+	active, err = suite.adapter.ListActiveSweepJobs(ctx, job.ID-1, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 1)
+	suite.Equal(model.SweepFunded, active[0].Status)
+	suite.Equal("jetton-message-hash", active[0].MessageHash)
+	suite.Equal([]byte{6}, active[0].BOC)
+	suite.NotNil(active[0].FundedAt)
+}
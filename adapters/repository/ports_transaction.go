@@ -2,24 +2,111 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/go-faster/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
 )
 
+// transactionConflict is the unique key identifying a transaction.
+const transactionConflict = "CONFLICT (account_addr, lt, hash) DO NOTHING"
+
+// InsertTransaction inserts the transaction, the sender and the receiver are stored in the raw form.
+// model.ErrTransactionExists is returned if it is already stored, also by a row stored before the hashes.
 func (d *DatabaseAdapter) InsertTransaction(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
 	idb := d.GetTxOrConn(ctx)
 
 	transactionModel := fromModelTransaction(transaction)
+	transactionModel.Sender = common.NormalizeAddress(transactionModel.Sender)
+	transactionModel.Receiver = common.NormalizeAddress(transactionModel.Receiver)
 	log.Debug().Any("transaction", transactionModel).Msg("insert transaction")
 
-	_, err := idb.NewInsert().Model(transactionModel).Returning("id").Exec(ctx)
+	adopted, err := adoptLegacyTransaction(ctx, idb, transactionModel)
+	if err != nil {
+		return nil, err
+	}
+	if adopted {
+		return nil, model.ErrTransactionExists
+	}
+
+	res, err := idb.NewInsert().Model(transactionModel).On(transactionConflict).Returning("id").Exec(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "insert exec")
 	}
-	return transactionModel.toModel(), nil
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrTransactionExists
+	}
+	return transactionModel.toModel()
+}
+
+// InsertTransactions inserts the transactions with a single statement and returns the ones that were not stored yet,
+// the senders and the receivers are stored in the raw form.
+func (d *DatabaseAdapter) InsertTransactions(ctx context.Context, transactions []*model.Transaction) ([]*model.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	idb := d.GetTxOrConn(ctx)
+
+	transactionModels := make([]*Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		transactionModel := fromModelTransaction(transaction)
+		transactionModel.Sender = common.NormalizeAddress(transactionModel.Sender)
+		transactionModel.Receiver = common.NormalizeAddress(transactionModel.Receiver)
+
+		adopted, err := adoptLegacyTransaction(ctx, idb, transactionModel)
+		if err != nil {
+			return nil, err
+		}
+		if !adopted {
+			transactionModels = append(transactionModels, transactionModel)
+		}
+	}
+
+	if len(transactionModels) == 0 {
+		return nil, nil
+	}
+
+	var inserted []Transaction
+	if _, err := idb.NewInsert().Model(&transactionModels).On(transactionConflict).Returning("*").Exec(ctx, &inserted); err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	result := make([]*model.Transaction, 0, len(inserted))
+	for i := range inserted {
+		item, err := inserted[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// adoptLegacyTransaction stores the hash of the transaction in the row of the same account and LT stored
+// without a hash before the hashes were kept, it reports whether such a row exists.
+func adoptLegacyTransaction(ctx context.Context, idb bun.IDB, transaction *Transaction) (bool, error) {
+	if transaction.Hash == "" {
+		return false, nil
+	}
+
+	res, err := idb.NewUpdate().Model((*Transaction)(nil)).
+		Set("hash = ?", transaction.Hash).
+		Where("account_addr IS NOT DISTINCT FROM ?", transaction.AccountAddr).
+		Where("lt = ?", transaction.LT).
+		Where("hash = ''").
+		Exec(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "adopt legacy transaction")
+	}
+
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
 }
 
 func (d *DatabaseAdapter) GetTransactions(ctx context.Context, limit, offset int) ([]*model.Transaction, error) {
@@ -33,7 +120,104 @@ func (d *DatabaseAdapter) GetTransactions(ctx context.Context, limit, offset int
 
 	result := make([]*model.Transaction, 0, len(transactions))
 	for _, transaction := range transactions {
-		result = append(result, transaction.toModel())
+		item, err := transaction.toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// ListTransactions returns the transactions matching the filter ordered by id from the newest,
+// the cursor continues after the transaction it was issued for.
+func (d *DatabaseAdapter) ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) ([]*model.Transaction, error) {
+	cursor, err := model.DecodeTransactionCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+	query := d.GetTxOrConn(ctx).NewSelect().Model(&transactions)
+
+	if cursor > 0 {
+		query.Where("id < ?", cursor)
+	}
+
+	if filter.AccountID != nil {
+		query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	if filter.Address != nil {
+		addr := common.NormalizeAddress(*filter.Address)
+		query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("sender = ?", addr).WhereOr("receiver = ?", addr)
+		})
+	}
+
+	if filter.Direction != nil {
+		query.Where("direction = ?", *filter.Direction)
+	}
+
+	if filter.Currency != nil {
+		query.Where("currency = ?", *filter.Currency)
+	}
+
+	if filter.Success != nil {
+		query.Where("success = ?", *filter.Success)
+	}
+
+	if filter.FromTime != nil {
+		query.Where("created_at >= ?", *filter.FromTime)
+	}
+
+	if filter.ToTime != nil {
+		query.Where("created_at < ?", *filter.ToTime)
+	}
+
+	if filter.FromLT != nil {
+		query.Where("lt >= ?", *filter.FromLT)
+	}
+
+	if filter.ToLT != nil {
+		query.Where("lt < ?", *filter.ToLT)
+	}
+
+	if err = query.Order("id DESC").Limit(filter.Limit).Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "select scan")
+	}
+
+	result := make([]*model.Transaction, 0, len(transactions))
+	for i := range transactions {
+		item, err := transactions[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
 	}
 	return result, nil
 }
+
+func (d *DatabaseAdapter) GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error) {
+	return d.getTransaction(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("hash = ?", hash)
+	})
+}
+
+func (d *DatabaseAdapter) GetTransactionByLT(ctx context.Context, accountID model.AccountID, lt int64) (*model.Transaction, error) {
+	return d.getTransaction(ctx, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("account_id = ?", accountID).Where("lt = ?", lt)
+	})
+}
+
+func (d *DatabaseAdapter) getTransaction(ctx context.Context, where func(q *bun.SelectQuery) *bun.SelectQuery) (*model.Transaction, error) {
+	var transaction Transaction
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&transaction).Apply(where).Order("id").Limit(1).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTransactionNotFound
+		}
+		return nil, errors.Wrap(err, "select scan")
+	}
+	return transaction.toModel()
+}
@@ -2,9 +2,16 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
 	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+	"github.com/kriuchkov/tonbeacon/ports/transaction"
 )
 
 func (suite *RepositoryTestSuite) TestInsertTransaction() {
@@ -13,18 +20,21 @@ func (suite *RepositoryTestSuite) TestInsertTransaction() {
 		LT:             1674235553000,
 		PrevTxHash:     "97b7bf0154d3b1a3ce9ac692944e53f518f819b7e09ba567a61ad0bd1724fc30",
 		PrevTxLT:       1674235552981,
+		Hash:           "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0",
 		Sender:         "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt",
 		Receiver:       "EQBAGi6wUF6SvjQtWyP5OvniQfYSI3Q-eTvM4BLiJztcRahv",
 		SenderIsOurs:   false,
 		ReceiverIsOurs: true,
-		Amount:         2.75,
-		TotalFees:      0.01,
+		Amount:         model.NewAmount(2_750_000_000),
+		TotalFees:      model.NewAmount(10_000_000),
 		ExitCode:       0,
 		Success:        true,
 		MessageType:    "INTERNAL",
 		Bounce:         true,
 		Bounced:        false,
 		Body:           "te6cckEBAQEADgAAGKzAUQkAAAAAAAAAAADA84rE",
+		OpCode:         lo.ToPtr(model.OpJettonNotification),
+		QueryID:        lo.ToPtr(uint64(18446744073709551615)),
 		BlockID:        "(-1,8000001,12345)",
 		CreatedAt:      time.Now(),
 		AccountStatus:  "ACTIVE",
@@ -42,10 +52,16 @@ func (suite *RepositoryTestSuite) TestInsertTransaction() {
 	suite.Equal(testTransaction.LT, inserted.LT)
 	suite.Equal(testTransaction.PrevTxHash, inserted.PrevTxHash)
 	suite.Equal(testTransaction.PrevTxLT, inserted.PrevTxLT)
-	suite.Equal(testTransaction.Sender, inserted.Sender)
-	suite.Equal(testTransaction.Receiver, inserted.Receiver)
-	suite.Equal(testTransaction.Amount, inserted.Amount)
-	suite.Equal(testTransaction.TotalFees, inserted.TotalFees)
+	suite.Equal(common.NormalizeAddress(testTransaction.Sender), inserted.Sender)
+	suite.Equal(common.NormalizeAddress(testTransaction.Receiver), inserted.Receiver)
+	suite.Equal(testTransaction.Amount.String(), inserted.Amount.String())
+	suite.Equal(testTransaction.TotalFees.String(), inserted.TotalFees.String())
+	suite.Equal(testTransaction.Hash, inserted.Hash)
+	suite.Equal(testTransaction.OpCode, inserted.OpCode)
+	suite.Equal(testTransaction.QueryID, inserted.QueryID)
+
+	_, err = suite.adapter.InsertTransaction(ctx, testTransaction)
+	suite.ErrorIs(err, model.ErrTransactionExists)
 }
 
 func (suite *RepositoryTestSuite) TestGetTransactions() {
@@ -56,12 +72,13 @@ func (suite *RepositoryTestSuite) TestGetTransactions() {
 			LT:             1674235553000,
 			PrevTxHash:     "97b7bf0154d3b1a3ce9ac692944e53f518f819b7e09ba567a61ad0bd1724fc30",
 			PrevTxLT:       1674235552981,
+			Hash:           "1f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f1",
 			Sender:         "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt",
 			Receiver:       "EQBAGi6wUF6SvjQtWyP5OvniQfYSI3Q-eTvM4BLiJztcRahv",
 			SenderIsOurs:   false,
 			ReceiverIsOurs: true,
-			Amount:         2.75,
-			TotalFees:      0.01,
+			Amount:         model.NewAmount(2_750_000_000),
+			TotalFees:      model.NewAmount(10_000_000),
 			ExitCode:       0,
 			Success:        true,
 			MessageType:    "INTERNAL",
@@ -79,12 +96,13 @@ func (suite *RepositoryTestSuite) TestGetTransactions() {
 			LT:             1674235554000,
 			PrevTxHash:     "a7b7bf0154d3b1a3ce9ac692944e53f518f819b7e09ba567a61ad0bd1724fc31",
 			PrevTxLT:       1674235553981,
+			Hash:           "2f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f2",
 			Sender:         "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt",
 			Receiver:       "EQBAGi6wUF6SvjQtWyP5OvniQfYSI3Q-eTvM4BLiJztcRahv",
 			SenderIsOurs:   false,
 			ReceiverIsOurs: true,
-			Amount:         3.50,
-			TotalFees:      0.02,
+			Amount:         model.NewAmount(3_500_000_000),
+			TotalFees:      model.NewAmount(20_000_000),
 			ExitCode:       0,
 			Success:        true,
 			MessageType:    "INTERNAL",
@@ -113,3 +131,191 @@ func (suite *RepositoryTestSuite) TestGetTransactions() {
 	suite.NoError(err)
 	suite.Len(retrievedTransactions, 2)
 }
+
+func (suite *RepositoryTestSuite) TestReplayedTransactionMessageIsStoredOnce() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const address = "EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N"
+
+	account, err := suite.adapter.InsertAccount(ctx, uuid.NewString())
+	suite.Require().NoError(err)
+
+	account.Address = address
+	suite.Require().NoError(suite.adapter.UpdateAccount(ctx, account))
+
+	handler := transaction.New(ctx, &transaction.Options{
+		DatabasePort:    suite.adapter,
+		TransactionPort: suite.adapter,
+		TxPort:          suite.adapter,
+	})
+
+	message := []byte(`{
+		"AccountAddr": "` + address + `",
+		"LT": 48112233000001,
+		"Hash": "` + base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")) + `",
+		"IO": {"In": {"Msg": {"SrcAddr": "EQDrLq-X6jKZNHAScgghh0h1iijd-NQO0lSPEoZ4exDtOFLt", "DstAddr": "` + address + `"}}}
+	}`)
+
+	suite.Require().NoError(handler.Handle(ctx, message))
+	suite.Require().NoError(handler.Handle(ctx, message))
+	suite.Require().NoError(handler.HandleBatch(ctx, [][]byte{message, message}))
+
+	count, err := suite.db.NewSelect().Model((*Transaction)(nil)).
+		Where("account_addr = ? AND lt = ?", address, 48112233000001).
+		Count(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(1, count)
+}
+
+func (suite *RepositoryTestSuite) TestInsertTransactionStoredWithoutHash() {
+	ctx := context.Background()
+
+	legacy := &model.Transaction{
+		AccountAddr: "EQBAGi6wUF6SvjQtWyP5OvniQfYSI3Q-eTvM4BLiJztcRahv",
+		LT:          1674235559000,
+		Amount:      model.NewAmount(1_000),
+		TotalFees:   model.NewAmount(0),
+		CreatedAt:   time.Now(),
+	}
+	_, err := suite.adapter.InsertTransaction(ctx, legacy)
+	suite.Require().NoError(err)
+
+	rescanned := *legacy
+	rescanned.Hash = "2f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f2"
+	_, err = suite.adapter.InsertTransaction(ctx, &rescanned)
+	suite.ErrorIs(err, model.ErrTransactionExists)
+
+	inserted, err := suite.adapter.InsertTransactions(ctx, []*model.Transaction{&rescanned})
+	suite.Require().NoError(err)
+	suite.Empty(inserted)
+
+	var hashes []string
+	err = suite.db.NewSelect().Model((*Transaction)(nil)).Column("hash").
+		Where("account_addr = ? AND lt = ?", legacy.AccountAddr, legacy.LT).
+		Scan(ctx, &hashes)
+	suite.Require().NoError(err)
+	suite.Equal([]string{rescanned.Hash}, hashes)
+}
+
+func (suite *RepositoryTestSuite) TestListTransactions() {
+	ctx := context.Background()
+	accountID := uuid.NewString()
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	for lt := int64(1); lt <= 3; lt++ {
+		_, err := suite.adapter.InsertTransaction(ctx, &model.Transaction{
+			AccountAddr: "list-account-addr",
+			LT:          lt,
+			Hash:        "list-hash-" + accountID + "-" + strconv.FormatInt(lt, 10),
+			Sender:      "list-sender",
+			Receiver:    "list-receiver",
+			AccountID:   accountID,
+			Direction:   model.DirectionIn,
+			Currency:    model.CurrencyTON,
+			Amount:      model.NewAmount(lt),
+			Success:     lt != 2,
+			CreatedAt:   createdAt,
+		})
+		suite.Require().NoError(err)
+	}
+
+	page, err := suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{AccountID: &accountID, Limit: 2})
+	suite.Require().NoError(err)
+	suite.Require().Len(page, 2)
+	suite.Equal(int64(3), page[0].LT)
+	suite.Equal(int64(2), page[1].LT)
+	suite.Equal(model.DirectionIn, page[0].Direction)
+
+	next, err := suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{
+		AccountID: &accountID,
+		Cursor:    model.EncodeTransactionCursor(page[1].ID),
+		Limit:     2,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(next, 1)
+	suite.Equal(int64(1), next[0].LT)
+
+	failed, err := suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{
+		AccountID: &accountID,
+		Success:   lo.ToPtr(false),
+		FromLT:    lo.ToPtr(int64(2)),
+		Limit:     10,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(failed, 1)
+	suite.Equal(int64(2), failed[0].LT)
+
+	_, err = suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{Cursor: "not a cursor", Limit: 1})
+	suite.ErrorIs(err, model.ErrInvalidCursor)
+}
+
+func (suite *RepositoryTestSuite) TestListTransactionsByAddress() {
+	ctx := context.Background()
+	accountID := uuid.NewString()
+
+	// the same wallet in the bounceable, the non-bounceable and the raw form
+	sender := "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z"
+	for lt, receiver := range []string{"0:01", "0:02"} {
+		_, err := suite.adapter.InsertTransaction(ctx, &model.Transaction{
+			AccountAddr: "address-account-addr",
+			LT:          int64(lt),
+			Hash:        "address-hash-" + accountID + "-" + strconv.Itoa(lt),
+			Sender:      sender,
+			Receiver:    receiver,
+			AccountID:   accountID,
+			Direction:   model.DirectionIn,
+			Currency:    model.CurrencyTON,
+			CreatedAt:   time.Now().UTC(),
+		})
+		suite.Require().NoError(err)
+	}
+
+	for _, addr := range []string{
+		sender,
+		"UQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO-Kc",
+		"0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b",
+	} {
+		found, err := suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{AccountID: &accountID, Address: &addr, Limit: 10})
+		suite.Require().NoError(err)
+		suite.Len(found, 2, addr)
+	}
+
+	receiver := "0:02"
+	found, err := suite.adapter.ListTransactions(ctx, model.ListTransactionsFilter{AccountID: &accountID, Address: &receiver, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(found, 1)
+	suite.Equal(int64(1), found[0].LT)
+}
+
+func (suite *RepositoryTestSuite) TestGetTransaction() {
+	ctx := context.Background()
+	accountID := uuid.NewString()
+
+	inserted, err := suite.adapter.InsertTransaction(ctx, &model.Transaction{
+		AccountAddr: "get-account-addr",
+		LT:          7,
+		Hash:        "get-hash-" + accountID,
+		AccountID:   accountID,
+		Direction:   model.DirectionIn,
+		Currency:    model.CurrencyTON,
+		Amount:      model.NewAmount(1_000),
+		CreatedAt:   time.Now().UTC(),
+	})
+	suite.Require().NoError(err)
+
+	byLT, err := suite.adapter.GetTransactionByLT(ctx, accountID, 7)
+	suite.Require().NoError(err)
+	suite.Equal(inserted.ID, byLT.ID)
+	suite.Equal("1000", byLT.Amount.Nano())
+
+	byHash, err := suite.adapter.GetTransactionByHash(ctx, "get-hash-"+accountID)
+	suite.Require().NoError(err)
+	suite.Equal(inserted.ID, byHash.ID)
+
+	_, err = suite.adapter.GetTransactionByLT(ctx, accountID, 8)
+	suite.ErrorIs(err, model.ErrTransactionNotFound)
+
+	_, err = suite.adapter.GetTransactionByHash(ctx, "unknown-hash")
+	suite.ErrorIs(err, model.ErrTransactionNotFound)
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-faster/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// InsertOutgoingTransfer registers the sent transfer, the addresses are stored in the raw form.
+// model.ErrTransferExists is returned if the transfer of the same message is already registered.
+func (d *DatabaseAdapter) InsertOutgoingTransfer(ctx context.Context, transfer *model.OutgoingTransfer) (*model.OutgoingTransfer, error) {
+	transferModel := fromModelOutgoingTransfer(transfer)
+	transferModel.From = common.NormalizeAddress(transferModel.From)
+	transferModel.To = common.NormalizeAddress(transferModel.To)
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(transferModel).
+		On("CONFLICT (message_hash, kind, reference) DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrTransferExists
+	}
+	return transferModel.toModel()
+}
+
+func (d *DatabaseAdapter) GetOutgoingTransferByMessageHash(ctx context.Context, messageHash string) (*model.OutgoingTransfer, error) {
+	var transfer OutgoingTransfer
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&transfer).Where("message_hash = ?", messageHash).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTransferNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return transfer.toModel()
+}
+
+// ListOutgoingTransfersByMessageHash returns the transfers of the message in the order of their registration,
+// the transfers of a highload batch share the message.
+func (d *DatabaseAdapter) ListOutgoingTransfersByMessageHash(ctx context.Context, messageHash string) ([]*model.OutgoingTransfer, error) {
+	var transfers []OutgoingTransfer
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&transfers).Where("message_hash = ?", messageHash).Order("id").Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.OutgoingTransfer, 0, len(transfers))
+	for i := range transfers {
+		item, err := transfers[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetOutgoingTransferByJettonQueryID returns the jetton transfer sent with the query id.
+// model.ErrTransferNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetOutgoingTransferByJettonQueryID(ctx context.Context, queryID uint64) (*model.OutgoingTransfer, error) {
+	var transfer OutgoingTransfer
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&transfer).Where("jetton_query_id = ?", queryID).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTransferNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return transfer.toModel()
+}
+
+// NextPayoutQueryID returns the next query id of the highload payout wallet.
+func (d *DatabaseAdapter) NextPayoutQueryID(ctx context.Context) (uint32, error) {
+	var queryID uint32
+	if err := d.GetTxOrConn(ctx).NewRaw("SELECT nextval('payout_query_ids')").Scan(ctx, &queryID); err != nil {
+		return 0, errors.Wrap(err, "select next query id")
+	}
+	return queryID, nil
+}
+
+// ReserveSeqno reserves the seqno of the sending wallet of the message until the transfers of the message are
+// settled. The reservation of the wallet is replaced by the reservation of a newer seqno, the wallet executed
+// the message of the older one, or once no transfer of its message is sent, model.ErrSeqnoReserved is returned otherwise.
+func (d *DatabaseAdapter) ReserveSeqno(ctx context.Context, message *model.WalletMessage) error {
+	result, err := d.GetTxOrConn(ctx).NewRaw(
+		`INSERT INTO wallet_seqnos (wallet, seqno, message_hash) VALUES (?, ?, ?)
+		ON CONFLICT (wallet) DO UPDATE SET seqno = EXCLUDED.seqno, message_hash = EXCLUDED.message_hash
+		WHERE wallet_seqnos.seqno < EXCLUDED.seqno OR NOT EXISTS (
+			SELECT 1 FROM outgoing_transfers
+			WHERE outgoing_transfers.message_hash = wallet_seqnos.message_hash AND outgoing_transfers.status = ?
+		)`,
+		common.NormalizeAddress(message.From), int64(message.Seqno), message.MessageHash, model.TransferSent,
+	).Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "upsert seqno")
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "rows affected")
+	}
+
+	if affected == 0 {
+		return errors.Wrapf(model.ErrSeqnoReserved, "seqno %d of %s", message.Seqno, message.From)
+	}
+	return nil
+}
+
+// GetBouncedTransferCandidate returns the oldest confirmed TON transfer between the wallets with the bounce hash,
+// the excluded transfers are skipped. model.ErrTransferNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetBouncedTransferCandidate(
+	ctx context.Context, from, to, bounceHash string, exclude []int64,
+) (*model.OutgoingTransfer, error) {
+	var transfer OutgoingTransfer
+	query := d.GetTxOrConn(ctx).NewSelect().Model(&transfer).
+		Where("from_addr = ?", common.NormalizeAddress(from)).
+		Where("to_addr = ?", common.NormalizeAddress(to)).
+		Where("bounce_hash = ?", bounceHash).
+		Where("status = ?", model.TransferConfirmed).
+		Where("currency = ?", model.CurrencyTON)
+
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN (?)", bun.In(exclude))
+	}
+
+	err := query.Order("id").
+		Limit(1).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTransferNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return transfer.toModel()
+}
+
+// UpdateOutgoingTransferStatus stores the status, transaction and fee of the transfer if it is still
+// in the previous status, model.ErrInvalidTransferTransition is returned if it was moved by someone else.
+func (d *DatabaseAdapter) UpdateOutgoingTransferStatus(
+	ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus,
+) error {
+	transferModel := fromModelOutgoingTransfer(transfer)
+
+	res, err := d.GetTxOrConn(ctx).NewUpdate().Model(transferModel).
+		Column("status", "tx_hash", "fee", "updated_at").
+		Where("id = ?", transfer.ID).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrInvalidTransferTransition, "transfer %d is not %s", transfer.ID, from)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (suite *RepositoryTestSuite) TestOutgoingTransfers() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	transfer := &model.OutgoingTransfer{
+		Kind:        model.TransferWithdrawal,
+		Reference:   "w-1",
+		AccountID:   "transfer-account",
+		From:        "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		To:          "0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63c",
+		Currency:    model.CurrencyTON,
+		Amount:      model.NewAmount(1_000),
+		MessageHash: "transfer-message-hash",
+		BounceHash:  "transfer-bounce-hash",
+		Status:      model.TransferSent,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	inserted, err := suite.adapter.InsertOutgoingTransfer(ctx, transfer)
+	suite.Require().NoError(err)
+	suite.NotZero(inserted.ID)
+	suite.Equal("0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b", inserted.From)
+
+	_, err = suite.adapter.InsertOutgoingTransfer(ctx, transfer)
+	suite.Require().ErrorIs(err, model.ErrTransferExists)
+
+	found, err := suite.adapter.GetOutgoingTransferByMessageHash(ctx, "transfer-message-hash")
+	suite.Require().NoError(err)
+	suite.Equal(inserted.ID, found.ID)
+	suite.Equal("1000", found.Amount.Nano())
+
+	_, err = suite.adapter.GetBouncedTransferCandidate(ctx, transfer.From, transfer.To, "transfer-bounce-hash", nil)
+	suite.Require().ErrorIs(err, model.ErrTransferNotFound)
+
+	found.Status, found.TxHash, found.Fee = model.TransferConfirmed, "transfer-tx-hash", model.NewAmount(5)
+	suite.Require().NoError(suite.adapter.UpdateOutgoingTransferStatus(ctx, found, model.TransferSent))
+	suite.Require().ErrorIs(suite.adapter.UpdateOutgoingTransferStatus(ctx, found, model.TransferSent), model.ErrInvalidTransferTransition)
+
+	candidate, err := suite.adapter.GetBouncedTransferCandidate(ctx, transfer.From, transfer.To, "transfer-bounce-hash", nil)
+	suite.Require().NoError(err)
+	suite.Equal(inserted.ID, candidate.ID)
+	suite.Equal("transfer-bounce-hash", candidate.BounceHash)
+	suite.Equal("transfer-tx-hash", candidate.TxHash)
+	suite.Equal("5", candidate.Fee.Nano())
+
+	_, err = suite.adapter.GetBouncedTransferCandidate(ctx, transfer.From, transfer.To, "other-bounce-hash", nil)
+	suite.Require().ErrorIs(err, model.ErrTransferNotFound)
+
+	_, err = suite.adapter.GetBouncedTransferCandidate(ctx, transfer.From, transfer.To, "transfer-bounce-hash", []int64{inserted.ID})
+	suite.Require().ErrorIs(err, model.ErrTransferNotFound)
+
+	_, err = suite.adapter.GetOutgoingTransferByMessageHash(ctx, "unknown-message-hash")
+	suite.Require().ErrorIs(err, model.ErrTransferNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestOutgoingTransferBatch() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	var ids []int64
+	for _, reference := range []string{"b-1", "b-2"} {
+		inserted, err := suite.adapter.InsertOutgoingTransfer(ctx, &model.OutgoingTransfer{
+			Kind:        model.TransferWithdrawal,
+			Reference:   reference,
+			AccountID:   "transfer-account",
+			From:        "0:03",
+			To:          "0:40",
+			Currency:    model.CurrencyTON,
+			Amount:      model.NewAmount(1_000),
+			MessageHash: "batch-message-hash",
+			Status:      model.TransferSent,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		suite.Require().NoError(err)
+		ids = append(ids, inserted.ID)
+	}
+
+	transfers, err := suite.adapter.ListOutgoingTransfersByMessageHash(ctx, "batch-message-hash")
+	suite.Require().NoError(err)
+	suite.Require().Len(transfers, 2)
+	suite.Equal(ids[0], transfers[0].ID)
+	suite.Equal("b-2", transfers[1].Reference)
+
+	transfers, err = suite.adapter.ListOutgoingTransfersByMessageHash(ctx, "unknown-message-hash")
+	suite.Require().NoError(err)
+	suite.Empty(transfers)
+
+	first, err := suite.adapter.NextPayoutQueryID(ctx)
+	suite.Require().NoError(err)
+	second, err := suite.adapter.NextPayoutQueryID(ctx)
+	suite.Require().NoError(err)
+	suite.Equal(first+1, second)
+}
+
+func (suite *RepositoryTestSuite) TestReserveSeqno() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	wallet := "0:5eed"
+
+	message := func(seqno uint32, hash string) *model.WalletMessage {
+		return &model.WalletMessage{From: wallet, Seqno: seqno, MessageHash: hash, ExpiresAt: now.Add(-time.Minute)}
+	}
+
+	transfer, err := suite.adapter.InsertOutgoingTransfer(ctx, &model.OutgoingTransfer{
+		Kind:        model.TransferRebalance,
+		Reference:   "seqno-5",
+		From:        wallet,
+		To:          "0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63c",
+		Currency:    model.CurrencyTON,
+		Amount:      model.NewAmount(1_000),
+		MessageHash: "seqno-5-first",
+		Status:      model.TransferSent,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.adapter.ReserveSeqno(ctx, message(5, "seqno-5-first")))
+	suite.ErrorIs(suite.adapter.ReserveSeqno(ctx, message(4, "seqno-4")), model.ErrSeqnoReserved)
+	// the expired message of seqno 5 holds the seqno while its transfer is sent
+	suite.ErrorIs(suite.adapter.ReserveSeqno(ctx, message(5, "seqno-5-second")), model.ErrSeqnoReserved)
+
+	suite.Require().NoError(transfer.Transition(model.TransferFailed, now))
+	suite.Require().NoError(suite.adapter.UpdateOutgoingTransferStatus(ctx, transfer, model.TransferSent))
+	suite.Require().NoError(suite.adapter.ReserveSeqno(ctx, message(5, "seqno-5-second")))
+
+	// the message of seqno 5 is executed, so the wallet signs the next one with seqno 6
+	suite.Require().NoError(suite.adapter.ReserveSeqno(ctx, message(6, "seqno-6")))
+
+	suite.Require().NoError(suite.adapter.ReserveSeqno(ctx, &model.WalletMessage{From: "0:0a", Seqno: 1}))
+}
+
+func (suite *RepositoryTestSuite) TestOutgoingTransferByJettonQueryID() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	inserted, err := suite.adapter.InsertOutgoingTransfer(ctx, &model.OutgoingTransfer{
+		Kind:          model.TransferSweep,
+		Reference:     "jetton-sweep",
+		AccountID:     "jetton-account",
+		From:          "0:01",
+		To:            "0:02",
+		Currency:      model.CurrencyUSDT,
+		Amount:        model.NewAmount(5_000),
+		Gas:           model.NewAmount(300),
+		MessageHash:   "jetton-message-hash",
+		JettonQueryID: 1<<62 + 7,
+		Status:        model.TransferSent,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	suite.Require().NoError(err)
+
+	found, err := suite.adapter.GetOutgoingTransferByJettonQueryID(ctx, 1<<62+7)
+	suite.Require().NoError(err)
+	suite.Equal(inserted.ID, found.ID)
+	suite.Equal(uint64(1<<62+7), found.JettonQueryID)
+
+	_, err = suite.adapter.GetOutgoingTransferByJettonQueryID(ctx, 8)
+	suite.Require().ErrorIs(err, model.ErrTransferNotFound)
+}
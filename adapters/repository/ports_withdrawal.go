@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-faster/errors"
+	"github.com/uptrace/bun"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// InsertWithdrawal inserts the withdrawal, model.ErrWithdrawalExists is returned if the account already has
+// a withdrawal with the idempotency key. The destination is stored as requested, so its bounce flag is kept.
+func (d *DatabaseAdapter) InsertWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error) {
+	withdrawalModel := fromModelWithdrawal(withdrawal)
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(withdrawalModel).
+		On("CONFLICT (account_id, idempotency_key) DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrWithdrawalExists
+	}
+	return withdrawalModel.toModel()
+}
+
+// GetWithdrawal returns the withdrawal by its id, model.ErrWithdrawalNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	var withdrawal Withdrawal
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&withdrawal).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return withdrawal.toModel()
+}
+
+// GetWithdrawalForUpdate returns the withdrawal by its id and locks it until the end of the transaction,
+// model.ErrWithdrawalNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetWithdrawalForUpdate(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	var withdrawal Withdrawal
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&withdrawal).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return withdrawal.toModel()
+}
+
+// GetWithdrawalByIdempotencyKey returns the withdrawal of the account with the idempotency key,
+// model.ErrWithdrawalNotFound is returned if there is none.
+func (d *DatabaseAdapter) GetWithdrawalByIdempotencyKey(
+	ctx context.Context, accountID model.AccountID, key string,
+) (*model.Withdrawal, error) {
+	var withdrawal Withdrawal
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&withdrawal).
+		Where("account_id = ?", accountID).
+		Where("idempotency_key = ?", key).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		return nil, errors.Wrap(err, "select exec")
+	}
+	return withdrawal.toModel()
+}
+
+// ListWithdrawals returns the withdrawals matching the filter ordered by id from the newest,
+// the cursor continues after the withdrawal it was issued for.
+func (d *DatabaseAdapter) ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) ([]*model.Withdrawal, error) {
+	cursor, err := model.DecodeWithdrawalCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var withdrawals []Withdrawal
+	query := d.GetTxOrConn(ctx).NewSelect().Model(&withdrawals)
+
+	if cursor > 0 {
+		query.Where("id < ?", cursor)
+	}
+
+	if filter.AccountID != nil {
+		query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	if filter.Status != nil {
+		query.Where("status = ?", *filter.Status)
+	}
+
+	if err = query.Order("id DESC").Limit(filter.Limit).Scan(ctx); err != nil {
+		return nil, errors.Wrap(err, "select scan")
+	}
+
+	result := make([]*model.Withdrawal, 0, len(withdrawals))
+	for i := range withdrawals {
+		item, err := withdrawals[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (d *DatabaseAdapter) ListActiveWithdrawals(ctx context.Context, afterID int64, limit int) ([]*model.Withdrawal, error) {
+	var withdrawals []Withdrawal
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&withdrawals).
+		Where("status IN (?)", bun.In(model.ActiveWithdrawalStatuses)).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.Withdrawal, 0, len(withdrawals))
+	for i := range withdrawals {
+		item, err := withdrawals[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetPendingWithdrawalAmount returns the sum of the active withdrawals of the account in the currency.
+func (d *DatabaseAdapter) GetPendingWithdrawalAmount(
+	ctx context.Context, accountID model.AccountID, currency model.Currency,
+) (model.Amount, error) {
+	var amount string
+	err := d.GetTxOrConn(ctx).NewSelect().Model((*Withdrawal)(nil)).
+		ColumnExpr("COALESCE(SUM(amount), 0)::TEXT").
+		Where("account_id = ?", accountID).
+		Where("currency = ?", currency).
+		Where("status IN (?)", bun.In(model.ActiveWithdrawalStatuses)).
+		Scan(ctx, &amount)
+	if err != nil {
+		return model.Amount{}, errors.Wrap(err, "select pending amount")
+	}
+	return toModelAmount(amount)
+}
+
+// UpdateWithdrawal stores the status, the attempts, the timestamps and the message of the withdrawal if it is
+// still in the previous status, model.ErrInvalidWithdrawalTransition is returned if it was moved by someone else.
+func (d *DatabaseAdapter) UpdateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus) error {
+	withdrawalModel := fromModelWithdrawal(withdrawal)
+	withdrawalModel.From = common.NormalizeAddress(withdrawalModel.From)
+
+	res, err := d.GetTxOrConn(ctx).NewUpdate().Model(withdrawalModel).
+		Column("status", "attempts", "error", "hold_reason", "tx_hash", "updated_at").
+		Column("approved_at", "signed_at", "broadcast_at", "confirmed_at", "failed_at").
+		Column("from_addr", "gas", "seqno", "query_id", "message_hash", "bounce_hash", "jetton_query_id", "boc", "expires_at").
+		Where("id = ?", withdrawal.ID).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrInvalidWithdrawalTransition, "withdrawal %d is not %s", withdrawal.ID, from)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// InsertWithdrawalApproval stores the decision of the operator on the withdrawal,
+// model.ErrWithdrawalApprovalExists is returned if the operator already decided on it.
+func (d *DatabaseAdapter) InsertWithdrawalApproval(
+	ctx context.Context, approval *model.WithdrawalApproval,
+) (*model.WithdrawalApproval, error) {
+	approvalModel := fromModelWithdrawalApproval(approval)
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(approvalModel).
+		On("CONFLICT (withdrawal_id, operator) DO NOTHING").
+		Returning("id").Exec(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, model.ErrWithdrawalApprovalExists
+	}
+	return approvalModel.toModel(), nil
+}
+
+// ListWithdrawalApprovals returns the decisions on the withdrawal in the order they were made.
+func (d *DatabaseAdapter) ListWithdrawalApprovals(ctx context.Context, withdrawalID int64) ([]*model.WithdrawalApproval, error) {
+	var approvals []WithdrawalApproval
+	err := d.GetTxOrConn(ctx).NewSelect().Model(&approvals).
+		Where("withdrawal_id = ?", withdrawalID).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "select exec")
+	}
+
+	result := make([]*model.WithdrawalApproval, 0, len(approvals))
+	for i := range approvals {
+		result = append(result, approvals[i].toModel())
+	}
+	return result, nil
+}
+
+// AddWithdrawalLimitUsage adds the amount to the counter if the counted amount stays within the limit,
+// model.ErrWithdrawalLimitExceeded is returned otherwise. The check and the addition are a single statement,
+// so the concurrent withdrawals can not exceed the limit together.
+func (d *DatabaseAdapter) AddWithdrawalLimitUsage(
+	ctx context.Context, counter model.WithdrawalLimitCounter, amount, limit model.Amount,
+) error {
+	if amount.Cmp(limit) > 0 {
+		return errors.Wrapf(model.ErrWithdrawalLimitExceeded, "%s of %s", counter.Scope, counter.Currency)
+	}
+
+	counterModel := &WithdrawalLimitCounter{
+		Scope:     counter.Scope,
+		Currency:  string(counter.Currency),
+		Day:       model.LimitDay(counter.Day),
+		Amount:    amount.Nano(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	res, err := d.GetTxOrConn(ctx).NewInsert().Model(counterModel).
+		On("CONFLICT (scope, currency, day) DO UPDATE").
+		Set("amount = counter.amount + EXCLUDED.amount").
+		Set("updated_at = EXCLUDED.updated_at").
+		Where("counter.amount + EXCLUDED.amount <= ?::NUMERIC", limit.Nano()).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "upsert exec")
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return errors.Wrapf(model.ErrWithdrawalLimitExceeded, "%s of %s", counter.Scope, counter.Currency)
+	}
+	return nil
+}
+
+// ReleaseWithdrawalLimitUsage subtracts the amount of a withdrawal that was not sent from the counter.
+func (d *DatabaseAdapter) ReleaseWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount) error {
+	_, err := d.GetTxOrConn(ctx).NewUpdate().Model((*WithdrawalLimitCounter)(nil)).
+		Set("amount = GREATEST(amount - ?::NUMERIC, 0)", amount.Nano()).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("scope = ?", counter.Scope).
+		Where("currency = ?", counter.Currency).
+		Where("day = ?", model.LimitDay(counter.Day)).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update exec")
+	}
+	return nil
+}
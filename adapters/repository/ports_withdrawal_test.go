@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func (suite *RepositoryTestSuite) TestWithdrawals() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	withdrawal, err := suite.adapter.InsertWithdrawal(ctx, &model.Withdrawal{
+		AccountID:      "withdrawal-account",
+		IdempotencyKey: "payout-1",
+		To:             "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		Currency:       model.CurrencyTON,
+		Amount:         model.NewAmount(700),
+		Comment:        "invoice 42",
+		Status:         model.WithdrawalRequested,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	suite.Require().NoError(err)
+	suite.NotZero(withdrawal.ID)
+
+	duplicate := *withdrawal
+	_, err = suite.adapter.InsertWithdrawal(ctx, &duplicate)
+	suite.Require().ErrorIs(err, model.ErrWithdrawalExists)
+
+	stored, err := suite.adapter.GetWithdrawalByIdempotencyKey(ctx, "withdrawal-account", "payout-1")
+	suite.Require().NoError(err)
+	suite.Equal(withdrawal.ID, stored.ID)
+	suite.Equal("EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z", stored.To)
+	suite.Equal("invoice 42", stored.Comment)
+
+	pending, err := suite.adapter.GetPendingWithdrawalAmount(ctx, "withdrawal-account", model.CurrencyTON)
+	suite.Require().NoError(err)
+	suite.Equal("700", pending.Nano())
+
+	withdrawal.HoldReason = "awaiting approvals: 0 of 1"
+	suite.Require().NoError(suite.adapter.UpdateWithdrawal(ctx, withdrawal, model.WithdrawalRequested))
+
+	locked, err := suite.adapter.GetWithdrawalForUpdate(ctx, withdrawal.ID)
+	suite.Require().NoError(err)
+	suite.Equal("awaiting approvals: 0 of 1", locked.HoldReason)
+	suite.Empty(locked.Error)
+
+	suite.Require().NoError(withdrawal.Transition(model.WithdrawalApproved, now))
+	suite.Require().NoError(suite.adapter.UpdateWithdrawal(ctx, withdrawal, model.WithdrawalRequested))
+
+	withdrawal.SetMessage(&model.WalletMessage{
+		From:        "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		Seqno:       12,
+		MessageHash: "withdrawal-message-hash",
+		BOC:         []byte{7, 8},
+		ExpiresAt:   now.Add(3 * time.Minute),
+	})
+	suite.Require().NoError(withdrawal.Transition(model.WithdrawalSigned, now))
+	suite.Require().NoError(suite.adapter.UpdateWithdrawal(ctx, withdrawal, model.WithdrawalApproved))
+	suite.Require().ErrorIs(
+		suite.adapter.UpdateWithdrawal(ctx, withdrawal, model.WithdrawalApproved), model.ErrInvalidWithdrawalTransition,
+	)
+
+	active, err := suite.adapter.ListActiveWithdrawals(ctx, withdrawal.ID-1, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(active, 1)
+	suite.Equal(model.WithdrawalSigned, active[0].Status)
+	suite.Equal("0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b", active[0].From)
+	suite.Equal(uint32(12), active[0].Seqno)
+	suite.Equal([]byte{7, 8}, active[0].BOC)
+	suite.NotNil(active[0].ApprovedAt)
+	suite.Empty(active[0].HoldReason)
+
+	withdrawal.TxHash = "withdrawal-tx-hash"
+	suite.Require().NoError(withdrawal.Transition(model.WithdrawalConfirmed, now))
+	suite.Require().NoError(suite.adapter.UpdateWithdrawal(ctx, withdrawal, model.WithdrawalSigned))
+
+	pending, err = suite.adapter.GetPendingWithdrawalAmount(ctx, "withdrawal-account", model.CurrencyTON)
+	suite.Require().NoError(err)
+	suite.Equal("0", pending.Nano())
+
+	accountID := model.AccountID("withdrawal-account")
+	listed, err := suite.adapter.ListWithdrawals(ctx, model.ListWithdrawalsFilter{AccountID: &accountID, Limit: 10})
+	suite.Require().NoError(err)
+	suite.Require().Len(listed, 1)
+	suite.Equal("withdrawal-tx-hash", listed[0].TxHash)
+
+	listed, err = suite.adapter.ListWithdrawals(ctx, model.ListWithdrawalsFilter{
+		AccountID: &accountID, Cursor: model.EncodeWithdrawalCursor(withdrawal.ID), Limit: 10,
+	})
+	suite.Require().NoError(err)
+	suite.Empty(listed)
+
+	_, err = suite.adapter.GetWithdrawal(ctx, withdrawal.ID+1000)
+	suite.Require().ErrorIs(err, model.ErrWithdrawalNotFound)
+	_, err = suite.adapter.GetWithdrawalForUpdate(ctx, withdrawal.ID+1000)
+	suite.Require().ErrorIs(err, model.ErrWithdrawalNotFound)
+}
+
+func (suite *RepositoryTestSuite) TestWithdrawalApprovals() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	withdrawal, err := suite.adapter.InsertWithdrawal(ctx, &model.Withdrawal{
+		AccountID:      "approval-account",
+		IdempotencyKey: "payout-1",
+		To:             "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		Currency:       model.CurrencyTON,
+		Amount:         model.NewAmount(700),
+		Status:         model.WithdrawalRequested,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	suite.Require().NoError(err)
+
+	for _, operator := range []string{"alice", "bob"} {
+		approval, err := suite.adapter.InsertWithdrawalApproval(ctx, &model.WithdrawalApproval{
+			WithdrawalID: withdrawal.ID, Operator: operator, Decision: model.ApprovalApproved, CreatedAt: now,
+		})
+		suite.Require().NoError(err)
+		suite.NotZero(approval.ID)
+	}
+
+	_, err = suite.adapter.InsertWithdrawalApproval(ctx, &model.WithdrawalApproval{
+		WithdrawalID: withdrawal.ID, Operator: "alice", Decision: model.ApprovalRejected, CreatedAt: now,
+	})
+	suite.Require().ErrorIs(err, model.ErrWithdrawalApprovalExists)
+
+	approvals, err := suite.adapter.ListWithdrawalApprovals(ctx, withdrawal.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(approvals, 2)
+	suite.Equal("alice", approvals[0].Operator)
+	suite.Equal(model.ApprovalApproved, approvals[1].Decision)
+}
+
+func (suite *RepositoryTestSuite) TestWithdrawalLimits() {
+	ctx := context.Background()
+	counter := model.WithdrawalLimitCounter{Scope: "limit-account", Currency: model.CurrencyTON, Day: time.Now()}
+	limit := model.NewAmount(1000)
+
+	suite.Require().NoError(suite.adapter.AddWithdrawalLimitUsage(ctx, counter, model.NewAmount(600), limit))
+	suite.Require().NoError(suite.adapter.AddWithdrawalLimitUsage(ctx, counter, model.NewAmount(400), limit))
+	suite.Require().ErrorIs(
+		suite.adapter.AddWithdrawalLimitUsage(ctx, counter, model.NewAmount(1), limit), model.ErrWithdrawalLimitExceeded,
+	)
+
+	suite.Require().NoError(suite.adapter.ReleaseWithdrawalLimitUsage(ctx, counter, model.NewAmount(400)))
+	suite.Require().NoError(suite.adapter.AddWithdrawalLimitUsage(ctx, counter, model.NewAmount(400), limit))
+
+	tomorrow := counter
+	tomorrow.Day = counter.Day.Add(24 * time.Hour)
+	suite.Require().ErrorIs(
+		suite.adapter.AddWithdrawalLimitUsage(ctx, tomorrow, model.NewAmount(1001), limit), model.ErrWithdrawalLimitExceeded,
+	)
+	suite.Require().NoError(suite.adapter.AddWithdrawalLimitUsage(ctx, tomorrow, model.NewAmount(1000), limit))
+}
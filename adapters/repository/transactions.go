@@ -32,7 +32,7 @@ func (t TxRepository) Begin(ctx context.Context) (context.Context, error) {
 	if err != nil {
 		return ctx, errors.Wrap(err, "begin tx")
 	}
-	return context.WithValue(ctx, contextKey{}, tx), nil
+	return context.WithValue(ctx, contextKey{}, &tx), nil
 }
 
 func (t TxRepository) Commit(ctx context.Context) error {
@@ -97,7 +97,7 @@ func (t TxRepository) WithInTransactionWithOptions(
 		}
 	}()
 
-	err = txFunc(inject(ctx, tx))
+	err = txFunc(inject(ctx, &tx))
 	return err
 }
 
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+)
+
+func (suite *RepositoryTestSuite) TestWithInTransaction() {
+	ctx := context.Background()
+	txRepository := NewTxRepository(suite.db)
+
+	errAbort := errors.New("abort")
+	err := txRepository.WithInTransaction(ctx, func(ctx context.Context) error {
+		_, err := suite.adapter.InsertAccount(ctx, "rolled-back-account")
+		suite.Require().NoError(err)
+		return errAbort
+	})
+	suite.Require().ErrorIs(err, errAbort)
+
+	exists, err := suite.adapter.IsAccountExists(ctx, "rolled-back-account")
+	suite.Require().NoError(err)
+	suite.False(exists, "the insert must be rolled back with the transaction")
+
+	err = txRepository.WithInTransaction(ctx, func(ctx context.Context) error {
+		_, err := suite.adapter.InsertAccount(ctx, "committed-account")
+		return err
+	})
+	suite.Require().NoError(err)
+
+	exists, err = suite.adapter.IsAccountExists(ctx, "committed-account")
+	suite.Require().NoError(err)
+	suite.True(exists)
+}
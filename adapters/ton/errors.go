@@ -3,5 +3,6 @@ package ton
 import "github.com/go-faster/errors"
 
 var (
-	ErrWalletIsEmpty = errors.New("wallet is empty")
+	ErrWalletIsEmpty   = errors.New("wallet is empty")
+	ErrWalletWatchOnly = errors.New("wallet is watch-only")
 )
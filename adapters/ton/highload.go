@@ -0,0 +1,367 @@
+package ton
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/jetton"
+	"github.com/xssnick/tonutils-go/ton/wallet"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+// createdAtLag is subtracted from the creation time of a batch, the wallet rejects the messages created
+// later than the time of the block processing them.
+const createdAtLag = 10 * time.Second
+
+var _ ports.PayoutWalletPort = (*HighloadWalletAdapter)(nil)
+
+type HighloadOptions struct {
+	// Subwallet is the subwallet id of the payout wallet, wallet.DefaultSubwallet if it is 0.
+	Subwallet uint32
+	// MessageTTL is the validity of the signed batches, the wallet remembers their query ids at least as long.
+	// The address of the wallet depends on the subwallet and the TTL.
+	MessageTTL time.Duration
+}
+
+func (o *HighloadOptions) SetDefaults() {
+	if o.Subwallet == 0 {
+		o.Subwallet = wallet.DefaultSubwallet
+	}
+	if o.MessageTTL == 0 {
+		o.MessageTTL = messageTTL
+	}
+}
+
+// HighloadWalletAdapter sends the payouts from a highload v3 wallet. The wallet executes the batch signed
+// with a query id once while the batch is valid, so the batches are not serialized by a seqno and a batch
+// sent again is not executed twice. A batch of several transfers is executed by the message the wallet sends
+// to itself, its transfers are sent all together or none of them.
+type HighloadWalletAdapter struct {
+	api       APIClientWrapped
+	key       ed25519.PrivateKey
+	subwallet uint32
+	ttl       time.Duration
+	address   *address.Address
+	jettons   map[model.Currency]*address.Address // Jetton master contracts by the currency
+}
+
+func NewHighloadWalletAdapter(api APIClientWrapped, key ed25519.PrivateKey, opts *HighloadOptions) (*HighloadWalletAdapter, error) {
+	opts.SetDefaults()
+
+	w := &HighloadWalletAdapter{
+		api:       api,
+		key:       key,
+		subwallet: opts.Subwallet,
+		ttl:       opts.MessageTTL,
+		jettons:   make(map[model.Currency]*address.Address),
+	}
+
+	payoutWallet, err := w.wallet(0, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	w.address = payoutWallet.WalletAddress()
+	return w, nil
+}
+
+// AddJetton registers the jetton master contract of the currency, the jetton wallet of the payout wallet
+// is resolved by it.
+func (w *HighloadWalletAdapter) AddJetton(currency model.Currency, master string) error {
+	addr, err := address.ParseAddr(master)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s jetton master", currency)
+	}
+	w.jettons[currency] = addr
+	return nil
+}
+
+// WalletAddress returns the address of the payout wallet.
+func (w *HighloadWalletAdapter) WalletAddress() *address.Address {
+	return w.address
+}
+
+// PreparePayouts signs the batch of the payouts with the query id and returns the message of every payout
+// in their order. The messages share the external message of the batch and the hash of the body executing it.
+// A TON payout bounces by the flag of the destination address and carries the comment, a jetton payout carries
+// the gas to the jetton wallet of the payout wallet and its excess is returned to the payout wallet.
+func (w *HighloadWalletAdapter) PreparePayouts(
+	ctx context.Context, queryID uint32, payouts []model.Payout,
+) ([]*model.WalletMessage, error) {
+	if len(payouts) == 0 || len(payouts) > model.MaxPayoutBatch {
+		return nil, errors.Errorf("batch of %d payouts, expected 1 to %d", len(payouts), model.MaxPayoutBatch)
+	}
+
+	if queryID > model.MaxHighloadQueryID {
+		return nil, errors.Errorf("query id %d is out of range", queryID)
+	}
+
+	createdAt := time.Now().Add(-createdAtLag).UTC()
+	payoutWallet, err := w.wallet(queryID, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get masterchain info")
+	}
+
+	account, err := w.api.WaitForBlock(block.SeqNo).GetAccount(ctx, block, w.address)
+	if err != nil {
+		return nil, errors.Wrap(err, "get account")
+	}
+
+	balance := big.NewInt(0)
+	active := account.IsActive && account.State != nil && account.State.Status == tlb.AccountStatusActive
+	if account.IsActive && account.State != nil {
+		balance = account.State.Balance.Nano()
+	}
+
+	var (
+		required      = big.NewInt(0)
+		jettonWallets = make(map[model.Currency]*jetton.WalletClient)
+		jettonAmounts = make(map[model.Currency]*big.Int)
+		transfers     = make([]*wallet.Message, 0, len(payouts))
+		result        = make([]*model.WalletMessage, 0, len(payouts))
+	)
+
+	for _, payout := range payouts {
+		destination, err := address.ParseAddr(payout.To)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse destination %s", payout.To)
+		}
+
+		var (
+			gas           = model.NewAmount(0)
+			transfer      *wallet.Message
+			jettonQueryID int64
+		)
+
+		if payout.Amount.Currency == model.CurrencyTON {
+			if transfer, err = w.tonPayout(destination, payout); err != nil {
+				return nil, err
+			}
+		} else {
+			jettonWallet, ok := jettonWallets[payout.Amount.Currency]
+			if !ok {
+				if jettonWallet, err = resolveJettonWallet(ctx, w.api, w.jettons, payout.Amount.Currency, w.address); err != nil {
+					return nil, err
+				}
+				jettonWallets[payout.Amount.Currency] = jettonWallet
+				jettonAmounts[payout.Amount.Currency] = big.NewInt(0)
+			}
+
+			jettonQueryID = newJettonQueryID()
+			if transfer, err = w.jettonPayout(jettonWallet, destination, payout, jettonQueryID); err != nil {
+				return nil, err
+			}
+			jettonAmounts[payout.Amount.Currency].Add(jettonAmounts[payout.Amount.Currency], payout.Amount.Amount.BigInt())
+			gas = payout.Gas
+		}
+
+		bounceHash, err := transferBounceHash(transfer)
+		if err != nil {
+			return nil, err
+		}
+
+		required.Add(required, transfer.InternalMessage.Amount.Nano())
+		transfers = append(transfers, transfer)
+		result = append(result, &model.WalletMessage{
+			From:          w.address.StringRaw(),
+			To:            destination.StringRaw(),
+			Currency:      payout.Amount.Currency,
+			Amount:        payout.Amount.Amount,
+			Gas:           gas,
+			QueryID:       queryID,
+			BounceHash:    bounceHash,
+			JettonQueryID: uint64(jettonQueryID),
+		})
+	}
+
+	if balance.Cmp(required) <= 0 {
+		return nil, errors.New("insufficient balance in payout wallet")
+	}
+
+	for currency, amount := range jettonAmounts {
+		jettonBalance, err := jettonWallets[currency].GetBalance(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "get jetton balance")
+		}
+
+		if jettonBalance.Cmp(amount) < 0 {
+			return nil, errors.Errorf("insufficient %s balance in payout wallet", currency)
+		}
+	}
+
+	external, err := payoutWallet.PrepareExternalMessageForMany(ctx, !active, transfers)
+	if err != nil {
+		return nil, errors.Wrap(err, "build external message")
+	}
+
+	externalCell, err := tlb.ToCell(external)
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize external message")
+	}
+
+	messageHash, err := batchMessageHash(external, len(transfers))
+	if err != nil {
+		return nil, err
+	}
+
+	boc, expiresAt := externalCell.ToBOC(), createdAt.Add(w.ttl)
+	for _, message := range result {
+		message.MessageHash, message.BOC, message.ExpiresAt = messageHash, boc, expiresAt
+	}
+	return result, nil
+}
+
+// tonPayout builds the TON transfer of the payout. The transfers of a batch do not ignore the errors,
+// so the wallet fails the batch as a whole.
+func (w *HighloadWalletAdapter) tonPayout(destination *address.Address, payout model.Payout) (*wallet.Message, error) {
+	var body *cell.Cell
+	if payout.Comment != "" {
+		comment, err := wallet.CreateCommentCell(payout.Comment)
+		if err != nil {
+			return nil, errors.Wrap(err, "create comment")
+		}
+		body = comment
+	}
+
+	return &wallet.Message{
+		Mode: wallet.PayGasSeparately,
+		InternalMessage: &tlb.InternalMessage{
+			IHRDisabled: true,
+			Bounce:      destination.IsBounceable(),
+			DstAddr:     destination,
+			Amount:      tlb.FromNanoTON(payout.Amount.Amount.BigInt()),
+			Body:        body,
+		},
+	}, nil
+}
+
+// jettonPayout builds the jetton transfer of the payout with the query id, the comment is forwarded
+// to the destination with a notification.
+func (w *HighloadWalletAdapter) jettonPayout(
+	jettonWallet *jetton.WalletClient, destination *address.Address, payout model.Payout, queryID int64,
+) (*wallet.Message, error) {
+	if payout.Gas.Sign() <= 0 {
+		return nil, errors.Errorf("no gas for the %s payout", payout.Amount.Currency)
+	}
+
+	forward := tlb.ZeroCoins
+	if payout.Comment != "" {
+		forward = tlb.FromNanoTONU(1)
+	}
+
+	body, err := MakeJettonTransferMessage(destination, w.address, payout.Amount.Amount.BigInt(), forward, queryID, payout.Comment, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "make jetton transfer message")
+	}
+
+	return &wallet.Message{
+		Mode: wallet.PayGasSeparately,
+		InternalMessage: &tlb.InternalMessage{
+			IHRDisabled: true,
+			Bounce:      true,
+			DstAddr:     jettonWallet.Address(),
+			Amount:      tlb.FromNanoTON(payout.Gas.BigInt()),
+			Body:        body,
+		},
+	}, nil
+}
+
+// batchMessageHash returns the hash of the body executing the transfers of the external message. The wallet
+// sends a single transfer itself, several transfers are sent by the batch message the wallet sends to itself.
+func batchMessageHash(external *tlb.ExternalMessage, transfers int) (string, error) {
+	if transfers == 1 {
+		return hex.EncodeToString(external.Body.Hash()), nil
+	}
+
+	payload, err := external.Body.PeekRef(0)
+	if err != nil {
+		return "", errors.Wrap(err, "load batch payload")
+	}
+
+	batchCell, err := payload.PeekRef(0)
+	if err != nil {
+		return "", errors.Wrap(err, "load batch message")
+	}
+
+	var batch tlb.InternalMessage
+	if err = tlb.LoadFromCell(&batch, batchCell.BeginParse()); err != nil {
+		return "", errors.Wrap(err, "parse batch message")
+	}
+	return hex.EncodeToString(batch.Body.Hash()), nil
+}
+
+// wallet returns the payout wallet signing its message with the query id and the creation time.
+func (w *HighloadWalletAdapter) wallet(queryID uint32, createdAt time.Time) (*wallet.Wallet, error) {
+	config := wallet.ConfigHighloadV3{
+		MessageTTL: uint32(w.ttl.Seconds()),
+		MessageBuilder: func(context.Context, uint32) (uint32, int64, error) {
+			return queryID, createdAt.Unix(), nil
+		},
+	}
+
+	highload, err := wallet.FromPrivateKey(w.api, w.key, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "create payout wallet")
+	}
+
+	payoutWallet, err := highload.GetSubwallet(w.subwallet)
+	if err != nil {
+		return nil, errors.Wrap(err, "get payout subwallet")
+	}
+	return payoutWallet, nil
+}
+
+// IsQueryProcessed runs the processed? get method of the payout wallet, the wallet remembers the query ids
+// of the executed batches while they may be sent again. A wallet that is not deployed processed nothing.
+func (w *HighloadWalletAdapter) IsQueryProcessed(ctx context.Context, queryID uint32) (bool, error) {
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get masterchain info")
+	}
+
+	res, err := w.api.WaitForBlock(block.SeqNo).RunGetMethod(ctx, block, w.address, "processed?", uint64(queryID), 0)
+	if err != nil {
+		var execErr ton.ContractExecError
+		if errors.As(err, &execErr) && execErr.Code == ton.ErrCodeContractNotInitialized {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "run processed? method")
+	}
+
+	processed, err := res.Int(0)
+	if err != nil {
+		return false, errors.Wrap(err, "parse processed")
+	}
+	return processed.Sign() != 0, nil
+}
+
+// SendWalletMessage sends the batch of the message without waiting for its transactions.
+func (w *HighloadWalletAdapter) SendWalletMessage(ctx context.Context, message *model.WalletMessage) error {
+	if err := sendExternal(ctx, w.api, message.BOC); err != nil {
+		return err
+	}
+
+	log.Info().
+		Uint32("query_id", message.QueryID).
+		Str("from_address", message.From).
+		Str("message_hash", message.MessageHash).
+		Msg("payout batch sent")
+
+	return nil
+}
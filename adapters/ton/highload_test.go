@@ -0,0 +1,172 @@
+package ton
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// payoutAPI serves the account of the payout wallet, the other methods are not used by the TON payouts.
+type payoutAPI struct {
+	ton.APIClientWrapped
+	balance tlb.Coins
+}
+
+func (a *payoutAPI) CurrentMasterchainInfo(context.Context) (*ton.BlockIDExt, error) {
+	return &ton.BlockIDExt{SeqNo: 1}, nil
+}
+
+func (a *payoutAPI) WaitForBlock(uint32) ton.APIClientWrapped {
+	return a
+}
+
+func (a *payoutAPI) GetAccount(context.Context, *ton.BlockIDExt, *address.Address) (*tlb.Account, error) {
+	return &tlb.Account{
+		IsActive: true,
+		State: &tlb.AccountState{
+			IsValid:        true,
+			AccountStorage: tlb.AccountStorage{Status: tlb.AccountStatusActive, Balance: a.balance},
+		},
+	}, nil
+}
+
+func newTestHighloadWallet(t *testing.T, balance tlb.Coins) *HighloadWalletAdapter {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	w, err := NewHighloadWalletAdapter(&payoutAPI{balance: balance}, key, &HighloadOptions{})
+	require.NoError(t, err)
+	return w
+}
+
+func tonPayout(to string, nano int64) model.Payout {
+	return model.Payout{To: to, Amount: model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}}
+}
+
+func TestBatchMessageHash(t *testing.T) {
+	t.Parallel()
+
+	batchBody := cell.BeginCell().MustStoreUInt(uint64(model.OpHighloadBatch), 32).MustStoreUInt(9, 64).EndCell()
+	batchMessage, err := tlb.ToCell(&tlb.InternalMessage{
+		IHRDisabled: true,
+		SrcAddr:     address.NewAddressNone(),
+		DstAddr:     address.MustParseRawAddr("0:" + hex.EncodeToString(make([]byte, 32))),
+		Amount:      tlb.ZeroCoins,
+		Body:        batchBody,
+	})
+	require.NoError(t, err)
+
+	payload := cell.BeginCell().MustStoreRef(batchMessage).EndCell()
+	body := cell.BeginCell().MustStoreSlice(make([]byte, 64), 512).MustStoreRef(payload).EndCell()
+	external := &tlb.ExternalMessage{Body: body}
+
+	hash, err := batchMessageHash(external, 1)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(body.Hash()), hash)
+
+	hash, err = batchMessageHash(external, 2)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(batchBody.Hash()), hash)
+
+	_, err = batchMessageHash(&tlb.ExternalMessage{Body: cell.BeginCell().EndCell()}, 2)
+	require.Error(t, err)
+}
+
+func TestHighloadWalletAdapter_PreparePayouts(t *testing.T) {
+	t.Parallel()
+
+	receiver := address.MustParseRawAddr("0:" + hex.EncodeToString(make([]byte, 32)))
+
+	t.Run("batch of payouts", func(t *testing.T) {
+		t.Parallel()
+
+		w := newTestHighloadWallet(t, tlb.MustFromTON("10"))
+		messages, err := w.PreparePayouts(context.Background(), 42, []model.Payout{
+			tonPayout(receiver.String(), 1000), tonPayout(receiver.String(), 2000),
+		})
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+
+		for i, message := range messages {
+			require.Equal(t, w.WalletAddress().StringRaw(), message.From)
+			require.Equal(t, receiver.StringRaw(), message.To)
+			require.Equal(t, model.NewAmount(int64(1000*(i+1))), message.Amount)
+			require.Equal(t, uint32(42), message.QueryID)
+			require.NotEmpty(t, message.BounceHash)
+			require.Equal(t, messages[0].MessageHash, message.MessageHash)
+			require.Equal(t, messages[0].BOC, message.BOC)
+			require.WithinDuration(t, time.Now().Add(messageTTL-createdAtLag), message.ExpiresAt, time.Minute)
+		}
+
+		// the transfers are executed by the batch the wallet sends to itself
+		externalCell, err := cell.FromBOC(messages[0].BOC)
+		require.NoError(t, err)
+		var external tlb.ExternalMessage
+		require.NoError(t, tlb.LoadFromCell(&external, externalCell.BeginParse()))
+
+		payload, err := external.Body.PeekRef(0)
+		require.NoError(t, err)
+		batchCell, err := payload.PeekRef(0)
+		require.NoError(t, err)
+		var batch tlb.InternalMessage
+		require.NoError(t, tlb.LoadFromCell(&batch, batchCell.BeginParse()))
+
+		require.Equal(t, w.WalletAddress().StringRaw(), batch.DstAddr.StringRaw())
+		require.Equal(t, hex.EncodeToString(batch.Body.Hash()), messages[0].MessageHash)
+		op, err := batch.Body.BeginParse().LoadUInt(32)
+		require.NoError(t, err)
+		require.Equal(t, uint64(model.OpHighloadBatch), op)
+	})
+
+	t.Run("single payout", func(t *testing.T) {
+		t.Parallel()
+
+		w := newTestHighloadWallet(t, tlb.MustFromTON("10"))
+		messages, err := w.PreparePayouts(context.Background(), 1, []model.Payout{tonPayout(receiver.String(), 1000)})
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+
+		externalCell, err := cell.FromBOC(messages[0].BOC)
+		require.NoError(t, err)
+		var external tlb.ExternalMessage
+		require.NoError(t, tlb.LoadFromCell(&external, externalCell.BeginParse()))
+		require.Equal(t, hex.EncodeToString(external.Body.Hash()), messages[0].MessageHash)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		t.Parallel()
+
+		w := newTestHighloadWallet(t, tlb.FromNanoTONU(1000))
+		_, err := w.PreparePayouts(context.Background(), 1, []model.Payout{tonPayout(receiver.String(), 1000)})
+		require.ErrorContains(t, err, "insufficient balance")
+	})
+
+	t.Run("invalid batch", func(t *testing.T) {
+		t.Parallel()
+
+		w := newTestHighloadWallet(t, tlb.MustFromTON("10"))
+		_, err := w.PreparePayouts(context.Background(), 1, nil)
+		require.Error(t, err)
+
+		_, err = w.PreparePayouts(context.Background(), 1, make([]model.Payout, model.MaxPayoutBatch+1))
+		require.Error(t, err)
+
+		_, err = w.PreparePayouts(context.Background(), model.MaxHighloadQueryID+1, []model.Payout{tonPayout(receiver.String(), 1000)})
+		require.ErrorContains(t, err, "out of range")
+
+		_, err = w.PreparePayouts(context.Background(), 1, []model.Payout{tonPayout("invalid", 1000)})
+		require.ErrorContains(t, err, "parse destination")
+	})
+}
@@ -6,6 +6,7 @@ package ton
 
 import (
 	"context"
+	"encoding/hex"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,33 @@ type accFetchTask struct {
 	callback func()
 }
 
+// ScannedTransaction is the transaction published by the scanner. The dictionary of its outgoing messages
+// is not serialized, so its internal outgoing messages are listed in OutMsgs.
+type ScannedTransaction struct {
+	*tlbutils.Transaction
+	OutMsgs []*tlbutils.InternalMessage `json:",omitempty"`
+}
+
+func newScannedTransaction(tx *tlbutils.Transaction) *ScannedTransaction {
+	scanned := &ScannedTransaction{Transaction: tx}
+	if tx.IO.Out == nil {
+		return scanned
+	}
+
+	messages, err := tx.IO.Out.ToSlice()
+	if err != nil {
+		log.Warn().Err(err).Str("hash", hex.EncodeToString(tx.Hash)).Msg("failed to list outgoing messages")
+		return scanned
+	}
+
+	for _, message := range messages {
+		if message.MsgType == tlbutils.MsgTypeInternal {
+			scanned.OutMsgs = append(scanned.OutMsgs, message.AsInternal())
+		}
+	}
+	return scanned
+}
+
 type OptionsScanner struct {
 	NumWorkers int
 }
@@ -199,7 +227,7 @@ func (v *Scanner) accFetcherWorker(ch chan<- any, threads int) {
 						return
 					}
 
-					ch <- task.tx
+					ch <- newScannedTransaction(task.tx)
 				}()
 			}
 		}()
@@ -365,10 +393,16 @@ func (v *Scanner) fetchBlock(ctx context.Context, master *tonutils.BlockIDExt) (
 								return errors.Wrap(err, "load aug currency collection of transaction")
 							}
 
+							var txCell *cell.Cell
+							if txCell, err = slcTx.LoadRefCell(); err != nil {
+								return errors.Wrap(err, "load transaction cell")
+							}
+
 							var tx tlbutils.Transaction
-							if err = tlbutils.LoadFromCell(&tx, slcTx.MustLoadRef()); err != nil {
+							if err = tlbutils.LoadFromCell(&tx, txCell.BeginParse()); err != nil {
 								return errors.Wrap(err, "load transaction")
 							}
+							tx.Hash = txCell.Hash()
 
 							wg.Add(1)
 							v.taskPool <- accFetchTask{
@@ -0,0 +1,41 @@
+package ton
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestScannedTransaction_OutMsgs(t *testing.T) {
+	t.Parallel()
+
+	receiver := address.MustParseRawAddr("0:" + hex.EncodeToString(make([]byte, 32)))
+	message, err := tlb.ToCell(&tlb.InternalMessage{
+		IHRDisabled: true,
+		SrcAddr:     receiver,
+		DstAddr:     receiver,
+		Amount:      tlb.FromNanoTONU(1000),
+	})
+	require.NoError(t, err)
+
+	out := cell.NewDict(15)
+	require.NoError(t, out.SetIntKey(big.NewInt(0), cell.BeginCell().MustStoreRef(message).EndCell()))
+
+	tx := &tlb.Transaction{Hash: []byte{1, 2}, AccountAddr: make([]byte, 32), LT: 7}
+	tx.IO.Out = &tlb.MessagesList{List: out}
+
+	payload, err := json.Marshal(newScannedTransaction(tx))
+	require.NoError(t, err)
+
+	parsed, err := model.UnmarshalTransaction(payload)
+	require.NoError(t, err)
+	require.Equal(t, []model.OutMessage{{Receiver: receiver.String(), Amount: model.NewAmount(1000)}}, parsed.OutMessages)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"log"
 	"math/big"
-	"math/rand"
 
 	"github.com/go-faster/errors"
 	"github.com/xssnick/tonutils-go/address"
@@ -41,34 +40,6 @@ func TonTransfer(ctx context.Context, from, to *wallet.Wallet, comment string) e
 	}, false)
 }
 
-func JettonsTransfer(
-	ctx context.Context,
-	from, to *wallet.Wallet,
-	jettonWallet *address.Address,
-	forwardAmount tlb.Coins,
-	amount Coins,
-	comment string,
-) error {
-	if from == nil || to == nil || to.Address() == nil {
-		return errors.New("nil wallet")
-	}
-	body, err := MakeJettonTransferMessage(to.Address(), to.Address(), amount.BigInt(), forwardAmount, rand.Int63(), comment, "")
-	if err != nil {
-		return errors.Wrap(err, "make jetton transfer message")
-	}
-
-	return from.Send(ctx, &wallet.Message{
-		Mode: 128 + 32, // 128 + 32 send all and destroy
-		InternalMessage: &tlb.InternalMessage{
-			IHRDisabled: true,
-			Bounce:      true,
-			DstAddr:     jettonWallet,
-			Amount:      tlb.FromNanoTONU(0),
-			Body:        body,
-		},
-	}, false)
-}
-
 func buildComment(comment string) (*cell.Cell, error) {
 	root := cell.BeginCell().MustStoreUInt(0, 32)
 	if err := root.StoreStringSnake(comment); err != nil {
@@ -3,13 +3,18 @@ package ton
 import (
 	"context"
 	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
-	"math/big"
+	"math"
+	"math/rand"
+	"time"
 
 	"github.com/go-faster/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/xssnick/tonutils-go/address"
 	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/jetton"
 	"github.com/xssnick/tonutils-go/ton/wallet"
 	"github.com/xssnick/tonutils-go/tvm/cell"
 
@@ -21,20 +26,55 @@ type (
 	WalletWrapped interface {
 		PrivateKey() ed25519.PrivateKey
 		GetSubwallet(subwallet uint32) (*wallet.Wallet, error)
+		GetSubwalletID() uint32
 		WalletAddress() *address.Address
 	}
 )
 
+// regularSpec is the spec of the seqno based wallets.
+type regularSpec interface {
+	SetMessagesTTL(ttl uint32)
+	SetSeqnoFetcher(fetcher func(ctx context.Context, subWallet uint32) (uint32, error))
+}
+
+const (
+	// messageTTL is the validity of the signed external messages.
+	messageTTL = 3 * time.Minute
+
+	// executedPageSize is the number of the transactions of a wallet read at once looking for an executed message.
+	executedPageSize = 16
+)
+
 var _ ports.WalletPort = (*WalletAdapter)(nil)
 var _ WalletWrapped = &wallet.Wallet{}
 
 type WalletAdapter struct {
 	api          APIClientWrapped
 	masterWallet WalletWrapped
+	jettons      map[model.Currency]*address.Address // Jetton master contracts by the currency
 }
 
 func NewWalletAdapter(api APIClientWrapped, masterWallet WalletWrapped) *WalletAdapter {
-	return &WalletAdapter{api: api, masterWallet: masterWallet}
+	return &WalletAdapter{api: api, masterWallet: masterWallet, jettons: make(map[model.Currency]*address.Address)}
+}
+
+// NewWatchOnlyWallet returns the master wallet of the public key. It resolves the subwallets and reads
+// their state like a wallet of the seed, but signing a message fails with ErrWalletWatchOnly.
+func NewWatchOnlyWallet(api wallet.TonAPI, publicKey ed25519.PublicKey, version wallet.VersionConfig) (*wallet.Wallet, error) {
+	return wallet.FromSigner(api, publicKey, version, func(context.Context, *cell.Cell, uint32) ([]byte, error) {
+		return nil, ErrWalletWatchOnly
+	})
+}
+
+// AddJetton registers the jetton master contract of the currency, the jetton wallets of the currency
+// are resolved by it.
+func (w *WalletAdapter) AddJetton(currency model.Currency, master string) error {
+	addr, err := address.ParseAddr(master)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s jetton master", currency)
+	}
+	w.jettons[currency] = addr
+	return nil
 }
 
 func (w *WalletAdapter) CreateWallet(ctx context.Context, walletID uint32) (model.WalletWrapper, error) {
@@ -135,39 +175,502 @@ func (w *WalletAdapter) SendWaitTransaction(ctx context.Context, walletID uint32
 	return errors.New("account is not active")
 }
 
-func (w *WalletAdapter) TransferToMainWallet(ctx context.Context, walletID uint32, amount uint64) error {
+// TransferToMainWallet sends the amount from the subwallet to the master wallet and returns the sent message.
+func (w *WalletAdapter) TransferToMainWallet(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error) {
+	message, err := w.PrepareTransferToMainWallet(ctx, walletID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.SendWalletMessage(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// PrepareTransferToMainWallet signs the non-bounceable transfer of the amount from the subwallet to the master
+// wallet, the subwallet is deployed by the message if it is not active yet.
+func (w *WalletAdapter) PrepareTransferToMainWallet(
+	ctx context.Context, walletID uint32, amount model.Amount,
+) (*model.WalletMessage, error) {
 	subwallet, err := w.masterWallet.GetSubwallet(walletID)
 	if err != nil {
-		return errors.Wrap(err, "get subwallet")
+		return nil, errors.Wrap(err, "get subwallet")
 	}
 
 	// Get the current block info
 	block, err := w.api.CurrentMasterchainInfo(ctx)
 	if err != nil {
-		return errors.Wrap(err, "get masterchain info")
+		return nil, errors.Wrap(err, "get masterchain info")
 	}
 
 	// Check if the subwallet has enough balance
 	balance, err := subwallet.GetBalance(ctx, block)
 	if err != nil {
-		return errors.Wrap(err, "get balance")
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	if balance.Nano().Cmp(amount.BigInt()) <= 0 {
+		return nil, errors.New("insufficient balance in subwallet")
+	}
+
+	transfer, err := subwallet.BuildTransfer(w.masterWallet.WalletAddress(), tlb.FromNanoTON(amount.BigInt()), false, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "build transfer")
+	}
+
+	message, err := w.sign(ctx, walletID, subwallet, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	message.To = w.masterWallet.WalletAddress().StringRaw()
+	message.Currency, message.Amount = model.CurrencyTON, amount
+	return message, nil
+}
+
+// PrepareTopUp signs the non-bounceable transfer of the amount from the master wallet to the subwallet,
+// it pays the gas of a jetton transfer of the subwallet.
+func (w *WalletAdapter) PrepareTopUp(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error) {
+	if walletID == 0 {
+		return nil, errors.New("top-up of the master wallet")
+	}
+
+	master, err := w.wallet(0)
+	if err != nil {
+		return nil, err
+	}
+
+	subwallet, err := w.masterWallet.GetSubwallet(walletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get subwallet")
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get masterchain info")
+	}
+
+	balance, err := master.GetBalance(ctx, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	if balance.Nano().Cmp(amount.BigInt()) <= 0 {
+		return nil, errors.New("insufficient balance in master wallet")
+	}
+
+	transfer, err := master.BuildTransfer(subwallet.WalletAddress(), tlb.FromNanoTON(amount.BigInt()), false, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "build transfer")
+	}
+
+	message, err := w.sign(ctx, 0, master, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	message.To = subwallet.WalletAddress().StringRaw()
+	message.Currency, message.Amount = model.CurrencyTON, amount
+	return message, nil
+}
+
+// PrepareJettonTransferToMainWallet signs the transfer of the jetton amount from the subwallet to the master
+// wallet. The message carries the gas to the jetton wallet of the subwallet, the excess of the gas is returned
+// to the master wallet.
+func (w *WalletAdapter) PrepareJettonTransferToMainWallet(
+	ctx context.Context, walletID uint32, currency model.Currency, amount, gas model.Amount,
+) (*model.WalletMessage, error) {
+	subwallet, err := w.masterWallet.GetSubwallet(walletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get subwallet")
+	}
+
+	jettonWallet, err := w.jettonWallet(ctx, currency, subwallet.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	jettonBalance, err := jettonWallet.GetBalance(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get jetton balance")
+	}
+
+	if jettonBalance.Cmp(amount.BigInt()) < 0 {
+		return nil, errors.Errorf("insufficient %s balance in subwallet", currency)
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get masterchain info")
+	}
+
+	balance, err := subwallet.GetBalance(ctx, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	if balance.Nano().Cmp(gas.BigInt()) <= 0 {
+		return nil, errors.New("insufficient gas in subwallet")
+	}
+
+	master, queryID := w.masterWallet.WalletAddress(), newJettonQueryID()
+	body, err := MakeJettonTransferMessage(master, master, amount.BigInt(), tlb.ZeroCoins, queryID, "", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "make jetton transfer message")
+	}
+
+	transfer := &wallet.Message{
+		Mode: wallet.PayGasSeparately + wallet.IgnoreErrors,
+		InternalMessage: &tlb.InternalMessage{
+			IHRDisabled: true,
+			Bounce:      true,
+			DstAddr:     jettonWallet.Address(),
+			Amount:      tlb.FromNanoTON(gas.BigInt()),
+			Body:        body,
+		},
+	}
+
+	message, err := w.sign(ctx, walletID, subwallet, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	message.To = master.StringRaw()
+	message.Currency, message.Amount, message.Gas, message.JettonQueryID = currency, amount, gas, uint64(queryID)
+	return message, nil
+}
+
+// PrepareWithdrawal signs the transfer of the amount from the master wallet to the destination. A TON transfer
+// bounces by the flag of the destination address and carries the comment. A jetton transfer carries the gas
+// to the jetton wallet of the master wallet, the comment is forwarded to the destination with a notification.
+func (w *WalletAdapter) PrepareWithdrawal(
+	ctx context.Context, to string, amount model.Balance, gas model.Amount, comment string,
+) (*model.WalletMessage, error) {
+	destination, err := address.ParseAddr(to)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse destination")
+	}
+
+	master, err := w.wallet(0)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get masterchain info")
+	}
+
+	balance, err := master.GetBalance(ctx, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	var (
+		transfer *wallet.Message
+		queryID  int64
+	)
+
+	if amount.Currency == model.CurrencyTON {
+		if balance.Nano().Cmp(amount.Amount.BigInt()) <= 0 {
+			return nil, errors.New("insufficient balance in master wallet")
+		}
+
+		transfer, err = master.BuildTransfer(destination, tlb.FromNanoTON(amount.Amount.BigInt()), destination.IsBounceable(), comment)
+		if err != nil {
+			return nil, errors.Wrap(err, "build transfer")
+		}
+		gas = model.NewAmount(0)
+	} else {
+		queryID = newJettonQueryID()
+		if transfer, err = w.jettonWithdrawal(ctx, master, destination, amount, gas, comment, queryID); err != nil {
+			return nil, err
+		}
+
+		if balance.Nano().Cmp(gas.BigInt()) <= 0 {
+			return nil, errors.New("insufficient gas in master wallet")
+		}
+	}
+
+	message, err := w.sign(ctx, 0, master, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	message.To = destination.StringRaw()
+	message.Currency, message.Amount, message.Gas, message.JettonQueryID = amount.Currency, amount.Amount, gas, uint64(queryID)
+	return message, nil
+}
+
+// jettonWithdrawal builds the jetton transfer of the master wallet to the destination with the query id,
+// the excess of the gas is returned to the master wallet.
+func (w *WalletAdapter) jettonWithdrawal(
+	ctx context.Context, master *wallet.Wallet, destination *address.Address, amount model.Balance, gas model.Amount,
+	comment string, queryID int64,
+) (*wallet.Message, error) {
+	jettonWallet, err := w.jettonWallet(ctx, amount.Currency, master.WalletAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	jettonBalance, err := jettonWallet.GetBalance(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get jetton balance")
+	}
+
+	if jettonBalance.Cmp(amount.Amount.BigInt()) < 0 {
+		return nil, errors.Errorf("insufficient %s balance in master wallet", amount.Currency)
+	}
+
+	forward := tlb.ZeroCoins
+	if comment != "" {
+		forward = tlb.FromNanoTONU(1)
+	}
+
+	body, err := MakeJettonTransferMessage(destination, master.WalletAddress(), amount.Amount.BigInt(), forward, queryID, comment, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "make jetton transfer message")
+	}
+
+	return &wallet.Message{
+		Mode: wallet.PayGasSeparately + wallet.IgnoreErrors,
+		InternalMessage: &tlb.InternalMessage{
+			IHRDisabled: true,
+			Bounce:      true,
+			DstAddr:     jettonWallet.Address(),
+			Amount:      tlb.FromNanoTON(gas.BigInt()),
+			Body:        body,
+		},
+	}, nil
+}
+
+// GetJettonBalance returns the balance of the jetton wallet of the subwallet, 0 if it is not deployed.
+func (w *WalletAdapter) GetJettonBalance(ctx context.Context, walletID uint32, currency model.Currency) (model.Balance, error) {
+	owner, err := w.wallet(walletID)
+	if err != nil {
+		return model.Balance{}, err
+	}
+
+	jettonWallet, err := w.jettonWallet(ctx, currency, owner.WalletAddress())
+	if err != nil {
+		return model.Balance{}, err
+	}
+
+	amount, err := jettonWallet.GetBalance(ctx)
+	if err != nil {
+		return model.Balance{}, errors.Wrap(err, "get jetton balance")
 	}
+	return model.Balance{Currency: currency, Amount: model.Amount(*amount)}, nil
+}
+
+// jettonWallet resolves the jetton wallet of the owner by the jetton master of the currency.
+func (w *WalletAdapter) jettonWallet(
+	ctx context.Context, currency model.Currency, owner *address.Address,
+) (*jetton.WalletClient, error) {
+	return resolveJettonWallet(ctx, w.api, w.jettons, currency, owner)
+}
 
-	if balance.Nano().Uint64() <= amount {
-		return errors.New("insufficient balance in subwallet")
+// resolveJettonWallet resolves the jetton wallet of the owner by the registered jetton master of the currency.
+func resolveJettonWallet(
+	ctx context.Context, api APIClientWrapped, jettons map[model.Currency]*address.Address, currency model.Currency, owner *address.Address,
+) (*jetton.WalletClient, error) {
+	master, ok := jettons[currency]
+	if !ok {
+		return nil, errors.Errorf("unknown jetton %s", currency)
 	}
 
-	coinAmount := tlb.MustFromNano(big.NewInt(int64(amount)), 0)
-	if err = subwallet.TransferNoBounce(ctx, w.masterWallet.WalletAddress(), coinAmount, ""); err != nil {
-		return errors.Wrap(err, "transfer funds to main wallet")
+	jettonWallet, err := jetton.NewJettonMasterClient(api, master).GetJettonWallet(ctx, owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "get jetton wallet")
+	}
+	return jettonWallet, nil
+}
+
+// sign builds the external message of the wallet carrying the internal message. The seqno and the expiration
+// are fixed, so they are known to the caller.
+func (w *WalletAdapter) sign(
+	ctx context.Context, walletID uint32, from *wallet.Wallet, transfer *wallet.Message,
+) (*model.WalletMessage, error) {
+	spec, ok := from.GetSpec().(regularSpec)
+	if !ok {
+		return nil, errors.Errorf("unsupported wallet spec %T", from.GetSpec())
+	}
+
+	seqno, err := w.GetSeqno(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(messageTTL).UTC()
+	spec.SetMessagesTTL(uint32(messageTTL.Seconds()))
+	spec.SetSeqnoFetcher(func(context.Context, uint32) (uint32, error) { return seqno, nil })
+
+	external, err := from.BuildExternalMessageForMany(ctx, []*wallet.Message{transfer})
+	if err != nil {
+		return nil, errors.Wrap(err, "build external message")
+	}
+
+	externalCell, err := tlb.ToCell(external)
+	if err != nil {
+		return nil, errors.Wrap(err, "serialize external message")
+	}
+
+	bounceHash, err := transferBounceHash(transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WalletMessage{
+		WalletID:    walletID,
+		From:        from.WalletAddress().StringRaw(),
+		Seqno:       seqno,
+		MessageHash: hex.EncodeToString(external.Body.Hash()),
+		BounceHash:  bounceHash,
+		BOC:         externalCell.ToBOC(),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// newJettonQueryID returns a random positive query id of a jetton transfer, the excess of the gas returned
+// to the sender carries it.
+func newJettonQueryID() int64 {
+	return rand.Int63n(math.MaxInt64) + 1
+}
+
+// transferBounceHash returns the model.BounceHash of the body of a bounceable transfer, a non-bounceable
+// transfer is not returned.
+func transferBounceHash(transfer *wallet.Message) (string, error) {
+	if !transfer.InternalMessage.Bounce {
+		return "", nil
+	}
+
+	hash, err := model.BounceHash(transfer.InternalMessage.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "bounce hash")
+	}
+	return hash, nil
+}
+
+// wallet returns a copy of the subwallet, the walletID 0 is the master wallet. The copy has its own spec,
+// so fixing the seqno of a message does not change the master wallet.
+func (w *WalletAdapter) wallet(walletID uint32) (*wallet.Wallet, error) {
+	if walletID == 0 {
+		walletID = w.masterWallet.GetSubwalletID()
+	}
+
+	subwallet, err := w.masterWallet.GetSubwallet(walletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get subwallet")
+	}
+	return subwallet, nil
+}
+
+// GetSeqno runs the seqno get method of the subwallet or the master wallet for the walletID 0,
+// a wallet that is not deployed has seqno 0.
+func (w *WalletAdapter) GetSeqno(ctx context.Context, walletID uint32) (uint32, error) {
+	subwallet, err := w.wallet(walletID)
+	if err != nil {
+		return 0, err
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "get masterchain info")
+	}
+
+	res, err := w.api.WaitForBlock(block.SeqNo).RunGetMethod(ctx, block, subwallet.WalletAddress(), "seqno")
+	if err != nil {
+		var execErr ton.ContractExecError
+		if errors.As(err, &execErr) && execErr.Code == ton.ErrCodeContractNotInitialized {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "run seqno method")
+	}
+
+	seqno, err := res.Int(0)
+	if err != nil {
+		return 0, errors.Wrap(err, "parse seqno")
+	}
+	return uint32(seqno.Uint64()), nil
+}
+
+// IsMessageExecuted looks for the external message among the transactions of the sending wallet since the message
+// was signed, by the hash of its body.
+func (w *WalletAdapter) IsMessageExecuted(ctx context.Context, message *model.WalletMessage) (bool, error) {
+	addr, err := address.ParseRawAddr(message.From)
+	if err != nil {
+		return false, errors.Wrap(err, "parse sending wallet")
+	}
+
+	block, err := w.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "get masterchain info")
+	}
+
+	account, err := w.api.WaitForBlock(block.SeqNo).GetAccount(ctx, block, addr)
+	if err != nil {
+		return false, errors.Wrap(err, "get account")
+	}
+
+	signedAt := message.ExpiresAt.Add(-messageTTL).Unix()
+	lt, hash := account.LastTxLT, account.LastTxHash
+	for lt != 0 {
+		txs, err := w.api.ListTransactions(ctx, addr, executedPageSize, lt, hash)
+		if err != nil {
+			if errors.Is(err, ton.ErrNoTransactionsWereFound) {
+				return false, nil
+			}
+			return false, errors.Wrap(err, "list transactions")
+		}
+
+		for i := len(txs) - 1; i >= 0; i-- {
+			if int64(txs[i].Now) < signedAt {
+				return false, nil
+			}
+
+			if in := txs[i].IO.In; in != nil && in.MsgType == tlb.MsgTypeExternalIn && in.AsExternalIn().Body != nil &&
+				hex.EncodeToString(in.AsExternalIn().Body.Hash()) == message.MessageHash {
+				return true, nil
+			}
+		}
+		lt, hash = txs[0].PrevTxLT, txs[0].PrevTxHash
+	}
+	return false, nil
+}
+
+// SendWalletMessage sends the prepared external message without waiting for its transaction.
+func (w *WalletAdapter) SendWalletMessage(ctx context.Context, message *model.WalletMessage) error {
+	if err := sendExternal(ctx, w.api, message.BOC); err != nil {
+		return err
 	}
 
 	log.Info().
-		Uint32("from_wallet_id", walletID).
-		Uint64("amount", amount).
-		Str("to_address", w.masterWallet.WalletAddress().String()).
-		Msg("transferring funds to main wallet")
+		Uint32("from_wallet_id", message.WalletID).
+		Str("currency", string(message.Currency)).
+		Str("amount", message.Amount.Nano()).
+		Str("to_address", message.To).
+		Str("message_hash", message.MessageHash).
+		Msg("wallet message sent")
+
+	return nil
+}
+
+// sendExternal sends the serialized external message.
+func sendExternal(ctx context.Context, api APIClientWrapped, boc []byte) error {
+	externalCell, err := cell.FromBOC(boc)
+	if err != nil {
+		return errors.Wrap(err, "parse external message")
+	}
 
+	var external tlb.ExternalMessage
+	if err = tlb.LoadFromCell(&external, externalCell.BeginParse()); err != nil {
+		return errors.Wrap(err, "load external message")
+	}
+
+	if err = api.SendExternalMessage(ctx, &external); err != nil {
+		return errors.Wrap(err, "send external message")
+	}
 	return nil
 }
 
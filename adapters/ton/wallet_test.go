@@ -0,0 +1,126 @@
+package ton_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	tonadapter "github.com/kriuchkov/tonbeacon/adapters/ton"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestNewWatchOnlyWallet(t *testing.T) {
+	t.Parallel()
+
+	seed := walletutils.NewSeed()
+	master, err := walletutils.FromSeed(nil, seed, walletutils.V4R2)
+	require.NoError(t, err)
+
+	watchOnly, err := tonadapter.NewWatchOnlyWallet(nil, master.PrivateKey().Public().(ed25519.PublicKey), walletutils.V4R2)
+	require.NoError(t, err)
+	require.Equal(t, master.WalletAddress().String(), watchOnly.WalletAddress().String())
+
+	subwallet, err := master.GetSubwallet(7)
+	require.NoError(t, err)
+	watchOnlySubwallet, err := watchOnly.GetSubwallet(7)
+	require.NoError(t, err)
+	require.Equal(t, subwallet.WalletAddress().String(), watchOnlySubwallet.WalletAddress().String())
+
+	watchOnlySubwallet.GetSpec().(*walletutils.SpecV4R2).SetSeqnoFetcher(func(context.Context, uint32) (uint32, error) {
+		return 1, nil
+	})
+	_, err = watchOnlySubwallet.PrepareExternalMessageForMany(context.Background(), false, nil)
+	require.ErrorIs(t, err, tonadapter.ErrWalletWatchOnly)
+}
+
+// historyAPI serves the transactions of a wallet from the newest, the other methods are not used.
+type historyAPI struct {
+	ton.APIClientWrapped
+	txs []*tlb.Transaction
+}
+
+func (a *historyAPI) CurrentMasterchainInfo(context.Context) (*ton.BlockIDExt, error) {
+	return &ton.BlockIDExt{SeqNo: 1}, nil
+}
+
+func (a *historyAPI) WaitForBlock(uint32) ton.APIClientWrapped {
+	return a
+}
+
+func (a *historyAPI) GetAccount(context.Context, *ton.BlockIDExt, *address.Address) (*tlb.Account, error) {
+	if len(a.txs) == 0 {
+		return &tlb.Account{}, nil
+	}
+	return &tlb.Account{IsActive: true, LastTxLT: a.txs[0].LT, LastTxHash: a.txs[0].Hash}, nil
+}
+
+func (a *historyAPI) ListTransactions(_ context.Context, _ *address.Address, limit uint32, lt uint64, _ []byte) ([]*tlb.Transaction, error) {
+	var page []*tlb.Transaction
+	for _, tx := range a.txs {
+		if tx.LT <= lt && len(page) < int(limit) {
+			page = append([]*tlb.Transaction{tx}, page...)
+		}
+	}
+	if len(page) == 0 {
+		return nil, ton.ErrNoTransactionsWereFound
+	}
+	return page, nil
+}
+
+func TestWalletAdapter_IsMessageExecuted(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	body := cell.BeginCell().MustStoreUInt(7, 32).EndCell()
+	external := func(lt uint64, at time.Time, body *cell.Cell) *tlb.Transaction {
+		tx := &tlb.Transaction{LT: lt, Hash: []byte{byte(lt)}, Now: uint32(at.Unix()), PrevTxLT: lt - 1, PrevTxHash: []byte{byte(lt - 1)}}
+		tx.IO.In = &tlb.Message{MsgType: tlb.MsgTypeExternalIn, Msg: &tlb.ExternalMessage{Body: body}}
+		return tx
+	}
+	other := cell.BeginCell().MustStoreUInt(8, 32).EndCell()
+
+	// the transactions are listed from the newest, the older ones precede the signing of the message
+	history := make([]*tlb.Transaction, 0, 40)
+	for lt := uint64(40); lt > 0; lt-- {
+		at := now.Add(-time.Duration(40-lt) * time.Second)
+		if lt < 10 {
+			at = now.Add(-time.Hour)
+		}
+		history = append(history, external(lt, at, other))
+	}
+
+	tests := []struct {
+		name     string
+		txs      []*tlb.Transaction
+		executed bool
+	}{
+		{name: "executed on an older page", txs: append(append([]*tlb.Transaction{}, history[:30]...), append([]*tlb.Transaction{external(10, now.Add(-30*time.Second), body)}, history[31:]...)...), executed: true},
+		{name: "not executed since the signing", txs: history},
+		{name: "executed before the signing", txs: append(append([]*tlb.Transaction{}, history[:35]...), append([]*tlb.Transaction{external(5, now.Add(-time.Hour), body)}, history[36:]...)...)},
+		{name: "wallet without transactions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := tonadapter.NewWalletAdapter(&historyAPI{txs: tt.txs}, nil)
+			executed, err := w.IsMessageExecuted(context.Background(), &model.WalletMessage{
+				From:        "0:" + hex.EncodeToString(make([]byte, 32)),
+				MessageHash: hex.EncodeToString(body.Hash()),
+				ExpiresAt:   now.Add(2 * time.Minute),
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.executed, executed)
+		})
+	}
+}
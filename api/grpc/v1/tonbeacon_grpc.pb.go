@@ -20,12 +20,24 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	TonBeacon_CreateAccount_FullMethodName    = "/tonbeacon.v1.TonBeacon/CreateAccount"
-	TonBeacon_GetAccount_FullMethodName       = "/tonbeacon.v1.TonBeacon/GetAccount"
-	TonBeacon_GetMasterAccount_FullMethodName = "/tonbeacon.v1.TonBeacon/GetMasterAccount"
-	TonBeacon_ListAccounts_FullMethodName     = "/tonbeacon.v1.TonBeacon/ListAccounts"
-	TonBeacon_CloseAccount_FullMethodName     = "/tonbeacon.v1.TonBeacon/CloseAccount"
-	TonBeacon_GetBalance_FullMethodName       = "/tonbeacon.v1.TonBeacon/GetBalance"
+	TonBeacon_CreateAccount_FullMethodName          = "/tonbeacon.v1.TonBeacon/CreateAccount"
+	TonBeacon_GetAccount_FullMethodName             = "/tonbeacon.v1.TonBeacon/GetAccount"
+	TonBeacon_GetMasterAccount_FullMethodName       = "/tonbeacon.v1.TonBeacon/GetMasterAccount"
+	TonBeacon_ListAccounts_FullMethodName           = "/tonbeacon.v1.TonBeacon/ListAccounts"
+	TonBeacon_CloseAccount_FullMethodName           = "/tonbeacon.v1.TonBeacon/CloseAccount"
+	TonBeacon_GetBalance_FullMethodName             = "/tonbeacon.v1.TonBeacon/GetBalance"
+	TonBeacon_ListTransactions_FullMethodName       = "/tonbeacon.v1.TonBeacon/ListTransactions"
+	TonBeacon_GetTransaction_FullMethodName         = "/tonbeacon.v1.TonBeacon/GetTransaction"
+	TonBeacon_SubscribeAccountEvents_FullMethodName = "/tonbeacon.v1.TonBeacon/SubscribeAccountEvents"
+	TonBeacon_SetSweepPolicy_FullMethodName         = "/tonbeacon.v1.TonBeacon/SetSweepPolicy"
+	TonBeacon_ListSweepPolicies_FullMethodName      = "/tonbeacon.v1.TonBeacon/ListSweepPolicies"
+	TonBeacon_DeleteSweepPolicy_FullMethodName      = "/tonbeacon.v1.TonBeacon/DeleteSweepPolicy"
+	TonBeacon_PlanSweep_FullMethodName              = "/tonbeacon.v1.TonBeacon/PlanSweep"
+	TonBeacon_CreateWithdrawal_FullMethodName       = "/tonbeacon.v1.TonBeacon/CreateWithdrawal"
+	TonBeacon_GetWithdrawal_FullMethodName          = "/tonbeacon.v1.TonBeacon/GetWithdrawal"
+	TonBeacon_ListWithdrawals_FullMethodName        = "/tonbeacon.v1.TonBeacon/ListWithdrawals"
+	TonBeacon_ApproveWithdrawal_FullMethodName      = "/tonbeacon.v1.TonBeacon/ApproveWithdrawal"
+	TonBeacon_RejectWithdrawal_FullMethodName       = "/tonbeacon.v1.TonBeacon/RejectWithdrawal"
 )
 
 // TonBeaconClient is the client API for TonBeacon service.
@@ -38,6 +50,18 @@ type TonBeaconClient interface {
 	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
 	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error)
 	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
+	SubscribeAccountEvents(ctx context.Context, in *SubscribeAccountEventsRequest, opts ...grpc.CallOption) (TonBeacon_SubscribeAccountEventsClient, error)
+	SetSweepPolicy(ctx context.Context, in *SetSweepPolicyRequest, opts ...grpc.CallOption) (*SetSweepPolicyResponse, error)
+	ListSweepPolicies(ctx context.Context, in *ListSweepPoliciesRequest, opts ...grpc.CallOption) (*ListSweepPoliciesResponse, error)
+	DeleteSweepPolicy(ctx context.Context, in *DeleteSweepPolicyRequest, opts ...grpc.CallOption) (*DeleteSweepPolicyResponse, error)
+	PlanSweep(ctx context.Context, in *PlanSweepRequest, opts ...grpc.CallOption) (*PlanSweepResponse, error)
+	CreateWithdrawal(ctx context.Context, in *CreateWithdrawalRequest, opts ...grpc.CallOption) (*CreateWithdrawalResponse, error)
+	GetWithdrawal(ctx context.Context, in *GetWithdrawalRequest, opts ...grpc.CallOption) (*GetWithdrawalResponse, error)
+	ListWithdrawals(ctx context.Context, in *ListWithdrawalsRequest, opts ...grpc.CallOption) (*ListWithdrawalsResponse, error)
+	ApproveWithdrawal(ctx context.Context, in *ApproveWithdrawalRequest, opts ...grpc.CallOption) (*ApproveWithdrawalResponse, error)
+	RejectWithdrawal(ctx context.Context, in *RejectWithdrawalRequest, opts ...grpc.CallOption) (*RejectWithdrawalResponse, error)
 }
 
 type tonBeaconClient struct {
@@ -102,6 +126,137 @@ func (c *tonBeaconClient) GetBalance(ctx context.Context, in *GetBalanceRequest,
 	return out, nil
 }
 
+func (c *tonBeaconClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_ListTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_GetTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) SubscribeAccountEvents(ctx context.Context, in *SubscribeAccountEventsRequest, opts ...grpc.CallOption) (TonBeacon_SubscribeAccountEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TonBeacon_ServiceDesc.Streams[0], TonBeacon_SubscribeAccountEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tonBeaconSubscribeAccountEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TonBeacon_SubscribeAccountEventsClient interface {
+	Recv() (*AccountEvent, error)
+	grpc.ClientStream
+}
+
+type tonBeaconSubscribeAccountEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *tonBeaconSubscribeAccountEventsClient) Recv() (*AccountEvent, error) {
+	m := new(AccountEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tonBeaconClient) SetSweepPolicy(ctx context.Context, in *SetSweepPolicyRequest, opts ...grpc.CallOption) (*SetSweepPolicyResponse, error) {
+	out := new(SetSweepPolicyResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_SetSweepPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) ListSweepPolicies(ctx context.Context, in *ListSweepPoliciesRequest, opts ...grpc.CallOption) (*ListSweepPoliciesResponse, error) {
+	out := new(ListSweepPoliciesResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_ListSweepPolicies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) DeleteSweepPolicy(ctx context.Context, in *DeleteSweepPolicyRequest, opts ...grpc.CallOption) (*DeleteSweepPolicyResponse, error) {
+	out := new(DeleteSweepPolicyResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_DeleteSweepPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) PlanSweep(ctx context.Context, in *PlanSweepRequest, opts ...grpc.CallOption) (*PlanSweepResponse, error) {
+	out := new(PlanSweepResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_PlanSweep_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) CreateWithdrawal(ctx context.Context, in *CreateWithdrawalRequest, opts ...grpc.CallOption) (*CreateWithdrawalResponse, error) {
+	out := new(CreateWithdrawalResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_CreateWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) GetWithdrawal(ctx context.Context, in *GetWithdrawalRequest, opts ...grpc.CallOption) (*GetWithdrawalResponse, error) {
+	out := new(GetWithdrawalResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_GetWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) ListWithdrawals(ctx context.Context, in *ListWithdrawalsRequest, opts ...grpc.CallOption) (*ListWithdrawalsResponse, error) {
+	out := new(ListWithdrawalsResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_ListWithdrawals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) ApproveWithdrawal(ctx context.Context, in *ApproveWithdrawalRequest, opts ...grpc.CallOption) (*ApproveWithdrawalResponse, error) {
+	out := new(ApproveWithdrawalResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_ApproveWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tonBeaconClient) RejectWithdrawal(ctx context.Context, in *RejectWithdrawalRequest, opts ...grpc.CallOption) (*RejectWithdrawalResponse, error) {
+	out := new(RejectWithdrawalResponse)
+	err := c.cc.Invoke(ctx, TonBeacon_RejectWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TonBeaconServer is the server API for TonBeacon service.
 // All implementations must embed UnimplementedTonBeaconServer
 // for forward compatibility
@@ -112,6 +267,18 @@ type TonBeaconServer interface {
 	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
 	CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error)
 	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
+	SubscribeAccountEvents(*SubscribeAccountEventsRequest, TonBeacon_SubscribeAccountEventsServer) error
+	SetSweepPolicy(context.Context, *SetSweepPolicyRequest) (*SetSweepPolicyResponse, error)
+	ListSweepPolicies(context.Context, *ListSweepPoliciesRequest) (*ListSweepPoliciesResponse, error)
+	DeleteSweepPolicy(context.Context, *DeleteSweepPolicyRequest) (*DeleteSweepPolicyResponse, error)
+	PlanSweep(context.Context, *PlanSweepRequest) (*PlanSweepResponse, error)
+	CreateWithdrawal(context.Context, *CreateWithdrawalRequest) (*CreateWithdrawalResponse, error)
+	GetWithdrawal(context.Context, *GetWithdrawalRequest) (*GetWithdrawalResponse, error)
+	ListWithdrawals(context.Context, *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error)
+	ApproveWithdrawal(context.Context, *ApproveWithdrawalRequest) (*ApproveWithdrawalResponse, error)
+	RejectWithdrawal(context.Context, *RejectWithdrawalRequest) (*RejectWithdrawalResponse, error)
 	mustEmbedUnimplementedTonBeaconServer()
 }
 
@@ -137,6 +304,42 @@ func (UnimplementedTonBeaconServer) CloseAccount(context.Context, *CloseAccountR
 func (UnimplementedTonBeaconServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
 }
+func (UnimplementedTonBeaconServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedTonBeaconServer) GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedTonBeaconServer) SubscribeAccountEvents(*SubscribeAccountEventsRequest, TonBeacon_SubscribeAccountEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAccountEvents not implemented")
+}
+func (UnimplementedTonBeaconServer) SetSweepPolicy(context.Context, *SetSweepPolicyRequest) (*SetSweepPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSweepPolicy not implemented")
+}
+func (UnimplementedTonBeaconServer) ListSweepPolicies(context.Context, *ListSweepPoliciesRequest) (*ListSweepPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSweepPolicies not implemented")
+}
+func (UnimplementedTonBeaconServer) DeleteSweepPolicy(context.Context, *DeleteSweepPolicyRequest) (*DeleteSweepPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSweepPolicy not implemented")
+}
+func (UnimplementedTonBeaconServer) PlanSweep(context.Context, *PlanSweepRequest) (*PlanSweepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanSweep not implemented")
+}
+func (UnimplementedTonBeaconServer) CreateWithdrawal(context.Context, *CreateWithdrawalRequest) (*CreateWithdrawalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWithdrawal not implemented")
+}
+func (UnimplementedTonBeaconServer) GetWithdrawal(context.Context, *GetWithdrawalRequest) (*GetWithdrawalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWithdrawal not implemented")
+}
+func (UnimplementedTonBeaconServer) ListWithdrawals(context.Context, *ListWithdrawalsRequest) (*ListWithdrawalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWithdrawals not implemented")
+}
+func (UnimplementedTonBeaconServer) ApproveWithdrawal(context.Context, *ApproveWithdrawalRequest) (*ApproveWithdrawalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveWithdrawal not implemented")
+}
+func (UnimplementedTonBeaconServer) RejectWithdrawal(context.Context, *RejectWithdrawalRequest) (*RejectWithdrawalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectWithdrawal not implemented")
+}
 func (UnimplementedTonBeaconServer) mustEmbedUnimplementedTonBeaconServer() {}
 
 // UnsafeTonBeaconServer may be embedded to opt out of forward compatibility for this service.
@@ -258,6 +461,225 @@ func _TonBeacon_GetBalance_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TonBeacon_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_GetTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_SubscribeAccountEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAccountEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TonBeaconServer).SubscribeAccountEvents(m, &tonBeaconSubscribeAccountEventsServer{stream})
+}
+
+type TonBeacon_SubscribeAccountEventsServer interface {
+	Send(*AccountEvent) error
+	grpc.ServerStream
+}
+
+type tonBeaconSubscribeAccountEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *tonBeaconSubscribeAccountEventsServer) Send(m *AccountEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TonBeacon_SetSweepPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSweepPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).SetSweepPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_SetSweepPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).SetSweepPolicy(ctx, req.(*SetSweepPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_ListSweepPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSweepPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).ListSweepPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_ListSweepPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).ListSweepPolicies(ctx, req.(*ListSweepPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_DeleteSweepPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSweepPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).DeleteSweepPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_DeleteSweepPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).DeleteSweepPolicy(ctx, req.(*DeleteSweepPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_PlanSweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).PlanSweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_PlanSweep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).PlanSweep(ctx, req.(*PlanSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_CreateWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).CreateWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_CreateWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).CreateWithdrawal(ctx, req.(*CreateWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_GetWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).GetWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_GetWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).GetWithdrawal(ctx, req.(*GetWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_ListWithdrawals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWithdrawalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).ListWithdrawals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_ListWithdrawals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).ListWithdrawals(ctx, req.(*ListWithdrawalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_ApproveWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).ApproveWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_ApproveWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).ApproveWithdrawal(ctx, req.(*ApproveWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TonBeacon_RejectWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TonBeaconServer).RejectWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TonBeacon_RejectWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TonBeaconServer).RejectWithdrawal(ctx, req.(*RejectWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TonBeacon_ServiceDesc is the grpc.ServiceDesc for TonBeacon service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -289,7 +711,57 @@ var TonBeacon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBalance",
 			Handler:    _TonBeacon_GetBalance_Handler,
 		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _TonBeacon_ListTransactions_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _TonBeacon_GetTransaction_Handler,
+		},
+		{
+			MethodName: "SetSweepPolicy",
+			Handler:    _TonBeacon_SetSweepPolicy_Handler,
+		},
+		{
+			MethodName: "ListSweepPolicies",
+			Handler:    _TonBeacon_ListSweepPolicies_Handler,
+		},
+		{
+			MethodName: "DeleteSweepPolicy",
+			Handler:    _TonBeacon_DeleteSweepPolicy_Handler,
+		},
+		{
+			MethodName: "PlanSweep",
+			Handler:    _TonBeacon_PlanSweep_Handler,
+		},
+		{
+			MethodName: "CreateWithdrawal",
+			Handler:    _TonBeacon_CreateWithdrawal_Handler,
+		},
+		{
+			MethodName: "GetWithdrawal",
+			Handler:    _TonBeacon_GetWithdrawal_Handler,
+		},
+		{
+			MethodName: "ListWithdrawals",
+			Handler:    _TonBeacon_ListWithdrawals_Handler,
+		},
+		{
+			MethodName: "ApproveWithdrawal",
+			Handler:    _TonBeacon_ApproveWithdrawal_Handler,
+		},
+		{
+			MethodName: "RejectWithdrawal",
+			Handler:    _TonBeacon_RejectWithdrawal_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeAccountEvents",
+			Handler:       _TonBeacon_SubscribeAccountEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/grpc/v1/tonbeacon.proto",
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"strings"
@@ -11,6 +13,8 @@ import (
 	"github.com/rs/zerolog/pkgerrors"
 	"github.com/spf13/viper"
 	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/cmd/internal/sweepconfig"
 )
 
 type MasterKey struct {
@@ -35,12 +39,57 @@ func (dc *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s", dc.User, dc.Password, dc.Host, dc.Port, dc.DBName, dc.SSLMode)
 }
 
+// GRPCAuthConfig authenticates the operators deciding on the withdrawals. An operator is authenticated
+// by an allowed common name of a client certificate signed by the client CA or by a bearer token.
+type GRPCAuthConfig struct {
+	CertFile     string `mapstructure:"cert_file"`
+	KeyFile      string `mapstructure:"key_file"`
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// OperatorCommonNames are the common names of the client certificates accepted as operators.
+	OperatorCommonNames []string `mapstructure:"operator_common_names"`
+	// OperatorTokens are the bearer tokens of the operators by their names, they require TLS.
+	OperatorTokens map[string]string `mapstructure:"operator_tokens"`
+}
+
+// TLSConfig returns the TLS config of the server, nil without a certificate. The client certificates
+// are verified if they are given, the operators may use the tokens instead.
+func (gc *GRPCAuthConfig) TLSConfig() (*tls.Config, error) {
+	if gc.CertFile == "" {
+		if len(gc.OperatorTokens) > 0 {
+			return nil, errors.New("operator tokens require TLS")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(gc.CertFile, gc.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "load server certificate")
+	}
+
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if gc.ClientCAFile != "" {
+		pem, err := os.ReadFile(gc.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read client CA")
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates in the client CA")
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
 type Config struct {
-	LogLevel  string         `mapstructure:"log_level"`
-	GRPCPort  string         `mapstructure:"grpc_port"`
-	IsMainnet bool           `mapstructure:"is_mainnet"`
-	Master    MasterKey      `mapstructure:"master"`
-	Database  DatabaseConfig `mapstructure:"database"`
+	LogLevel  string                      `mapstructure:"log_level"`
+	GRPCPort  string                      `mapstructure:"grpc_port"`
+	IsMainnet bool                        `mapstructure:"is_mainnet"`
+	Master    MasterKey                   `mapstructure:"master"`
+	Database  DatabaseConfig              `mapstructure:"database"`
+	Collector sweepconfig.CollectorConfig `mapstructure:"collector"`
+	GRPCAuth  GRPCAuthConfig              `mapstructure:"grpc_auth"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -52,6 +101,8 @@ func LoadConfig() (*Config, error) {
 	v.AddConfigPath("$HOME")
 	v.AddConfigPath("./.dev")
 
+	sweepconfig.SetDefaults(v, "collector")
+
 	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.Wrap(err, "read config")
 	}
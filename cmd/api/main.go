@@ -16,8 +16,14 @@ import (
 	"github.com/kriuchkov/tonbeacon/adapters/grpc"
 	"github.com/kriuchkov/tonbeacon/adapters/repository"
 	"github.com/kriuchkov/tonbeacon/adapters/ton"
+	"github.com/kriuchkov/tonbeacon/core/model"
 	"github.com/kriuchkov/tonbeacon/pkg/common"
 	"github.com/kriuchkov/tonbeacon/ports/account"
+	"github.com/kriuchkov/tonbeacon/ports/collector"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+	"github.com/kriuchkov/tonbeacon/ports/policy"
+	"github.com/kriuchkov/tonbeacon/ports/withdrawal"
 )
 
 func main() {
@@ -49,12 +55,60 @@ func main() {
 		log.Panic().Err(err).Msg("master wallet creation")
 	}
 
+	walletAdapter := ton.NewWalletAdapter(liteClient, masterWallet)
+	jettons := make([]collector.Jetton, 0, len(cfg.Collector.Jettons))
+	currencies := make([]model.Currency, 0, len(cfg.Collector.Jettons))
+	for _, jetton := range cfg.Collector.Jettons {
+		currency := model.Currency(jetton.Currency)
+		if err = walletAdapter.AddJetton(currency, jetton.Master); err != nil {
+			log.Panic().Err(err).Str("currency", jetton.Currency).Msg("jetton setup")
+		}
+		jettons = append(jettons, collector.Jetton{Currency: currency, Threshold: model.NewAmount(jetton.ThresholdNano)})
+		currencies = append(currencies, currency)
+	}
+
+	ledgerSvc := ledger.New(&ledger.Options{Database: repositoryAdapter, Withdrawals: repositoryAdapter, TxManager: repository.NewTxRepository(db)})
 	accountSvc := account.New(account.Options{
-		WalletManager:   ton.NewWalletAdapter(liteClient, masterWallet),
+		WalletManager:   walletAdapter,
 		TxManager:       repository.NewTxRepository(db),
 		DatabaseManager: repositoryAdapter,
+		EventManager:    outbox.New(repositoryAdapter),
+		Ledger:          ledgerSvc,
 	})
 
+	// The withdrawals are only accepted here, they are sent by cmd/withdrawer.
+	withdrawalSvc := withdrawal.New(&withdrawal.Options{
+		Database:  repositoryAdapter,
+		Approvals: repositoryAdapter,
+		Accounts:  repositoryAdapter,
+		Ledger:    ledgerSvc,
+		Locks:     repositoryAdapter,
+		TxPort:    repository.NewTxRepository(db),
+		Events:    outbox.New(repositoryAdapter),
+		Jettons:   currencies,
+	})
+
+	// The collector only plans the sweeps here, it is run by cmd/collector.
+	collectorSvc := collector.NewCollectorService(&collector.Options{
+		WalletPort:     walletAdapter,
+		RepositoryPort: repositoryAdapter,
+		TransferPort:   repositoryAdapter,
+		SweepPort:      repositoryAdapter,
+		TxPort:         repository.NewTxRepository(db),
+		Seqnos:         repositoryAdapter,
+		Policies:       repositoryAdapter,
+		Threshold:      model.NewAmount(cfg.Collector.ThresholdNano),
+		Reserve:        model.NewAmount(cfg.Collector.ReserveNano),
+		Jettons:        jettons,
+		JettonGas:      model.NewAmount(cfg.Collector.JettonGasNano),
+		SweepFee:       model.NewAmount(cfg.Collector.SweepFeeNano),
+	})
+
+	tlsConfig, err := cfg.GRPCAuth.TLSConfig()
+	if err != nil {
+		log.Panic().Err(err).Msg("grpc tls config")
+	}
+
 	lis, err := net.Listen("tcp", cfg.GRPCPort)
 	if err != nil {
 		log.Panic().Err(err).Msg("grpc server listen")
@@ -63,7 +117,17 @@ func main() {
 	log.Info().Str("port", cfg.GRPCPort).Msg("grpc server started")
 
 	go func() {
-		grpcServer := grpc.NewTonBeacon(accountSvc)
+		grpcServer := grpc.NewTonBeacon(&grpc.Options{
+			Account:     accountSvc,
+			Events:      outbox.NewStream(&outbox.StreamOptions{Database: repositoryAdapter}),
+			Policies:    policy.New(&policy.Options{Database: repositoryAdapter, Accounts: repositoryAdapter}),
+			Collector:   collectorSvc,
+			Withdrawals: withdrawalSvc,
+
+			TLS:            tlsConfig,
+			Operators:      cfg.GRPCAuth.OperatorCommonNames,
+			OperatorTokens: cfg.GRPCAuth.OperatorTokens,
+		})
 		if err = grpcServer.Run(lis); err != nil {
 			log.Panic().Err(err).Msg("grpc server run")
 		}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -20,6 +22,12 @@ func cmdGenerateSeed(_ context.Context) *cobra.Command {
 			phrase := walletutils.NewSeed()
 			fmt.Println("Your seed phrase:")
 			fmt.Println(phrase)
+
+			// the public key configures the read-only commands, like sweep plan, without the seed
+			if key, err := walletutils.SeedToPrivateKey(phrase, "", false); err == nil {
+				fmt.Println("\nPublic key:")
+				fmt.Println(hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+			}
 			fmt.Println("\nWARNING: Store this seed phrase securely. Anyone with access to this phrase will have access to your wallet.")
 		},
 	}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	archiveadapter "github.com/kriuchkov/tonbeacon/adapters/archive"
+	"github.com/kriuchkov/tonbeacon/adapters/repository"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+)
+
+func cmdOutbox(ctx context.Context) *cobra.Command {
+	command := cobra.Command{
+		Use:   "outbox",
+		Short: "Outbox maintenance",
+	}
+
+	command.AddCommand(cmdOutboxPurge(ctx))
+	return &command
+}
+
+func cmdOutboxPurge(ctx context.Context) *cobra.Command {
+	command := cobra.Command{
+		Use:   "purge",
+		Short: "Archive and delete processed outbox events",
+		Run: func(cmd *cobra.Command, _ []string) {
+			cfg, err := LoadConfig()
+			if err != nil {
+				log.Warn().Err(err).Msg("config loading")
+				os.Exit(64)
+			}
+
+			if err = cfg.Database.Validate(); err != nil {
+				log.Warn().Err(err).Msg("database config validation")
+				os.Exit(64)
+			}
+
+			maxAge, _ := cmd.Flags().GetDuration("max-age")
+			batchSize, _ := cmd.Flags().GetInt("batch-size")
+			archiveMode, _ := cmd.Flags().GetString("archive")
+			archivePath, _ := cmd.Flags().GetString("archive-path")
+
+			db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.Database.DSN()))), pgdialect.New())
+			defer db.Close()
+
+			if err = db.PingContext(ctx); err != nil {
+				log.Error().Err(err).Msg("db connection")
+				os.Exit(1)
+			}
+
+			dataBase := repository.New(db)
+
+			var archive ports.OutboxArchivePort
+			switch model.OutboxArchiveMode(archiveMode) {
+			case model.OutboxArchiveTable:
+				archive = dataBase
+			case model.OutboxArchiveFile:
+				if archive, err = archiveadapter.NewJSONLArchive(archivePath); err != nil {
+					log.Warn().Err(err).Msg("jsonl archive")
+					os.Exit(64)
+				}
+			case model.OutboxArchiveNone:
+			default:
+				log.Warn().Str("archive", archiveMode).Msg("unknown archive mode")
+				os.Exit(64)
+			}
+
+			retention := outbox.NewRetention(&outbox.RetentionOptions{
+				Database:  dataBase,
+				TxManager: repository.NewTxRepository(db),
+				Archive:   archive,
+				MaxAge:    maxAge,
+				BatchSize: batchSize,
+			})
+
+			purged, err := retention.Purge(ctx)
+			if err != nil {
+				log.Error().Err(err).Int("purged", purged).Msg("purge outbox events")
+				os.Exit(1)
+			}
+
+			fmt.Println("Purged outbox events:", purged)
+		},
+	}
+
+	command.Flags().Duration("max-age", 7*24*time.Hour, "Purge processed events older than this age")
+	command.Flags().Int("batch-size", 1000, "Number of events deleted per transaction")
+	command.Flags().String("archive", string(model.OutboxArchiveTable), "Archive mode: none, table or file")
+	command.Flags().String("archive-path", "", "Directory for JSONL archive files (archive=file)")
+	return &command
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+
+	"github.com/kriuchkov/tonbeacon/adapters/repository"
+	"github.com/kriuchkov/tonbeacon/adapters/ton"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/ports/collector"
+)
+
+func cmdSweep(ctx context.Context) *cobra.Command {
+	command := cobra.Command{
+		Use:   "sweep",
+		Short: "Sweeps of the subwallets to the master wallet",
+	}
+
+	command.AddCommand(cmdSweepPlan(ctx))
+	return &command
+}
+
+func cmdSweepPlan(ctx context.Context) *cobra.Command {
+	command := cobra.Command{
+		Use:   "plan",
+		Short: "Print the sweeps the collector would plan in the current balances without sending anything",
+		Run: func(cmd *cobra.Command, _ []string) {
+			cfg, err := LoadConfig()
+			if err != nil {
+				log.Warn().Err(err).Msg("config loading")
+				os.Exit(64)
+			}
+
+			if err = cfg.Database.Validate(); err != nil {
+				log.Warn().Err(err).Msg("database config validation")
+				os.Exit(64)
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			accountIDs, _ := cmd.Flags().GetStringSlice("account")
+			mainnet, _ := cmd.Flags().GetBool("mainnet")
+
+			if output != "table" && output != "json" {
+				log.Warn().Str("output", output).Msg("unknown output format")
+				os.Exit(64)
+			}
+
+			db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.Database.DSN()))), pgdialect.New())
+			defer db.Close()
+
+			if err = db.PingContext(ctx); err != nil {
+				log.Error().Err(err).Msg("db connection")
+				os.Exit(1)
+			}
+
+			liteClient, err := setupLiteClient(ctx, mainnet)
+			if err != nil {
+				log.Error().Err(err).Msg("liteclient setup")
+				os.Exit(1)
+			}
+
+			// the plan is read-only, so the master wallet is built from its public key without the seed
+			publicKey, err := cfg.Master.GetPublicKey()
+			if err != nil {
+				log.Warn().Err(err).Msg("master public key")
+				os.Exit(64)
+			}
+
+			masterWallet, err := ton.NewWatchOnlyWallet(liteClient, publicKey, cfg.Master.Version)
+			if err != nil {
+				log.Warn().Err(err).Msg("master wallet creation")
+				os.Exit(64)
+			}
+
+			walletAdapter := ton.NewWalletAdapter(liteClient, masterWallet)
+			jettons := make([]collector.Jetton, 0, len(cfg.Collector.Jettons))
+			for _, jetton := range cfg.Collector.Jettons {
+				currency := model.Currency(jetton.Currency)
+				if err = walletAdapter.AddJetton(currency, jetton.Master); err != nil {
+					log.Warn().Err(err).Str("currency", jetton.Currency).Msg("jetton setup")
+					os.Exit(64)
+				}
+				jettons = append(jettons, collector.Jetton{Currency: currency, Threshold: model.NewAmount(jetton.ThresholdNano)})
+			}
+
+			dataBase := repository.New(db)
+			collectorSvc := collector.NewCollectorService(&collector.Options{
+				WalletPort:     walletAdapter,
+				RepositoryPort: dataBase,
+				TransferPort:   dataBase,
+				SweepPort:      dataBase,
+				TxPort:         repository.NewTxRepository(db),
+				Seqnos:         dataBase,
+				Policies:       dataBase,
+				Threshold:      model.NewAmount(cfg.Collector.ThresholdNano),
+				Reserve:        model.NewAmount(cfg.Collector.ReserveNano),
+				Jettons:        jettons,
+				JettonGas:      model.NewAmount(cfg.Collector.JettonGasNano),
+				SweepFee:       model.NewAmount(cfg.Collector.SweepFeeNano),
+			})
+
+			sweeps, err := collectorSvc.PlanSweeps(ctx, accountIDs)
+			if errors.Is(err, model.ErrAccountNotFound) {
+				log.Warn().Err(err).Msg("plan sweeps")
+				os.Exit(64)
+			}
+			if err != nil {
+				log.Error().Err(err).Msg("plan sweeps")
+				os.Exit(1)
+			}
+
+			if output == "json" {
+				err = printSweepPlanJSON(os.Stdout, sweeps)
+			} else {
+				err = printSweepPlanTable(os.Stdout, sweeps)
+			}
+			if err != nil {
+				log.Error().Err(err).Msg("print sweep plan")
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringP("output", "o", "table", "Output format: table or json")
+	command.Flags().StringSlice("account", nil, "Plan the sweeps of these accounts only")
+	command.Flags().Bool("mainnet", false, "Use mainnet")
+	return &command
+}
+
+// plannedSweep is the JSON of a planned sweep, the amounts are in whole units like in the API.
+type plannedSweep struct {
+	AccountID string `json:"account_id"`
+	WalletID  uint32 `json:"wallet_id"`
+	Address   string `json:"address"`
+	Currency  string `json:"currency"`
+	Balance   string `json:"balance"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
+	TopUp     string `json:"top_up"`
+	Reserve   string `json:"reserve"`
+	// UnableToPayFee is set if the reserve of a TON sweep does not cover its fee.
+	UnableToPayFee bool `json:"unable_to_pay_fee,omitempty"`
+}
+
+func printSweepPlanJSON(w io.Writer, sweeps []*model.PlannedSweep) error {
+	plan := make([]plannedSweep, 0, len(sweeps))
+	for _, sweep := range sweeps {
+		plan = append(plan, plannedSweep{
+			AccountID: sweep.AccountID,
+			WalletID:  sweep.WalletID,
+			Address:   sweep.Address.String(),
+			Currency:  string(sweep.Currency),
+			Balance:   sweep.Balance.String(),
+			Amount:    sweep.Amount.String(),
+			Fee:       sweep.Fee.String(),
+			TopUp:     sweep.TopUp.String(),
+			Reserve:   sweep.Reserve.String(),
+
+			UnableToPayFee: sweep.UnableToPayFee,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan)
+}
+
+func printSweepPlanTable(w io.Writer, sweeps []*model.PlannedSweep) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ACCOUNT\tWALLET\tCURRENCY\tBALANCE\tAMOUNT\tFEE (TON)\tTOP-UP (TON)\tRESERVE")
+
+	for _, sweep := range sweeps {
+		reserve := sweep.Reserve.String()
+		if sweep.UnableToPayFee {
+			reserve = "unable to pay fee"
+		}
+
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			sweep.AccountID, sweep.WalletID, sweep.Currency, sweep.Balance.String(), sweep.Amount.String(),
+			sweep.Fee.String(), sweep.TopUp.String(), reserve)
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, "Planned sweeps:", len(sweeps))
+	return err
+}
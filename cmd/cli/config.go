@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"strings"
 
@@ -12,11 +15,15 @@ import (
 	"github.com/spf13/viper"
 
 	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/cmd/internal/sweepconfig"
 )
 
 type MasterKey struct {
 	Seed    string              `mapstructure:"seed" validate:"required"`
 	Version walletutils.Version `mapstructure:"version" validate:"required"`
+	// PublicKey is the hex public key of the master wallet, the read-only commands use it instead of the seed.
+	PublicKey string `mapstructure:"public_key"`
 }
 
 func (mk *MasterKey) Validate() error {
@@ -30,9 +37,47 @@ func (mk *MasterKey) GetSeed() []string {
 	return strings.Split(mk.Seed, " ")
 }
 
+func (mk *MasterKey) GetPublicKey() (ed25519.PublicKey, error) {
+	if mk.PublicKey == "" {
+		return nil, errors.New("master public key is not set")
+	}
+
+	key, err := hex.DecodeString(mk.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode master public key")
+	}
+
+	if len(key) != ed25519.PublicKeySize {
+		return nil, errors.Errorf("master public key has %d bytes", len(key))
+	}
+	return key, nil
+}
+
+type DatabaseConfig struct {
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     int    `mapstructure:"port" validate:"required"`
+	User     string `mapstructure:"user" validate:"required"`
+	Password string `mapstructure:"password" validate:"required"`
+	DBName   string `mapstructure:"dbname" validate:"required"`
+	SSLMode  string `mapstructure:"sslmode" default:"disable"`
+}
+
+func (dc *DatabaseConfig) Validate() error {
+	if err := validator.New().Struct(dc); err != nil {
+		return errors.Wrap(err, "validate database config")
+	}
+	return nil
+}
+
+func (dc *DatabaseConfig) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s", dc.User, dc.Password, dc.Host, dc.Port, dc.DBName, dc.SSLMode)
+}
+
 type Config struct {
-	LogLevel string    `mapstructure:"log_level"`
-	Master   MasterKey `mapstructure:"master"`
+	LogLevel  string                      `mapstructure:"log_level"`
+	Master    MasterKey                   `mapstructure:"master"`
+	Database  DatabaseConfig              `mapstructure:"database"`
+	Collector sweepconfig.CollectorConfig `mapstructure:"collector"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -46,6 +91,15 @@ func LoadConfig() (*Config, error) {
 
 	v.BindEnv("log_level")
 	v.BindEnv("master.seed")
+	v.BindEnv("master.public_key")
+	v.BindEnv("database.host")
+	v.BindEnv("database.port")
+	v.BindEnv("database.user")
+	v.BindEnv("database.password")
+	v.BindEnv("database.dbname")
+	v.BindEnv("database.sslmode")
+
+	sweepconfig.SetDefaults(v, "collector")
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.Wrap(err, "read config")
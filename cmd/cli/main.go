@@ -18,6 +18,8 @@ func main() {
 	rootCmd.AddCommand(cmdGenerateSeed(ctx))
 	rootCmd.AddCommand(cmdTransfer(ctx))
 	rootCmd.AddCommand(cmdAccount(ctx))
+	rootCmd.AddCommand(cmdOutbox(ctx))
+	rootCmd.AddCommand(cmdSweep(ctx))
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/pkgerrors"
+	"github.com/spf13/viper"
+	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/cmd/internal/sweepconfig"
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+const (
+	// defaultLogLevel is the default log level.
+	defaultLogLevel = "info"
+
+	// defaultCollectInterval is the default interval between the collection runs.
+	defaultCollectInterval = time.Minute
+
+	// defaultRebalanceInterval is the default interval between the checks of the master wallet balance.
+	defaultRebalanceInterval = 10 * time.Minute
+)
+
+type MasterKey struct {
+	Seed    string              `mapstructure:"seed" validate:"required"`
+	Version walletutils.Version `mapstructure:"version"`
+}
+
+func (mk *MasterKey) GetSeed() []string {
+	return strings.Split(mk.Seed, " ")
+}
+
+type DatabaseConfig struct {
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     int    `mapstructure:"port" validate:"required"`
+	User     string `mapstructure:"user" validate:"required"`
+	Password string `mapstructure:"password" validate:"required"`
+	DBName   string `mapstructure:"dbname" validate:"required"`
+	SSLMode  string `mapstructure:"sslmode" default:"disable"`
+}
+
+func (dc *DatabaseConfig) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s", dc.User, dc.Password, dc.Host, dc.Port, dc.DBName, dc.SSLMode)
+}
+
+type CollectorConfig struct {
+	Interval time.Duration `mapstructure:"interval" validate:"gt=0"`
+
+	// ThresholdNano is the balance in nanotons above which a subwallet is swept.
+	ThresholdNano int64 `mapstructure:"threshold_nano" validate:"gte=0"`
+
+	// ReserveNano is the balance in nanotons left on a subwallet to pay the storage and the gas of the sweep.
+	ReserveNano int64 `mapstructure:"reserve_nano" validate:"gte=0"`
+
+	// ConfirmTimeout is how long after the expiration of its message a sweep waits for the confirmation
+	// before the seqno of the subwallet is checked.
+	ConfirmTimeout time.Duration `mapstructure:"confirm_timeout" validate:"gte=0"`
+
+	// JettonGasNano is the TON in nanotons attached to a jetton transfer, the subwallet is topped up
+	// by the master wallet to hold it with the reserve.
+	JettonGasNano int64 `mapstructure:"jetton_gas_nano" validate:"gte=0"`
+
+	// Jettons are the jettons swept from the subwallets, they are configured in the file only.
+	Jettons []sweepconfig.JettonConfig `mapstructure:"jettons" validate:"dive"`
+}
+
+type RebalanceConfig struct {
+	Interval time.Duration `mapstructure:"interval" validate:"gt=0"`
+
+	// ColdAddress receives the balance of the master wallet above the ceilings.
+	ColdAddress string `mapstructure:"cold_address" validate:"required_with=Limits"`
+
+	// Limits bound the balance of the master wallet per currency, they are configured in the file only.
+	// The rebalancer does not run without them.
+	Limits []HotWalletLimitConfig `mapstructure:"limits" validate:"dive"`
+}
+
+// HotWalletLimitConfig bounds the balance of the master wallet in the currency, the amounts are in whole units
+// of the currency and an empty amount is not checked.
+type HotWalletLimitConfig struct {
+	Currency string `mapstructure:"currency" validate:"required"`
+	// Ceiling is the balance above which the excess is moved to the cold address.
+	Ceiling string `mapstructure:"ceiling"`
+	// Floor is the balance below which the hot_wallet_low event is published.
+	Floor string `mapstructure:"floor"`
+}
+
+// ToModel returns the hot wallet limit.
+func (hc *HotWalletLimitConfig) ToModel() (model.HotWalletLimit, error) {
+	limit := model.HotWalletLimit{Currency: model.Currency(hc.Currency)}
+
+	var err error
+	if limit.Ceiling, err = parseLimit(hc.Ceiling); err != nil {
+		return limit, errors.Wrapf(err, "ceiling of %s", hc.Currency)
+	}
+
+	if limit.Floor, err = parseLimit(hc.Floor); err != nil {
+		return limit, errors.Wrapf(err, "floor of %s", hc.Currency)
+	}
+	return limit, nil
+}
+
+func parseLimit(limit string) (model.Amount, error) {
+	if limit == "" {
+		return model.NewAmount(0), nil
+	}
+	return model.ParseUnits(limit)
+}
+
+type Config struct {
+	LogLevel  string          `mapstructure:"log_level"`
+	IsMainnet bool            `mapstructure:"is_mainnet"`
+	Master    MasterKey       `mapstructure:"master"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Collector CollectorConfig `mapstructure:"collector"`
+	Rebalance RebalanceConfig `mapstructure:"rebalance"`
+}
+
+func (c *Config) Validate() error {
+	if err := validator.New().Struct(c); err != nil {
+		return errors.Wrap(err, "validate config")
+	}
+	return nil
+}
+
+func LoadConfig() (*Config, error) {
+	v := viper.New()
+
+	// file
+	v.SetConfigName(".config.collector")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+	v.AddConfigPath("$HOME")
+	v.AddConfigPath("./.dev")
+
+	// env
+	v.SetEnvPrefix("tonbeacon")
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// Bind environment variables
+	v.BindEnv("log_level")
+	v.BindEnv("is_mainnet")
+	v.BindEnv("master.seed")
+	v.BindEnv("master.version")
+	v.BindEnv("database.host")
+	v.BindEnv("database.port")
+	v.BindEnv("database.user")
+	v.BindEnv("database.password")
+	v.BindEnv("database.dbname")
+	v.BindEnv("database.sslmode")
+	v.BindEnv("collector.interval")
+	v.BindEnv("collector.threshold_nano")
+	v.BindEnv("collector.reserve_nano")
+	v.BindEnv("collector.confirm_timeout")
+	v.BindEnv("collector.jetton_gas_nano")
+	v.BindEnv("rebalance.interval")
+	v.BindEnv("rebalance.cold_address")
+
+	// Defaults
+	v.SetDefault("log_level", defaultLogLevel)
+	v.SetDefault("collector.interval", defaultCollectInterval)
+	v.SetDefault("collector.threshold_nano", sweepconfig.DefaultThresholdNano)
+	v.SetDefault("collector.reserve_nano", sweepconfig.DefaultReserveNano)
+	v.SetDefault("collector.jetton_gas_nano", sweepconfig.DefaultJettonGasNano)
+	v.SetDefault("rebalance.interval", defaultRebalanceInterval)
+
+	if err := v.ReadInConfig(); err != nil {
+		var errViper viper.ConfigFileNotFoundError
+		if !errors.As(err, &errViper) {
+			return nil, errors.Wrap(err, "read config")
+		}
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config")
+	}
+
+	level, err := zerolog.ParseLevel(config.LogLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse log level")
+	}
+	zerolog.SetGlobalLevel(level)
+
+	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+	log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.TimeFieldFormat = "2006-01-02 15:04:05.000"
+	return &config, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/signal"
+
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/adapters/repository"
+	"github.com/kriuchkov/tonbeacon/adapters/ton"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+	"github.com/kriuchkov/tonbeacon/ports/collector"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+	"github.com/kriuchkov/tonbeacon/ports/rebalance"
+)
+
+// Main runs the collector, it sweeps the TON and the jettons of the subwallets above the thresholds to the master
+// wallet on schedule until an OS signal is received. The sweeps are confirmed by the transaction processor,
+// the collector finishes the sweep jobs after it. With the hot wallet limits configured it also moves the balance
+// of the master wallet above the ceilings to the cold address and alerts the balance below the floors.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer cancel()
+	defer log.Info().Msg("collector stopped")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Warn().Err(err).Msg("load config")
+		os.Exit(64)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		log.Warn().Err(err).Msg("config validation")
+		os.Exit(64)
+	}
+
+	log.Info().
+		Dur("interval", cfg.Collector.Interval).
+		Int64("threshold_nano", cfg.Collector.ThresholdNano).
+		Int64("reserve_nano", cfg.Collector.ReserveNano).
+		Int64("jetton_gas_nano", cfg.Collector.JettonGasNano).
+		Int("jettons", len(cfg.Collector.Jettons)).
+		Int("hot_wallet_limits", len(cfg.Rebalance.Limits)).
+		Msg("config loaded")
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.Database.DSN()))), pgdialect.New())
+	if err = db.PingContext(ctx); err != nil {
+		log.Panic().Err(err).Msg("db connection")
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Warn().Err(err).Msg("close database")
+		}
+	}()
+
+	liteClient, err := common.SetupLiteClient(ctx, cfg.IsMainnet)
+	if err != nil {
+		log.Warn().Err(err).Msg("lite client setup")
+		os.Exit(1)
+	}
+
+	masterWallet, err := walletutils.FromSeed(liteClient, cfg.Master.GetSeed(), cfg.Master.Version)
+	if err != nil {
+		log.Panic().Err(err).Msg("master wallet creation")
+	}
+
+	walletAdapter := ton.NewWalletAdapter(liteClient, masterWallet)
+	jettons := make([]collector.Jetton, 0, len(cfg.Collector.Jettons))
+	for _, jetton := range cfg.Collector.Jettons {
+		currency := model.Currency(jetton.Currency)
+		if err = walletAdapter.AddJetton(currency, jetton.Master); err != nil {
+			log.Panic().Err(err).Str("currency", jetton.Currency).Msg("jetton setup")
+		}
+		jettons = append(jettons, collector.Jetton{Currency: currency, Threshold: model.NewAmount(jetton.ThresholdNano)})
+	}
+
+	repositoryAdapter := repository.New(db)
+	collectorService := collector.NewCollectorService(&collector.Options{
+		WalletPort:      walletAdapter,
+		RepositoryPort:  repositoryAdapter,
+		TransferPort:    repositoryAdapter,
+		SweepPort:       repositoryAdapter,
+		TxPort:          repository.NewTxRepository(db),
+		Seqnos:          repositoryAdapter,
+		Events:          outbox.New(repositoryAdapter),
+		Policies:        repositoryAdapter,
+		Threshold:       model.NewAmount(cfg.Collector.ThresholdNano),
+		Reserve:         model.NewAmount(cfg.Collector.ReserveNano),
+		Jettons:         jettons,
+		JettonGas:       model.NewAmount(cfg.Collector.JettonGasNano),
+		CollectInterval: cfg.Collector.Interval,
+		ConfirmTimeout:  cfg.Collector.ConfirmTimeout,
+	})
+
+	if len(cfg.Rebalance.Limits) > 0 {
+		limits := make([]model.HotWalletLimit, 0, len(cfg.Rebalance.Limits))
+		for _, limitConfig := range cfg.Rebalance.Limits {
+			limit, err := limitConfig.ToModel()
+			if err != nil {
+				log.Panic().Err(err).Msg("invalid hot wallet limit")
+			}
+			limits = append(limits, limit)
+		}
+
+		rebalancer := rebalance.New(&rebalance.Options{
+			WalletPort:     walletAdapter,
+			Database:       repositoryAdapter,
+			TransferPort:   repositoryAdapter,
+			TxPort:         repository.NewTxRepository(db),
+			Events:         outbox.New(repositoryAdapter),
+			Seqnos:         repositoryAdapter,
+			ColdAddress:    cfg.Rebalance.ColdAddress,
+			Limits:         limits,
+			JettonGas:      model.NewAmount(cfg.Collector.JettonGasNano),
+			Interval:       cfg.Rebalance.Interval,
+			ConfirmTimeout: cfg.Collector.ConfirmTimeout,
+		})
+
+		log.Info().Str("cold_address", cfg.Rebalance.ColdAddress).Msg("rebalancer started")
+		go rebalancer.Run(ctx)
+	}
+
+	log.Info().Str("master_address", masterWallet.WalletAddress().String()).Msg("collector started")
+	collectorService.Run(ctx)
+}
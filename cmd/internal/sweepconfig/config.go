@@ -0,0 +1,48 @@
+// Package sweepconfig is the sweep configuration of the commands running or planning the sweeps of the subwallets.
+package sweepconfig
+
+import (
+	"github.com/spf13/viper"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+const (
+	// DefaultThresholdNano is the default balance in nanotons above which a subwallet is swept.
+	DefaultThresholdNano = 1_000_000_000
+
+	// DefaultReserveNano is the default balance in nanotons left on a subwallet for the storage and the gas.
+	DefaultReserveNano = 50_000_000
+
+	// DefaultJettonGasNano is the default TON in nanotons attached to a jetton transfer.
+	DefaultJettonGasNano = model.DefaultJettonGasNano
+
+	// DefaultSweepFeeNano is the default estimated fee in nanotons of a sweep transaction.
+	DefaultSweepFeeNano = 5_000_000
+)
+
+// CollectorConfig mirrors the sweep settings of the collector, the sweeps are planned with them.
+type CollectorConfig struct {
+	ThresholdNano int64 `mapstructure:"threshold_nano"`
+	ReserveNano   int64 `mapstructure:"reserve_nano"`
+	JettonGasNano int64 `mapstructure:"jetton_gas_nano"`
+	// SweepFeeNano is the estimated fee in nanotons of a sweep or a top-up transaction.
+	SweepFeeNano int64          `mapstructure:"sweep_fee_nano"`
+	Jettons      []JettonConfig `mapstructure:"jettons"`
+}
+
+type JettonConfig struct {
+	Currency string `mapstructure:"currency" validate:"required"`
+	// Master is the address of the jetton master contract.
+	Master string `mapstructure:"master" validate:"required"`
+	// ThresholdNano is the jetton balance in the jetton units above which a subwallet is swept.
+	ThresholdNano int64 `mapstructure:"threshold_nano" validate:"gte=0"`
+}
+
+// SetDefaults sets the defaults of the collector config read under the key.
+func SetDefaults(v *viper.Viper, key string) {
+	v.SetDefault(key+".threshold_nano", DefaultThresholdNano)
+	v.SetDefault(key+".reserve_nano", DefaultReserveNano)
+	v.SetDefault(key+".jetton_gas_nano", DefaultJettonGasNano)
+	v.SetDefault(key+".sweep_fee_nano", DefaultSweepFeeNano)
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/go-faster/errors"
@@ -12,6 +14,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 	"github.com/spf13/viper"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
 )
 
 const (
@@ -23,6 +28,27 @@ const (
 
 	// defaultKafkaRequiredAcks is the default number of required acks for Kafka producer.
 	defaultKafkaRequiredAcks = sarama.WaitForAll
+
+	// defaultRetryStartDelay is the delay before the second attempt to handle a message.
+	defaultRetryStartDelay = 100 * time.Millisecond
+
+	// defaultRetryMaxDelay is the maximal delay between attempts to handle a message.
+	defaultRetryMaxDelay = time.Second
+
+	// defaultRetryBackoffCoefficient is the multiplier of the delay between attempts.
+	defaultRetryBackoffCoefficient = 2
+
+	// defaultOutboxRetentionMaxAge is the default age after which processed outbox events are purged.
+	defaultOutboxRetentionMaxAge = 7 * 24 * time.Hour
+
+	// defaultOutboxRetentionBatchSize is the default number of outbox events purged per transaction.
+	defaultOutboxRetentionBatchSize = 1000
+
+	// defaultOutboxRetentionInterval is the default interval between outbox retention runs.
+	defaultOutboxRetentionInterval = time.Hour
+
+	// defaultOutboxRetentionArchive is the default archive mode for purged outbox events.
+	defaultOutboxRetentionArchive = model.OutboxArchiveTable
 )
 
 type DatabaseConfig struct {
@@ -51,14 +77,60 @@ type Kafka struct {
 	GroupID      string              `mapstructure:"group_id"`
 	MaxRetries   int                 `mapstructure:"max_retries"`
 	RequiredAcks sarama.RequiredAcks `mapstructure:"required_acks"`
+
+	// Client holds TLS, SASL and other connection settings.
+	Client kafka.Config `mapstructure:",squash"`
 }
 
 type TransactionProcessorConfig struct {
 	Kafka `mapstructure:",squash"`
+
+	// RetryAttempts is the number of in-place attempts to handle a message.
+	RetryAttempts   int           `mapstructure:"retry_attempts"`
+	RetryTopic      string        `mapstructure:"retry_topic"`
+	RetryDelay      time.Duration `mapstructure:"retry_delay"`
+	MaxRetryRounds  int           `mapstructure:"max_retry_rounds"`
+	DeadLetterTopic string        `mapstructure:"dead_letter_topic"`
+
+	// Concurrency is the number of workers per partition, messages of the same address are handled in order.
+	Concurrency int `mapstructure:"concurrency"`
+	BatchSize   int `mapstructure:"batch_size"`
+
+	// AccountEventsTopic is the topic of the outbox events, account events update the account index immediately.
+	// Every processor instance needs all events, so the group is unique per host unless AccountEventsGroupID is set.
+	AccountEventsTopic    string        `mapstructure:"account_events_topic"`
+	AccountEventsGroupID  string        `mapstructure:"account_events_group_id"`
+	AccountResyncInterval time.Duration `mapstructure:"account_resync_interval"`
+
+	// MinDepositNano is the minimal credited deposit in nanotons, smaller deposits are rejected.
+	MinDepositNano int64 `mapstructure:"min_deposit_nano" validate:"gte=0"`
+
+	// MasterAddress is the address of the master wallet, its transactions are classified as well.
+	MasterAddress string `mapstructure:"master_address"`
+	// PayoutAddress is the address of the highload payout wallet of the withdrawer, if it is enabled.
+	PayoutAddress string `mapstructure:"payout_address"`
+}
+
+// accountEventsGroupID returns the consumer group of the account events of this processor instance.
+func (tc *TransactionProcessorConfig) accountEventsGroupID() string {
+	if tc.AccountEventsGroupID != "" {
+		return tc.AccountEventsGroupID
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = strconv.Itoa(os.Getpid())
+	}
+	return tc.GroupID + "-accounts-" + hostname
 }
 
 type OutboxProcessorConfig struct {
 	Kafka `mapstructure:",squash" validate:"required"`
+
+	// ExactlyOnce relays events with the Kafka transactional producer.
+	ExactlyOnce     bool   `mapstructure:"exactly_once"`
+	TransactionalID string `mapstructure:"transactional_id" validate:"required_if=ExactlyOnce true"`
+	BatchSize       int    `mapstructure:"batch_size" validate:"gte=0"`
 }
 
 func (oc *OutboxProcessorConfig) Validate() error {
@@ -68,6 +140,21 @@ func (oc *OutboxProcessorConfig) Validate() error {
 	return nil
 }
 
+type OutboxRetentionConfig struct {
+	MaxAge      time.Duration           `mapstructure:"max_age" validate:"gt=0"`
+	BatchSize   int                     `mapstructure:"batch_size" validate:"gt=0"`
+	Interval    time.Duration           `mapstructure:"interval" validate:"gt=0"`
+	Archive     model.OutboxArchiveMode `mapstructure:"archive" validate:"oneof=none table file"`
+	ArchivePath string                  `mapstructure:"archive_path" validate:"required_if=Archive file"`
+}
+
+func (rc *OutboxRetentionConfig) Validate() error {
+	if err := validator.New().Struct(rc); err != nil {
+		return errors.Wrap(err, "validate outbox retention config")
+	}
+	return nil
+}
+
 type Config struct {
 	PPROF    string         `mapstructure:"pprof"`
 	LogLevel string         `mapstructure:"log_level"`
@@ -76,6 +163,7 @@ type Config struct {
 	// Configs for processors
 	TransactionProcessor TransactionProcessorConfig `mapstructure:"transaction_processor"`
 	OutboxProcessor      OutboxProcessorConfig      `mapstructure:"outbox_processor"`
+	OutboxRetention      OutboxRetentionConfig      `mapstructure:"outbox_retention"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -107,6 +195,10 @@ func LoadConfig() (*Config, error) {
 	v.BindEnv("outbox_processor.topic")
 	v.BindEnv("outbox_processor.max_retries")
 	v.BindEnv("outbox_processor.required_acks")
+	v.BindEnv("outbox_processor.exactly_once")
+	v.BindEnv("outbox_processor.transactional_id")
+	v.BindEnv("outbox_processor.batch_size")
+	kafka.BindEnv(v, "outbox_processor")
 
 	// transaction processor
 	v.BindEnv("transaction_processor.brokers")
@@ -114,6 +206,27 @@ func LoadConfig() (*Config, error) {
 	v.BindEnv("transaction_processor.group_id")
 	v.BindEnv("transaction_processor.max_retries")
 	v.BindEnv("transaction_processor.required_acks")
+	v.BindEnv("transaction_processor.retry_attempts")
+	v.BindEnv("transaction_processor.retry_topic")
+	v.BindEnv("transaction_processor.retry_delay")
+	v.BindEnv("transaction_processor.max_retry_rounds")
+	v.BindEnv("transaction_processor.dead_letter_topic")
+	v.BindEnv("transaction_processor.concurrency")
+	v.BindEnv("transaction_processor.batch_size")
+	v.BindEnv("transaction_processor.account_events_topic")
+	v.BindEnv("transaction_processor.account_events_group_id")
+	v.BindEnv("transaction_processor.account_resync_interval")
+	v.BindEnv("transaction_processor.min_deposit_nano")
+	v.BindEnv("transaction_processor.master_address")
+	v.BindEnv("transaction_processor.payout_address")
+	kafka.BindEnv(v, "transaction_processor")
+
+	// outbox retention
+	v.BindEnv("outbox_retention.max_age")
+	v.BindEnv("outbox_retention.batch_size")
+	v.BindEnv("outbox_retention.interval")
+	v.BindEnv("outbox_retention.archive")
+	v.BindEnv("outbox_retention.archive_path")
 
 	// Defaults
 	v.SetDefault("log_level", defaultLogLevel)
@@ -123,6 +236,11 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("transaction_processor.max_retries", defaultKafkaMaxRetries)
 	v.SetDefault("transaction_processor.required_acks", defaultKafkaRequiredAcks)
 
+	v.SetDefault("outbox_retention.max_age", defaultOutboxRetentionMaxAge)
+	v.SetDefault("outbox_retention.batch_size", defaultOutboxRetentionBatchSize)
+	v.SetDefault("outbox_retention.interval", defaultOutboxRetentionInterval)
+	v.SetDefault("outbox_retention.archive", defaultOutboxRetentionArchive)
+
 	if err := v.ReadInConfig(); err != nil {
 		var errViper viper.ConfigFileNotFoundError
 		if !errors.As(err, &errViper) {
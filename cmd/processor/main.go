@@ -9,14 +9,22 @@ import (
 
 	"github.com/go-faster/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"golang.org/x/sync/errgroup"
 
+	archiveadapter "github.com/kriuchkov/tonbeacon/adapters/archive"
 	"github.com/kriuchkov/tonbeacon/adapters/consumer"
 	"github.com/kriuchkov/tonbeacon/adapters/producer"
 	"github.com/kriuchkov/tonbeacon/adapters/repository"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
+	"github.com/kriuchkov/tonbeacon/pkg/retrier"
+	"github.com/kriuchkov/tonbeacon/ports/deposit"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
 	"github.com/kriuchkov/tonbeacon/ports/outbox"
 	"github.com/kriuchkov/tonbeacon/ports/transaction"
 )
@@ -24,10 +32,11 @@ import (
 var (
 	enableOutboxProcessor = flag.Bool("outbox-processor", false, "Enable the outbox processor")
 	enableKafkaProcessor  = flag.Bool("kafka-processor", false, "Enable the Kafka processor")
+	enableOutboxRetention = flag.Bool("outbox-retention", false, "Enable the outbox retention job")
 )
 
 // Main initializes the processor application, loads configuration, and starts
-// enabled processors (Outbox or Kafka) based on flags. It handles graceful
+// enabled processors (Outbox, Kafka or outbox retention) based on flags. It handles graceful
 // shutdown via OS signals and logs application activity.
 func main() {
 	flag.Parse()
@@ -46,7 +55,11 @@ func main() {
 		os.Exit(64)
 	}
 
-	log.Info().Bool("outbox-processor", *enableOutboxProcessor).Bool("kafka-processor", *enableKafkaProcessor).Msg("config loaded")
+	log.Info().
+		Bool("outbox-processor", *enableOutboxProcessor).
+		Bool("kafka-processor", *enableKafkaProcessor).
+		Bool("outbox-retention", *enableOutboxRetention).
+		Msg("config loaded")
 
 	if err := cfg.Database.Validate(); err != nil {
 		log.Warn().Err(err).Msg("database config validation")
@@ -76,21 +89,11 @@ func main() {
 
 		log.Info().Msg("outbox processor is enabled")
 
-		kafkaProducer, err := producer.NewKafkaProducer(&producer.ProducerOptions{
-			Brokers: cfg.OutboxProcessor.Brokers,
-			Topic:   cfg.OutboxProcessor.Topic,
-			ReqAcks: cfg.OutboxProcessor.RequiredAcks,
-			Retries: cfg.OutboxProcessor.MaxRetries,
-		})
-		if err != nil {
-			panic(err.Error())
-		}
-
-		outboxConsumer, err := setupOutboxProcessor(db, kafkaProducer)
+		outboxConsumer, closeProducer, err := setupOutboxProcessor(db, &cfg.OutboxProcessor)
 		if err != nil {
 			panic(err.Error())
 		}
-		defer kafkaProducer.Close() //nolint:errcheck
+		defer closeProducer() //nolint:errcheck
 
 		eg.Go(func() error { log.Info().Msg("outbox processor started"); outboxConsumer.Consumer(ctx); return nil })
 	}
@@ -98,39 +101,170 @@ func main() {
 	if *enableKafkaProcessor {
 		log.Info().Msg("kafka consumer is enabled")
 
-		txProcessor, err := setupTransactionProcessor(ctx, cfg, db)
+		txProcessor, accountEvents, err := setupTransactionProcessor(ctx, cfg, db)
 		if err != nil {
 			panic(err.Error())
 		}
 
 		eg.Go(func() error { log.Info().Msg("kafka processor started"); txProcessor.Consume(ctx); return nil })
+
+		if accountEvents != nil {
+			eg.Go(func() error {
+				log.Info().Msg("account events consumer started")
+				accountEvents.Consume(ctx)
+				return nil
+			})
+		}
+	}
+
+	if *enableOutboxRetention {
+		if err := cfg.OutboxRetention.Validate(); err != nil {
+			log.Warn().Err(err).Msg("outbox retention config validation")
+			os.Exit(1)
+		}
+
+		log.Info().Msg("outbox retention is enabled")
+
+		retention, err := setupOutboxRetention(db, &cfg.OutboxRetention)
+		if err != nil {
+			panic(err.Error())
+		}
+
+		eg.Go(func() error { log.Info().Msg("outbox retention started"); retention.Run(ctx); return nil })
 	}
 
 	eg.Wait()
 }
 
-func setupOutboxProcessor(db *bun.DB, writer consumer.OutboxWriter) (*consumer.Outbox, error) {
-	outbox := consumer.NewOutbox(consumer.OutboxOptions{
+func setupOutboxProcessor(db *bun.DB, cfg *OutboxProcessorConfig) (*consumer.Outbox, func() error, error) {
+	options := consumer.OutboxOptions{
 		OutboxManager: outbox.New(repository.New(db)),
 		TxManager:     repository.NewTxRepository(db),
-		Writer:        writer,
+		BatchSize:     cfg.BatchSize,
+	}
+
+	var closeProducer func() error
+	if cfg.ExactlyOnce {
+		txnProducer, err := producer.NewKafkaTxnProducer(&producer.TxnProducerOptions{
+			Brokers:         cfg.Brokers,
+			Topic:           cfg.Topic,
+			TransactionalID: cfg.TransactionalID,
+			Retries:         cfg.MaxRetries,
+			Client:          cfg.Client,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "create transactional producer")
+		}
+
+		log.Info().Str("transactional_id", cfg.TransactionalID).Msg("outbox processor runs in exactly-once mode")
+		options.TxnWriter, closeProducer = txnProducer, txnProducer.Close
+	} else {
+		kafkaProducer, err := producer.NewKafkaProducer(&producer.ProducerOptions{
+			Brokers: cfg.Brokers,
+			Topic:   cfg.Topic,
+			ReqAcks: cfg.RequiredAcks,
+			Retries: cfg.MaxRetries,
+			Client:  cfg.Client,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "create producer")
+		}
+
+		options.Writer, closeProducer = kafkaProducer, kafkaProducer.Close
+	}
+
+	return consumer.NewOutbox(options), closeProducer, nil
+}
+
+func setupOutboxRetention(db *bun.DB, cfg *OutboxRetentionConfig) (*outbox.Retention, error) {
+	dataBase := repository.New(db)
+
+	var archive ports.OutboxArchivePort
+	switch cfg.Archive {
+	case model.OutboxArchiveTable:
+		archive = dataBase
+	case model.OutboxArchiveFile:
+		fileArchive, err := archiveadapter.NewJSONLArchive(cfg.ArchivePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "jsonl archive")
+		}
+		archive = fileArchive
+	case model.OutboxArchiveNone:
+	}
+
+	retention := outbox.NewRetention(&outbox.RetentionOptions{
+		Database:  dataBase,
+		TxManager: repository.NewTxRepository(db),
+		Archive:   archive,
+		MaxAge:    cfg.MaxAge,
+		BatchSize: cfg.BatchSize,
+		Interval:  cfg.Interval,
 	})
-	return outbox, nil
+	return retention, nil
 }
 
-func setupTransactionProcessor(ctx context.Context, cfg *Config, db *bun.DB) (*consumer.Kafka, error) {
+// setupTransactionProcessor returns the consumer of the scanned transactions and, if the topic is configured,
+// the consumer of the account outbox events keeping the account index of the processor up to date.
+func setupTransactionProcessor(ctx context.Context, cfg *Config, db *bun.DB) (*consumer.Kafka, *consumer.Kafka, error) {
 	dataBase := repository.New(db)
+	ledgerService := ledger.New(&ledger.Options{Database: dataBase, Withdrawals: dataBase, TxManager: repository.NewTxRepository(db)})
 	handler := transaction.New(ctx, &transaction.Options{
 		DatabasePort:    dataBase,
 		TransactionPort: dataBase,
 		TxPort:          repository.NewTxRepository(db),
+		Interval:        cfg.TransactionProcessor.AccountResyncInterval,
+		Deposits: deposit.New(&deposit.Options{
+			Database: dataBase,
+			Events:   outbox.New(dataBase),
+			Ledger:   ledgerService,
+		}),
+		MinDepositAmount: model.NewAmount(cfg.TransactionProcessor.MinDepositNano),
+		Transfers:        dataBase,
+		Ledger:           ledgerService,
+		MasterAddress:    cfg.TransactionProcessor.MasterAddress,
+		PayoutAddress:    cfg.TransactionProcessor.PayoutAddress,
+		Events:           outbox.New(dataBase),
 	})
 
-	kafkaConsumer := consumer.NewKafka(consumer.KafkaOptions{
+	options := consumer.KafkaOptions{
+		Brokers:         cfg.TransactionProcessor.Brokers,
+		Topic:           cfg.TransactionProcessor.Topic,
+		GroupID:         cfg.TransactionProcessor.GroupID,
+		Handler:         handler,
+		RetryTopic:      cfg.TransactionProcessor.RetryTopic,
+		RetryDelay:      cfg.TransactionProcessor.RetryDelay,
+		MaxRetries:      cfg.TransactionProcessor.MaxRetryRounds,
+		DeadLetterTopic: cfg.TransactionProcessor.DeadLetterTopic,
+		Concurrency:     cfg.TransactionProcessor.Concurrency,
+		BatchSize:       cfg.TransactionProcessor.BatchSize,
+		Client:          cfg.TransactionProcessor.Client,
+	}
+
+	if cfg.TransactionProcessor.RetryAttempts > 0 {
+		options.RetryPolicy = &retrier.RetryPolicy{
+			MaxAttempts:        cfg.TransactionProcessor.RetryAttempts,
+			StartDelay:         defaultRetryStartDelay,
+			MaxDelay:           lo.ToPtr(defaultRetryMaxDelay),
+			BackoffCoefficient: defaultRetryBackoffCoefficient,
+		}
+	}
+
+	kafkaConsumer := consumer.NewKafka(options)
+	if cfg.TransactionProcessor.AccountEventsTopic == "" {
+		log.Warn().Msg("account events topic is not set, new accounts are picked up by the periodic resync only")
+		return kafkaConsumer, nil, nil
+	}
+
+	// The index is loaded on start, so only the events published since then are needed.
+	client := cfg.TransactionProcessor.Client
+	client.Consumer.InitialOffset = kafka.InitialOffsetNewest
+
+	accountEvents := consumer.NewKafka(consumer.KafkaOptions{
 		Brokers: cfg.TransactionProcessor.Brokers,
-		Topic:   cfg.TransactionProcessor.Topic,
-		GroupID: cfg.TransactionProcessor.GroupID,
-		Handler: handler,
+		Topic:   cfg.TransactionProcessor.AccountEventsTopic,
+		GroupID: cfg.TransactionProcessor.accountEventsGroupID(),
+		Handler: handler.AccountEvents(),
+		Client:  client,
 	})
-	return kafkaConsumer, nil
+	return kafkaConsumer, accountEvents, nil
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 	"github.com/spf13/viper"
+
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
 )
 
 const (
@@ -47,6 +49,9 @@ type KafkaConfig struct {
 	Topic        string              `mapstructure:"topic"`
 	MaxRetries   int                 `mapstructure:"max_retries"`
 	RequiredAcks sarama.RequiredAcks `mapstructure:"required_acks"`
+
+	// Client holds TLS, SASL and other connection settings.
+	Client kafka.Config `mapstructure:",squash"`
 }
 
 type ScanningConfig struct {
@@ -90,6 +95,7 @@ func LoadConfig() (*Config, error) {
 	v.BindEnv("kafka.topic")
 	v.BindEnv("kafka.max_retries")
 	v.BindEnv("kafka.required_acks")
+	kafka.BindEnv(v, "kafka")
 	v.BindEnv("publisher_type")
 	v.BindEnv("scanning.num_workers")
 	v.BindEnv("ton.url")
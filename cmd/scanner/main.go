@@ -106,6 +106,7 @@ func setPublisher(cfg *Config) (ports.PublisherPort, error) {
 			Topic:        cfg.Kafka.Topic,
 			RequiredAcks: cfg.Kafka.RequiredAcks,
 			MaxRetries:   cfg.Kafka.MaxRetries,
+			Client:       cfg.Kafka.Client,
 		})
 	case NoopPublisherType:
 		return &publisher.NoopPublisher{}, nil
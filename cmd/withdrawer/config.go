@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog/pkgerrors"
+	"github.com/spf13/viper"
+	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+const (
+	// defaultLogLevel is the default log level.
+	defaultLogLevel = "info"
+
+	// defaultInterval is the default interval between the runs of the worker.
+	defaultInterval = 10 * time.Second
+)
+
+type MasterKey struct {
+	Seed    string              `mapstructure:"seed" validate:"required"`
+	Version walletutils.Version `mapstructure:"version"`
+}
+
+func (mk *MasterKey) GetSeed() []string {
+	return strings.Split(mk.Seed, " ")
+}
+
+type DatabaseConfig struct {
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     int    `mapstructure:"port" validate:"required"`
+	User     string `mapstructure:"user" validate:"required"`
+	Password string `mapstructure:"password" validate:"required"`
+	DBName   string `mapstructure:"dbname" validate:"required"`
+	SSLMode  string `mapstructure:"sslmode" default:"disable"`
+}
+
+func (dc *DatabaseConfig) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s", dc.User, dc.Password, dc.Host, dc.Port, dc.DBName, dc.SSLMode)
+}
+
+type WithdrawerConfig struct {
+	Interval time.Duration `mapstructure:"interval" validate:"gt=0"`
+
+	// ConfirmTimeout is how long after the expiration of its message a withdrawal waits for the confirmation
+	// before the seqno of the master wallet is checked.
+	ConfirmTimeout time.Duration `mapstructure:"confirm_timeout" validate:"gte=0"`
+
+	// JettonGasNano is the TON in nanotons attached to a jetton withdrawal.
+	JettonGasNano int64 `mapstructure:"jetton_gas_nano" validate:"gte=0"`
+
+	// Jettons are the jettons which may be withdrawn, they are configured in the file only.
+	Jettons []JettonConfig `mapstructure:"jettons" validate:"dive"`
+
+	// Payout sends the withdrawals from the highload payout wallet.
+	Payout PayoutConfig `mapstructure:"payout"`
+
+	// Policies require the approvals and limit the withdrawals per currency, they are configured in the file only.
+	Policies []PolicyConfig `mapstructure:"policies" validate:"dive"`
+}
+
+// PolicyConfig is the withdrawal policy of a currency, the amounts are in whole units of the currency
+// and an empty limit does not limit.
+type PolicyConfig struct {
+	Currency          string            `mapstructure:"currency" validate:"required"`
+	Approvals         []ThresholdConfig `mapstructure:"approvals" validate:"dive"`
+	AccountDailyLimit string            `mapstructure:"account_daily_limit"`
+	GlobalDailyLimit  string            `mapstructure:"global_daily_limit"`
+}
+
+// ThresholdConfig requires the approvals of distinct operators for the withdrawals above the amount.
+type ThresholdConfig struct {
+	Above     string `mapstructure:"above" validate:"required"`
+	Approvals int    `mapstructure:"approvals" validate:"gt=0"`
+}
+
+// ToModel returns the withdrawal policy.
+func (pc *PolicyConfig) ToModel() (model.WithdrawalPolicy, error) {
+	policy := model.WithdrawalPolicy{Currency: model.Currency(pc.Currency)}
+
+	for _, threshold := range pc.Approvals {
+		above, err := model.ParseUnits(threshold.Above)
+		if err != nil {
+			return policy, errors.Wrapf(err, "approval threshold of %s", pc.Currency)
+		}
+		policy.Thresholds = append(policy.Thresholds, model.ApprovalThreshold{Above: above, Approvals: threshold.Approvals})
+	}
+
+	var err error
+	if policy.AccountDailyLimit, err = parseLimit(pc.AccountDailyLimit); err != nil {
+		return policy, errors.Wrapf(err, "account daily limit of %s", pc.Currency)
+	}
+
+	if policy.GlobalDailyLimit, err = parseLimit(pc.GlobalDailyLimit); err != nil {
+		return policy, errors.Wrapf(err, "global daily limit of %s", pc.Currency)
+	}
+	return policy, nil
+}
+
+func parseLimit(limit string) (model.Amount, error) {
+	if limit == "" {
+		return model.NewAmount(0), nil
+	}
+	return model.ParseUnits(limit)
+}
+
+type PayoutConfig struct {
+	// Enabled sends the withdrawals in batches of the highload v3 payout wallet instead of the master wallet.
+	// The payout wallet must be funded, its address is logged on start and is set as the payout address
+	// of the transaction processor.
+	Enabled bool `mapstructure:"enabled"`
+	// Seed of the payout wallet, the seed of the master wallet is used if it is empty.
+	Seed string `mapstructure:"seed"`
+	// Subwallet and MessageTTL define the address of the payout wallet, they must not change while it is used.
+	Subwallet  uint32        `mapstructure:"subwallet"`
+	MessageTTL time.Duration `mapstructure:"message_ttl" validate:"omitempty,gt=5s,lt=1000h"`
+	// MaxBatch is the largest number of withdrawals sent in one batch.
+	MaxBatch int `mapstructure:"max_batch" validate:"gte=0,lte=253"`
+}
+
+// GetSeed returns the seed of the payout wallet.
+func (pc *PayoutConfig) GetSeed(master MasterKey) []string {
+	if pc.Seed == "" {
+		return master.GetSeed()
+	}
+	return strings.Split(pc.Seed, " ")
+}
+
+type JettonConfig struct {
+	Currency string `mapstructure:"currency" validate:"required"`
+	// Master is the address of the jetton master contract.
+	Master string `mapstructure:"master" validate:"required"`
+}
+
+type Config struct {
+	LogLevel   string           `mapstructure:"log_level"`
+	IsMainnet  bool             `mapstructure:"is_mainnet"`
+	Master     MasterKey        `mapstructure:"master"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Withdrawer WithdrawerConfig `mapstructure:"withdrawer"`
+}
+
+func (c *Config) Validate() error {
+	if err := validator.New().Struct(c); err != nil {
+		return errors.Wrap(err, "validate config")
+	}
+	return nil
+}
+
+func LoadConfig() (*Config, error) {
+	v := viper.New()
+
+	// file
+	v.SetConfigName(".config.withdrawer")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+	v.AddConfigPath("$HOME")
+	v.AddConfigPath("./.dev")
+
+	// env
+	v.SetEnvPrefix("tonbeacon")
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// Bind environment variables
+	v.BindEnv("log_level")
+	v.BindEnv("is_mainnet")
+	v.BindEnv("master.seed")
+	v.BindEnv("master.version")
+	v.BindEnv("database.host")
+	v.BindEnv("database.port")
+	v.BindEnv("database.user")
+	v.BindEnv("database.password")
+	v.BindEnv("database.dbname")
+	v.BindEnv("database.sslmode")
+	v.BindEnv("withdrawer.interval")
+	v.BindEnv("withdrawer.confirm_timeout")
+	v.BindEnv("withdrawer.jetton_gas_nano")
+	v.BindEnv("withdrawer.payout.enabled")
+	v.BindEnv("withdrawer.payout.seed")
+	v.BindEnv("withdrawer.payout.subwallet")
+	v.BindEnv("withdrawer.payout.message_ttl")
+	v.BindEnv("withdrawer.payout.max_batch")
+
+	// Defaults
+	v.SetDefault("log_level", defaultLogLevel)
+	v.SetDefault("withdrawer.interval", defaultInterval)
+	v.SetDefault("withdrawer.jetton_gas_nano", model.DefaultJettonGasNano)
+
+	if err := v.ReadInConfig(); err != nil {
+		var errViper viper.ConfigFileNotFoundError
+		if !errors.As(err, &errViper) {
+			return nil, errors.Wrap(err, "read config")
+		}
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config")
+	}
+
+	level, err := zerolog.ParseLevel(config.LogLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse log level")
+	}
+	zerolog.SetGlobalLevel(level)
+
+	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+	log.Logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	zerolog.TimeFieldFormat = "2006-01-02 15:04:05.000"
+	return &config, nil
+}
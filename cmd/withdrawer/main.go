@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"os/signal"
+
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	walletutils "github.com/xssnick/tonutils-go/ton/wallet"
+
+	"github.com/kriuchkov/tonbeacon/adapters/repository"
+	"github.com/kriuchkov/tonbeacon/adapters/ton"
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+	"github.com/kriuchkov/tonbeacon/ports/outbox"
+	"github.com/kriuchkov/tonbeacon/ports/withdrawal"
+)
+
+// Main runs the withdrawal worker, it sends the requested withdrawals from the master wallet one at a time
+// or in batches of the highload payout wallet until an OS signal is received. The withdrawals are confirmed by the transaction processor, the worker
+// finishes them after it.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer cancel()
+	defer log.Info().Msg("withdrawer stopped")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Warn().Err(err).Msg("load config")
+		os.Exit(64)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		log.Warn().Err(err).Msg("config validation")
+		os.Exit(64)
+	}
+
+	log.Info().
+		Dur("interval", cfg.Withdrawer.Interval).
+		Dur("confirm_timeout", cfg.Withdrawer.ConfirmTimeout).
+		Int64("jetton_gas_nano", cfg.Withdrawer.JettonGasNano).
+		Int("jettons", len(cfg.Withdrawer.Jettons)).
+		Bool("payout", cfg.Withdrawer.Payout.Enabled).
+		Msg("config loaded")
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.Database.DSN()))), pgdialect.New())
+	if err = db.PingContext(ctx); err != nil {
+		log.Panic().Err(err).Msg("db connection")
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Warn().Err(err).Msg("close database")
+		}
+	}()
+
+	liteClient, err := common.SetupLiteClient(ctx, cfg.IsMainnet)
+	if err != nil {
+		log.Warn().Err(err).Msg("lite client setup")
+		os.Exit(1)
+	}
+
+	masterWallet, err := walletutils.FromSeed(liteClient, cfg.Master.GetSeed(), cfg.Master.Version)
+	if err != nil {
+		log.Panic().Err(err).Msg("master wallet creation")
+	}
+
+	walletAdapter := ton.NewWalletAdapter(liteClient, masterWallet)
+	for _, jetton := range cfg.Withdrawer.Jettons {
+		if err = walletAdapter.AddJetton(model.Currency(jetton.Currency), jetton.Master); err != nil {
+			log.Panic().Err(err).Str("currency", jetton.Currency).Msg("jetton setup")
+		}
+	}
+
+	repositoryAdapter := repository.New(db)
+	opts := &withdrawal.WorkerOptions{
+		WalletPort:     walletAdapter,
+		Database:       repositoryAdapter,
+		TransferPort:   repositoryAdapter,
+		TxPort:         repository.NewTxRepository(db),
+		Events:         outbox.New(repositoryAdapter),
+		Seqnos:         repositoryAdapter,
+		JettonGas:      model.NewAmount(cfg.Withdrawer.JettonGasNano),
+		Interval:       cfg.Withdrawer.Interval,
+		ConfirmTimeout: cfg.Withdrawer.ConfirmTimeout,
+	}
+
+	if payout := cfg.Withdrawer.Payout; payout.Enabled {
+		key, err := walletutils.SeedToPrivateKey(payout.GetSeed(cfg.Master), "", false)
+		if err != nil {
+			log.Panic().Err(err).Msg("payout wallet key")
+		}
+
+		payoutAdapter, err := ton.NewHighloadWalletAdapter(liteClient, key, &ton.HighloadOptions{
+			Subwallet:  payout.Subwallet,
+			MessageTTL: payout.MessageTTL,
+		})
+		if err != nil {
+			log.Panic().Err(err).Msg("payout wallet creation")
+		}
+
+		for _, jetton := range cfg.Withdrawer.Jettons {
+			if err = payoutAdapter.AddJetton(model.Currency(jetton.Currency), jetton.Master); err != nil {
+				log.Panic().Err(err).Str("currency", jetton.Currency).Msg("payout jetton setup")
+			}
+		}
+
+		opts.Payouts, opts.QueryIDs, opts.MaxBatch = payoutAdapter, repositoryAdapter, payout.MaxBatch
+		log.Info().Str("payout_address", payoutAdapter.WalletAddress().String()).Msg("payout wallet enabled")
+	}
+
+	for _, policyConfig := range cfg.Withdrawer.Policies {
+		policy, err := policyConfig.ToModel()
+		if err != nil {
+			log.Panic().Err(err).Msg("withdrawal policy")
+		}
+		opts.Policies = append(opts.Policies, policy)
+	}
+
+	if len(opts.Policies) > 0 {
+		opts.Approvals, opts.Limits = repositoryAdapter, repositoryAdapter
+	}
+
+	worker := withdrawal.NewWorker(opts)
+
+	log.Info().Str("master_address", masterWallet.WalletAddress().String()).Msg("withdrawer started")
+	worker.Run(ctx)
+}
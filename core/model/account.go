@@ -2,6 +2,9 @@ package model
 
 type AccountID = string
 
+// MasterAccountID is the account id of the master wallet.
+const MasterAccountID AccountID = "master"
+
 type Account struct {
 	ID       AccountID
 	WalletID uint32
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Known op codes of the message bodies.
+const (
+	OpTextComment          uint32 = 0x00000000
+	OpEncryptedComment     uint32 = 0x2167da4b
+	OpBounce               uint32 = 0xffffffff
+	OpJettonTransfer       uint32 = 0x0f8a7ea5
+	OpJettonInternal       uint32 = 0x178d4519
+	OpJettonNotification   uint32 = 0x7362d09c
+	OpJettonBurn           uint32 = 0x595f07bc
+	OpExcesses             uint32 = 0xd53276db
+	OpNFTTransfer          uint32 = 0x5fcc3d14
+	OpNFTOwnershipAssigned uint32 = 0x05138d91
+	// OpHighloadBatch is the internal message of a highload v3 wallet to itself carrying a batch of transfers.
+	OpHighloadBatch uint32 = 0xae42e5a4
+)
+
+var opNames = map[uint32]string{
+	OpTextComment:          "text_comment",
+	OpEncryptedComment:     "encrypted_comment",
+	OpBounce:               "bounce",
+	OpJettonTransfer:       "jetton_transfer",
+	OpJettonInternal:       "jetton_internal_transfer",
+	OpJettonNotification:   "jetton_transfer_notification",
+	OpJettonBurn:           "jetton_burn",
+	OpExcesses:             "excesses",
+	OpNFTTransfer:          "nft_transfer",
+	OpNFTOwnershipAssigned: "nft_ownership_assigned",
+	OpHighloadBatch:        "highload_batch",
+}
+
+// OpName returns the name of a known op code or an empty string.
+func OpName(op uint32) string {
+	return opNames[op]
+}
+
+// MessageBody is the decoded message body.
+type MessageBody struct {
+	// OpCode is nil for an empty body.
+	OpCode *uint32
+	// QueryID is set for the bodies of op codes other than comments that carry it.
+	QueryID *uint64
+	// Comment is the text of the op 0 body.
+	Comment string
+	// EncryptedComment is set for the op 0x2167da4b body, the comment itself can not be read.
+	EncryptedComment bool
+}
+
+// DecodeMessageBody decodes the op code, query id and text comment of the base64 encoded BOC of a message body.
+func DecodeMessageBody(body string) (MessageBody, error) {
+	if body == "" {
+		return MessageBody{}, nil
+	}
+
+	boc, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return MessageBody{}, errors.Wrap(err, "decode base64")
+	}
+
+	root, err := cell.FromBOC(boc)
+	if err != nil {
+		return MessageBody{}, errors.Wrap(err, "parse boc")
+	}
+
+	slice := root.BeginParse()
+	if slice.BitsLeft() < 32 {
+		return MessageBody{}, nil
+	}
+
+	op64, err := slice.LoadUInt(32)
+	if err != nil {
+		return MessageBody{}, errors.Wrap(err, "load op code")
+	}
+
+	op := uint32(op64)
+	decoded := MessageBody{OpCode: &op}
+
+	switch op {
+	case OpTextComment:
+		comment, err := slice.LoadStringSnake()
+		if err != nil {
+			return decoded, errors.Wrap(err, "load comment")
+		}
+		decoded.Comment = sanitizeComment(comment)
+	case OpEncryptedComment:
+		decoded.EncryptedComment = true
+	case OpBounce:
+	default:
+		if slice.BitsLeft() >= 64 {
+			queryID, err := slice.LoadUInt(64)
+			if err != nil {
+				return decoded, errors.Wrap(err, "load query id")
+			}
+			decoded.QueryID = &queryID
+		}
+	}
+	return decoded, nil
+}
+
+// MessageBodyHash returns the hex hash of the root cell of the base64 encoded BOC of a message body.
+func MessageBodyHash(body string) (string, error) {
+	boc, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return "", errors.Wrap(err, "decode base64")
+	}
+
+	root, err := cell.FromBOC(boc)
+	if err != nil {
+		return "", errors.Wrap(err, "parse boc")
+	}
+	return hex.EncodeToString(root.Hash()), nil
+}
+
+// bouncePrefixBits is the length of the prefix of the original body a bounced message returns after its op code.
+const bouncePrefixBits = 256
+
+// BounceHash returns the hex hash of the prefix of the message body a bounce of the message returns, so the bounce
+// is linked to the transfer it returns. A nil body is empty.
+func BounceHash(body *cell.Cell) (string, error) {
+	if body == nil {
+		return prefixHash(cell.BeginCell().EndCell().BeginParse())
+	}
+	return prefixHash(body.BeginParse())
+}
+
+// BouncedBodyHash returns the BounceHash of the original body returned by the base64 encoded BOC
+// of a bounced message body.
+func BouncedBodyHash(body string) (string, error) {
+	boc, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return "", errors.Wrap(err, "decode base64")
+	}
+
+	root, err := cell.FromBOC(boc)
+	if err != nil {
+		return "", errors.Wrap(err, "parse boc")
+	}
+
+	slice := root.BeginParse()
+	op, err := slice.LoadUInt(32)
+	if err != nil {
+		return "", errors.Wrap(err, "load op code")
+	}
+
+	if uint32(op) != OpBounce {
+		return "", errors.Errorf("op code %#x is not a bounce", op)
+	}
+	return prefixHash(slice)
+}
+
+// BouncedJettonQueryID returns the query id of the jetton transfer returned by the base64 encoded BOC
+// of a bounced message body, false if the bounced message is not a jetton transfer.
+func BouncedJettonQueryID(body string) (uint64, bool) {
+	boc, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return 0, false
+	}
+
+	root, err := cell.FromBOC(boc)
+	if err != nil {
+		return 0, false
+	}
+
+	slice := root.BeginParse()
+	if slice.BitsLeft() < 32+32+64 {
+		return 0, false
+	}
+
+	if bounce, _ := slice.LoadUInt(32); uint32(bounce) != OpBounce {
+		return 0, false
+	}
+
+	if op, _ := slice.LoadUInt(32); uint32(op) != OpJettonTransfer {
+		return 0, false
+	}
+
+	queryID, err := slice.LoadUInt(64)
+	return queryID, err == nil
+}
+
+// prefixHash returns the hex hash of the cell of the first bouncePrefixBits bits of the slice.
+func prefixHash(slice *cell.Slice) (string, error) {
+	bits := min(slice.BitsLeft(), bouncePrefixBits)
+
+	data, err := slice.LoadSlice(bits)
+	if err != nil {
+		return "", errors.Wrap(err, "load prefix")
+	}
+
+	prefix := cell.BeginCell()
+	if err = prefix.StoreSlice(data, bits); err != nil {
+		return "", errors.Wrap(err, "store prefix")
+	}
+	return hex.EncodeToString(prefix.EndCell().Hash()), nil
+}
+
+// sanitizeComment drops the bytes that can not be stored as text.
+func sanitizeComment(comment string) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(comment, ""), "\x00", "")
+}
@@ -0,0 +1,208 @@
+package model_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func encodeBody(t *testing.T, c *cell.Cell) string {
+	t.Helper()
+	return base64.StdEncoding.EncodeToString(c.ToBOC())
+}
+
+func TestDecodeMessageBody(t *testing.T) {
+	longComment := strings.Repeat("deposit memo ", 20)
+
+	comment := func(text string) *cell.Cell {
+		return cell.BeginCell().MustStoreUInt(0, 32).MustStoreStringSnake(text).EndCell()
+	}
+
+	tests := []struct {
+		name      string
+		body      func(t *testing.T) string
+		opCode    *uint32
+		queryID   *uint64
+		comment   string
+		encrypted bool
+		isError   bool
+	}{
+		{
+			name: "empty body",
+			body: func(t *testing.T) string { return encodeBody(t, cell.BeginCell().EndCell()) },
+		},
+		{
+			name:    "text comment",
+			body:    func(t *testing.T) string { return encodeBody(t, comment("user-42")) },
+			opCode:  ptr(model.OpTextComment),
+			comment: "user-42",
+		},
+		{
+			name:    "snake comment in several cells",
+			body:    func(t *testing.T) string { return encodeBody(t, comment(longComment)) },
+			opCode:  ptr(model.OpTextComment),
+			comment: longComment,
+		},
+		{
+			name: "encrypted comment",
+			body: func(t *testing.T) string {
+				return encodeBody(t, cell.BeginCell().MustStoreUInt(uint64(model.OpEncryptedComment), 32).MustStoreUInt(1, 64).EndCell())
+			},
+			opCode:    ptr(model.OpEncryptedComment),
+			encrypted: true,
+		},
+		{
+			name: "jetton notification with query id",
+			body: func(t *testing.T) string {
+				return encodeBody(t, cell.BeginCell().MustStoreUInt(uint64(model.OpJettonNotification), 32).
+					MustStoreUInt(0xfffffffffffffff0, 64).MustStoreCoins(100).EndCell())
+			},
+			opCode:  ptr(model.OpJettonNotification),
+			queryID: ptr(uint64(0xfffffffffffffff0)),
+		},
+		{
+			name:    "not a boc",
+			body:    func(*testing.T) string { return "!!!" },
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := model.DecodeMessageBody(tt.body(t))
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.opCode, decoded.OpCode)
+			require.Equal(t, tt.queryID, decoded.QueryID)
+			require.Equal(t, tt.comment, decoded.Comment)
+			require.Equal(t, tt.encrypted, decoded.EncryptedComment)
+		})
+	}
+
+	require.Equal(t, "jetton_transfer_notification", model.OpName(model.OpJettonNotification))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestUnmarshalTransaction_ExternalMessageHash(t *testing.T) {
+	body := cell.BeginCell().MustStoreUInt(42, 32).MustStoreUInt(7, 64).EndCell()
+	message := `{"IO": {"In": {"MsgType": "EXTERNAL_IN", "Msg": {"DstAddr": "ours", "Body": "` + encodeBody(t, body) + `"}}}}`
+
+	tx, err := model.UnmarshalTransaction([]byte(message))
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeExternalIn, tx.MessageType)
+	require.Equal(t, hex.EncodeToString(body.Hash()), tx.InMsgHash)
+
+	hash, err := model.MessageBodyHash(encodeBody(t, body))
+	require.NoError(t, err)
+	require.Equal(t, tx.InMsgHash, hash)
+
+	internal := `{"IO": {"In": {"MsgType": "INTERNAL", "Msg": {"SrcAddr": "other", "DstAddr": "ours", "Body": "` + encodeBody(t, body) + `"}}}}`
+	tx, err = model.UnmarshalTransaction([]byte(internal))
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeInternal, tx.MessageType)
+	require.Empty(t, tx.InMsgHash)
+}
+
+func TestUnmarshalTransaction_HighloadBatch(t *testing.T) {
+	body := cell.BeginCell().MustStoreUInt(uint64(model.OpHighloadBatch), 32).MustStoreUInt(1025, 64).
+		MustStoreRef(cell.BeginCell().EndCell()).EndCell()
+	batch := func(sender, actions string) string {
+		return `{"IO": {"In": {"MsgType": "INTERNAL", "Msg": {"SrcAddr": "` + sender + `", "DstAddr": "payout", "Body": "` +
+			encodeBody(t, body) + `"}}}, "Description": {"ComputePhase": {"Phase": {"Success": true}}, "ActionPhase": ` + actions + `}}`
+	}
+
+	tx, err := model.UnmarshalTransaction([]byte(batch("payout", `{"Success": true}`)))
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(body.Hash()), tx.InMsgHash)
+	require.Equal(t, uint64(1025), *tx.QueryID)
+	require.True(t, tx.Success)
+
+	tx, err = model.UnmarshalTransaction([]byte(batch("payout", `{"Success": false, "NoFunds": true}`)))
+	require.NoError(t, err)
+	require.False(t, tx.Success)
+
+	tx, err = model.UnmarshalTransaction([]byte(batch("other", `null`)))
+	require.NoError(t, err)
+	require.Empty(t, tx.InMsgHash)
+	require.True(t, tx.Success)
+}
+
+func TestBounceHash(t *testing.T) {
+	comment := func(text string) *cell.Cell {
+		return cell.BeginCell().MustStoreUInt(0, 32).MustStoreStringSnake(text).EndCell()
+	}
+
+	// bounced returns the body of the bounce of the message, it keeps the first 256 bits of the original body.
+	bounced := func(original *cell.Cell) string {
+		slice := original.BeginParse()
+		bits := min(slice.BitsLeft(), 256)
+		return encodeBody(t, cell.BeginCell().MustStoreUInt(uint64(model.OpBounce), 32).
+			MustStoreSlice(slice.MustLoadSlice(bits), bits).EndCell())
+	}
+
+	tests := []struct {
+		name string
+		body *cell.Cell
+	}{
+		{name: "empty body", body: nil},
+		{name: "short comment", body: comment("invoice 42")},
+		{name: "long comment", body: comment(strings.Repeat("invoice 42 ", 10))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := model.BounceHash(tt.body)
+			require.NoError(t, err)
+
+			body := tt.body
+			if body == nil {
+				body = cell.BeginCell().EndCell()
+			}
+
+			hash, err := model.BouncedBodyHash(bounced(body))
+			require.NoError(t, err)
+			require.Equal(t, expected, hash)
+		})
+	}
+
+	first, err := model.BounceHash(comment("invoice 42"))
+	require.NoError(t, err)
+	second, err := model.BounceHash(comment("invoice 43"))
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+
+	_, err = model.BouncedBodyHash(encodeBody(t, comment("invoice 42")))
+	require.Error(t, err)
+}
+
+func TestBouncedJettonQueryID(t *testing.T) {
+	t.Parallel()
+
+	bounced := func(op uint32) string {
+		body := cell.BeginCell().MustStoreUInt(uint64(model.OpBounce), 32).MustStoreUInt(uint64(op), 32).MustStoreUInt(77, 64).EndCell()
+		return encodeBody(t, body)
+	}
+
+	queryID, ok := model.BouncedJettonQueryID(bounced(model.OpJettonTransfer))
+	require.True(t, ok)
+	require.Equal(t, uint64(77), queryID)
+
+	_, ok = model.BouncedJettonQueryID(bounced(model.OpTextComment))
+	require.False(t, ok)
+
+	_, ok = model.BouncedJettonQueryID("not a boc")
+	require.False(t, ok)
+}
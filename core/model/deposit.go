@@ -0,0 +1,123 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// DepositStatus is the state of a deposit in its lifecycle:
+// detected → confirmed → credited → swept, a detected deposit may be bounced or rejected instead.
+type DepositStatus string
+
+const (
+	DepositDetected  DepositStatus = "detected"
+	DepositConfirmed DepositStatus = "confirmed"
+	DepositCredited  DepositStatus = "credited"
+	DepositSwept     DepositStatus = "swept"
+	DepositBounced   DepositStatus = "bounced"
+	DepositRejected  DepositStatus = "rejected"
+)
+
+// depositTransitions lists the statuses reachable from a status, final statuses are absent.
+var depositTransitions = map[DepositStatus][]DepositStatus{
+	DepositDetected:  {DepositConfirmed, DepositBounced, DepositRejected},
+	DepositConfirmed: {DepositCredited, DepositRejected},
+	DepositCredited:  {DepositSwept},
+}
+
+// Deposit is an incoming transfer to one of our accounts.
+type Deposit struct {
+	ID          int64
+	AccountID   AccountID
+	AccountAddr string
+	LT          int64
+	TxHash      string
+	Sender      string
+	Currency    Currency
+	Amount      Amount
+	Comment     string
+	Status      DepositStatus
+	Reason      string
+
+	DetectedAt  time.Time
+	ConfirmedAt *time.Time
+	CreditedAt  *time.Time
+	SweptAt     *time.Time
+	BouncedAt   *time.Time
+	RejectedAt  *time.Time
+	UpdatedAt   time.Time
+}
+
+// CanTransition reports whether the deposit may move to the status.
+func (d *Deposit) CanTransition(to DepositStatus) bool {
+	for _, status := range depositTransitions[d.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the deposit to the status and stamps the time of the transition.
+func (d *Deposit) Transition(to DepositStatus, at time.Time) error {
+	if !d.CanTransition(to) {
+		return errors.Wrapf(ErrInvalidDepositTransition, "%s to %s", d.Status, to)
+	}
+
+	switch to {
+	case DepositConfirmed:
+		d.ConfirmedAt = &at
+	case DepositCredited:
+		d.CreditedAt = &at
+	case DepositSwept:
+		d.SweptAt = &at
+	case DepositBounced:
+		d.BouncedAt = &at
+	case DepositRejected:
+		d.RejectedAt = &at
+	case DepositDetected:
+	}
+
+	d.Status, d.UpdatedAt = to, at
+	return nil
+}
+
+// DepositEventType returns the outbox event type emitted when a deposit reaches the status.
+func DepositEventType(status DepositStatus) EventType {
+	return EventType("deposit_" + string(status))
+}
+
+// DepositPayload is the payload of the deposit events.
+type DepositPayload struct {
+	DepositID int64         `json:"deposit_id"`
+	AccountID AccountID     `json:"account_id"`
+	TxHash    string        `json:"tx_hash"`
+	Sender    string        `json:"sender"`
+	Currency  Currency      `json:"currency"`
+	Amount    string        `json:"amount"`
+	Comment   string        `json:"comment,omitempty"`
+	Status    DepositStatus `json:"status"`
+	Reason    string        `json:"reason,omitempty"`
+	At        time.Time     `json:"at"`
+}
+
+// AggregateID keeps the events of the deposits of an account in order.
+func (p DepositPayload) AggregateID() string {
+	return p.AccountID
+}
+
+func NewDepositPayload(deposit *Deposit) DepositPayload {
+	return DepositPayload{
+		DepositID: deposit.ID,
+		AccountID: deposit.AccountID,
+		TxHash:    deposit.TxHash,
+		Sender:    deposit.Sender,
+		Currency:  deposit.Currency,
+		Amount:    deposit.Amount.Nano(),
+		Comment:   deposit.Comment,
+		Status:    deposit.Status,
+		Reason:    deposit.Reason,
+		At:        deposit.UpdatedAt,
+	}
+}
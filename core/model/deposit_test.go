@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestDeposit_Transition(t *testing.T) {
+	tests := []struct {
+		from    model.DepositStatus
+		to      model.DepositStatus
+		allowed bool
+	}{
+		{from: model.DepositDetected, to: model.DepositConfirmed, allowed: true},
+		{from: model.DepositDetected, to: model.DepositBounced, allowed: true},
+		{from: model.DepositDetected, to: model.DepositRejected, allowed: true},
+		{from: model.DepositDetected, to: model.DepositCredited},
+		{from: model.DepositConfirmed, to: model.DepositCredited, allowed: true},
+		{from: model.DepositCredited, to: model.DepositSwept, allowed: true},
+		{from: model.DepositCredited, to: model.DepositRejected},
+		{from: model.DepositSwept, to: model.DepositCredited},
+		{from: model.DepositBounced, to: model.DepositConfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"-"+string(tt.to), func(t *testing.T) {
+			at := time.Now()
+			deposit := &model.Deposit{Status: tt.from}
+
+			err := deposit.Transition(tt.to, at)
+			if !tt.allowed {
+				require.ErrorIs(t, err, model.ErrInvalidDepositTransition)
+				require.Equal(t, tt.from, deposit.Status)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.to, deposit.Status)
+			require.Equal(t, at, deposit.UpdatedAt)
+		})
+	}
+}
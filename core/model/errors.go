@@ -6,4 +6,45 @@ var (
 	ErrAccountExists   = errors.New("account already exists")
 	ErrAccountNotFound = errors.New("account not found")
 	ErrNoPendingEvents = errors.New("no pending events")
+
+	ErrTransactionExists   = errors.New("transaction already exists")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrCursorExpired       = errors.New("cursor is older than the retained events")
+
+	ErrInvalidTransactionQuery = errors.New("transaction hash or account id and lt are required")
+
+	ErrLedgerEntryExists     = errors.New("ledger entry already exists")
+	ErrLedgerEntryUnbalanced = errors.New("ledger entry is unbalanced")
+	ErrInvalidAmount         = errors.New("invalid amount")
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+
+	ErrDepositExists            = errors.New("deposit already exists")
+	ErrDepositNotFound          = errors.New("deposit not found")
+	ErrInvalidDepositTransition = errors.New("invalid deposit transition")
+
+	ErrSeqnoReserved = errors.New("seqno is reserved by a message in flight")
+
+	ErrTransferExists            = errors.New("transfer already exists")
+	ErrTransferNotFound          = errors.New("transfer not found")
+	ErrInvalidTransferTransition = errors.New("invalid transfer transition")
+
+	ErrSweepJobExists         = errors.New("active sweep job already exists")
+	ErrSweepJobNotFound       = errors.New("sweep job not found")
+	ErrInvalidSweepTransition = errors.New("invalid sweep transition")
+
+	ErrRebalanceExists            = errors.New("active rebalance already exists")
+	ErrInvalidRebalanceTransition = errors.New("invalid rebalance transition")
+
+	ErrSweepPolicyNotFound = errors.New("sweep policy not found")
+	ErrInvalidSweepPolicy  = errors.New("invalid sweep policy")
+
+	ErrWithdrawalExists            = errors.New("withdrawal already exists")
+	ErrWithdrawalNotFound          = errors.New("withdrawal not found")
+	ErrInvalidWithdrawal           = errors.New("invalid withdrawal")
+	ErrInvalidWithdrawalTransition = errors.New("invalid withdrawal transition")
+
+	ErrWithdrawalApprovalExists = errors.New("withdrawal already decided by the operator")
+	ErrWithdrawalNotPending     = errors.New("withdrawal is not pending approval")
+	ErrWithdrawalLimitExceeded  = errors.New("withdrawal limit exceeded")
 )
@@ -5,4 +5,59 @@ type EventType string
 const (
 	AccountCreated EventType = "account_created"
 	AccountClosed  EventType = "account_closed"
+
+	DepositDetectedEvent  EventType = "deposit_detected"
+	DepositConfirmedEvent EventType = "deposit_confirmed"
+	DepositCreditedEvent  EventType = "deposit_credited"
+	DepositSweptEvent     EventType = "deposit_swept"
+	DepositBouncedEvent   EventType = "deposit_bounced"
+	DepositRejectedEvent  EventType = "deposit_rejected"
+
+	SweepSentEvent             EventType = "sweep_sent"
+	SweepConfirmedEvent        EventType = "sweep_confirmed"
+	SweepBouncedEvent          EventType = "sweep_bounced"
+	SweepFailedEvent           EventType = "sweep_failed"
+	WithdrawalRequestedEvent   EventType = "withdrawal_requested"
+	WithdrawalApprovedEvent    EventType = "withdrawal_approved"
+	WithdrawalSignedEvent      EventType = "withdrawal_signed"
+	WithdrawalBroadcastEvent   EventType = "withdrawal_broadcast"
+	WithdrawalConfirmedEvent   EventType = "withdrawal_confirmed"
+	WithdrawalBouncedEvent     EventType = "withdrawal_bounced"
+	WithdrawalFailedEvent      EventType = "withdrawal_failed"
+	WithdrawalUnconfirmedEvent EventType = "withdrawal_unconfirmed"
+	TopUpSentEvent             EventType = "top_up_sent"
+	TopUpConfirmedEvent        EventType = "top_up_confirmed"
+	TopUpFailedEvent           EventType = "top_up_failed"
+	RebalanceConfirmedEvent    EventType = "rebalance_confirmed"
+	RebalanceBouncedEvent      EventType = "rebalance_bounced"
+	RebalanceFailedEvent       EventType = "rebalance_failed"
+	HotWalletLowEvent          EventType = "hot_wallet_low"
+	BounceUnmatchedEvent       EventType = "bounce_unmatched"
+	// TransferUnconfirmedEvent alerts that the wallet seqno advanced past the message of the transfer
+	// but the transaction processor did not confirm it, the payload is TransferPayload.
+	TransferUnconfirmedEvent EventType = "transfer_unconfirmed"
 )
+
+// AccountCreatedPayload is the payload of the AccountCreated event.
+type AccountCreatedPayload struct {
+	AccountID AccountID `json:"account_id"`
+	WalletID  uint32    `json:"wallet_id"`
+	Address   Address   `json:"address"`
+}
+
+func (p AccountCreatedPayload) AggregateID() string {
+	return p.AccountID
+}
+
+func NewAccountCreatedPayload(account *Account) AccountCreatedPayload {
+	return AccountCreatedPayload{AccountID: account.ID, WalletID: account.WalletID, Address: account.Address}
+}
+
+// AccountClosedPayload is the payload of the AccountClosed event.
+type AccountClosedPayload struct {
+	AccountID AccountID `json:"account_id"`
+}
+
+func (p AccountClosedPayload) AggregateID() string {
+	return p.AccountID
+}
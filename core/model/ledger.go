@@ -0,0 +1,91 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+// LedgerEntryType is the business operation recorded by a ledger entry.
+type LedgerEntryType string
+
+const (
+	LedgerDeposit    LedgerEntryType = "deposit"
+	LedgerSweep      LedgerEntryType = "sweep"
+	LedgerFee        LedgerEntryType = "fee"
+	LedgerWithdrawal LedgerEntryType = "withdrawal"
+	LedgerTransfer   LedgerEntryType = "transfer"
+	LedgerBounce     LedgerEntryType = "bounce"
+	LedgerTopUp      LedgerEntryType = "top_up"
+	LedgerRebalance  LedgerEntryType = "rebalance"
+)
+
+// LedgerAccount identifies a ledger account.
+//
+// Postings are signed: debits are positive and credits are negative, so the postings of an entry sum to zero.
+// Wallet accounts are assets holding the coins on chain, customer accounts are liabilities owed to the customers,
+// so the custodial balance of a customer is the negated sum of its postings.
+type LedgerAccount string
+
+const (
+	// LedgerMasterWallet holds the coins swept to the master wallet.
+	LedgerMasterWallet LedgerAccount = "wallet:master"
+
+	// LedgerColdWallet holds the coins moved from the master wallet to the cold storage.
+	LedgerColdWallet LedgerAccount = "wallet:cold"
+
+	// LedgerNetworkFees collects the network fees paid by the custodian.
+	LedgerNetworkFees LedgerAccount = "expense:network_fees"
+)
+
+// CustomerLedgerAccount returns the liability account of the custodial balance of the account.
+func CustomerLedgerAccount(accountID AccountID) LedgerAccount {
+	return LedgerAccount("customer:" + accountID)
+}
+
+// WalletLedgerAccount returns the asset account of the coins held by the deposit wallet of the account.
+func WalletLedgerAccount(accountID AccountID) LedgerAccount {
+	return LedgerAccount("wallet:" + accountID)
+}
+
+// LedgerEntry is a balanced set of postings recorded for a single operation.
+// Reference makes the entry idempotent, an entry of the same type and reference is recorded once.
+type LedgerEntry struct {
+	ID          int64
+	Type        LedgerEntryType
+	Reference   string
+	Description string
+	Postings    []LedgerPosting
+	CreatedAt   time.Time
+}
+
+// LedgerPosting is a signed amount booked to a ledger account.
+type LedgerPosting struct {
+	Account  LedgerAccount
+	Currency Currency
+	Amount   Amount
+}
+
+// IsBalanced reports whether the postings of every currency sum to zero.
+func (e *LedgerEntry) IsBalanced() bool {
+	sums := make(map[Currency]*big.Int)
+	for _, posting := range e.Postings {
+		if _, ok := sums[posting.Currency]; !ok {
+			sums[posting.Currency] = new(big.Int)
+		}
+		sums[posting.Currency].Add(sums[posting.Currency], posting.Amount.BigInt())
+	}
+
+	for _, sum := range sums {
+		if sum.Sign() != 0 {
+			return false
+		}
+	}
+	return len(e.Postings) > 1
+}
+
+// AccountBalance is the custodial balance of an account from the ledger
+// together with the balance of its wallet on chain.
+type AccountBalance struct {
+	Ledger  []Balance
+	OnChain []Balance
+}
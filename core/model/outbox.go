@@ -1,18 +1,72 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
 type OutboxEvent struct {
-	ID        int64
-	EventType EventType
-	Payload   []byte
-	CreatedAt time.Time
-	Processed bool
+	ID          int64
+	EventType   EventType
+	AggregateID string
+	Payload     []byte
+	CreatedAt   time.Time
+	Processed   bool
+	ProcessedAt *time.Time
 }
 
+// Key returns the message key. Events of the same aggregate share the key,
+// so they are written to the same partition and observed in order.
 func (o OutboxEvent) Key() string {
+	if o.AggregateID != "" {
+		return o.AggregateID
+	}
 	return fmt.Sprintf("%s:%d", o.EventType, o.ID)
 }
+
+// Message returns the event in the wire format delivered to external consumers.
+func (o OutboxEvent) Message() ([]byte, error) {
+	payload := json.RawMessage(o.Payload)
+	if len(payload) == 0 {
+		payload = json.RawMessage("null")
+	}
+
+	return json.Marshal(OutboxMessage{
+		EventID:     o.ID,
+		EventType:   o.EventType,
+		AggregateID: o.AggregateID,
+		CreatedAt:   o.CreatedAt,
+		Payload:     payload,
+	})
+}
+
+// OutboxMessage is the stable envelope of an outbox event on the wire.
+type OutboxMessage struct {
+	EventID     int64           `json:"event_id"`
+	EventType   EventType       `json:"event_type"`
+	AggregateID string          `json:"aggregate_id,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// AggregatePayload is implemented by event payloads that belong to an aggregate.
+// Events of the same aggregate are delivered in the order they were published.
+type AggregatePayload interface {
+	AggregateID() string
+}
+
+// EventSubscription selects the outbox events streamed to a subscriber.
+type EventSubscription struct {
+	AccountIDs []AccountID // Empty for the events of all accounts
+	AfterID    *int64      // Resumes after the event, nil streams the new events only
+}
+
+// OutboxArchiveMode defines where processed outbox events are moved before they are purged.
+type OutboxArchiveMode string
+
+const (
+	OutboxArchiveNone  OutboxArchiveMode = "none"
+	OutboxArchiveTable OutboxArchiveMode = "table"
+	OutboxArchiveFile  OutboxArchiveMode = "file"
+)
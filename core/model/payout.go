@@ -0,0 +1,18 @@
+package model
+
+const (
+	// MaxPayoutBatch is the number of transfers sent by a highload wallet with one external message,
+	// the batch fits a single action list of the wallet.
+	MaxPayoutBatch = 253
+
+	// MaxHighloadQueryID is the largest query id of a highload v3 wallet, the ids are reused after it.
+	MaxHighloadQueryID = 1<<23 - 1
+)
+
+// Payout is a transfer of a batch sent by the highload payout wallet.
+type Payout struct {
+	To      string  // Destination address
+	Amount  Balance // Amount in the currency of the transfer
+	Gas     Amount  // TON attached to a jetton transfer
+	Comment string
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// RebalanceStatus is the state of a rebalance: planned → sent → confirmed, a rebalance that is not final may fail instead.
+// A rebalance whose message was executed by the wallet without a confirmation of its transfer is unconfirmed
+// until the transfer is confirmed or fails.
+type RebalanceStatus string
+
+const (
+	RebalancePlanned     RebalanceStatus = "planned"
+	RebalanceSent        RebalanceStatus = "sent"
+	RebalanceConfirmed   RebalanceStatus = "confirmed"
+	RebalanceFailed      RebalanceStatus = "failed"
+	RebalanceUnconfirmed RebalanceStatus = "unconfirmed"
+)
+
+// rebalanceTransitions lists the statuses reachable from a status, final statuses are absent.
+var rebalanceTransitions = map[RebalanceStatus][]RebalanceStatus{
+	RebalancePlanned:     {RebalanceSent, RebalanceFailed, RebalanceUnconfirmed},
+	RebalanceSent:        {RebalanceConfirmed, RebalanceFailed, RebalanceUnconfirmed},
+	RebalanceUnconfirmed: {RebalanceConfirmed, RebalanceFailed},
+}
+
+// ActiveRebalanceStatuses are the statuses of the rebalances that are not final. A currency has at most one
+// planned or sent rebalance, an unconfirmed rebalance only waits for its transfer.
+var ActiveRebalanceStatuses = []RebalanceStatus{RebalancePlanned, RebalanceSent, RebalanceUnconfirmed}
+
+// HotWalletLimit bounds the balance of the master wallet in a currency. The balance above the ceiling is moved
+// to the cold storage, the balance below the floor does not cover the gas and the withdrawals and is alerted.
+// A zero ceiling or floor is not checked.
+type HotWalletLimit struct {
+	Currency Currency
+	Ceiling  Amount
+	Floor    Amount
+}
+
+// Rebalance moves the balance of the master wallet above the ceiling of the currency to the cold storage.
+// Like a sweep job, the signed message is stored with its outgoing transfer before it is broadcast.
+type Rebalance struct {
+	ID          int64
+	Currency    Currency
+	From        string // Raw address of the master wallet
+	To          string // Address of the cold storage
+	Amount      Amount
+	Gas         Amount // TON attached to a jetton transfer
+	Seqno       uint32 // Seqno of the master wallet the message is signed with
+	MessageHash string // Hex hash of the signed body, it links the rebalance to its outgoing transfer
+	BounceHash  string // See OutgoingTransfer.BounceHash
+	// JettonQueryID is the query id of a jetton transfer, see OutgoingTransfer.JettonQueryID.
+	JettonQueryID uint64
+	BOC           []byte
+	ExpiresAt     time.Time
+	Status        RebalanceStatus
+	Attempts      int    // Number of the broadcasts
+	Error         string // Last broadcast error or the reason of the failure
+	SentAt        *time.Time
+	ConfirmedAt   *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewRebalance returns the planned rebalance of the signed message.
+func NewRebalance(message *WalletMessage, at time.Time) *Rebalance {
+	rebalance := &Rebalance{
+		Currency:  message.Currency,
+		Amount:    message.Amount,
+		Gas:       message.Gas,
+		Status:    RebalancePlanned,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	rebalance.SetMessage(message)
+	return rebalance
+}
+
+// SetMessage stores the signed message of the master wallet.
+func (r *Rebalance) SetMessage(message *WalletMessage) {
+	r.From, r.To = message.From, message.To
+	r.Seqno, r.MessageHash, r.BOC, r.ExpiresAt = message.Seqno, message.MessageHash, message.BOC, message.ExpiresAt
+	r.BounceHash, r.JettonQueryID = message.BounceHash, message.JettonQueryID
+}
+
+// Message returns the signed message of the master wallet.
+func (r *Rebalance) Message() *WalletMessage {
+	return &WalletMessage{
+		From:          r.From,
+		To:            r.To,
+		Currency:      r.Currency,
+		Amount:        r.Amount,
+		Gas:           r.Gas,
+		Seqno:         r.Seqno,
+		MessageHash:   r.MessageHash,
+		BounceHash:    r.BounceHash,
+		JettonQueryID: r.JettonQueryID,
+		BOC:           r.BOC,
+		ExpiresAt:     r.ExpiresAt,
+	}
+}
+
+// Transfer returns the outgoing transfer of the message, it belongs to the master account.
+func (r *Rebalance) Transfer() *OutgoingTransfer {
+	return &OutgoingTransfer{
+		Kind:          TransferRebalance,
+		Reference:     r.Reference(),
+		AccountID:     MasterAccountID,
+		From:          r.From,
+		To:            r.To,
+		Currency:      r.Currency,
+		Amount:        r.Amount,
+		Gas:           r.Gas,
+		MessageHash:   r.MessageHash,
+		BounceHash:    r.BounceHash,
+		JettonQueryID: r.JettonQueryID,
+		Status:        TransferSent,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.CreatedAt,
+	}
+}
+
+// Reference is the reference of the outgoing transfer of the rebalance.
+func (r *Rebalance) Reference() string {
+	return strconv.FormatInt(r.ID, 10)
+}
+
+// CanTransition reports whether the rebalance may move to the status.
+func (r *Rebalance) CanTransition(to RebalanceStatus) bool {
+	for _, status := range rebalanceTransitions[r.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the rebalance to the status and stamps the time of the transition.
+func (r *Rebalance) Transition(to RebalanceStatus, at time.Time) error {
+	if !r.CanTransition(to) {
+		return errors.Wrapf(ErrInvalidRebalanceTransition, "%s to %s", r.Status, to)
+	}
+
+	switch to {
+	case RebalanceSent:
+		r.SentAt = &at
+	case RebalanceConfirmed:
+		r.ConfirmedAt = &at
+	case RebalanceFailed:
+		r.FailedAt = &at
+	case RebalancePlanned, RebalanceUnconfirmed:
+	}
+
+	r.Status, r.UpdatedAt = to, at
+	return nil
+}
+
+// HotWalletLowPayload is the payload of the HotWalletLowEvent.
+type HotWalletLowPayload struct {
+	Currency Currency  `json:"currency"`
+	Balance  string    `json:"balance"`
+	Floor    string    `json:"floor"`
+	At       time.Time `json:"at"`
+}
+
+// AggregateID keeps the alerts in order with the other events of the master account.
+func (p HotWalletLowPayload) AggregateID() string {
+	return MasterAccountID
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// SweepStatus is the state of a sweep job: planned → sent → confirmed, a job that is not final may fail instead.
+// A jetton sweep first tops up the subwallet with the gas: planned → funding → funded → sent → confirmed,
+// it starts funded if the subwallet holds enough TON. A job whose wallet seqno advanced without the transaction
+// processor confirming its transfer is unconfirmed, an operator reconciles it.
+type SweepStatus string
+
+const (
+	SweepPlanned   SweepStatus = "planned"
+	SweepFunding   SweepStatus = "funding"
+	SweepFunded    SweepStatus = "funded"
+	SweepSent      SweepStatus = "sent"
+	SweepConfirmed SweepStatus = "confirmed"
+	SweepFailed    SweepStatus = "failed"
+	// SweepUnconfirmed is final for the collector, the transfer may have been executed.
+	SweepUnconfirmed SweepStatus = "unconfirmed"
+)
+
+// sweepTransitions lists the statuses reachable from a status, final statuses are absent.
+var sweepTransitions = map[SweepStatus][]SweepStatus{
+	SweepPlanned: {SweepFunding, SweepSent, SweepFailed, SweepUnconfirmed},
+	SweepFunding: {SweepFunded, SweepFailed, SweepUnconfirmed},
+	SweepFunded:  {SweepSent, SweepFailed, SweepUnconfirmed},
+	SweepSent:    {SweepConfirmed, SweepFailed, SweepUnconfirmed},
+}
+
+// ActiveSweepStatuses are the statuses of the jobs in flight, an account has at most one such job.
+var ActiveSweepStatuses = []SweepStatus{SweepPlanned, SweepFunding, SweepFunded, SweepSent}
+
+// SweepJob moves the balance of a subwallet to the master wallet. The signed message is stored before it is
+// broadcast, so a restarted collector sends the same message again instead of signing a new one, the wallet
+// accepts a message with the seqno once.
+//
+// A jetton sweep has two legs tracked by the job: the top-up of the gas sent by the master wallet and
+// the jetton transfer sent by the subwallet, the jetton transfer is signed once the top-up is confirmed.
+type SweepJob struct {
+	ID        int64
+	AccountID AccountID
+	WalletID  uint32
+	From      string // Raw address of the subwallet
+	To        string // Raw address of the master wallet
+	Currency  Currency
+	Amount    Amount // Expected amount of the transfer
+	Gas       Amount // TON attached to the jetton transfer
+	Seqno     uint32 // Seqno of the subwallet the message is signed with
+	// MessageHash is the hex hash of the signed body, it links the job to its outgoing transfer.
+	// It is empty for a jetton sweep which is not funded yet.
+	MessageHash string
+	// JettonQueryID is the query id of a jetton transfer, see OutgoingTransfer.JettonQueryID.
+	JettonQueryID uint64
+	BOC           []byte    // Serialized external message
+	ExpiresAt     time.Time // The message is not accepted by the wallet after this time
+	// TopUp is the gas transfer of the master wallet, nil if the subwallet holds enough TON.
+	TopUp       *WalletMessage
+	Status      SweepStatus
+	Attempts    int    // Number of the broadcasts
+	Error       string // Last broadcast error or the reason of the failure
+	FundedAt    *time.Time
+	SentAt      *time.Time
+	ConfirmedAt *time.Time
+	FailedAt    *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewSweepJob returns the planned TON sweep of the signed message.
+func NewSweepJob(accountID AccountID, message *WalletMessage, at time.Time) *SweepJob {
+	job := &SweepJob{
+		AccountID: accountID,
+		WalletID:  message.WalletID,
+		From:      message.From,
+		To:        message.To,
+		Currency:  CurrencyTON,
+		Amount:    message.Amount,
+		Status:    SweepPlanned,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+	job.SetMessage(message)
+	return job
+}
+
+// NewJettonSweepJob returns the jetton sweep of the subwallet, it is planned with the top-up of the gas
+// or funded if there is no top-up.
+func NewJettonSweepJob(
+	account Account, to string, amount Balance, gas Amount, topUp *WalletMessage, at time.Time,
+) *SweepJob {
+	job := &SweepJob{
+		AccountID: account.ID,
+		WalletID:  account.WalletID,
+		From:      string(account.Address),
+		To:        to,
+		Currency:  amount.Currency,
+		Amount:    amount.Amount,
+		Gas:       gas,
+		TopUp:     topUp,
+		Status:    SweepPlanned,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+
+	if topUp == nil {
+		job.Status, job.FundedAt = SweepFunded, &at
+	}
+	return job
+}
+
+// Signed reports whether the message of the subwallet is signed.
+func (j *SweepJob) Signed() bool {
+	return j.MessageHash != ""
+}
+
+// Funding reports whether the job waits for its top-up.
+func (j *SweepJob) Funding() bool {
+	return j.TopUp != nil && (j.Status == SweepPlanned || j.Status == SweepFunding)
+}
+
+// SetMessage stores the signed message of the subwallet.
+func (j *SweepJob) SetMessage(message *WalletMessage) {
+	j.From, j.To = message.From, message.To
+	j.Seqno, j.MessageHash, j.BOC, j.ExpiresAt = message.Seqno, message.MessageHash, message.BOC, message.ExpiresAt
+	j.JettonQueryID = message.JettonQueryID
+}
+
+// Message returns the signed message of the subwallet.
+func (j *SweepJob) Message() *WalletMessage {
+	return &WalletMessage{
+		WalletID:      j.WalletID,
+		From:          j.From,
+		To:            j.To,
+		Currency:      j.Currency,
+		Amount:        j.Amount,
+		Gas:           j.Gas,
+		Seqno:         j.Seqno,
+		MessageHash:   j.MessageHash,
+		JettonQueryID: j.JettonQueryID,
+		BOC:           j.BOC,
+		ExpiresAt:     j.ExpiresAt,
+	}
+}
+
+// Transfer returns the outgoing transfer of the message of the subwallet, it is sent from the processor point of view.
+func (j *SweepJob) Transfer() *OutgoingTransfer {
+	return &OutgoingTransfer{
+		Kind:          TransferSweep,
+		Reference:     j.Reference(),
+		AccountID:     j.AccountID,
+		From:          j.From,
+		To:            j.To,
+		Currency:      j.Currency,
+		Amount:        j.Amount,
+		Gas:           j.Gas,
+		MessageHash:   j.MessageHash,
+		JettonQueryID: j.JettonQueryID,
+		Status:        TransferSent,
+		CreatedAt:     j.UpdatedAt,
+		UpdatedAt:     j.UpdatedAt,
+	}
+}
+
+// TopUpTransfer returns the outgoing transfer of the top-up of the gas.
+func (j *SweepJob) TopUpTransfer() *OutgoingTransfer {
+	return &OutgoingTransfer{
+		Kind:        TransferTopUp,
+		Reference:   j.Reference(),
+		AccountID:   j.AccountID,
+		From:        j.TopUp.From,
+		To:          j.TopUp.To,
+		Currency:    CurrencyTON,
+		Amount:      j.TopUp.Amount,
+		MessageHash: j.TopUp.MessageHash,
+		Status:      TransferSent,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.CreatedAt,
+	}
+}
+
+// Reference is the reference of the outgoing transfers of the job.
+func (j *SweepJob) Reference() string {
+	return strconv.FormatInt(j.ID, 10)
+}
+
+// CanTransition reports whether the job may move to the status.
+func (j *SweepJob) CanTransition(to SweepStatus) bool {
+	for _, status := range sweepTransitions[j.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the job to the status and stamps the time of the transition.
+func (j *SweepJob) Transition(to SweepStatus, at time.Time) error {
+	if !j.CanTransition(to) {
+		return errors.Wrapf(ErrInvalidSweepTransition, "%s to %s", j.Status, to)
+	}
+
+	switch to {
+	case SweepFunded:
+		j.FundedAt = &at
+	case SweepSent:
+		j.SentAt = &at
+	case SweepConfirmed:
+		j.ConfirmedAt = &at
+	case SweepFailed:
+		j.FailedAt = &at
+	case SweepPlanned, SweepFunding, SweepUnconfirmed:
+	}
+
+	j.Status, j.UpdatedAt = to, at
+	return nil
+}
@@ -0,0 +1,21 @@
+package model
+
+// PlannedSweep is the sweep of an account the collector would plan in the current balances.
+// The amounts are in the units of the currency of the sweep, the fee and the top-up are in TON.
+type PlannedSweep struct {
+	AccountID AccountID
+	WalletID  uint32
+	Address   Address
+	Currency  Currency
+	Balance   Amount // Balance of the subwallet
+	Amount    Amount // Swept to the master wallet
+	// Fee is the estimated TON spent by the sweep: the fee of its transactions and the gas attached
+	// to a jetton transfer, the excess of the gas is returned to the master wallet.
+	Fee Amount
+	// TopUp is sent by the master wallet to the subwallet before a jetton sweep, zero if it holds the gas.
+	TopUp Amount
+	// Reserve is the balance left on the subwallet after the sweep, the fee of a TON sweep is paid from it.
+	Reserve Amount
+	// UnableToPayFee is set if the reserve of a TON sweep does not cover its fee, the reserve is zero then.
+	UnableToPayFee bool
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// SweepPolicy decides when the balance of a subwallet in a currency is swept to the master wallet.
+//
+// A policy applies to an account and a currency, the empty account or currency applies to all of them.
+// The most specific policy applies as a whole: the policy of the account and the currency, of the account,
+// of the currency and of all the accounts and currencies.
+type SweepPolicy struct {
+	ID        int64
+	AccountID AccountID // Empty for all the accounts
+	Currency  Currency  // Empty for all the currencies
+	Disabled  bool      // The balance is never swept
+	Threshold Amount    // The balance is swept if it is above the threshold
+	Reserve   Amount    // Left on the subwallet
+	// MinInterval is the minimal time between the sweeps of the balance.
+	MinInterval time.Duration
+	// Schedule is the cron expression of the times the balance is swept after, empty to sweep at any time.
+	Schedule  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Matches reports whether the policy applies to the account and the currency.
+func (p *SweepPolicy) Matches(accountID AccountID, currency Currency) bool {
+	return (p.AccountID == "" || p.AccountID == accountID) && (p.Currency == "" || p.Currency == currency)
+}
+
+// specificity orders the policies matching an account and a currency, the account outweighs the currency.
+func (p *SweepPolicy) specificity() int {
+	specificity := 0
+	if p.AccountID != "" {
+		specificity += 2
+	}
+	if p.Currency != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// ResolveSweepPolicy returns the most specific of the policies applying to the account and the currency,
+// the fallback if none of them applies.
+func ResolveSweepPolicy(policies []*SweepPolicy, accountID AccountID, currency Currency, fallback *SweepPolicy) *SweepPolicy {
+	resolved := fallback
+	for _, policy := range policies {
+		if !policy.Matches(accountID, currency) {
+			continue
+		}
+
+		if resolved == fallback || policy.specificity() > resolved.specificity() {
+			resolved = policy
+		}
+	}
+	return resolved
+}
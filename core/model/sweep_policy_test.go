@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestResolveSweepPolicy(t *testing.T) {
+	all := &model.SweepPolicy{ID: 1}
+	ton := &model.SweepPolicy{ID: 2, Currency: model.CurrencyTON}
+	account := &model.SweepPolicy{ID: 3, AccountID: "vip"}
+	accountTON := &model.SweepPolicy{ID: 4, AccountID: "vip", Currency: model.CurrencyTON}
+	fallback := &model.SweepPolicy{}
+
+	policies := []*model.SweepPolicy{accountTON, account, ton, all}
+
+	require.Equal(t, accountTON, model.ResolveSweepPolicy(policies, "vip", model.CurrencyTON, fallback))
+	require.Equal(t, account, model.ResolveSweepPolicy(policies, "vip", model.CurrencyUSDT, fallback))
+	require.Equal(t, ton, model.ResolveSweepPolicy(policies, "other", model.CurrencyTON, fallback))
+	require.Equal(t, all, model.ResolveSweepPolicy(policies, "other", model.CurrencyUSDT, fallback))
+	require.Equal(t, fallback, model.ResolveSweepPolicy(policies[:3], "other", model.CurrencyUSDT, fallback))
+}
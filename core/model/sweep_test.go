@@ -0,0 +1,106 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestSweepJob_Transition(t *testing.T) {
+	tests := []struct {
+		from    model.SweepStatus
+		to      model.SweepStatus
+		allowed bool
+	}{
+		{from: model.SweepPlanned, to: model.SweepSent, allowed: true},
+		{from: model.SweepPlanned, to: model.SweepFailed, allowed: true},
+		{from: model.SweepPlanned, to: model.SweepConfirmed},
+		{from: model.SweepPlanned, to: model.SweepFunding, allowed: true},
+		{from: model.SweepPlanned, to: model.SweepFunded},
+		{from: model.SweepFunding, to: model.SweepFunded, allowed: true},
+		{from: model.SweepFunding, to: model.SweepFailed, allowed: true},
+		{from: model.SweepFunding, to: model.SweepSent},
+		{from: model.SweepFunded, to: model.SweepSent, allowed: true},
+		{from: model.SweepFunded, to: model.SweepConfirmed},
+		{from: model.SweepSent, to: model.SweepConfirmed, allowed: true},
+		{from: model.SweepSent, to: model.SweepFailed, allowed: true},
+		{from: model.SweepConfirmed, to: model.SweepFailed},
+		{from: model.SweepFailed, to: model.SweepSent},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"-"+string(tt.to), func(t *testing.T) {
+			at := time.Now()
+			job := &model.SweepJob{Status: tt.from}
+
+			err := job.Transition(tt.to, at)
+			if !tt.allowed {
+				require.ErrorIs(t, err, model.ErrInvalidSweepTransition)
+				require.Equal(t, tt.from, job.Status)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.to, job.Status)
+			require.Equal(t, at, job.UpdatedAt)
+		})
+	}
+}
+
+func TestSweepJob_Transfer(t *testing.T) {
+	at := time.Now()
+	message := &model.WalletMessage{
+		WalletID: 1, From: "0:01", To: "0:02", Currency: model.CurrencyTON, Amount: model.NewAmount(900), Seqno: 3, MessageHash: "hash",
+	}
+
+	job := model.NewSweepJob("1", message, at)
+	job.ID = 7
+
+	require.Equal(t, message, job.Message())
+
+	transfer := job.Transfer()
+	require.Equal(t, "sweep:7", transfer.LedgerReference())
+	require.Equal(t, model.TransferSent, transfer.Status)
+	require.Equal(t, "hash", transfer.MessageHash)
+	require.Equal(t, "900", transfer.Amount.Nano())
+}
+
+func TestSweepJob_Jetton(t *testing.T) {
+	at := time.Now()
+	account := model.Account{ID: "1", WalletID: 1, Address: "0:01"}
+	amount := model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}
+	topUp := &model.WalletMessage{From: "0:02", To: "0:01", Currency: model.CurrencyTON, Amount: model.NewAmount(300), MessageHash: "top-up"}
+
+	job := model.NewJettonSweepJob(account, "0:02", amount, model.NewAmount(300), topUp, at)
+	job.ID = 7
+	require.Equal(t, model.SweepPlanned, job.Status)
+	require.True(t, job.Funding())
+	require.False(t, job.Signed())
+
+	transfer := job.TopUpTransfer()
+	require.Equal(t, "top_up:7", transfer.LedgerReference())
+	require.Equal(t, "0:01", transfer.To)
+	require.Equal(t, model.CurrencyTON, transfer.Currency)
+	require.Equal(t, "top-up", transfer.MessageHash)
+
+	require.NoError(t, job.Transition(model.SweepFunding, at))
+	require.NoError(t, job.Transition(model.SweepFunded, at))
+	require.False(t, job.Funding())
+	require.NotNil(t, job.FundedAt)
+
+	job.SetMessage(&model.WalletMessage{From: "0:01", To: "0:02", Seqno: 2, MessageHash: "hash"})
+	require.True(t, job.Signed())
+
+	transfer = job.Transfer()
+	require.Equal(t, "sweep:7", transfer.LedgerReference())
+	require.Equal(t, model.CurrencyUSDT, transfer.Currency)
+	require.Equal(t, "5000", transfer.Amount.Nano())
+	require.Equal(t, "300", transfer.Gas.Nano())
+
+	funded := model.NewJettonSweepJob(account, "0:02", amount, model.NewAmount(300), nil, at)
+	require.Equal(t, model.SweepFunded, funded.Status)
+	require.False(t, funded.Funding())
+}
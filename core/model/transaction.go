@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -8,12 +10,37 @@ import (
 	"github.com/valyala/fastjson"
 )
 
+// TransactionKind is the role of a transaction in the custodial flows.
+type TransactionKind string
+
+const (
+	// TxKindDeposit is an incoming transfer from the outside world to one of our wallets.
+	TxKindDeposit TransactionKind = "deposit"
+	// TxKindSweep is a transfer of a deposit wallet to the master wallet sent by a sweep.
+	TxKindSweep TransactionKind = "sweep"
+	// TxKindWithdrawal is a payout of the master wallet sent by a withdrawal.
+	TxKindWithdrawal TransactionKind = "withdrawal"
+	// TxKindTopUp is a transfer of the master wallet paying the gas of a jetton sweep of a deposit wallet.
+	TxKindTopUp TransactionKind = "top_up"
+	// TxKindRebalance is a transfer of the master wallet to the cold storage sent by a rebalance.
+	TxKindRebalance TransactionKind = "rebalance"
+	// TxKindBounce returns the value of our outgoing transfer rejected by its destination.
+	TxKindBounce TransactionKind = "bounce"
+	// TxKindFee is an external message to our wallet that sends none of our transfers, e.g. its deployment.
+	TxKindFee TransactionKind = "fee"
+	// TxKindInternal is a transfer between our own wallets, e.g. a sweep arriving at the master wallet.
+	TxKindInternal TransactionKind = "internal"
+)
+
 type Transaction struct {
+	ID int64 // Storage id, set for the stored transactions
+
 	// Transaction identifiers
 	AccountAddr string // Transaction identifier (AccountAddr or LT)
 	LT          int64  // Logical time
 	PrevTxHash  string // Previous transaction hash
 	PrevTxLT    int64  // Previous transaction logical time
+	Hash        string // Transaction hash (hex)
 
 	// Address information
 	Sender         string // Source address
@@ -22,16 +49,32 @@ type Transaction struct {
 	ReceiverIsOurs bool   // Flag indicating if receiver is our address
 
 	// Financial information
-	Amount    float64 // Transaction amount in TON
-	TotalFees float64 // Total fees in TON
-	ExitCode  int     // Compute phase exit code
-	Success   bool    // Transaction execution success flag
+	Amount    Amount // Transaction amount in nanotons
+	TotalFees Amount // Total fees in nanotons
+	ExitCode  int    // Compute phase exit code
+	Success   bool   // Transaction execution success flag
 
 	// Message information
 	MessageType string // Type of message (INTERNAL, EXTERNAL_IN, etc.)
 	Bounce      bool   // Bounce flag
 	Bounced     bool   // Bounced flag
 	Body        string // Transaction body (payload)
+	InMsgHash   string // Hash of the body of the external incoming message (hex)
+	// OutMessages are the internal messages sent by the transaction, they are not stored.
+	OutMessages []OutMessage
+
+	// Decoded message body
+	OpCode           *uint32 // Op code of the body, nil for an empty body
+	QueryID          *uint64 // Query id of the body
+	Comment          string  // Text comment (op 0)
+	EncryptedComment bool    // Body is an encrypted comment (op 0x2167da4b)
+
+	// Classification
+	AccountID  AccountID            // Our account the transaction belongs to
+	Direction  TransactionDirection // Whether the value came to our wallet or left it
+	Currency   Currency             // Currency of the transferred value
+	Kind       TransactionKind      // Role of the transaction, empty if it is not classified
+	TransferID *int64               // Outgoing transfer executed or bounced by the transaction
 
 	// State information
 	BlockID       string    // Block ID containing this transaction
@@ -43,6 +86,18 @@ type Transaction struct {
 	Description    string // Human-readable transaction description (optional)
 }
 
+// OutMessage is an internal message sent by a transaction.
+type OutMessage struct {
+	Receiver string // Destination address
+	Amount   Amount // Value in nanotons
+}
+
+// Message types of the incoming messages.
+const (
+	MessageTypeInternal   = "INTERNAL"
+	MessageTypeExternalIn = "EXTERNAL_IN"
+)
+
 func UnmarshalTransaction(data []byte) (*Transaction, error) {
 	var p fastjson.Parser
 
@@ -51,7 +106,8 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 		return nil, errors.Wrap(err, "parse json")
 	}
 
-	tx := Transaction{}
+	// the value of the incoming message is in TON, jetton transfers are decoded from its body
+	tx := Transaction{Currency: CurrencyTON}
 
 	// Transaction identifiers
 	if accountAddr := v.GetStringBytes("AccountAddr"); accountAddr != nil {
@@ -70,8 +126,20 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 		tx.PrevTxLT = prevLT
 	}
 
+	if hash := v.GetStringBytes("Hash"); hash != nil {
+		decoded, err := base64.StdEncoding.DecodeString(string(hash))
+		if err != nil {
+			return nil, errors.Wrap(err, "decode hash")
+		}
+		tx.Hash = hex.EncodeToString(decoded)
+	}
+
 	// IO information - handle messages
 	if io := v.Get("IO"); io != nil {
+		if msgType := io.Get("In", "MsgType"); msgType != nil && msgType.Type() == fastjson.TypeString {
+			tx.MessageType = string(msgType.GetStringBytes())
+		}
+
 		// Handle incoming message
 		if inMsg := io.Get("In", "Msg"); inMsg != nil {
 			// Address information
@@ -84,10 +152,6 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 			}
 
 			// Message information
-			if msgType := inMsg.Get("MsgType"); msgType != nil && msgType.Type() == fastjson.TypeString {
-				tx.MessageType = string(msgType.GetStringBytes())
-			}
-
 			if bounce := inMsg.Get("Bounce"); bounce != nil {
 				tx.Bounce = bounce.GetBool()
 			}
@@ -98,18 +162,45 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 
 			// Amount
 			if amount := inMsg.Get("Amount"); amount != nil && amount.Type() == fastjson.TypeString {
-				amountStr := string(amount.GetStringBytes())
-				var amountInt float64
-				if _, err := fmt.Sscanf(amountStr, "%f", &amountInt); err == nil {
-					tx.Amount = amountInt / 1_000_000_000 // Convert from nano TON to TON
+				if tx.Amount, err = ParseAmount(string(amount.GetStringBytes())); err != nil {
+					return nil, errors.Wrap(err, "parse amount")
 				}
 			}
 
 			// Body
 			if body := inMsg.Get("Body"); body != nil && body.Type() == fastjson.TypeString {
 				tx.Body = string(body.GetStringBytes())
+
+				// an undecodable body is kept as is, it does not make the transaction invalid
+				if decoded, err := DecodeMessageBody(tx.Body); err == nil {
+					tx.OpCode, tx.QueryID = decoded.OpCode, decoded.QueryID
+					tx.Comment, tx.EncryptedComment = decoded.Comment, decoded.EncryptedComment
+				}
+
+				// the signed body of an external message identifies the transfer sent by our wallet,
+				// the batch a highload wallet sends to itself identifies the transfers of the batch
+				if tx.MessageType == MessageTypeExternalIn || tx.IsHighloadBatch() {
+					if tx.InMsgHash, err = MessageBodyHash(tx.Body); err != nil {
+						return nil, errors.Wrap(err, "hash external message body")
+					}
+				}
+			}
+		}
+	}
+
+	// the internal outgoing messages are listed by the scanner, see ton.ScannedTransaction
+	for _, outMsg := range v.GetArray("OutMsgs") {
+		var message OutMessage
+		if dst := outMsg.Get("DstAddr"); dst != nil && dst.Type() == fastjson.TypeString {
+			message.Receiver = string(dst.GetStringBytes())
+		}
+
+		if amount := outMsg.Get("Amount"); amount != nil && amount.Type() == fastjson.TypeString {
+			if message.Amount, err = ParseAmount(string(amount.GetStringBytes())); err != nil {
+				return nil, errors.Wrap(err, "parse outgoing amount")
 			}
 		}
+		tx.OutMessages = append(tx.OutMessages, message)
 	}
 
 	// Time information
@@ -128,10 +219,8 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 
 	// Fee information
 	if totalFees := v.Get("TotalFees", "Coins"); totalFees != nil && totalFees.Type() == fastjson.TypeString {
-		feesStr := string(totalFees.GetStringBytes())
-		var fees float64
-		if _, err := fmt.Sscanf(feesStr, "%f", &fees); err == nil {
-			tx.TotalFees = fees / 1_000_000_000 // Convert from nano TON to TON
+		if tx.TotalFees, err = ParseAmount(string(totalFees.GetStringBytes())); err != nil {
+			return nil, errors.Wrap(err, "parse total fees")
 		}
 	}
 
@@ -149,13 +238,31 @@ func UnmarshalTransaction(data []byte) (*Transaction, error) {
 				tx.ComputeGasUsed = details.GetInt("GasUsed")
 			}
 		}
+
+		// the messages of a failed action phase are not sent, e.g. a batch without the balance
+		if actionPhase := desc.Get("ActionPhase"); actionPhase != nil && actionPhase.Type() == fastjson.TypeObject {
+			tx.Success = tx.Success && actionPhase.GetBool("Success")
+		}
 	}
 
 	// Generate a description if success is true
 	if tx.Success {
-		tx.Description = fmt.Sprintf("Successfully transferred %.9f TON", tx.Amount)
+		tx.Description = fmt.Sprintf("Successfully transferred %s TON", tx.Amount.String())
 	} else {
 		tx.Description = fmt.Sprintf("Failed transaction with exit code %d", tx.ExitCode)
 	}
 	return &tx, nil
 }
+
+// IsHighloadBatch reports whether the incoming message is the batch a highload wallet sends to itself.
+func (tx *Transaction) IsHighloadBatch() bool {
+	return tx.MessageType == MessageTypeInternal && tx.Sender != "" && tx.Sender == tx.Receiver &&
+		tx.OpCode != nil && *tx.OpCode == OpHighloadBatch
+}
+
+// IsJettonExcess reports whether the incoming message returns the excess of the gas of a jetton transfer,
+// it carries the query id of the transfer.
+func (tx *Transaction) IsJettonExcess() bool {
+	return tx.MessageType == MessageTypeInternal && !tx.Bounced &&
+		tx.OpCode != nil && *tx.OpCode == OpExcesses && tx.QueryID != nil
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// TransactionDirection tells whether the transaction brought value to our wallet or sent it out.
+type TransactionDirection string
+
+const (
+	DirectionIn  TransactionDirection = "in"
+	DirectionOut TransactionDirection = "out"
+)
+
+// ListTransactionsFilter selects the stored transactions, unset fields do not filter.
+// Transactions are listed from the newest to the oldest, Cursor continues the listing after the previous page.
+type ListTransactionsFilter struct {
+	AccountID *AccountID
+	Address   *string // Sender or receiver of the incoming message
+	Direction *TransactionDirection
+	Currency  *Currency
+	Success   *bool
+	FromTime  *time.Time // Inclusive
+	ToTime    *time.Time // Exclusive
+	FromLT    *int64     // Inclusive
+	ToLT      *int64     // Exclusive
+	Cursor    string
+	Limit     int
+}
+
+// TransactionPage is a page of the listed transactions, NextCursor is empty on the last page.
+type TransactionPage struct {
+	Transactions []*Transaction
+	NextCursor   string
+}
+
+// EncodeTransactionCursor returns the opaque cursor continuing the listing after the transaction.
+func EncodeTransactionCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// DecodeTransactionCursor returns the id of the transaction the cursor continues after, 0 for an empty cursor.
+func DecodeTransactionCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// GetTransactionQuery identifies a transaction by its hash or by the account and logical time.
+type GetTransactionQuery struct {
+	Hash      string
+	AccountID AccountID
+	LT        int64
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// DefaultJettonGasNano is the TON in nanotons attached to a jetton transfer by default, 0.05 TON.
+const DefaultJettonGasNano = 50_000_000
+
+// TransferKind is the operation that sent an outgoing transfer.
+type TransferKind string
+
+const (
+	TransferSweep      TransferKind = "sweep"
+	TransferWithdrawal TransferKind = "withdrawal"
+	TransferTopUp      TransferKind = "top_up"
+	TransferRebalance  TransferKind = "rebalance"
+)
+
+// TransferStatus is the state of an outgoing transfer: sent → confirmed → bounced, or sent → failed.
+type TransferStatus string
+
+const (
+	TransferSent      TransferStatus = "sent"
+	TransferConfirmed TransferStatus = "confirmed"
+	TransferBounced   TransferStatus = "bounced"
+	TransferFailed    TransferStatus = "failed"
+)
+
+// transferTransitions lists the statuses reachable from a status, final statuses are absent.
+var transferTransitions = map[TransferStatus][]TransferStatus{
+	TransferSent:      {TransferConfirmed, TransferFailed},
+	TransferConfirmed: {TransferBounced},
+}
+
+// OutgoingTransfer is a transfer sent by one of our wallets. Sweeps and withdrawals register the transfers
+// they send, so the transaction processor links the transactions of the wallets to them.
+type OutgoingTransfer struct {
+	ID        int64
+	Kind      TransferKind
+	Reference string // ID of the sweep or withdrawal that sent the transfer
+	AccountID AccountID
+	From      string // Raw address of the sending wallet
+	To        string // Raw address of the destination
+	Currency  Currency
+	Amount    Amount
+	// Gas is the TON attached to a jetton transfer, it pays the forwarding by the jetton wallets
+	// and its excess is returned to the master wallet.
+	Gas Amount
+	// MessageHash is the hex hash of the body of the external message sent to the wallet,
+	// wallets sign unique bodies, so it identifies the transaction executing the transfer.
+	// The transfers of a highload batch share the hash of the batch message the wallet sends to itself.
+	MessageHash string
+	// BounceHash is the hash of the prefix of the body of the sent message, see BounceHash. A bounce returns
+	// the prefix, so it is matched to the transfer by the hash. It is empty for the non-bounceable transfers.
+	BounceHash string
+	// JettonQueryID is the query id of a jetton transfer, the excess of its gas returned by the jetton wallet
+	// of the destination carries it, so the transfer is confirmed by the excess. It is 0 for a TON transfer.
+	JettonQueryID uint64
+	Status        TransferStatus
+	TxHash        string // Hash of the transaction executing the transfer or returning the excess of its jetton gas
+	Fee           Amount
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// WalletMessage is an external message signed for one of our wallets, it is built before it is sent,
+// so the transfer it executes is registered with its hash first.
+type WalletMessage struct {
+	WalletID    uint32
+	From        string // Raw address of the sending wallet
+	To          string // Raw address of the destination, the owner of the jetton wallet for a jetton transfer
+	Currency    Currency
+	Amount      Amount
+	Gas         Amount // TON attached to a jetton transfer
+	Seqno       uint32 // Seqno of the wallet the message is signed with
+	QueryID     uint32 // Query id of the highload wallet the message is signed with
+	MessageHash string // Hex hash of the body executing the transfer, see OutgoingTransfer.MessageHash
+	BounceHash  string // Hash of the prefix of the body of the transfer, see OutgoingTransfer.BounceHash
+	// JettonQueryID is the query id of a jetton transfer, see OutgoingTransfer.JettonQueryID.
+	JettonQueryID uint64
+	BOC           []byte    // Serialized external message
+	ExpiresAt     time.Time // The wallet does not accept the message after this time
+}
+
+// LedgerReference identifies the ledger entry of the transfer.
+func (t *OutgoingTransfer) LedgerReference() string {
+	return string(t.Kind) + ":" + t.Reference
+}
+
+// Transition moves the transfer to the status.
+func (t *OutgoingTransfer) Transition(to TransferStatus, at time.Time) error {
+	for _, status := range transferTransitions[t.Status] {
+		if status == to {
+			t.Status, t.UpdatedAt = to, at
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrInvalidTransferTransition, "%s to %s", t.Status, to)
+}
+
+// TransferEventType returns the outbox event type emitted when a transfer reaches the status, e.g. sweep_confirmed.
+func TransferEventType(kind TransferKind, status TransferStatus) EventType {
+	return EventType(string(kind) + "_" + string(status))
+}
+
+// TransferPayload is the payload of the sweep and withdrawal transfer events.
+type TransferPayload struct {
+	TransferID int64          `json:"transfer_id"`
+	Kind       TransferKind   `json:"kind"`
+	Reference  string         `json:"reference"`
+	AccountID  AccountID      `json:"account_id"`
+	To         string         `json:"to"`
+	Currency   Currency       `json:"currency"`
+	Amount     string         `json:"amount"`
+	Status     TransferStatus `json:"status"`
+	TxHash     string         `json:"tx_hash,omitempty"`
+	Fee        string         `json:"fee"`
+	At         time.Time      `json:"at"`
+}
+
+// AggregateID keeps the transfer events of an account in order with its deposit events.
+func (p TransferPayload) AggregateID() string {
+	return p.AccountID
+}
+
+func NewTransferPayload(transfer *OutgoingTransfer) TransferPayload {
+	return TransferPayload{
+		TransferID: transfer.ID,
+		Kind:       transfer.Kind,
+		Reference:  transfer.Reference,
+		AccountID:  transfer.AccountID,
+		To:         transfer.To,
+		Currency:   transfer.Currency,
+		Amount:     transfer.Amount.Nano(),
+		Status:     transfer.Status,
+		TxHash:     transfer.TxHash,
+		Fee:        transfer.Fee.Nano(),
+		At:         transfer.UpdatedAt,
+	}
+}
+
+// BounceUnmatchedPayload is the payload of the BounceUnmatchedEvent, the bounce returned a value to our wallet
+// but none of our transfers matches it.
+type BounceUnmatchedPayload struct {
+	AccountID AccountID `json:"account_id"`
+	Wallet    string    `json:"wallet"`
+	Sender    string    `json:"sender"`
+	TxHash    string    `json:"tx_hash"`
+	LT        int64     `json:"lt"`
+	Amount    string    `json:"amount"`
+	At        time.Time `json:"at"`
+}
+
+// AggregateID keeps the alert in order with the transfer events of the account.
+func (p BounceUnmatchedPayload) AggregateID() string {
+	return p.AccountID
+}
+
+func NewBounceUnmatchedPayload(tx *Transaction) BounceUnmatchedPayload {
+	return BounceUnmatchedPayload{
+		AccountID: tx.AccountID,
+		Wallet:    tx.AccountAddr,
+		Sender:    tx.Sender,
+		TxHash:    tx.Hash,
+		LT:        tx.LT,
+		Amount:    tx.Amount.Nano(),
+		At:        tx.CreatedAt,
+	}
+}
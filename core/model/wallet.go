@@ -3,6 +3,7 @@ package model
 import (
 	"math/big"
 
+	"github.com/go-faster/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,17 +18,84 @@ const (
 	CurrencyUSDT Currency = "USDT"
 )
 
+// amountDecimals is the number of decimals of nano units.
+const amountDecimals = 9
+
+// Amount is an integer amount in nano units.
 type Amount big.Int
 
+// NewAmount returns the amount of nano units.
+func NewAmount(nano int64) Amount {
+	return Amount(*big.NewInt(nano))
+}
+
+// ParseAmount parses an integer amount of nano units.
+func ParseAmount(nano string) (Amount, error) {
+	value, ok := new(big.Int).SetString(nano, 10)
+	if !ok {
+		return Amount{}, errors.Errorf("invalid amount %q", nano)
+	}
+	return Amount(*value), nil
+}
+
+// ParseUnits parses an amount in whole units, it is rejected if it has more decimals than nano units.
+func ParseUnits(units string) (Amount, error) {
+	value, err := decimal.NewFromString(units)
+	if err != nil {
+		return Amount{}, errors.Wrapf(err, "invalid amount %q", units)
+	}
+
+	nano := value.Shift(amountDecimals)
+	if !nano.IsInteger() {
+		return Amount{}, errors.Errorf("invalid amount %q, more than %d decimals", units, amountDecimals)
+	}
+	return Amount(*nano.BigInt()), nil
+}
+
+// BigInt returns a copy of the amount in nano units.
+func (a *Amount) BigInt() *big.Int {
+	if a == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set((*big.Int)(a))
+}
+
+// Neg returns the negated amount.
+func (a *Amount) Neg() Amount {
+	return Amount(*new(big.Int).Neg(a.BigInt()))
+}
+
+// Add returns the sum of the amounts.
+func (a *Amount) Add(b Amount) Amount {
+	return Amount(*new(big.Int).Add(a.BigInt(), b.BigInt()))
+}
+
+// Sub returns the difference of the amounts.
+func (a *Amount) Sub(b Amount) Amount {
+	return Amount(*new(big.Int).Sub(a.BigInt(), b.BigInt()))
+}
+
+// Cmp returns -1, 0 or +1 depending on whether the amount is less than, equal to or greater than b.
+func (a *Amount) Cmp(b Amount) int {
+	return a.BigInt().Cmp(b.BigInt())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (a *Amount) Sign() int {
+	return a.BigInt().Sign()
+}
+
+// Nano returns the amount in nano units.
+func (a *Amount) Nano() string {
+	return a.BigInt().String()
+}
+
+// String returns the amount in whole units without losing precision.
 func (a *Amount) String() string {
 	if a == nil {
 		return "0"
 	}
-
-	bigInt := (*big.Int)(a)
-	decimalValue := decimal.NewFromBigInt(bigInt, 0)
-	result := decimalValue.Div(decimal.NewFromInt(1000000000))
-	return result.String()
+	return decimal.NewFromBigInt((*big.Int)(a), -amountDecimals).String()
 }
 
 type Balance struct {
@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		nano     string
+		expected string
+	}{
+		{nano: "0", expected: "0"},
+		{nano: "1", expected: "0.000000001"},
+		{nano: "1500000000", expected: "1.5"},
+		{nano: "123456789123456789123456789", expected: "123456789123456789.123456789"},
+		{nano: "-2000000001", expected: "-2.000000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nano, func(t *testing.T) {
+			amount, err := model.ParseAmount(tt.nano)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, amount.String())
+			require.Equal(t, tt.nano, amount.Nano())
+		})
+	}
+}
+
+func TestParseAmount_Invalid(t *testing.T) {
+	for _, value := range []string{"", "1.5", "1e9", "abc"} {
+		_, err := model.ParseAmount(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	for units, nano := range map[string]string{"0": "0", "1.5": "1500000000", "0.000000001": "1", "-2": "-2000000000"} {
+		amount, err := model.ParseUnits(units)
+		require.NoError(t, err, units)
+		require.Equal(t, nano, amount.Nano(), units)
+	}
+
+	for _, value := range []string{"", "0.0000000001", "abc"} {
+		_, err := model.ParseUnits(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestUnmarshalTransaction_Amounts(t *testing.T) {
+	tx, err := model.UnmarshalTransaction([]byte(`{
+		"IO": {"In": {"Msg": {"Amount": "123456789123456789"}}},
+		"TotalFees": {"Coins": "532801"}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, "123456789123456789", tx.Amount.Nano())
+	require.Equal(t, "532801", tx.TotalFees.Nano())
+}
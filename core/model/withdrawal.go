@@ -0,0 +1,237 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// WithdrawalStatus is the state of a withdrawal: requested → approved → signed → broadcast → confirmed,
+// a withdrawal that is not final may fail instead. A signed withdrawal is confirmed without being broadcast
+// if its transfer is confirmed before the broadcast is stored. A withdrawal whose wallet executed the message
+// without the transfer being confirmed is unconfirmed until its transfer is confirmed or fails.
+type WithdrawalStatus string
+
+const (
+	WithdrawalRequested   WithdrawalStatus = "requested"
+	WithdrawalApproved    WithdrawalStatus = "approved"
+	WithdrawalSigned      WithdrawalStatus = "signed"
+	WithdrawalBroadcast   WithdrawalStatus = "broadcast"
+	WithdrawalConfirmed   WithdrawalStatus = "confirmed"
+	WithdrawalFailed      WithdrawalStatus = "failed"
+	WithdrawalUnconfirmed WithdrawalStatus = "unconfirmed"
+)
+
+// withdrawalTransitions lists the statuses reachable from a status, final statuses are absent.
+var withdrawalTransitions = map[WithdrawalStatus][]WithdrawalStatus{
+	WithdrawalRequested:   {WithdrawalApproved, WithdrawalFailed},
+	WithdrawalApproved:    {WithdrawalSigned, WithdrawalFailed},
+	WithdrawalSigned:      {WithdrawalBroadcast, WithdrawalConfirmed, WithdrawalFailed, WithdrawalUnconfirmed},
+	WithdrawalBroadcast:   {WithdrawalConfirmed, WithdrawalFailed, WithdrawalUnconfirmed},
+	WithdrawalUnconfirmed: {WithdrawalConfirmed, WithdrawalFailed},
+}
+
+// ActiveWithdrawalStatuses are the statuses of the withdrawals whose amount is reserved on the custodial balance,
+// the confirmed withdrawals are recorded in the ledger instead. The amount of an unconfirmed withdrawal stays reserved,
+// its message was executed.
+var ActiveWithdrawalStatuses = []WithdrawalStatus{
+	WithdrawalRequested, WithdrawalApproved, WithdrawalSigned, WithdrawalBroadcast, WithdrawalUnconfirmed,
+}
+
+// Withdrawal is a payout of the custodial balance of an account sent by the master wallet or in a batch
+// of the highload payout wallet. The signed message is stored before it is broadcast like the message
+// of a sweep job, so it is sent again instead of signed again.
+type Withdrawal struct {
+	ID        int64
+	AccountID AccountID
+	// IdempotencyKey identifies the request of the client, a repeated request returns the same withdrawal.
+	IdempotencyKey string
+	To             string // Destination address as requested, its bounce flag is kept
+	Currency       Currency
+	Amount         Amount
+	Comment        string
+	Gas            Amount // TON attached to a jetton transfer
+	Status         WithdrawalStatus
+	From           string // Raw address of the sending wallet
+	Seqno          uint32 // Seqno of the master wallet the message is signed with
+	QueryID        uint32 // Query id of the highload payout wallet the batch is signed with
+	// MessageHash links the withdrawal to its outgoing transfer, the withdrawals of a batch share it.
+	// It is empty until the withdrawal is signed.
+	MessageHash string
+	BounceHash  string // See OutgoingTransfer.BounceHash
+	// JettonQueryID is the query id of a jetton transfer, see OutgoingTransfer.JettonQueryID.
+	JettonQueryID uint64
+	BOC           []byte    // Serialized external message
+	ExpiresAt     time.Time // The message is not accepted by the wallet after this time
+	TxHash        string    // Hash of the transaction of the master wallet executing the transfer
+	Attempts      int       // Number of the broadcasts
+	Error         string    // Last error or the reason of the failure
+	HoldReason    string    // Why the requested withdrawal is not approved yet, e.g. the missing approvals
+	ApprovedAt    *time.Time
+	SignedAt      *time.Time
+	BroadcastAt   *time.Time
+	ConfirmedAt   *time.Time
+	FailedAt      *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Signed reports whether the message of the withdrawal is signed.
+func (w *Withdrawal) Signed() bool {
+	return w.MessageHash != ""
+}
+
+// SetMessage stores the signed message of the sending wallet.
+func (w *Withdrawal) SetMessage(message *WalletMessage) {
+	w.From, w.Gas, w.Seqno, w.QueryID = message.From, message.Gas, message.Seqno, message.QueryID
+	w.MessageHash, w.BounceHash, w.BOC, w.ExpiresAt = message.MessageHash, message.BounceHash, message.BOC, message.ExpiresAt
+	w.JettonQueryID = message.JettonQueryID
+}
+
+// Message returns the signed message of the sending wallet.
+func (w *Withdrawal) Message() *WalletMessage {
+	return &WalletMessage{
+		From:          w.From,
+		To:            w.To,
+		Currency:      w.Currency,
+		Amount:        w.Amount,
+		Gas:           w.Gas,
+		Seqno:         w.Seqno,
+		QueryID:       w.QueryID,
+		MessageHash:   w.MessageHash,
+		BounceHash:    w.BounceHash,
+		JettonQueryID: w.JettonQueryID,
+		BOC:           w.BOC,
+		ExpiresAt:     w.ExpiresAt,
+	}
+}
+
+// Transfer returns the outgoing transfer of the signed message.
+func (w *Withdrawal) Transfer() *OutgoingTransfer {
+	return &OutgoingTransfer{
+		Kind:          TransferWithdrawal,
+		Reference:     w.Reference(),
+		AccountID:     w.AccountID,
+		From:          w.From,
+		To:            w.To,
+		Currency:      w.Currency,
+		Amount:        w.Amount,
+		Gas:           w.Gas,
+		MessageHash:   w.MessageHash,
+		BounceHash:    w.BounceHash,
+		JettonQueryID: w.JettonQueryID,
+		Status:        TransferSent,
+		CreatedAt:     w.UpdatedAt,
+		UpdatedAt:     w.UpdatedAt,
+	}
+}
+
+// Reference is the reference of the outgoing transfer of the withdrawal.
+func (w *Withdrawal) Reference() string {
+	return strconv.FormatInt(w.ID, 10)
+}
+
+// CanTransition reports whether the withdrawal may move to the status.
+func (w *Withdrawal) CanTransition(to WithdrawalStatus) bool {
+	for _, status := range withdrawalTransitions[w.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the withdrawal to the status and stamps the time of the transition.
+func (w *Withdrawal) Transition(to WithdrawalStatus, at time.Time) error {
+	if !w.CanTransition(to) {
+		return errors.Wrapf(ErrInvalidWithdrawalTransition, "%s to %s", w.Status, to)
+	}
+
+	switch to {
+	case WithdrawalApproved:
+		w.ApprovedAt = &at
+	case WithdrawalSigned:
+		w.SignedAt = &at
+	case WithdrawalBroadcast:
+		w.BroadcastAt = &at
+	case WithdrawalConfirmed:
+		w.ConfirmedAt = &at
+	case WithdrawalFailed:
+		w.FailedAt = &at
+	case WithdrawalRequested, WithdrawalUnconfirmed:
+	}
+
+	// only a requested withdrawal is held
+	w.Status, w.UpdatedAt, w.HoldReason = to, at, ""
+	return nil
+}
+
+// ListWithdrawalsFilter selects the withdrawals, unset fields do not filter. Withdrawals are listed
+// from the newest to the oldest, Cursor continues the listing after the previous page.
+type ListWithdrawalsFilter struct {
+	AccountID *AccountID
+	Status    *WithdrawalStatus
+	Cursor    string
+	Limit     int
+}
+
+// WithdrawalPage is a page of the listed withdrawals, NextCursor is empty on the last page.
+type WithdrawalPage struct {
+	Withdrawals []*Withdrawal
+	NextCursor  string
+}
+
+// EncodeWithdrawalCursor returns the opaque cursor continuing the listing after the withdrawal.
+func EncodeWithdrawalCursor(id int64) string {
+	return EncodeTransactionCursor(id)
+}
+
+// DecodeWithdrawalCursor returns the id of the withdrawal the cursor continues after, 0 for an empty cursor.
+func DecodeWithdrawalCursor(cursor string) (int64, error) {
+	return DecodeTransactionCursor(cursor)
+}
+
+// WithdrawalEventType returns the outbox event type emitted when a withdrawal reaches the status.
+func WithdrawalEventType(status WithdrawalStatus) EventType {
+	return EventType("withdrawal_" + string(status))
+}
+
+// WithdrawalPayload is the payload of the withdrawal events, withdrawal_bounced carries the TransferPayload
+// of the bounced transfer instead.
+type WithdrawalPayload struct {
+	WithdrawalID   int64            `json:"withdrawal_id"`
+	AccountID      AccountID        `json:"account_id"`
+	IdempotencyKey string           `json:"idempotency_key"`
+	To             string           `json:"to"`
+	Currency       Currency         `json:"currency"`
+	Amount         string           `json:"amount"`
+	Comment        string           `json:"comment,omitempty"`
+	Status         WithdrawalStatus `json:"status"`
+	MessageHash    string           `json:"message_hash,omitempty"`
+	TxHash         string           `json:"tx_hash,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	At             time.Time        `json:"at"`
+}
+
+// AggregateID keeps the withdrawal events of an account in order with its other events.
+func (p WithdrawalPayload) AggregateID() string {
+	return p.AccountID
+}
+
+func NewWithdrawalPayload(withdrawal *Withdrawal) WithdrawalPayload {
+	return WithdrawalPayload{
+		WithdrawalID:   withdrawal.ID,
+		AccountID:      withdrawal.AccountID,
+		IdempotencyKey: withdrawal.IdempotencyKey,
+		To:             withdrawal.To,
+		Currency:       withdrawal.Currency,
+		Amount:         withdrawal.Amount.Nano(),
+		Comment:        withdrawal.Comment,
+		Status:         withdrawal.Status,
+		MessageHash:    withdrawal.MessageHash,
+		TxHash:         withdrawal.TxHash,
+		Error:          withdrawal.Error,
+		At:             withdrawal.UpdatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// ApprovalDecision is the decision of an operator on a requested withdrawal.
+type ApprovalDecision string
+
+const (
+	ApprovalApproved ApprovalDecision = "approved"
+	ApprovalRejected ApprovalDecision = "rejected"
+)
+
+// WithdrawalApproval is the decision of an operator on a requested withdrawal, an operator decides once.
+// A rejection fails the withdrawal, the approvals are counted by the worker before the withdrawal is sent.
+type WithdrawalApproval struct {
+	ID           int64
+	WithdrawalID int64
+	Operator     string
+	Decision     ApprovalDecision
+	Comment      string // Comment of the approval or the reason of the rejection
+	CreatedAt    time.Time
+}
+
+// ApprovalThreshold requires the approvals of distinct operators for the withdrawals above the amount.
+type ApprovalThreshold struct {
+	Above     Amount
+	Approvals int
+}
+
+// WithdrawalPolicy is the approval and limit policy of the withdrawals in a currency. The withdrawals above
+// an approval threshold wait for the approvals, the daily limits cap the amount of the withdrawals approved
+// for sending on a UTC day, a zero limit does not limit. A withdrawal that fails after its approval releases
+// its amount.
+type WithdrawalPolicy struct {
+	Currency          Currency
+	Thresholds        []ApprovalThreshold
+	AccountDailyLimit Amount // Limit of the withdrawals of an account
+	GlobalDailyLimit  Amount // Limit of the withdrawals of all the accounts
+}
+
+// RequiredApprovals returns the number of approvals the withdrawal of the amount requires,
+// the highest of the thresholds below the amount applies.
+func (p *WithdrawalPolicy) RequiredApprovals(amount Amount) int {
+	required := 0
+	for _, threshold := range p.Thresholds {
+		if amount.Cmp(threshold.Above) > 0 {
+			required = max(required, threshold.Approvals)
+		}
+	}
+	return required
+}
+
+// GlobalLimitScope is the scope of the counters of the global limits, the counters of an account
+// are scoped by its id.
+const GlobalLimitScope = "*"
+
+// WithdrawalLimitCounter identifies the counter of the amount of the withdrawals approved on a day.
+type WithdrawalLimitCounter struct {
+	Scope    string // Account id or GlobalLimitScope
+	Currency Currency
+	Day      time.Time // Start of the UTC day
+}
+
+// LimitDay returns the start of the UTC day of the time, the daily limits are counted by it.
+func LimitDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestWithdrawal_Transition(t *testing.T) {
+	tests := []struct {
+		from    model.WithdrawalStatus
+		to      model.WithdrawalStatus
+		allowed bool
+	}{
+		{from: model.WithdrawalRequested, to: model.WithdrawalApproved, allowed: true},
+		{from: model.WithdrawalRequested, to: model.WithdrawalFailed, allowed: true},
+		{from: model.WithdrawalRequested, to: model.WithdrawalSigned},
+		{from: model.WithdrawalApproved, to: model.WithdrawalSigned, allowed: true},
+		{from: model.WithdrawalApproved, to: model.WithdrawalFailed, allowed: true},
+		{from: model.WithdrawalApproved, to: model.WithdrawalBroadcast},
+		{from: model.WithdrawalSigned, to: model.WithdrawalBroadcast, allowed: true},
+		{from: model.WithdrawalSigned, to: model.WithdrawalConfirmed, allowed: true},
+		{from: model.WithdrawalSigned, to: model.WithdrawalFailed, allowed: true},
+		{from: model.WithdrawalBroadcast, to: model.WithdrawalConfirmed, allowed: true},
+		{from: model.WithdrawalBroadcast, to: model.WithdrawalFailed, allowed: true},
+		{from: model.WithdrawalBroadcast, to: model.WithdrawalSigned},
+		{from: model.WithdrawalConfirmed, to: model.WithdrawalFailed},
+		{from: model.WithdrawalFailed, to: model.WithdrawalApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"-"+string(tt.to), func(t *testing.T) {
+			at := time.Now()
+			withdrawal := &model.Withdrawal{Status: tt.from}
+
+			err := withdrawal.Transition(tt.to, at)
+			if !tt.allowed {
+				require.ErrorIs(t, err, model.ErrInvalidWithdrawalTransition)
+				require.Equal(t, tt.from, withdrawal.Status)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.to, withdrawal.Status)
+			require.Equal(t, at, withdrawal.UpdatedAt)
+		})
+	}
+}
+
+func TestWithdrawal_Transfer(t *testing.T) {
+	at := time.Now()
+	message := &model.WalletMessage{
+		From: "0:02", To: "0:40", Currency: model.CurrencyUSDT, Amount: model.NewAmount(900),
+		Gas: model.NewAmount(50), Seqno: 3, MessageHash: "hash", ExpiresAt: at,
+	}
+
+	withdrawal := &model.Withdrawal{ID: 7, AccountID: "1", To: "EQ...", Currency: model.CurrencyUSDT, Amount: model.NewAmount(900)}
+	require.False(t, withdrawal.Signed())
+
+	withdrawal.SetMessage(message)
+	require.True(t, withdrawal.Signed())
+	require.Equal(t, uint32(3), withdrawal.Message().Seqno)
+
+	transfer := withdrawal.Transfer()
+	require.Equal(t, model.TransferWithdrawal, transfer.Kind)
+	require.Equal(t, "7", transfer.Reference)
+	require.Equal(t, model.TransferSent, transfer.Status)
+	require.Equal(t, "hash", transfer.MessageHash)
+	require.Equal(t, "50", transfer.Gas.Nano())
+}
+
+func TestWithdrawalPolicy_RequiredApprovals(t *testing.T) {
+	policy := model.WithdrawalPolicy{Thresholds: []model.ApprovalThreshold{
+		{Above: model.NewAmount(10_000), Approvals: 2},
+		{Above: model.NewAmount(1_000), Approvals: 1},
+	}}
+
+	require.Equal(t, 0, policy.RequiredApprovals(model.NewAmount(1_000)))
+	require.Equal(t, 1, policy.RequiredApprovals(model.NewAmount(1_001)))
+	require.Equal(t, 2, policy.RequiredApprovals(model.NewAmount(10_001)))
+	require.Equal(t, 0, (&model.WithdrawalPolicy{}).RequiredApprovals(model.NewAmount(10_001)))
+}
@@ -8,17 +8,82 @@ import (
 
 type AccountServicePort interface {
 	CreateAccount(ctx context.Context, accountID model.AccountID) (*model.Account, error)
-	GetBalance(ctx context.Context, accountID model.AccountID) ([]model.Balance, error)
+	GetBalance(ctx context.Context, accountID model.AccountID) (*model.AccountBalance, error)
 	MasterAccount(ctx context.Context) (*model.Account, error)
 	CloseAccount(ctx context.Context, accountID model.AccountID) error
 	ListAccounts(ctx context.Context, req model.ListAccountFilter) ([]model.Account, error)
+	ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) (*model.TransactionPage, error)
+	GetTransaction(ctx context.Context, query model.GetTransactionQuery) (*model.Transaction, error)
+}
+
+// LedgerServicePort records the double-entry postings of the custodial operations
+// and derives the custodial balances of the accounts from them.
+type LedgerServicePort interface {
+	RecordDeposit(ctx context.Context, accountID model.AccountID, amount model.Balance, reference string) (*model.LedgerEntry, error)
+	RecordSweep(ctx context.Context, accountID model.AccountID, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error)
+	RecordTopUp(ctx context.Context, accountID model.AccountID, amount model.Amount, fee model.Amount, reference string) (*model.LedgerEntry, error)
+	RecordFee(ctx context.Context, accountID model.AccountID, fee model.Amount, reference string) (*model.LedgerEntry, error)
+	RecordWithdrawal(ctx context.Context, accountID model.AccountID, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error)
+	RecordRebalance(ctx context.Context, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error)
+	RecordBounce(
+		ctx context.Context, kind model.TransferKind, accountID model.AccountID, sent model.Balance, returned model.Amount, reference string,
+	) (*model.LedgerEntry, error)
+	Transfer(ctx context.Context, from, to model.AccountID, amount model.Balance, reference string) (*model.LedgerEntry, error)
+	GetBalance(ctx context.Context, accountID model.AccountID) ([]model.Balance, error)
+}
+
+// DepositServicePort moves the deposits through their lifecycle, every transition is persisted
+// and published as an outbox event within the transaction of the context.
+type DepositServicePort interface {
+	Detect(ctx context.Context, accountID model.AccountID, tx *model.Transaction) (*model.Deposit, error)
+	Confirm(ctx context.Context, deposit *model.Deposit) error
+	Credit(ctx context.Context, deposit *model.Deposit) error
+	MarkSwept(ctx context.Context, deposit *model.Deposit) error
+	Bounce(ctx context.Context, deposit *model.Deposit, reason string) error
+	Reject(ctx context.Context, deposit *model.Deposit, reason string) error
 }
 
 type OutboxServicePort interface {
 	GetPendingEvent(ctx context.Context) (*model.OutboxEvent, error)
+	GetPendingEvents(ctx context.Context, limit int) ([]model.OutboxEvent, error)
 	MarkEventAsProcessed(ctx context.Context, eventID int64) error
 }
 
+// EventStreamServicePort streams the outbox events of the accounts, send is called for every event
+// in the order of the event ids until the context is done or send fails.
+type EventStreamServicePort interface {
+	Subscribe(ctx context.Context, subscription model.EventSubscription, send func(event model.OutboxEvent) error) error
+}
+
+type OutboxRetentionServicePort interface {
+	Purge(ctx context.Context) (int, error)
+}
+
 type CollectorServicePort interface {
 	CollectFunds(ctx context.Context) error
+	// PlanSweeps returns the sweeps of the accounts, of all the accounts if none is given, the collector would plan
+	// in the current balances without signing or sending anything.
+	PlanSweeps(ctx context.Context, accountIDs []model.AccountID) ([]*model.PlannedSweep, error)
+}
+
+// SweepPolicyServicePort manages the sweep policies evaluated by the collector.
+type SweepPolicyServicePort interface {
+	// SetSweepPolicy replaces the policy of the account and the currency of the policy.
+	SetSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error)
+	ListSweepPolicies(ctx context.Context, accountID *model.AccountID) ([]*model.SweepPolicy, error)
+	DeleteSweepPolicy(ctx context.Context, accountID model.AccountID, currency model.Currency) error
+}
+
+// WithdrawalServicePort accepts the withdrawals of the custodial balances, the withdrawals are sent
+// by the withdrawal worker.
+type WithdrawalServicePort interface {
+	// CreateWithdrawal requests the withdrawal, a repeated request with the idempotency key returns the stored one.
+	CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error)
+	GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error)
+	ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) (*model.WithdrawalPage, error)
+	// ApproveWithdrawal records the approval of the requested withdrawal by the operator and returns
+	// the decisions on it, the worker sends the withdrawal once it has the approvals its policy requires.
+	ApproveWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) ([]*model.WithdrawalApproval, error)
+	// RejectWithdrawal records the rejection of the requested withdrawal by the operator and fails it.
+	RejectWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) (*model.Withdrawal, error)
 }
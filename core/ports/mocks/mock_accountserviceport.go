@@ -129,23 +129,23 @@ func (_c *MockAccountServicePort_CreateAccount_Call) RunAndReturn(run func(conte
 }
 
 // GetBalance provides a mock function with given fields: ctx, accountID
-func (_m *MockAccountServicePort) GetBalance(ctx context.Context, accountID string) ([]model.Balance, error) {
+func (_m *MockAccountServicePort) GetBalance(ctx context.Context, accountID string) (*model.AccountBalance, error) {
 	ret := _m.Called(ctx, accountID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBalance")
 	}
 
-	var r0 []model.Balance
+	var r0 *model.AccountBalance
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Balance, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.AccountBalance, error)); ok {
 		return rf(ctx, accountID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Balance); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.AccountBalance); ok {
 		r0 = rf(ctx, accountID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Balance)
+			r0 = ret.Get(0).(*model.AccountBalance)
 		}
 	}
 
@@ -177,12 +177,71 @@ func (_c *MockAccountServicePort_GetBalance_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockAccountServicePort_GetBalance_Call) Return(_a0 []model.Balance, _a1 error) *MockAccountServicePort_GetBalance_Call {
+func (_c *MockAccountServicePort_GetBalance_Call) Return(_a0 *model.AccountBalance, _a1 error) *MockAccountServicePort_GetBalance_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAccountServicePort_GetBalance_Call) RunAndReturn(run func(context.Context, string) ([]model.Balance, error)) *MockAccountServicePort_GetBalance_Call {
+func (_c *MockAccountServicePort_GetBalance_Call) RunAndReturn(run func(context.Context, string) (*model.AccountBalance, error)) *MockAccountServicePort_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransaction provides a mock function with given fields: ctx, query
+func (_m *MockAccountServicePort) GetTransaction(ctx context.Context, query model.GetTransactionQuery) (*model.Transaction, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.GetTransactionQuery) (*model.Transaction, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.GetTransactionQuery) *model.Transaction); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.GetTransactionQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountServicePort_GetTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransaction'
+type MockAccountServicePort_GetTransaction_Call struct {
+	*mock.Call
+}
+
+// GetTransaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query model.GetTransactionQuery
+func (_e *MockAccountServicePort_Expecter) GetTransaction(ctx interface{}, query interface{}) *MockAccountServicePort_GetTransaction_Call {
+	return &MockAccountServicePort_GetTransaction_Call{Call: _e.mock.On("GetTransaction", ctx, query)}
+}
+
+func (_c *MockAccountServicePort_GetTransaction_Call) Run(run func(ctx context.Context, query model.GetTransactionQuery)) *MockAccountServicePort_GetTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.GetTransactionQuery))
+	})
+	return _c
+}
+
+func (_c *MockAccountServicePort_GetTransaction_Call) Return(_a0 *model.Transaction, _a1 error) *MockAccountServicePort_GetTransaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountServicePort_GetTransaction_Call) RunAndReturn(run func(context.Context, model.GetTransactionQuery) (*model.Transaction, error)) *MockAccountServicePort_GetTransaction_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -246,6 +305,65 @@ func (_c *MockAccountServicePort_ListAccounts_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// ListTransactions provides a mock function with given fields: ctx, filter
+func (_m *MockAccountServicePort) ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) (*model.TransactionPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 *model.TransactionPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) (*model.TransactionPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) *model.TransactionPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TransactionPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListTransactionsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAccountServicePort_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type MockAccountServicePort_ListTransactions_Call struct {
+	*mock.Call
+}
+
+// ListTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListTransactionsFilter
+func (_e *MockAccountServicePort_Expecter) ListTransactions(ctx interface{}, filter interface{}) *MockAccountServicePort_ListTransactions_Call {
+	return &MockAccountServicePort_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, filter)}
+}
+
+func (_c *MockAccountServicePort_ListTransactions_Call) Run(run func(ctx context.Context, filter model.ListTransactionsFilter)) *MockAccountServicePort_ListTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListTransactionsFilter))
+	})
+	return _c
+}
+
+func (_c *MockAccountServicePort_ListTransactions_Call) Return(_a0 *model.TransactionPage, _a1 error) *MockAccountServicePort_ListTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAccountServicePort_ListTransactions_Call) RunAndReturn(run func(context.Context, model.ListTransactionsFilter) (*model.TransactionPage, error)) *MockAccountServicePort_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MasterAccount provides a mock function with given fields: ctx
 func (_m *MockAccountServicePort) MasterAccount(ctx context.Context) (*model.Account, error) {
 	ret := _m.Called(ctx)
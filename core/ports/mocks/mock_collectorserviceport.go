@@ -0,0 +1,142 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCollectorServicePort is an autogenerated mock type for the CollectorServicePort type
+type MockCollectorServicePort struct {
+	mock.Mock
+}
+
+type MockCollectorServicePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCollectorServicePort) EXPECT() *MockCollectorServicePort_Expecter {
+	return &MockCollectorServicePort_Expecter{mock: &_m.Mock}
+}
+
+// CollectFunds provides a mock function with given fields: ctx
+func (_m *MockCollectorServicePort) CollectFunds(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CollectFunds")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCollectorServicePort_CollectFunds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CollectFunds'
+type MockCollectorServicePort_CollectFunds_Call struct {
+	*mock.Call
+}
+
+// CollectFunds is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockCollectorServicePort_Expecter) CollectFunds(ctx interface{}) *MockCollectorServicePort_CollectFunds_Call {
+	return &MockCollectorServicePort_CollectFunds_Call{Call: _e.mock.On("CollectFunds", ctx)}
+}
+
+func (_c *MockCollectorServicePort_CollectFunds_Call) Run(run func(ctx context.Context)) *MockCollectorServicePort_CollectFunds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockCollectorServicePort_CollectFunds_Call) Return(_a0 error) *MockCollectorServicePort_CollectFunds_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCollectorServicePort_CollectFunds_Call) RunAndReturn(run func(context.Context) error) *MockCollectorServicePort_CollectFunds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PlanSweeps provides a mock function with given fields: ctx, accountIDs
+func (_m *MockCollectorServicePort) PlanSweeps(ctx context.Context, accountIDs []string) ([]*model.PlannedSweep, error) {
+	ret := _m.Called(ctx, accountIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlanSweeps")
+	}
+
+	var r0 []*model.PlannedSweep
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*model.PlannedSweep, error)); ok {
+		return rf(ctx, accountIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*model.PlannedSweep); ok {
+		r0 = rf(ctx, accountIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PlannedSweep)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, accountIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCollectorServicePort_PlanSweeps_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlanSweeps'
+type MockCollectorServicePort_PlanSweeps_Call struct {
+	*mock.Call
+}
+
+// PlanSweeps is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountIDs []string
+func (_e *MockCollectorServicePort_Expecter) PlanSweeps(ctx interface{}, accountIDs interface{}) *MockCollectorServicePort_PlanSweeps_Call {
+	return &MockCollectorServicePort_PlanSweeps_Call{Call: _e.mock.On("PlanSweeps", ctx, accountIDs)}
+}
+
+func (_c *MockCollectorServicePort_PlanSweeps_Call) Run(run func(ctx context.Context, accountIDs []string)) *MockCollectorServicePort_PlanSweeps_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockCollectorServicePort_PlanSweeps_Call) Return(_a0 []*model.PlannedSweep, _a1 error) *MockCollectorServicePort_PlanSweeps_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCollectorServicePort_PlanSweeps_Call) RunAndReturn(run func(context.Context, []string) ([]*model.PlannedSweep, error)) *MockCollectorServicePort_PlanSweeps_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCollectorServicePort creates a new instance of MockCollectorServicePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCollectorServicePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCollectorServicePort {
+	mock := &MockCollectorServicePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
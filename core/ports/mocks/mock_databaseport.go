@@ -7,6 +7,8 @@ import (
 
 	model "github.com/kriuchkov/tonbeacon/core/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockDatabasePort is an autogenerated mock type for the DatabasePort type
@@ -22,6 +24,55 @@ func (_m *MockDatabasePort) EXPECT() *MockDatabasePort_Expecter {
 	return &MockDatabasePort_Expecter{mock: &_m.Mock}
 }
 
+// AddWithdrawalLimitUsage provides a mock function with given fields: ctx, counter, amount, limit
+func (_m *MockDatabasePort) AddWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount, limit model.Amount) error {
+	ret := _m.Called(ctx, counter, amount, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWithdrawalLimitUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WithdrawalLimitCounter, model.Amount, model.Amount) error); ok {
+		r0 = rf(ctx, counter, amount, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_AddWithdrawalLimitUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddWithdrawalLimitUsage'
+type MockDatabasePort_AddWithdrawalLimitUsage_Call struct {
+	*mock.Call
+}
+
+// AddWithdrawalLimitUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - counter model.WithdrawalLimitCounter
+//   - amount model.Amount
+//   - limit model.Amount
+func (_e *MockDatabasePort_Expecter) AddWithdrawalLimitUsage(ctx interface{}, counter interface{}, amount interface{}, limit interface{}) *MockDatabasePort_AddWithdrawalLimitUsage_Call {
+	return &MockDatabasePort_AddWithdrawalLimitUsage_Call{Call: _e.mock.On("AddWithdrawalLimitUsage", ctx, counter, amount, limit)}
+}
+
+func (_c *MockDatabasePort_AddWithdrawalLimitUsage_Call) Run(run func(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount, limit model.Amount)) *MockDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.WithdrawalLimitCounter), args[2].(model.Amount), args[3].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_AddWithdrawalLimitUsage_Call) Return(_a0 error) *MockDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_AddWithdrawalLimitUsage_Call) RunAndReturn(run func(context.Context, model.WithdrawalLimitCounter, model.Amount, model.Amount) error) *MockDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CloseAccount provides a mock function with given fields: ctx, accountID
 func (_m *MockDatabasePort) CloseAccount(ctx context.Context, accountID string) error {
 	ret := _m.Called(ctx, accountID)
@@ -69,29 +120,29 @@ func (_c *MockDatabasePort_CloseAccount_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
-// GetEvents provides a mock function with given fields: ctx, limit
-func (_m *MockDatabasePort) GetEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error) {
-	ret := _m.Called(ctx, limit)
+// DeleteProcessedEvents provides a mock function with given fields: ctx, before, limit
+func (_m *MockDatabasePort) DeleteProcessedEvents(ctx context.Context, before time.Time, limit int) ([]model.OutboxEvent, error) {
+	ret := _m.Called(ctx, before, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetEvents")
+		panic("no return value specified for DeleteProcessedEvents")
 	}
 
 	var r0 []model.OutboxEvent
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]model.OutboxEvent, error)); ok {
-		return rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]model.OutboxEvent, error)); ok {
+		return rf(ctx, before, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.OutboxEvent); ok {
-		r0 = rf(ctx, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []model.OutboxEvent); ok {
+		r0 = rf(ctx, before, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.OutboxEvent)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,115 +150,107 @@ func (_m *MockDatabasePort) GetEvents(ctx context.Context, limit int64) ([]model
 	return r0, r1
 }
 
-// MockDatabasePort_GetEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEvents'
-type MockDatabasePort_GetEvents_Call struct {
+// MockDatabasePort_DeleteProcessedEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteProcessedEvents'
+type MockDatabasePort_DeleteProcessedEvents_Call struct {
 	*mock.Call
 }
 
-// GetEvents is a helper method to define mock.On call
+// DeleteProcessedEvents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - limit int64
-func (_e *MockDatabasePort_Expecter) GetEvents(ctx interface{}, limit interface{}) *MockDatabasePort_GetEvents_Call {
-	return &MockDatabasePort_GetEvents_Call{Call: _e.mock.On("GetEvents", ctx, limit)}
+//   - before time.Time
+//   - limit int
+func (_e *MockDatabasePort_Expecter) DeleteProcessedEvents(ctx interface{}, before interface{}, limit interface{}) *MockDatabasePort_DeleteProcessedEvents_Call {
+	return &MockDatabasePort_DeleteProcessedEvents_Call{Call: _e.mock.On("DeleteProcessedEvents", ctx, before, limit)}
 }
 
-func (_c *MockDatabasePort_GetEvents_Call) Run(run func(ctx context.Context, limit int64)) *MockDatabasePort_GetEvents_Call {
+func (_c *MockDatabasePort_DeleteProcessedEvents_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockDatabasePort_DeleteProcessedEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_GetEvents_Call) Return(_a0 []model.OutboxEvent, _a1 error) *MockDatabasePort_GetEvents_Call {
+func (_c *MockDatabasePort_DeleteProcessedEvents_Call) Return(_a0 []model.OutboxEvent, _a1 error) *MockDatabasePort_DeleteProcessedEvents_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_GetEvents_Call) RunAndReturn(run func(context.Context, int64) ([]model.OutboxEvent, error)) *MockDatabasePort_GetEvents_Call {
+func (_c *MockDatabasePort_DeleteProcessedEvents_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]model.OutboxEvent, error)) *MockDatabasePort_DeleteProcessedEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetWalletIDByAccountID provides a mock function with given fields: ctx, accountID
-func (_m *MockDatabasePort) GetWalletIDByAccountID(ctx context.Context, accountID string) (uint32, error) {
-	ret := _m.Called(ctx, accountID)
+// DeleteSweepPolicy provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockDatabasePort) DeleteSweepPolicy(ctx context.Context, accountID string, currency model.Currency) error {
+	ret := _m.Called(ctx, accountID, currency)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetWalletIDByAccountID")
-	}
-
-	var r0 uint32
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (uint32, error)); ok {
-		return rf(ctx, accountID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) uint32); ok {
-		r0 = rf(ctx, accountID)
-	} else {
-		r0 = ret.Get(0).(uint32)
+		panic("no return value specified for DeleteSweepPolicy")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, accountID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) error); ok {
+		r0 = rf(ctx, accountID, currency)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// MockDatabasePort_GetWalletIDByAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWalletIDByAccountID'
-type MockDatabasePort_GetWalletIDByAccountID_Call struct {
+// MockDatabasePort_DeleteSweepPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSweepPolicy'
+type MockDatabasePort_DeleteSweepPolicy_Call struct {
 	*mock.Call
 }
 
-// GetWalletIDByAccountID is a helper method to define mock.On call
+// DeleteSweepPolicy is a helper method to define mock.On call
 //   - ctx context.Context
 //   - accountID string
-func (_e *MockDatabasePort_Expecter) GetWalletIDByAccountID(ctx interface{}, accountID interface{}) *MockDatabasePort_GetWalletIDByAccountID_Call {
-	return &MockDatabasePort_GetWalletIDByAccountID_Call{Call: _e.mock.On("GetWalletIDByAccountID", ctx, accountID)}
+//   - currency model.Currency
+func (_e *MockDatabasePort_Expecter) DeleteSweepPolicy(ctx interface{}, accountID interface{}, currency interface{}) *MockDatabasePort_DeleteSweepPolicy_Call {
+	return &MockDatabasePort_DeleteSweepPolicy_Call{Call: _e.mock.On("DeleteSweepPolicy", ctx, accountID, currency)}
 }
 
-func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_GetWalletIDByAccountID_Call {
+func (_c *MockDatabasePort_DeleteSweepPolicy_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockDatabasePort_DeleteSweepPolicy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) Return(_a0 uint32, _a1 error) *MockDatabasePort_GetWalletIDByAccountID_Call {
-	_c.Call.Return(_a0, _a1)
+func (_c *MockDatabasePort_DeleteSweepPolicy_Call) Return(_a0 error) *MockDatabasePort_DeleteSweepPolicy_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) RunAndReturn(run func(context.Context, string) (uint32, error)) *MockDatabasePort_GetWalletIDByAccountID_Call {
+func (_c *MockDatabasePort_DeleteSweepPolicy_Call) RunAndReturn(run func(context.Context, string, model.Currency) error) *MockDatabasePort_DeleteSweepPolicy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InsertAccount provides a mock function with given fields: ctx, accountID
-func (_m *MockDatabasePort) InsertAccount(ctx context.Context, accountID string) (*model.Account, error) {
-	ret := _m.Called(ctx, accountID)
+// GetBouncedTransferCandidate provides a mock function with given fields: ctx, from, to, bounceHash, exclude
+func (_m *MockDatabasePort) GetBouncedTransferCandidate(ctx context.Context, from string, to string, bounceHash string, exclude []int64) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, from, to, bounceHash, exclude)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertAccount")
+		panic("no return value specified for GetBouncedTransferCandidate")
 	}
 
-	var r0 *model.Account
+	var r0 *model.OutgoingTransfer
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Account, error)); ok {
-		return rf(ctx, accountID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []int64) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, from, to, bounceHash, exclude)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Account); ok {
-		r0 = rf(ctx, accountID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []int64) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, from, to, bounceHash, exclude)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Account)
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, accountID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []int64) error); ok {
+		r1 = rf(ctx, from, to, bounceHash, exclude)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -215,58 +258,61 @@ func (_m *MockDatabasePort) InsertAccount(ctx context.Context, accountID string)
 	return r0, r1
 }
 
-// MockDatabasePort_InsertAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertAccount'
-type MockDatabasePort_InsertAccount_Call struct {
+// MockDatabasePort_GetBouncedTransferCandidate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBouncedTransferCandidate'
+type MockDatabasePort_GetBouncedTransferCandidate_Call struct {
 	*mock.Call
 }
 
-// InsertAccount is a helper method to define mock.On call
+// GetBouncedTransferCandidate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - accountID string
-func (_e *MockDatabasePort_Expecter) InsertAccount(ctx interface{}, accountID interface{}) *MockDatabasePort_InsertAccount_Call {
-	return &MockDatabasePort_InsertAccount_Call{Call: _e.mock.On("InsertAccount", ctx, accountID)}
+//   - from string
+//   - to string
+//   - bounceHash string
+//   - exclude []int64
+func (_e *MockDatabasePort_Expecter) GetBouncedTransferCandidate(ctx interface{}, from interface{}, to interface{}, bounceHash interface{}, exclude interface{}) *MockDatabasePort_GetBouncedTransferCandidate_Call {
+	return &MockDatabasePort_GetBouncedTransferCandidate_Call{Call: _e.mock.On("GetBouncedTransferCandidate", ctx, from, to, bounceHash, exclude)}
 }
 
-func (_c *MockDatabasePort_InsertAccount_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_InsertAccount_Call {
+func (_c *MockDatabasePort_GetBouncedTransferCandidate_Call) Run(run func(ctx context.Context, from string, to string, bounceHash string, exclude []int64)) *MockDatabasePort_GetBouncedTransferCandidate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].([]int64))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_InsertAccount_Call) Return(_a0 *model.Account, _a1 error) *MockDatabasePort_InsertAccount_Call {
+func (_c *MockDatabasePort_GetBouncedTransferCandidate_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockDatabasePort_GetBouncedTransferCandidate_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_InsertAccount_Call) RunAndReturn(run func(context.Context, string) (*model.Account, error)) *MockDatabasePort_InsertAccount_Call {
+func (_c *MockDatabasePort_GetBouncedTransferCandidate_Call) RunAndReturn(run func(context.Context, string, string, string, []int64) (*model.OutgoingTransfer, error)) *MockDatabasePort_GetBouncedTransferCandidate_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InsertTransaction provides a mock function with given fields: ctx, tx
-func (_m *MockDatabasePort) InsertTransaction(ctx context.Context, tx *model.Transaction) (*model.Transaction, error) {
-	ret := _m.Called(ctx, tx)
+// GetEvents provides a mock function with given fields: ctx, limit
+func (_m *MockDatabasePort) GetEvents(ctx context.Context, limit int64) ([]model.OutboxEvent, error) {
+	ret := _m.Called(ctx, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertTransaction")
+		panic("no return value specified for GetEvents")
 	}
 
-	var r0 *model.Transaction
+	var r0 []model.OutboxEvent
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Transaction) (*model.Transaction, error)); ok {
-		return rf(ctx, tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]model.OutboxEvent, error)); ok {
+		return rf(ctx, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Transaction) *model.Transaction); ok {
-		r0 = rf(ctx, tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []model.OutboxEvent); ok {
+		r0 = rf(ctx, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Transaction)
+			r0 = ret.Get(0).([]model.OutboxEvent)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *model.Transaction) error); ok {
-		r1 = rf(ctx, tx)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -274,56 +320,56 @@ func (_m *MockDatabasePort) InsertTransaction(ctx context.Context, tx *model.Tra
 	return r0, r1
 }
 
-// MockDatabasePort_InsertTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertTransaction'
-type MockDatabasePort_InsertTransaction_Call struct {
+// MockDatabasePort_GetEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEvents'
+type MockDatabasePort_GetEvents_Call struct {
 	*mock.Call
 }
 
-// InsertTransaction is a helper method to define mock.On call
+// GetEvents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - tx *model.Transaction
-func (_e *MockDatabasePort_Expecter) InsertTransaction(ctx interface{}, tx interface{}) *MockDatabasePort_InsertTransaction_Call {
-	return &MockDatabasePort_InsertTransaction_Call{Call: _e.mock.On("InsertTransaction", ctx, tx)}
+//   - limit int64
+func (_e *MockDatabasePort_Expecter) GetEvents(ctx interface{}, limit interface{}) *MockDatabasePort_GetEvents_Call {
+	return &MockDatabasePort_GetEvents_Call{Call: _e.mock.On("GetEvents", ctx, limit)}
 }
 
-func (_c *MockDatabasePort_InsertTransaction_Call) Run(run func(ctx context.Context, tx *model.Transaction)) *MockDatabasePort_InsertTransaction_Call {
+func (_c *MockDatabasePort_GetEvents_Call) Run(run func(ctx context.Context, limit int64)) *MockDatabasePort_GetEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*model.Transaction))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_InsertTransaction_Call) Return(_a0 *model.Transaction, _a1 error) *MockDatabasePort_InsertTransaction_Call {
+func (_c *MockDatabasePort_GetEvents_Call) Return(_a0 []model.OutboxEvent, _a1 error) *MockDatabasePort_GetEvents_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_InsertTransaction_Call) RunAndReturn(run func(context.Context, *model.Transaction) (*model.Transaction, error)) *MockDatabasePort_InsertTransaction_Call {
+func (_c *MockDatabasePort_GetEvents_Call) RunAndReturn(run func(context.Context, int64) ([]model.OutboxEvent, error)) *MockDatabasePort_GetEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// IsAccountExists provides a mock function with given fields: ctx, accountID
-func (_m *MockDatabasePort) IsAccountExists(ctx context.Context, accountID string) (bool, error) {
-	ret := _m.Called(ctx, accountID)
+// GetFirstEventID provides a mock function with given fields: ctx
+func (_m *MockDatabasePort) GetFirstEventID(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsAccountExists")
+		panic("no return value specified for GetFirstEventID")
 	}
 
-	var r0 bool
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return rf(ctx, accountID)
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = rf(ctx, accountID)
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, accountID)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -331,58 +377,55 @@ func (_m *MockDatabasePort) IsAccountExists(ctx context.Context, accountID strin
 	return r0, r1
 }
 
-// MockDatabasePort_IsAccountExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAccountExists'
-type MockDatabasePort_IsAccountExists_Call struct {
+// MockDatabasePort_GetFirstEventID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFirstEventID'
+type MockDatabasePort_GetFirstEventID_Call struct {
 	*mock.Call
 }
 
-// IsAccountExists is a helper method to define mock.On call
+// GetFirstEventID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - accountID string
-func (_e *MockDatabasePort_Expecter) IsAccountExists(ctx interface{}, accountID interface{}) *MockDatabasePort_IsAccountExists_Call {
-	return &MockDatabasePort_IsAccountExists_Call{Call: _e.mock.On("IsAccountExists", ctx, accountID)}
+func (_e *MockDatabasePort_Expecter) GetFirstEventID(ctx interface{}) *MockDatabasePort_GetFirstEventID_Call {
+	return &MockDatabasePort_GetFirstEventID_Call{Call: _e.mock.On("GetFirstEventID", ctx)}
 }
 
-func (_c *MockDatabasePort_IsAccountExists_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_IsAccountExists_Call {
+func (_c *MockDatabasePort_GetFirstEventID_Call) Run(run func(ctx context.Context)) *MockDatabasePort_GetFirstEventID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_IsAccountExists_Call) Return(_a0 bool, _a1 error) *MockDatabasePort_IsAccountExists_Call {
+func (_c *MockDatabasePort_GetFirstEventID_Call) Return(_a0 int64, _a1 error) *MockDatabasePort_GetFirstEventID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_IsAccountExists_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockDatabasePort_IsAccountExists_Call {
+func (_c *MockDatabasePort_GetFirstEventID_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockDatabasePort_GetFirstEventID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListAccounts provides a mock function with given fields: ctx, filter
-func (_m *MockDatabasePort) ListAccounts(ctx context.Context, filter model.ListAccountFilter) ([]model.Account, error) {
-	ret := _m.Called(ctx, filter)
+// GetLastEventID provides a mock function with given fields: ctx
+func (_m *MockDatabasePort) GetLastEventID(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAccounts")
+		panic("no return value specified for GetLastEventID")
 	}
 
-	var r0 []model.Account
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.ListAccountFilter) ([]model.Account, error)); ok {
-		return rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.ListAccountFilter) []model.Account); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Account)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.ListAccountFilter) error); ok {
-		r1 = rf(ctx, filter)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -390,115 +433,2200 @@ func (_m *MockDatabasePort) ListAccounts(ctx context.Context, filter model.ListA
 	return r0, r1
 }
 
-// MockDatabasePort_ListAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccounts'
-type MockDatabasePort_ListAccounts_Call struct {
+// MockDatabasePort_GetLastEventID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastEventID'
+type MockDatabasePort_GetLastEventID_Call struct {
 	*mock.Call
 }
 
-// ListAccounts is a helper method to define mock.On call
+// GetLastEventID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter model.ListAccountFilter
-func (_e *MockDatabasePort_Expecter) ListAccounts(ctx interface{}, filter interface{}) *MockDatabasePort_ListAccounts_Call {
-	return &MockDatabasePort_ListAccounts_Call{Call: _e.mock.On("ListAccounts", ctx, filter)}
+func (_e *MockDatabasePort_Expecter) GetLastEventID(ctx interface{}) *MockDatabasePort_GetLastEventID_Call {
+	return &MockDatabasePort_GetLastEventID_Call{Call: _e.mock.On("GetLastEventID", ctx)}
 }
 
-func (_c *MockDatabasePort_ListAccounts_Call) Run(run func(ctx context.Context, filter model.ListAccountFilter)) *MockDatabasePort_ListAccounts_Call {
+func (_c *MockDatabasePort_GetLastEventID_Call) Run(run func(ctx context.Context)) *MockDatabasePort_GetLastEventID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(model.ListAccountFilter))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_ListAccounts_Call) Return(_a0 []model.Account, _a1 error) *MockDatabasePort_ListAccounts_Call {
+func (_c *MockDatabasePort_GetLastEventID_Call) Return(_a0 int64, _a1 error) *MockDatabasePort_GetLastEventID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_ListAccounts_Call) RunAndReturn(run func(context.Context, model.ListAccountFilter) ([]model.Account, error)) *MockDatabasePort_ListAccounts_Call {
+func (_c *MockDatabasePort_GetLastEventID_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockDatabasePort_GetLastEventID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// MarkEventAsProcessed provides a mock function with given fields: ctx, eventID
-func (_m *MockDatabasePort) MarkEventAsProcessed(ctx context.Context, eventID uint64) error {
-	ret := _m.Called(ctx, eventID)
+// GetLastSweepJob provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockDatabasePort) GetLastSweepJob(ctx context.Context, accountID string, currency model.Currency) (*model.SweepJob, error) {
+	ret := _m.Called(ctx, accountID, currency)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MarkEventAsProcessed")
+		panic("no return value specified for GetLastSweepJob")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
-		r0 = rf(ctx, eventID)
+	var r0 *model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) (*model.SweepJob, error)); ok {
+		return rf(ctx, accountID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) *model.SweepJob); ok {
+		r0 = rf(ctx, accountID, currency)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepJob)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Currency) error); ok {
+		r1 = rf(ctx, accountID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockDatabasePort_MarkEventAsProcessed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEventAsProcessed'
-type MockDatabasePort_MarkEventAsProcessed_Call struct {
+// MockDatabasePort_GetLastSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastSweepJob'
+type MockDatabasePort_GetLastSweepJob_Call struct {
 	*mock.Call
 }
 
-// MarkEventAsProcessed is a helper method to define mock.On call
+// GetLastSweepJob is a helper method to define mock.On call
 //   - ctx context.Context
-//   - eventID uint64
-func (_e *MockDatabasePort_Expecter) MarkEventAsProcessed(ctx interface{}, eventID interface{}) *MockDatabasePort_MarkEventAsProcessed_Call {
-	return &MockDatabasePort_MarkEventAsProcessed_Call{Call: _e.mock.On("MarkEventAsProcessed", ctx, eventID)}
+//   - accountID string
+//   - currency model.Currency
+func (_e *MockDatabasePort_Expecter) GetLastSweepJob(ctx interface{}, accountID interface{}, currency interface{}) *MockDatabasePort_GetLastSweepJob_Call {
+	return &MockDatabasePort_GetLastSweepJob_Call{Call: _e.mock.On("GetLastSweepJob", ctx, accountID, currency)}
 }
 
-func (_c *MockDatabasePort_MarkEventAsProcessed_Call) Run(run func(ctx context.Context, eventID uint64)) *MockDatabasePort_MarkEventAsProcessed_Call {
+func (_c *MockDatabasePort_GetLastSweepJob_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockDatabasePort_GetLastSweepJob_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
 	})
 	return _c
 }
 
-func (_c *MockDatabasePort_MarkEventAsProcessed_Call) Return(_a0 error) *MockDatabasePort_MarkEventAsProcessed_Call {
-	_c.Call.Return(_a0)
+func (_c *MockDatabasePort_GetLastSweepJob_Call) Return(_a0 *model.SweepJob, _a1 error) *MockDatabasePort_GetLastSweepJob_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockDatabasePort_MarkEventAsProcessed_Call) RunAndReturn(run func(context.Context, uint64) error) *MockDatabasePort_MarkEventAsProcessed_Call {
+func (_c *MockDatabasePort_GetLastSweepJob_Call) RunAndReturn(run func(context.Context, string, model.Currency) (*model.SweepJob, error)) *MockDatabasePort_GetLastSweepJob_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveEvent provides a mock function with given fields: ctx, event
-func (_m *MockDatabasePort) SaveEvent(ctx context.Context, event model.OutboxEvent) error {
-	ret := _m.Called(ctx, event)
+// GetLedgerBalances provides a mock function with given fields: ctx, account
+func (_m *MockDatabasePort) GetLedgerBalances(ctx context.Context, account model.LedgerAccount) ([]model.Balance, error) {
+	ret := _m.Called(ctx, account)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveEvent")
+		panic("no return value specified for GetLedgerBalances")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.OutboxEvent) error); ok {
-		r0 = rf(ctx, event)
+	var r0 []model.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LedgerAccount) ([]model.Balance, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.LedgerAccount) []model.Balance); ok {
+		r0 = rf(ctx, account)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Balance)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, model.LedgerAccount) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// MockDatabasePort_SaveEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEvent'
-type MockDatabasePort_SaveEvent_Call struct {
+// MockDatabasePort_GetLedgerBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLedgerBalances'
+type MockDatabasePort_GetLedgerBalances_Call struct {
 	*mock.Call
 }
 
-// SaveEvent is a helper method to define mock.On call
+// GetLedgerBalances is a helper method to define mock.On call
 //   - ctx context.Context
-//   - event model.OutboxEvent
-func (_e *MockDatabasePort_Expecter) SaveEvent(ctx interface{}, event interface{}) *MockDatabasePort_SaveEvent_Call {
-	return &MockDatabasePort_SaveEvent_Call{Call: _e.mock.On("SaveEvent", ctx, event)}
+//   - account model.LedgerAccount
+func (_e *MockDatabasePort_Expecter) GetLedgerBalances(ctx interface{}, account interface{}) *MockDatabasePort_GetLedgerBalances_Call {
+	return &MockDatabasePort_GetLedgerBalances_Call{Call: _e.mock.On("GetLedgerBalances", ctx, account)}
 }
 
-func (_c *MockDatabasePort_SaveEvent_Call) Run(run func(ctx context.Context, event model.OutboxEvent)) *MockDatabasePort_SaveEvent_Call {
+func (_c *MockDatabasePort_GetLedgerBalances_Call) Run(run func(ctx context.Context, account model.LedgerAccount)) *MockDatabasePort_GetLedgerBalances_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(model.OutboxEvent))
+		run(args[0].(context.Context), args[1].(model.LedgerAccount))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetLedgerBalances_Call) Return(_a0 []model.Balance, _a1 error) *MockDatabasePort_GetLedgerBalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetLedgerBalances_Call) RunAndReturn(run func(context.Context, model.LedgerAccount) ([]model.Balance, error)) *MockDatabasePort_GetLedgerBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOutgoingTransferByJettonQueryID provides a mock function with given fields: ctx, queryID
+func (_m *MockDatabasePort) GetOutgoingTransferByJettonQueryID(ctx context.Context, queryID uint64) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, queryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingTransferByJettonQueryID")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, queryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, queryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, queryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOutgoingTransferByJettonQueryID'
+type MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call struct {
+	*mock.Call
+}
+
+// GetOutgoingTransferByJettonQueryID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queryID uint64
+func (_e *MockDatabasePort_Expecter) GetOutgoingTransferByJettonQueryID(ctx interface{}, queryID interface{}) *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	return &MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call{Call: _e.mock.On("GetOutgoingTransferByJettonQueryID", ctx, queryID)}
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call) Run(run func(ctx context.Context, queryID uint64)) *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call) RunAndReturn(run func(context.Context, uint64) (*model.OutgoingTransfer, error)) *MockDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOutgoingTransferByMessageHash provides a mock function with given fields: ctx, messageHash
+func (_m *MockDatabasePort) GetOutgoingTransferByMessageHash(ctx context.Context, messageHash string) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, messageHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingTransferByMessageHash")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, messageHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, messageHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetOutgoingTransferByMessageHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOutgoingTransferByMessageHash'
+type MockDatabasePort_GetOutgoingTransferByMessageHash_Call struct {
+	*mock.Call
+}
+
+// GetOutgoingTransferByMessageHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageHash string
+func (_e *MockDatabasePort_Expecter) GetOutgoingTransferByMessageHash(ctx interface{}, messageHash interface{}) *MockDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	return &MockDatabasePort_GetOutgoingTransferByMessageHash_Call{Call: _e.mock.On("GetOutgoingTransferByMessageHash", ctx, messageHash)}
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByMessageHash_Call) Run(run func(ctx context.Context, messageHash string)) *MockDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByMessageHash_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetOutgoingTransferByMessageHash_Call) RunAndReturn(run func(context.Context, string) (*model.OutgoingTransfer, error)) *MockDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPendingWithdrawalAmount provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockDatabasePort) GetPendingWithdrawalAmount(ctx context.Context, accountID string, currency model.Currency) (model.Amount, error) {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingWithdrawalAmount")
+	}
+
+	var r0 model.Amount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) (model.Amount, error)); ok {
+		return rf(ctx, accountID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) model.Amount); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		r0 = ret.Get(0).(model.Amount)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Currency) error); ok {
+		r1 = rf(ctx, accountID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetPendingWithdrawalAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingWithdrawalAmount'
+type MockDatabasePort_GetPendingWithdrawalAmount_Call struct {
+	*mock.Call
+}
+
+// GetPendingWithdrawalAmount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - currency model.Currency
+func (_e *MockDatabasePort_Expecter) GetPendingWithdrawalAmount(ctx interface{}, accountID interface{}, currency interface{}) *MockDatabasePort_GetPendingWithdrawalAmount_Call {
+	return &MockDatabasePort_GetPendingWithdrawalAmount_Call{Call: _e.mock.On("GetPendingWithdrawalAmount", ctx, accountID, currency)}
+}
+
+func (_c *MockDatabasePort_GetPendingWithdrawalAmount_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetPendingWithdrawalAmount_Call) Return(_a0 model.Amount, _a1 error) *MockDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetPendingWithdrawalAmount_Call) RunAndReturn(run func(context.Context, string, model.Currency) (model.Amount, error)) *MockDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactionByHash provides a mock function with given fields: ctx, hash
+func (_m *MockDatabasePort) GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByHash")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Transaction, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Transaction); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetTransactionByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByHash'
+type MockDatabasePort_GetTransactionByHash_Call struct {
+	*mock.Call
+}
+
+// GetTransactionByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockDatabasePort_Expecter) GetTransactionByHash(ctx interface{}, hash interface{}) *MockDatabasePort_GetTransactionByHash_Call {
+	return &MockDatabasePort_GetTransactionByHash_Call{Call: _e.mock.On("GetTransactionByHash", ctx, hash)}
+}
+
+func (_c *MockDatabasePort_GetTransactionByHash_Call) Run(run func(ctx context.Context, hash string)) *MockDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetTransactionByHash_Call) Return(_a0 *model.Transaction, _a1 error) *MockDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetTransactionByHash_Call) RunAndReturn(run func(context.Context, string) (*model.Transaction, error)) *MockDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactionByLT provides a mock function with given fields: ctx, accountID, lt
+func (_m *MockDatabasePort) GetTransactionByLT(ctx context.Context, accountID string, lt int64) (*model.Transaction, error) {
+	ret := _m.Called(ctx, accountID, lt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByLT")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*model.Transaction, error)); ok {
+		return rf(ctx, accountID, lt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *model.Transaction); ok {
+		r0 = rf(ctx, accountID, lt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, accountID, lt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetTransactionByLT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByLT'
+type MockDatabasePort_GetTransactionByLT_Call struct {
+	*mock.Call
+}
+
+// GetTransactionByLT is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - lt int64
+func (_e *MockDatabasePort_Expecter) GetTransactionByLT(ctx interface{}, accountID interface{}, lt interface{}) *MockDatabasePort_GetTransactionByLT_Call {
+	return &MockDatabasePort_GetTransactionByLT_Call{Call: _e.mock.On("GetTransactionByLT", ctx, accountID, lt)}
+}
+
+func (_c *MockDatabasePort_GetTransactionByLT_Call) Run(run func(ctx context.Context, accountID string, lt int64)) *MockDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetTransactionByLT_Call) Return(_a0 *model.Transaction, _a1 error) *MockDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetTransactionByLT_Call) RunAndReturn(run func(context.Context, string, int64) (*model.Transaction, error)) *MockDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWalletIDByAccountID provides a mock function with given fields: ctx, accountID
+func (_m *MockDatabasePort) GetWalletIDByAccountID(ctx context.Context, accountID string) (uint32, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletIDByAccountID")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uint32, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uint32); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetWalletIDByAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWalletIDByAccountID'
+type MockDatabasePort_GetWalletIDByAccountID_Call struct {
+	*mock.Call
+}
+
+// GetWalletIDByAccountID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+func (_e *MockDatabasePort_Expecter) GetWalletIDByAccountID(ctx interface{}, accountID interface{}) *MockDatabasePort_GetWalletIDByAccountID_Call {
+	return &MockDatabasePort_GetWalletIDByAccountID_Call{Call: _e.mock.On("GetWalletIDByAccountID", ctx, accountID)}
+}
+
+func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_GetWalletIDByAccountID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) Return(_a0 uint32, _a1 error) *MockDatabasePort_GetWalletIDByAccountID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWalletIDByAccountID_Call) RunAndReturn(run func(context.Context, string) (uint32, error)) *MockDatabasePort_GetWalletIDByAccountID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawal provides a mock function with given fields: ctx, id
+func (_m *MockDatabasePort) GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawal'
+type MockDatabasePort_GetWithdrawal_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockDatabasePort_Expecter) GetWithdrawal(ctx interface{}, id interface{}) *MockDatabasePort_GetWithdrawal_Call {
+	return &MockDatabasePort_GetWithdrawal_Call{Call: _e.mock.On("GetWithdrawal", ctx, id)}
+}
+
+func (_c *MockDatabasePort_GetWithdrawal_Call) Run(run func(ctx context.Context, id int64)) *MockDatabasePort_GetWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockDatabasePort_GetWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawal_Call) RunAndReturn(run func(context.Context, int64) (*model.Withdrawal, error)) *MockDatabasePort_GetWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalByIdempotencyKey provides a mock function with given fields: ctx, accountID, key
+func (_m *MockDatabasePort) GetWithdrawalByIdempotencyKey(ctx context.Context, accountID string, key string) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, accountID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalByIdempotencyKey")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Withdrawal, error)); ok {
+		return rf(ctx, accountID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Withdrawal); ok {
+		r0 = rf(ctx, accountID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accountID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetWithdrawalByIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalByIdempotencyKey'
+type MockDatabasePort_GetWithdrawalByIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalByIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - key string
+func (_e *MockDatabasePort_Expecter) GetWithdrawalByIdempotencyKey(ctx interface{}, accountID interface{}, key interface{}) *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	return &MockDatabasePort_GetWithdrawalByIdempotencyKey_Call{Call: _e.mock.On("GetWithdrawalByIdempotencyKey", ctx, accountID, key)}
+}
+
+func (_c *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call) Run(run func(ctx context.Context, accountID string, key string)) *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call) RunAndReturn(run func(context.Context, string, string) (*model.Withdrawal, error)) *MockDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalForUpdate provides a mock function with given fields: ctx, id
+func (_m *MockDatabasePort) GetWithdrawalForUpdate(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalForUpdate")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_GetWithdrawalForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalForUpdate'
+type MockDatabasePort_GetWithdrawalForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockDatabasePort_Expecter) GetWithdrawalForUpdate(ctx interface{}, id interface{}) *MockDatabasePort_GetWithdrawalForUpdate_Call {
+	return &MockDatabasePort_GetWithdrawalForUpdate_Call{Call: _e.mock.On("GetWithdrawalForUpdate", ctx, id)}
+}
+
+func (_c *MockDatabasePort_GetWithdrawalForUpdate_Call) Run(run func(ctx context.Context, id int64)) *MockDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawalForUpdate_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_GetWithdrawalForUpdate_Call) RunAndReturn(run func(context.Context, int64) (*model.Withdrawal, error)) *MockDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertAccount provides a mock function with given fields: ctx, accountID
+func (_m *MockDatabasePort) InsertAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertAccount")
+	}
+
+	var r0 *model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Account, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Account); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertAccount'
+type MockDatabasePort_InsertAccount_Call struct {
+	*mock.Call
+}
+
+// InsertAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+func (_e *MockDatabasePort_Expecter) InsertAccount(ctx interface{}, accountID interface{}) *MockDatabasePort_InsertAccount_Call {
+	return &MockDatabasePort_InsertAccount_Call{Call: _e.mock.On("InsertAccount", ctx, accountID)}
+}
+
+func (_c *MockDatabasePort_InsertAccount_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_InsertAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertAccount_Call) Return(_a0 *model.Account, _a1 error) *MockDatabasePort_InsertAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertAccount_Call) RunAndReturn(run func(context.Context, string) (*model.Account, error)) *MockDatabasePort_InsertAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertDeposit provides a mock function with given fields: ctx, deposit
+func (_m *MockDatabasePort) InsertDeposit(ctx context.Context, deposit *model.Deposit) (*model.Deposit, error) {
+	ret := _m.Called(ctx, deposit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertDeposit")
+	}
+
+	var r0 *model.Deposit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit) (*model.Deposit, error)); ok {
+		return rf(ctx, deposit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit) *model.Deposit); ok {
+		r0 = rf(ctx, deposit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Deposit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Deposit) error); ok {
+		r1 = rf(ctx, deposit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertDeposit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertDeposit'
+type MockDatabasePort_InsertDeposit_Call struct {
+	*mock.Call
+}
+
+// InsertDeposit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+func (_e *MockDatabasePort_Expecter) InsertDeposit(ctx interface{}, deposit interface{}) *MockDatabasePort_InsertDeposit_Call {
+	return &MockDatabasePort_InsertDeposit_Call{Call: _e.mock.On("InsertDeposit", ctx, deposit)}
+}
+
+func (_c *MockDatabasePort_InsertDeposit_Call) Run(run func(ctx context.Context, deposit *model.Deposit)) *MockDatabasePort_InsertDeposit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertDeposit_Call) Return(_a0 *model.Deposit, _a1 error) *MockDatabasePort_InsertDeposit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertDeposit_Call) RunAndReturn(run func(context.Context, *model.Deposit) (*model.Deposit, error)) *MockDatabasePort_InsertDeposit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertLedgerEntry provides a mock function with given fields: ctx, entry
+func (_m *MockDatabasePort) InsertLedgerEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertLedgerEntry")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.LedgerEntry) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, entry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.LedgerEntry) *model.LedgerEntry); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.LedgerEntry) error); ok {
+		r1 = rf(ctx, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertLedgerEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertLedgerEntry'
+type MockDatabasePort_InsertLedgerEntry_Call struct {
+	*mock.Call
+}
+
+// InsertLedgerEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry *model.LedgerEntry
+func (_e *MockDatabasePort_Expecter) InsertLedgerEntry(ctx interface{}, entry interface{}) *MockDatabasePort_InsertLedgerEntry_Call {
+	return &MockDatabasePort_InsertLedgerEntry_Call{Call: _e.mock.On("InsertLedgerEntry", ctx, entry)}
+}
+
+func (_c *MockDatabasePort_InsertLedgerEntry_Call) Run(run func(ctx context.Context, entry *model.LedgerEntry)) *MockDatabasePort_InsertLedgerEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.LedgerEntry))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertLedgerEntry_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockDatabasePort_InsertLedgerEntry_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertLedgerEntry_Call) RunAndReturn(run func(context.Context, *model.LedgerEntry) (*model.LedgerEntry, error)) *MockDatabasePort_InsertLedgerEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertOutgoingTransfer provides a mock function with given fields: ctx, transfer
+func (_m *MockDatabasePort) InsertOutgoingTransfer(ctx context.Context, transfer *model.OutgoingTransfer) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOutgoingTransfer")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.OutgoingTransfer) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertOutgoingTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertOutgoingTransfer'
+type MockDatabasePort_InsertOutgoingTransfer_Call struct {
+	*mock.Call
+}
+
+// InsertOutgoingTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *model.OutgoingTransfer
+func (_e *MockDatabasePort_Expecter) InsertOutgoingTransfer(ctx interface{}, transfer interface{}) *MockDatabasePort_InsertOutgoingTransfer_Call {
+	return &MockDatabasePort_InsertOutgoingTransfer_Call{Call: _e.mock.On("InsertOutgoingTransfer", ctx, transfer)}
+}
+
+func (_c *MockDatabasePort_InsertOutgoingTransfer_Call) Run(run func(ctx context.Context, transfer *model.OutgoingTransfer)) *MockDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.OutgoingTransfer))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertOutgoingTransfer_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertOutgoingTransfer_Call) RunAndReturn(run func(context.Context, *model.OutgoingTransfer) (*model.OutgoingTransfer, error)) *MockDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertRebalance provides a mock function with given fields: ctx, rebalance
+func (_m *MockDatabasePort) InsertRebalance(ctx context.Context, rebalance *model.Rebalance) (*model.Rebalance, error) {
+	ret := _m.Called(ctx, rebalance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRebalance")
+	}
+
+	var r0 *model.Rebalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance) (*model.Rebalance, error)); ok {
+		return rf(ctx, rebalance)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance) *model.Rebalance); ok {
+		r0 = rf(ctx, rebalance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Rebalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Rebalance) error); ok {
+		r1 = rf(ctx, rebalance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRebalance'
+type MockDatabasePort_InsertRebalance_Call struct {
+	*mock.Call
+}
+
+// InsertRebalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rebalance *model.Rebalance
+func (_e *MockDatabasePort_Expecter) InsertRebalance(ctx interface{}, rebalance interface{}) *MockDatabasePort_InsertRebalance_Call {
+	return &MockDatabasePort_InsertRebalance_Call{Call: _e.mock.On("InsertRebalance", ctx, rebalance)}
+}
+
+func (_c *MockDatabasePort_InsertRebalance_Call) Run(run func(ctx context.Context, rebalance *model.Rebalance)) *MockDatabasePort_InsertRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Rebalance))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertRebalance_Call) Return(_a0 *model.Rebalance, _a1 error) *MockDatabasePort_InsertRebalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertRebalance_Call) RunAndReturn(run func(context.Context, *model.Rebalance) (*model.Rebalance, error)) *MockDatabasePort_InsertRebalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertSweepJob provides a mock function with given fields: ctx, job
+func (_m *MockDatabasePort) InsertSweepJob(ctx context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertSweepJob")
+	}
+
+	var r0 *model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob) (*model.SweepJob, error)); ok {
+		return rf(ctx, job)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob) *model.SweepJob); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SweepJob) error); ok {
+		r1 = rf(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertSweepJob'
+type MockDatabasePort_InsertSweepJob_Call struct {
+	*mock.Call
+}
+
+// InsertSweepJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *model.SweepJob
+func (_e *MockDatabasePort_Expecter) InsertSweepJob(ctx interface{}, job interface{}) *MockDatabasePort_InsertSweepJob_Call {
+	return &MockDatabasePort_InsertSweepJob_Call{Call: _e.mock.On("InsertSweepJob", ctx, job)}
+}
+
+func (_c *MockDatabasePort_InsertSweepJob_Call) Run(run func(ctx context.Context, job *model.SweepJob)) *MockDatabasePort_InsertSweepJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepJob))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertSweepJob_Call) Return(_a0 *model.SweepJob, _a1 error) *MockDatabasePort_InsertSweepJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertSweepJob_Call) RunAndReturn(run func(context.Context, *model.SweepJob) (*model.SweepJob, error)) *MockDatabasePort_InsertSweepJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertTransaction provides a mock function with given fields: ctx, tx
+func (_m *MockDatabasePort) InsertTransaction(ctx context.Context, tx *model.Transaction) (*model.Transaction, error) {
+	ret := _m.Called(ctx, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertTransaction")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Transaction) (*model.Transaction, error)); ok {
+		return rf(ctx, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Transaction) *model.Transaction); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Transaction) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertTransaction'
+type MockDatabasePort_InsertTransaction_Call struct {
+	*mock.Call
+}
+
+// InsertTransaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tx *model.Transaction
+func (_e *MockDatabasePort_Expecter) InsertTransaction(ctx interface{}, tx interface{}) *MockDatabasePort_InsertTransaction_Call {
+	return &MockDatabasePort_InsertTransaction_Call{Call: _e.mock.On("InsertTransaction", ctx, tx)}
+}
+
+func (_c *MockDatabasePort_InsertTransaction_Call) Run(run func(ctx context.Context, tx *model.Transaction)) *MockDatabasePort_InsertTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Transaction))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertTransaction_Call) Return(_a0 *model.Transaction, _a1 error) *MockDatabasePort_InsertTransaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertTransaction_Call) RunAndReturn(run func(context.Context, *model.Transaction) (*model.Transaction, error)) *MockDatabasePort_InsertTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertTransactions provides a mock function with given fields: ctx, txs
+func (_m *MockDatabasePort) InsertTransactions(ctx context.Context, txs []*model.Transaction) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, txs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Transaction) ([]*model.Transaction, error)); ok {
+		return rf(ctx, txs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Transaction) []*model.Transaction); ok {
+		r0 = rf(ctx, txs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Transaction) error); ok {
+		r1 = rf(ctx, txs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertTransactions'
+type MockDatabasePort_InsertTransactions_Call struct {
+	*mock.Call
+}
+
+// InsertTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - txs []*model.Transaction
+func (_e *MockDatabasePort_Expecter) InsertTransactions(ctx interface{}, txs interface{}) *MockDatabasePort_InsertTransactions_Call {
+	return &MockDatabasePort_InsertTransactions_Call{Call: _e.mock.On("InsertTransactions", ctx, txs)}
+}
+
+func (_c *MockDatabasePort_InsertTransactions_Call) Run(run func(ctx context.Context, txs []*model.Transaction)) *MockDatabasePort_InsertTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.Transaction))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertTransactions_Call) Return(_a0 []*model.Transaction, _a1 error) *MockDatabasePort_InsertTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertTransactions_Call) RunAndReturn(run func(context.Context, []*model.Transaction) ([]*model.Transaction, error)) *MockDatabasePort_InsertTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertWithdrawal provides a mock function with given fields: ctx, withdrawal
+func (_m *MockDatabasePort) InsertWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, withdrawal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)); ok {
+		return rf(ctx, withdrawal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) *model.Withdrawal); ok {
+		r0 = rf(ctx, withdrawal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Withdrawal) error); ok {
+		r1 = rf(ctx, withdrawal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertWithdrawal'
+type MockDatabasePort_InsertWithdrawal_Call struct {
+	*mock.Call
+}
+
+// InsertWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawal *model.Withdrawal
+func (_e *MockDatabasePort_Expecter) InsertWithdrawal(ctx interface{}, withdrawal interface{}) *MockDatabasePort_InsertWithdrawal_Call {
+	return &MockDatabasePort_InsertWithdrawal_Call{Call: _e.mock.On("InsertWithdrawal", ctx, withdrawal)}
+}
+
+func (_c *MockDatabasePort_InsertWithdrawal_Call) Run(run func(ctx context.Context, withdrawal *model.Withdrawal)) *MockDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Withdrawal))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertWithdrawal_Call) RunAndReturn(run func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)) *MockDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertWithdrawalApproval provides a mock function with given fields: ctx, approval
+func (_m *MockDatabasePort) InsertWithdrawalApproval(ctx context.Context, approval *model.WithdrawalApproval) (*model.WithdrawalApproval, error) {
+	ret := _m.Called(ctx, approval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertWithdrawalApproval")
+	}
+
+	var r0 *model.WithdrawalApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) (*model.WithdrawalApproval, error)); ok {
+		return rf(ctx, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) *model.WithdrawalApproval); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WithdrawalApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WithdrawalApproval) error); ok {
+		r1 = rf(ctx, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_InsertWithdrawalApproval_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertWithdrawalApproval'
+type MockDatabasePort_InsertWithdrawalApproval_Call struct {
+	*mock.Call
+}
+
+// InsertWithdrawalApproval is a helper method to define mock.On call
+//   - ctx context.Context
+//   - approval *model.WithdrawalApproval
+func (_e *MockDatabasePort_Expecter) InsertWithdrawalApproval(ctx interface{}, approval interface{}) *MockDatabasePort_InsertWithdrawalApproval_Call {
+	return &MockDatabasePort_InsertWithdrawalApproval_Call{Call: _e.mock.On("InsertWithdrawalApproval", ctx, approval)}
+}
+
+func (_c *MockDatabasePort_InsertWithdrawalApproval_Call) Run(run func(ctx context.Context, approval *model.WithdrawalApproval)) *MockDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WithdrawalApproval))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertWithdrawalApproval_Call) Return(_a0 *model.WithdrawalApproval, _a1 error) *MockDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_InsertWithdrawalApproval_Call) RunAndReturn(run func(context.Context, *model.WithdrawalApproval) (*model.WithdrawalApproval, error)) *MockDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsAccountExists provides a mock function with given fields: ctx, accountID
+func (_m *MockDatabasePort) IsAccountExists(ctx context.Context, accountID string) (bool, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAccountExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_IsAccountExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAccountExists'
+type MockDatabasePort_IsAccountExists_Call struct {
+	*mock.Call
+}
+
+// IsAccountExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+func (_e *MockDatabasePort_Expecter) IsAccountExists(ctx interface{}, accountID interface{}) *MockDatabasePort_IsAccountExists_Call {
+	return &MockDatabasePort_IsAccountExists_Call{Call: _e.mock.On("IsAccountExists", ctx, accountID)}
+}
+
+func (_c *MockDatabasePort_IsAccountExists_Call) Run(run func(ctx context.Context, accountID string)) *MockDatabasePort_IsAccountExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_IsAccountExists_Call) Return(_a0 bool, _a1 error) *MockDatabasePort_IsAccountExists_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_IsAccountExists_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockDatabasePort_IsAccountExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAccounts provides a mock function with given fields: ctx, filter
+func (_m *MockDatabasePort) ListAccounts(ctx context.Context, filter model.ListAccountFilter) ([]model.Account, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccounts")
+	}
+
+	var r0 []model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListAccountFilter) ([]model.Account, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListAccountFilter) []model.Account); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListAccountFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccounts'
+type MockDatabasePort_ListAccounts_Call struct {
+	*mock.Call
+}
+
+// ListAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListAccountFilter
+func (_e *MockDatabasePort_Expecter) ListAccounts(ctx interface{}, filter interface{}) *MockDatabasePort_ListAccounts_Call {
+	return &MockDatabasePort_ListAccounts_Call{Call: _e.mock.On("ListAccounts", ctx, filter)}
+}
+
+func (_c *MockDatabasePort_ListAccounts_Call) Run(run func(ctx context.Context, filter model.ListAccountFilter)) *MockDatabasePort_ListAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListAccountFilter))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListAccounts_Call) Return(_a0 []model.Account, _a1 error) *MockDatabasePort_ListAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListAccounts_Call) RunAndReturn(run func(context.Context, model.ListAccountFilter) ([]model.Account, error)) *MockDatabasePort_ListAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveRebalances provides a mock function with given fields: ctx
+func (_m *MockDatabasePort) ListActiveRebalances(ctx context.Context) ([]*model.Rebalance, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveRebalances")
+	}
+
+	var r0 []*model.Rebalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Rebalance, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Rebalance); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Rebalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListActiveRebalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveRebalances'
+type MockDatabasePort_ListActiveRebalances_Call struct {
+	*mock.Call
+}
+
+// ListActiveRebalances is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockDatabasePort_Expecter) ListActiveRebalances(ctx interface{}) *MockDatabasePort_ListActiveRebalances_Call {
+	return &MockDatabasePort_ListActiveRebalances_Call{Call: _e.mock.On("ListActiveRebalances", ctx)}
+}
+
+func (_c *MockDatabasePort_ListActiveRebalances_Call) Run(run func(ctx context.Context)) *MockDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveRebalances_Call) Return(_a0 []*model.Rebalance, _a1 error) *MockDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveRebalances_Call) RunAndReturn(run func(context.Context) ([]*model.Rebalance, error)) *MockDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveSweepJobs provides a mock function with given fields: ctx, afterID, limit
+func (_m *MockDatabasePort) ListActiveSweepJobs(ctx context.Context, afterID int64, limit int) ([]*model.SweepJob, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveSweepJobs")
+	}
+
+	var r0 []*model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*model.SweepJob, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*model.SweepJob); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SweepJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListActiveSweepJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveSweepJobs'
+type MockDatabasePort_ListActiveSweepJobs_Call struct {
+	*mock.Call
+}
+
+// ListActiveSweepJobs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *MockDatabasePort_Expecter) ListActiveSweepJobs(ctx interface{}, afterID interface{}, limit interface{}) *MockDatabasePort_ListActiveSweepJobs_Call {
+	return &MockDatabasePort_ListActiveSweepJobs_Call{Call: _e.mock.On("ListActiveSweepJobs", ctx, afterID, limit)}
+}
+
+func (_c *MockDatabasePort_ListActiveSweepJobs_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *MockDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveSweepJobs_Call) Return(_a0 []*model.SweepJob, _a1 error) *MockDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveSweepJobs_Call) RunAndReturn(run func(context.Context, int64, int) ([]*model.SweepJob, error)) *MockDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveWithdrawals provides a mock function with given fields: ctx, afterID, limit
+func (_m *MockDatabasePort) ListActiveWithdrawals(ctx context.Context, afterID int64, limit int) ([]*model.Withdrawal, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveWithdrawals")
+	}
+
+	var r0 []*model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*model.Withdrawal, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*model.Withdrawal); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListActiveWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveWithdrawals'
+type MockDatabasePort_ListActiveWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListActiveWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *MockDatabasePort_Expecter) ListActiveWithdrawals(ctx interface{}, afterID interface{}, limit interface{}) *MockDatabasePort_ListActiveWithdrawals_Call {
+	return &MockDatabasePort_ListActiveWithdrawals_Call{Call: _e.mock.On("ListActiveWithdrawals", ctx, afterID, limit)}
+}
+
+func (_c *MockDatabasePort_ListActiveWithdrawals_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *MockDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveWithdrawals_Call) Return(_a0 []*model.Withdrawal, _a1 error) *MockDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListActiveWithdrawals_Call) RunAndReturn(run func(context.Context, int64, int) ([]*model.Withdrawal, error)) *MockDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListEventsAfter provides a mock function with given fields: ctx, afterID, limit
+func (_m *MockDatabasePort) ListEventsAfter(ctx context.Context, afterID int64, limit int) ([]model.OutboxEvent, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEventsAfter")
+	}
+
+	var r0 []model.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]model.OutboxEvent, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []model.OutboxEvent); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListEventsAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEventsAfter'
+type MockDatabasePort_ListEventsAfter_Call struct {
+	*mock.Call
+}
+
+// ListEventsAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *MockDatabasePort_Expecter) ListEventsAfter(ctx interface{}, afterID interface{}, limit interface{}) *MockDatabasePort_ListEventsAfter_Call {
+	return &MockDatabasePort_ListEventsAfter_Call{Call: _e.mock.On("ListEventsAfter", ctx, afterID, limit)}
+}
+
+func (_c *MockDatabasePort_ListEventsAfter_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *MockDatabasePort_ListEventsAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListEventsAfter_Call) Return(_a0 []model.OutboxEvent, _a1 error) *MockDatabasePort_ListEventsAfter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListEventsAfter_Call) RunAndReturn(run func(context.Context, int64, int) ([]model.OutboxEvent, error)) *MockDatabasePort_ListEventsAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOutgoingTransfersByMessageHash provides a mock function with given fields: ctx, messageHash
+func (_m *MockDatabasePort) ListOutgoingTransfersByMessageHash(ctx context.Context, messageHash string) ([]*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, messageHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOutgoingTransfersByMessageHash")
+	}
+
+	var r0 []*model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, messageHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.OutgoingTransfer); ok {
+		r0 = rf(ctx, messageHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListOutgoingTransfersByMessageHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOutgoingTransfersByMessageHash'
+type MockDatabasePort_ListOutgoingTransfersByMessageHash_Call struct {
+	*mock.Call
+}
+
+// ListOutgoingTransfersByMessageHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageHash string
+func (_e *MockDatabasePort_Expecter) ListOutgoingTransfersByMessageHash(ctx interface{}, messageHash interface{}) *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	return &MockDatabasePort_ListOutgoingTransfersByMessageHash_Call{Call: _e.mock.On("ListOutgoingTransfersByMessageHash", ctx, messageHash)}
+}
+
+func (_c *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call) Run(run func(ctx context.Context, messageHash string)) *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call) Return(_a0 []*model.OutgoingTransfer, _a1 error) *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call) RunAndReturn(run func(context.Context, string) ([]*model.OutgoingTransfer, error)) *MockDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSweepPolicies provides a mock function with given fields: ctx, accountID
+func (_m *MockDatabasePort) ListSweepPolicies(ctx context.Context, accountID *string) ([]*model.SweepPolicy, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSweepPolicies")
+	}
+
+	var r0 []*model.SweepPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) ([]*model.SweepPolicy, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) []*model.SweepPolicy); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SweepPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListSweepPolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSweepPolicies'
+type MockDatabasePort_ListSweepPolicies_Call struct {
+	*mock.Call
+}
+
+// ListSweepPolicies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID *string
+func (_e *MockDatabasePort_Expecter) ListSweepPolicies(ctx interface{}, accountID interface{}) *MockDatabasePort_ListSweepPolicies_Call {
+	return &MockDatabasePort_ListSweepPolicies_Call{Call: _e.mock.On("ListSweepPolicies", ctx, accountID)}
+}
+
+func (_c *MockDatabasePort_ListSweepPolicies_Call) Run(run func(ctx context.Context, accountID *string)) *MockDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*string))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListSweepPolicies_Call) Return(_a0 []*model.SweepPolicy, _a1 error) *MockDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListSweepPolicies_Call) RunAndReturn(run func(context.Context, *string) ([]*model.SweepPolicy, error)) *MockDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTransactions provides a mock function with given fields: ctx, filter
+func (_m *MockDatabasePort) ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) ([]*model.Transaction, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) []*model.Transaction); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListTransactionsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type MockDatabasePort_ListTransactions_Call struct {
+	*mock.Call
+}
+
+// ListTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListTransactionsFilter
+func (_e *MockDatabasePort_Expecter) ListTransactions(ctx interface{}, filter interface{}) *MockDatabasePort_ListTransactions_Call {
+	return &MockDatabasePort_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, filter)}
+}
+
+func (_c *MockDatabasePort_ListTransactions_Call) Run(run func(ctx context.Context, filter model.ListTransactionsFilter)) *MockDatabasePort_ListTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListTransactionsFilter))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListTransactions_Call) Return(_a0 []*model.Transaction, _a1 error) *MockDatabasePort_ListTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListTransactions_Call) RunAndReturn(run func(context.Context, model.ListTransactionsFilter) ([]*model.Transaction, error)) *MockDatabasePort_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawalApprovals provides a mock function with given fields: ctx, withdrawalID
+func (_m *MockDatabasePort) ListWithdrawalApprovals(ctx context.Context, withdrawalID int64) ([]*model.WithdrawalApproval, error) {
+	ret := _m.Called(ctx, withdrawalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawalApprovals")
+	}
+
+	var r0 []*model.WithdrawalApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*model.WithdrawalApproval, error)); ok {
+		return rf(ctx, withdrawalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*model.WithdrawalApproval); ok {
+		r0 = rf(ctx, withdrawalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WithdrawalApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, withdrawalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListWithdrawalApprovals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawalApprovals'
+type MockDatabasePort_ListWithdrawalApprovals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawalApprovals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawalID int64
+func (_e *MockDatabasePort_Expecter) ListWithdrawalApprovals(ctx interface{}, withdrawalID interface{}) *MockDatabasePort_ListWithdrawalApprovals_Call {
+	return &MockDatabasePort_ListWithdrawalApprovals_Call{Call: _e.mock.On("ListWithdrawalApprovals", ctx, withdrawalID)}
+}
+
+func (_c *MockDatabasePort_ListWithdrawalApprovals_Call) Run(run func(ctx context.Context, withdrawalID int64)) *MockDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListWithdrawalApprovals_Call) Return(_a0 []*model.WithdrawalApproval, _a1 error) *MockDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListWithdrawalApprovals_Call) RunAndReturn(run func(context.Context, int64) ([]*model.WithdrawalApproval, error)) *MockDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawals provides a mock function with given fields: ctx, filter
+func (_m *MockDatabasePort) ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) ([]*model.Withdrawal, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawals")
+	}
+
+	var r0 []*model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) ([]*model.Withdrawal, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) []*model.Withdrawal); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListWithdrawalsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_ListWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawals'
+type MockDatabasePort_ListWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListWithdrawalsFilter
+func (_e *MockDatabasePort_Expecter) ListWithdrawals(ctx interface{}, filter interface{}) *MockDatabasePort_ListWithdrawals_Call {
+	return &MockDatabasePort_ListWithdrawals_Call{Call: _e.mock.On("ListWithdrawals", ctx, filter)}
+}
+
+func (_c *MockDatabasePort_ListWithdrawals_Call) Run(run func(ctx context.Context, filter model.ListWithdrawalsFilter)) *MockDatabasePort_ListWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListWithdrawalsFilter))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ListWithdrawals_Call) Return(_a0 []*model.Withdrawal, _a1 error) *MockDatabasePort_ListWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_ListWithdrawals_Call) RunAndReturn(run func(context.Context, model.ListWithdrawalsFilter) ([]*model.Withdrawal, error)) *MockDatabasePort_ListWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockLedgerAccount provides a mock function with given fields: ctx, account
+func (_m *MockDatabasePort) LockLedgerAccount(ctx context.Context, account model.LedgerAccount) error {
+	ret := _m.Called(ctx, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockLedgerAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LedgerAccount) error); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_LockLedgerAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockLedgerAccount'
+type MockDatabasePort_LockLedgerAccount_Call struct {
+	*mock.Call
+}
+
+// LockLedgerAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - account model.LedgerAccount
+func (_e *MockDatabasePort_Expecter) LockLedgerAccount(ctx interface{}, account interface{}) *MockDatabasePort_LockLedgerAccount_Call {
+	return &MockDatabasePort_LockLedgerAccount_Call{Call: _e.mock.On("LockLedgerAccount", ctx, account)}
+}
+
+func (_c *MockDatabasePort_LockLedgerAccount_Call) Run(run func(ctx context.Context, account model.LedgerAccount)) *MockDatabasePort_LockLedgerAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.LedgerAccount))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_LockLedgerAccount_Call) Return(_a0 error) *MockDatabasePort_LockLedgerAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_LockLedgerAccount_Call) RunAndReturn(run func(context.Context, model.LedgerAccount) error) *MockDatabasePort_LockLedgerAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEventAsProcessed provides a mock function with given fields: ctx, eventID
+func (_m *MockDatabasePort) MarkEventAsProcessed(ctx context.Context, eventID uint64) error {
+	ret := _m.Called(ctx, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEventAsProcessed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_MarkEventAsProcessed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEventAsProcessed'
+type MockDatabasePort_MarkEventAsProcessed_Call struct {
+	*mock.Call
+}
+
+// MarkEventAsProcessed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID uint64
+func (_e *MockDatabasePort_Expecter) MarkEventAsProcessed(ctx interface{}, eventID interface{}) *MockDatabasePort_MarkEventAsProcessed_Call {
+	return &MockDatabasePort_MarkEventAsProcessed_Call{Call: _e.mock.On("MarkEventAsProcessed", ctx, eventID)}
+}
+
+func (_c *MockDatabasePort_MarkEventAsProcessed_Call) Run(run func(ctx context.Context, eventID uint64)) *MockDatabasePort_MarkEventAsProcessed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_MarkEventAsProcessed_Call) Return(_a0 error) *MockDatabasePort_MarkEventAsProcessed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_MarkEventAsProcessed_Call) RunAndReturn(run func(context.Context, uint64) error) *MockDatabasePort_MarkEventAsProcessed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NextPayoutQueryID provides a mock function with given fields: ctx
+func (_m *MockDatabasePort) NextPayoutQueryID(ctx context.Context) (uint32, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextPayoutQueryID")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint32, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint32); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_NextPayoutQueryID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextPayoutQueryID'
+type MockDatabasePort_NextPayoutQueryID_Call struct {
+	*mock.Call
+}
+
+// NextPayoutQueryID is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockDatabasePort_Expecter) NextPayoutQueryID(ctx interface{}) *MockDatabasePort_NextPayoutQueryID_Call {
+	return &MockDatabasePort_NextPayoutQueryID_Call{Call: _e.mock.On("NextPayoutQueryID", ctx)}
+}
+
+func (_c *MockDatabasePort_NextPayoutQueryID_Call) Run(run func(ctx context.Context)) *MockDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_NextPayoutQueryID_Call) Return(_a0 uint32, _a1 error) *MockDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_NextPayoutQueryID_Call) RunAndReturn(run func(context.Context) (uint32, error)) *MockDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseWithdrawalLimitUsage provides a mock function with given fields: ctx, counter, amount
+func (_m *MockDatabasePort) ReleaseWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount) error {
+	ret := _m.Called(ctx, counter, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseWithdrawalLimitUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WithdrawalLimitCounter, model.Amount) error); ok {
+		r0 = rf(ctx, counter, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_ReleaseWithdrawalLimitUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseWithdrawalLimitUsage'
+type MockDatabasePort_ReleaseWithdrawalLimitUsage_Call struct {
+	*mock.Call
+}
+
+// ReleaseWithdrawalLimitUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - counter model.WithdrawalLimitCounter
+//   - amount model.Amount
+func (_e *MockDatabasePort_Expecter) ReleaseWithdrawalLimitUsage(ctx interface{}, counter interface{}, amount interface{}) *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	return &MockDatabasePort_ReleaseWithdrawalLimitUsage_Call{Call: _e.mock.On("ReleaseWithdrawalLimitUsage", ctx, counter, amount)}
+}
+
+func (_c *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call) Run(run func(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount)) *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.WithdrawalLimitCounter), args[2].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call) Return(_a0 error) *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call) RunAndReturn(run func(context.Context, model.WithdrawalLimitCounter, model.Amount) error) *MockDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReserveSeqno provides a mock function with given fields: ctx, message
+func (_m *MockDatabasePort) ReserveSeqno(ctx context.Context, message *model.WalletMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveSeqno")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_ReserveSeqno_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveSeqno'
+type MockDatabasePort_ReserveSeqno_Call struct {
+	*mock.Call
+}
+
+// ReserveSeqno is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *model.WalletMessage
+func (_e *MockDatabasePort_Expecter) ReserveSeqno(ctx interface{}, message interface{}) *MockDatabasePort_ReserveSeqno_Call {
+	return &MockDatabasePort_ReserveSeqno_Call{Call: _e.mock.On("ReserveSeqno", ctx, message)}
+}
+
+func (_c *MockDatabasePort_ReserveSeqno_Call) Run(run func(ctx context.Context, message *model.WalletMessage)) *MockDatabasePort_ReserveSeqno_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WalletMessage))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_ReserveSeqno_Call) Return(_a0 error) *MockDatabasePort_ReserveSeqno_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_ReserveSeqno_Call) RunAndReturn(run func(context.Context, *model.WalletMessage) error) *MockDatabasePort_ReserveSeqno_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveEvent provides a mock function with given fields: ctx, event
+func (_m *MockDatabasePort) SaveEvent(ctx context.Context, event model.OutboxEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.OutboxEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_SaveEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEvent'
+type MockDatabasePort_SaveEvent_Call struct {
+	*mock.Call
+}
+
+// SaveEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event model.OutboxEvent
+func (_e *MockDatabasePort_Expecter) SaveEvent(ctx interface{}, event interface{}) *MockDatabasePort_SaveEvent_Call {
+	return &MockDatabasePort_SaveEvent_Call{Call: _e.mock.On("SaveEvent", ctx, event)}
+}
+
+func (_c *MockDatabasePort_SaveEvent_Call) Run(run func(ctx context.Context, event model.OutboxEvent)) *MockDatabasePort_SaveEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.OutboxEvent))
 	})
 	return _c
 }
@@ -560,6 +2688,305 @@ func (_c *MockDatabasePort_UpdateAccount_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// UpdateDepositStatus provides a mock function with given fields: ctx, deposit, from
+func (_m *MockDatabasePort) UpdateDepositStatus(ctx context.Context, deposit *model.Deposit, from model.DepositStatus) error {
+	ret := _m.Called(ctx, deposit, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDepositStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit, model.DepositStatus) error); ok {
+		r0 = rf(ctx, deposit, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_UpdateDepositStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDepositStatus'
+type MockDatabasePort_UpdateDepositStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateDepositStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+//   - from model.DepositStatus
+func (_e *MockDatabasePort_Expecter) UpdateDepositStatus(ctx interface{}, deposit interface{}, from interface{}) *MockDatabasePort_UpdateDepositStatus_Call {
+	return &MockDatabasePort_UpdateDepositStatus_Call{Call: _e.mock.On("UpdateDepositStatus", ctx, deposit, from)}
+}
+
+func (_c *MockDatabasePort_UpdateDepositStatus_Call) Run(run func(ctx context.Context, deposit *model.Deposit, from model.DepositStatus)) *MockDatabasePort_UpdateDepositStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit), args[2].(model.DepositStatus))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateDepositStatus_Call) Return(_a0 error) *MockDatabasePort_UpdateDepositStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateDepositStatus_Call) RunAndReturn(run func(context.Context, *model.Deposit, model.DepositStatus) error) *MockDatabasePort_UpdateDepositStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateOutgoingTransferStatus provides a mock function with given fields: ctx, transfer, from
+func (_m *MockDatabasePort) UpdateOutgoingTransferStatus(ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus) error {
+	ret := _m.Called(ctx, transfer, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOutgoingTransferStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer, model.TransferStatus) error); ok {
+		r0 = rf(ctx, transfer, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_UpdateOutgoingTransferStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOutgoingTransferStatus'
+type MockDatabasePort_UpdateOutgoingTransferStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateOutgoingTransferStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *model.OutgoingTransfer
+//   - from model.TransferStatus
+func (_e *MockDatabasePort_Expecter) UpdateOutgoingTransferStatus(ctx interface{}, transfer interface{}, from interface{}) *MockDatabasePort_UpdateOutgoingTransferStatus_Call {
+	return &MockDatabasePort_UpdateOutgoingTransferStatus_Call{Call: _e.mock.On("UpdateOutgoingTransferStatus", ctx, transfer, from)}
+}
+
+func (_c *MockDatabasePort_UpdateOutgoingTransferStatus_Call) Run(run func(ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus)) *MockDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.OutgoingTransfer), args[2].(model.TransferStatus))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateOutgoingTransferStatus_Call) Return(_a0 error) *MockDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateOutgoingTransferStatus_Call) RunAndReturn(run func(context.Context, *model.OutgoingTransfer, model.TransferStatus) error) *MockDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRebalance provides a mock function with given fields: ctx, rebalance, from
+func (_m *MockDatabasePort) UpdateRebalance(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus) error {
+	ret := _m.Called(ctx, rebalance, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRebalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance, model.RebalanceStatus) error); ok {
+		r0 = rf(ctx, rebalance, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_UpdateRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRebalance'
+type MockDatabasePort_UpdateRebalance_Call struct {
+	*mock.Call
+}
+
+// UpdateRebalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rebalance *model.Rebalance
+//   - from model.RebalanceStatus
+func (_e *MockDatabasePort_Expecter) UpdateRebalance(ctx interface{}, rebalance interface{}, from interface{}) *MockDatabasePort_UpdateRebalance_Call {
+	return &MockDatabasePort_UpdateRebalance_Call{Call: _e.mock.On("UpdateRebalance", ctx, rebalance, from)}
+}
+
+func (_c *MockDatabasePort_UpdateRebalance_Call) Run(run func(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus)) *MockDatabasePort_UpdateRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Rebalance), args[2].(model.RebalanceStatus))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateRebalance_Call) Return(_a0 error) *MockDatabasePort_UpdateRebalance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateRebalance_Call) RunAndReturn(run func(context.Context, *model.Rebalance, model.RebalanceStatus) error) *MockDatabasePort_UpdateRebalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSweepJob provides a mock function with given fields: ctx, job, from
+func (_m *MockDatabasePort) UpdateSweepJob(ctx context.Context, job *model.SweepJob, from model.SweepStatus) error {
+	ret := _m.Called(ctx, job, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSweepJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob, model.SweepStatus) error); ok {
+		r0 = rf(ctx, job, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_UpdateSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSweepJob'
+type MockDatabasePort_UpdateSweepJob_Call struct {
+	*mock.Call
+}
+
+// UpdateSweepJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *model.SweepJob
+//   - from model.SweepStatus
+func (_e *MockDatabasePort_Expecter) UpdateSweepJob(ctx interface{}, job interface{}, from interface{}) *MockDatabasePort_UpdateSweepJob_Call {
+	return &MockDatabasePort_UpdateSweepJob_Call{Call: _e.mock.On("UpdateSweepJob", ctx, job, from)}
+}
+
+func (_c *MockDatabasePort_UpdateSweepJob_Call) Run(run func(ctx context.Context, job *model.SweepJob, from model.SweepStatus)) *MockDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepJob), args[2].(model.SweepStatus))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateSweepJob_Call) Return(_a0 error) *MockDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateSweepJob_Call) RunAndReturn(run func(context.Context, *model.SweepJob, model.SweepStatus) error) *MockDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWithdrawal provides a mock function with given fields: ctx, withdrawal, from
+func (_m *MockDatabasePort) UpdateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus) error {
+	ret := _m.Called(ctx, withdrawal, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWithdrawal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal, model.WithdrawalStatus) error); ok {
+		r0 = rf(ctx, withdrawal, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDatabasePort_UpdateWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWithdrawal'
+type MockDatabasePort_UpdateWithdrawal_Call struct {
+	*mock.Call
+}
+
+// UpdateWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawal *model.Withdrawal
+//   - from model.WithdrawalStatus
+func (_e *MockDatabasePort_Expecter) UpdateWithdrawal(ctx interface{}, withdrawal interface{}, from interface{}) *MockDatabasePort_UpdateWithdrawal_Call {
+	return &MockDatabasePort_UpdateWithdrawal_Call{Call: _e.mock.On("UpdateWithdrawal", ctx, withdrawal, from)}
+}
+
+func (_c *MockDatabasePort_UpdateWithdrawal_Call) Run(run func(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus)) *MockDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Withdrawal), args[2].(model.WithdrawalStatus))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateWithdrawal_Call) Return(_a0 error) *MockDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpdateWithdrawal_Call) RunAndReturn(run func(context.Context, *model.Withdrawal, model.WithdrawalStatus) error) *MockDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertSweepPolicy provides a mock function with given fields: ctx, policy
+func (_m *MockDatabasePort) UpsertSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSweepPolicy")
+	}
+
+	var r0 *model.SweepPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepPolicy) (*model.SweepPolicy, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepPolicy) *model.SweepPolicy); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SweepPolicy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDatabasePort_UpsertSweepPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertSweepPolicy'
+type MockDatabasePort_UpsertSweepPolicy_Call struct {
+	*mock.Call
+}
+
+// UpsertSweepPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - policy *model.SweepPolicy
+func (_e *MockDatabasePort_Expecter) UpsertSweepPolicy(ctx interface{}, policy interface{}) *MockDatabasePort_UpsertSweepPolicy_Call {
+	return &MockDatabasePort_UpsertSweepPolicy_Call{Call: _e.mock.On("UpsertSweepPolicy", ctx, policy)}
+}
+
+func (_c *MockDatabasePort_UpsertSweepPolicy_Call) Run(run func(ctx context.Context, policy *model.SweepPolicy)) *MockDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepPolicy))
+	})
+	return _c
+}
+
+func (_c *MockDatabasePort_UpsertSweepPolicy_Call) Return(_a0 *model.SweepPolicy, _a1 error) *MockDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDatabasePort_UpsertSweepPolicy_Call) RunAndReturn(run func(context.Context, *model.SweepPolicy) (*model.SweepPolicy, error)) *MockDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockDatabasePort creates a new instance of MockDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockDatabasePort(t interface {
@@ -0,0 +1,334 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDepositServicePort is an autogenerated mock type for the DepositServicePort type
+type MockDepositServicePort struct {
+	mock.Mock
+}
+
+type MockDepositServicePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDepositServicePort) EXPECT() *MockDepositServicePort_Expecter {
+	return &MockDepositServicePort_Expecter{mock: &_m.Mock}
+}
+
+// Bounce provides a mock function with given fields: ctx, deposit, reason
+func (_m *MockDepositServicePort) Bounce(ctx context.Context, deposit *model.Deposit, reason string) error {
+	ret := _m.Called(ctx, deposit, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bounce")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit, string) error); ok {
+		r0 = rf(ctx, deposit, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDepositServicePort_Bounce_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bounce'
+type MockDepositServicePort_Bounce_Call struct {
+	*mock.Call
+}
+
+// Bounce is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+//   - reason string
+func (_e *MockDepositServicePort_Expecter) Bounce(ctx interface{}, deposit interface{}, reason interface{}) *MockDepositServicePort_Bounce_Call {
+	return &MockDepositServicePort_Bounce_Call{Call: _e.mock.On("Bounce", ctx, deposit, reason)}
+}
+
+func (_c *MockDepositServicePort_Bounce_Call) Run(run func(ctx context.Context, deposit *model.Deposit, reason string)) *MockDepositServicePort_Bounce_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_Bounce_Call) Return(_a0 error) *MockDepositServicePort_Bounce_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDepositServicePort_Bounce_Call) RunAndReturn(run func(context.Context, *model.Deposit, string) error) *MockDepositServicePort_Bounce_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Confirm provides a mock function with given fields: ctx, deposit
+func (_m *MockDepositServicePort) Confirm(ctx context.Context, deposit *model.Deposit) error {
+	ret := _m.Called(ctx, deposit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit) error); ok {
+		r0 = rf(ctx, deposit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDepositServicePort_Confirm_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Confirm'
+type MockDepositServicePort_Confirm_Call struct {
+	*mock.Call
+}
+
+// Confirm is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+func (_e *MockDepositServicePort_Expecter) Confirm(ctx interface{}, deposit interface{}) *MockDepositServicePort_Confirm_Call {
+	return &MockDepositServicePort_Confirm_Call{Call: _e.mock.On("Confirm", ctx, deposit)}
+}
+
+func (_c *MockDepositServicePort_Confirm_Call) Run(run func(ctx context.Context, deposit *model.Deposit)) *MockDepositServicePort_Confirm_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_Confirm_Call) Return(_a0 error) *MockDepositServicePort_Confirm_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDepositServicePort_Confirm_Call) RunAndReturn(run func(context.Context, *model.Deposit) error) *MockDepositServicePort_Confirm_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Credit provides a mock function with given fields: ctx, deposit
+func (_m *MockDepositServicePort) Credit(ctx context.Context, deposit *model.Deposit) error {
+	ret := _m.Called(ctx, deposit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Credit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit) error); ok {
+		r0 = rf(ctx, deposit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDepositServicePort_Credit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Credit'
+type MockDepositServicePort_Credit_Call struct {
+	*mock.Call
+}
+
+// Credit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+func (_e *MockDepositServicePort_Expecter) Credit(ctx interface{}, deposit interface{}) *MockDepositServicePort_Credit_Call {
+	return &MockDepositServicePort_Credit_Call{Call: _e.mock.On("Credit", ctx, deposit)}
+}
+
+func (_c *MockDepositServicePort_Credit_Call) Run(run func(ctx context.Context, deposit *model.Deposit)) *MockDepositServicePort_Credit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_Credit_Call) Return(_a0 error) *MockDepositServicePort_Credit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDepositServicePort_Credit_Call) RunAndReturn(run func(context.Context, *model.Deposit) error) *MockDepositServicePort_Credit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Detect provides a mock function with given fields: ctx, accountID, tx
+func (_m *MockDepositServicePort) Detect(ctx context.Context, accountID string, tx *model.Transaction) (*model.Deposit, error) {
+	ret := _m.Called(ctx, accountID, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Detect")
+	}
+
+	var r0 *model.Deposit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Transaction) (*model.Deposit, error)); ok {
+		return rf(ctx, accountID, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Transaction) *model.Deposit); ok {
+		r0 = rf(ctx, accountID, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Deposit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *model.Transaction) error); ok {
+		r1 = rf(ctx, accountID, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDepositServicePort_Detect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Detect'
+type MockDepositServicePort_Detect_Call struct {
+	*mock.Call
+}
+
+// Detect is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - tx *model.Transaction
+func (_e *MockDepositServicePort_Expecter) Detect(ctx interface{}, accountID interface{}, tx interface{}) *MockDepositServicePort_Detect_Call {
+	return &MockDepositServicePort_Detect_Call{Call: _e.mock.On("Detect", ctx, accountID, tx)}
+}
+
+func (_c *MockDepositServicePort_Detect_Call) Run(run func(ctx context.Context, accountID string, tx *model.Transaction)) *MockDepositServicePort_Detect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*model.Transaction))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_Detect_Call) Return(_a0 *model.Deposit, _a1 error) *MockDepositServicePort_Detect_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDepositServicePort_Detect_Call) RunAndReturn(run func(context.Context, string, *model.Transaction) (*model.Deposit, error)) *MockDepositServicePort_Detect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkSwept provides a mock function with given fields: ctx, deposit
+func (_m *MockDepositServicePort) MarkSwept(ctx context.Context, deposit *model.Deposit) error {
+	ret := _m.Called(ctx, deposit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSwept")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit) error); ok {
+		r0 = rf(ctx, deposit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDepositServicePort_MarkSwept_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkSwept'
+type MockDepositServicePort_MarkSwept_Call struct {
+	*mock.Call
+}
+
+// MarkSwept is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+func (_e *MockDepositServicePort_Expecter) MarkSwept(ctx interface{}, deposit interface{}) *MockDepositServicePort_MarkSwept_Call {
+	return &MockDepositServicePort_MarkSwept_Call{Call: _e.mock.On("MarkSwept", ctx, deposit)}
+}
+
+func (_c *MockDepositServicePort_MarkSwept_Call) Run(run func(ctx context.Context, deposit *model.Deposit)) *MockDepositServicePort_MarkSwept_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_MarkSwept_Call) Return(_a0 error) *MockDepositServicePort_MarkSwept_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDepositServicePort_MarkSwept_Call) RunAndReturn(run func(context.Context, *model.Deposit) error) *MockDepositServicePort_MarkSwept_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reject provides a mock function with given fields: ctx, deposit, reason
+func (_m *MockDepositServicePort) Reject(ctx context.Context, deposit *model.Deposit, reason string) error {
+	ret := _m.Called(ctx, deposit, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Deposit, string) error); ok {
+		r0 = rf(ctx, deposit, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDepositServicePort_Reject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reject'
+type MockDepositServicePort_Reject_Call struct {
+	*mock.Call
+}
+
+// Reject is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deposit *model.Deposit
+//   - reason string
+func (_e *MockDepositServicePort_Expecter) Reject(ctx interface{}, deposit interface{}, reason interface{}) *MockDepositServicePort_Reject_Call {
+	return &MockDepositServicePort_Reject_Call{Call: _e.mock.On("Reject", ctx, deposit, reason)}
+}
+
+func (_c *MockDepositServicePort_Reject_Call) Run(run func(ctx context.Context, deposit *model.Deposit, reason string)) *MockDepositServicePort_Reject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Deposit), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockDepositServicePort_Reject_Call) Return(_a0 error) *MockDepositServicePort_Reject_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDepositServicePort_Reject_Call) RunAndReturn(run func(context.Context, *model.Deposit, string) error) *MockDepositServicePort_Reject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDepositServicePort creates a new instance of MockDepositServicePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDepositServicePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDepositServicePort {
+	mock := &MockDepositServicePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
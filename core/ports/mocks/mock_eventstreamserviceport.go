@@ -0,0 +1,85 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockEventStreamServicePort is an autogenerated mock type for the EventStreamServicePort type
+type MockEventStreamServicePort struct {
+	mock.Mock
+}
+
+type MockEventStreamServicePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEventStreamServicePort) EXPECT() *MockEventStreamServicePort_Expecter {
+	return &MockEventStreamServicePort_Expecter{mock: &_m.Mock}
+}
+
+// Subscribe provides a mock function with given fields: ctx, subscription, send
+func (_m *MockEventStreamServicePort) Subscribe(ctx context.Context, subscription model.EventSubscription, send func(model.OutboxEvent) error) error {
+	ret := _m.Called(ctx, subscription, send)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EventSubscription, func(model.OutboxEvent) error) error); ok {
+		r0 = rf(ctx, subscription, send)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEventStreamServicePort_Subscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribe'
+type MockEventStreamServicePort_Subscribe_Call struct {
+	*mock.Call
+}
+
+// Subscribe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subscription model.EventSubscription
+//   - send func(model.OutboxEvent) error
+func (_e *MockEventStreamServicePort_Expecter) Subscribe(ctx interface{}, subscription interface{}, send interface{}) *MockEventStreamServicePort_Subscribe_Call {
+	return &MockEventStreamServicePort_Subscribe_Call{Call: _e.mock.On("Subscribe", ctx, subscription, send)}
+}
+
+func (_c *MockEventStreamServicePort_Subscribe_Call) Run(run func(ctx context.Context, subscription model.EventSubscription, send func(model.OutboxEvent) error)) *MockEventStreamServicePort_Subscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.EventSubscription), args[2].(func(model.OutboxEvent) error))
+	})
+	return _c
+}
+
+func (_c *MockEventStreamServicePort_Subscribe_Call) Return(_a0 error) *MockEventStreamServicePort_Subscribe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventStreamServicePort_Subscribe_Call) RunAndReturn(run func(context.Context, model.EventSubscription, func(model.OutboxEvent) error) error) *MockEventStreamServicePort_Subscribe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockEventStreamServicePort creates a new instance of MockEventStreamServicePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEventStreamServicePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEventStreamServicePort {
+	mock := &MockEventStreamServicePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,590 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockLedgerServicePort is an autogenerated mock type for the LedgerServicePort type
+type MockLedgerServicePort struct {
+	mock.Mock
+}
+
+type MockLedgerServicePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLedgerServicePort) EXPECT() *MockLedgerServicePort_Expecter {
+	return &MockLedgerServicePort_Expecter{mock: &_m.Mock}
+}
+
+// GetBalance provides a mock function with given fields: ctx, accountID
+func (_m *MockLedgerServicePort) GetBalance(ctx context.Context, accountID string) ([]model.Balance, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 []model.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Balance, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Balance); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_GetBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalance'
+type MockLedgerServicePort_GetBalance_Call struct {
+	*mock.Call
+}
+
+// GetBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+func (_e *MockLedgerServicePort_Expecter) GetBalance(ctx interface{}, accountID interface{}) *MockLedgerServicePort_GetBalance_Call {
+	return &MockLedgerServicePort_GetBalance_Call{Call: _e.mock.On("GetBalance", ctx, accountID)}
+}
+
+func (_c *MockLedgerServicePort_GetBalance_Call) Run(run func(ctx context.Context, accountID string)) *MockLedgerServicePort_GetBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_GetBalance_Call) Return(_a0 []model.Balance, _a1 error) *MockLedgerServicePort_GetBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_GetBalance_Call) RunAndReturn(run func(context.Context, string) ([]model.Balance, error)) *MockLedgerServicePort_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordBounce provides a mock function with given fields: ctx, kind, accountID, sent, returned, reference
+func (_m *MockLedgerServicePort) RecordBounce(ctx context.Context, kind model.TransferKind, accountID string, sent model.Balance, returned model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, kind, accountID, sent, returned, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordBounce")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TransferKind, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, kind, accountID, sent, returned, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TransferKind, string, model.Balance, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, kind, accountID, sent, returned, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TransferKind, string, model.Balance, model.Amount, string) error); ok {
+		r1 = rf(ctx, kind, accountID, sent, returned, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordBounce_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordBounce'
+type MockLedgerServicePort_RecordBounce_Call struct {
+	*mock.Call
+}
+
+// RecordBounce is a helper method to define mock.On call
+//   - ctx context.Context
+//   - kind model.TransferKind
+//   - accountID string
+//   - sent model.Balance
+//   - returned model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordBounce(ctx interface{}, kind interface{}, accountID interface{}, sent interface{}, returned interface{}, reference interface{}) *MockLedgerServicePort_RecordBounce_Call {
+	return &MockLedgerServicePort_RecordBounce_Call{Call: _e.mock.On("RecordBounce", ctx, kind, accountID, sent, returned, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordBounce_Call) Run(run func(ctx context.Context, kind model.TransferKind, accountID string, sent model.Balance, returned model.Amount, reference string)) *MockLedgerServicePort_RecordBounce_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.TransferKind), args[2].(string), args[3].(model.Balance), args[4].(model.Amount), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordBounce_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordBounce_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordBounce_Call) RunAndReturn(run func(context.Context, model.TransferKind, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordBounce_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordDeposit provides a mock function with given fields: ctx, accountID, amount, reference
+func (_m *MockLedgerServicePort) RecordDeposit(ctx context.Context, accountID string, amount model.Balance, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, accountID, amount, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordDeposit")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, accountID, amount, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, accountID, amount, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Balance, string) error); ok {
+		r1 = rf(ctx, accountID, amount, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordDeposit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordDeposit'
+type MockLedgerServicePort_RecordDeposit_Call struct {
+	*mock.Call
+}
+
+// RecordDeposit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount model.Balance
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordDeposit(ctx interface{}, accountID interface{}, amount interface{}, reference interface{}) *MockLedgerServicePort_RecordDeposit_Call {
+	return &MockLedgerServicePort_RecordDeposit_Call{Call: _e.mock.On("RecordDeposit", ctx, accountID, amount, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordDeposit_Call) Run(run func(ctx context.Context, accountID string, amount model.Balance, reference string)) *MockLedgerServicePort_RecordDeposit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Balance), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordDeposit_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordDeposit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordDeposit_Call) RunAndReturn(run func(context.Context, string, model.Balance, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordDeposit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordFee provides a mock function with given fields: ctx, accountID, fee, reference
+func (_m *MockLedgerServicePort) RecordFee(ctx context.Context, accountID string, fee model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, accountID, fee, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFee")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, accountID, fee, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, accountID, fee, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Amount, string) error); ok {
+		r1 = rf(ctx, accountID, fee, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordFee_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordFee'
+type MockLedgerServicePort_RecordFee_Call struct {
+	*mock.Call
+}
+
+// RecordFee is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - fee model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordFee(ctx interface{}, accountID interface{}, fee interface{}, reference interface{}) *MockLedgerServicePort_RecordFee_Call {
+	return &MockLedgerServicePort_RecordFee_Call{Call: _e.mock.On("RecordFee", ctx, accountID, fee, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordFee_Call) Run(run func(ctx context.Context, accountID string, fee model.Amount, reference string)) *MockLedgerServicePort_RecordFee_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Amount), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordFee_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordFee_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordFee_Call) RunAndReturn(run func(context.Context, string, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordFee_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordRebalance provides a mock function with given fields: ctx, amount, fee, reference
+func (_m *MockLedgerServicePort) RecordRebalance(ctx context.Context, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, amount, fee, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordRebalance")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Balance, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, amount, fee, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Balance, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, amount, fee, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Balance, model.Amount, string) error); ok {
+		r1 = rf(ctx, amount, fee, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordRebalance'
+type MockLedgerServicePort_RecordRebalance_Call struct {
+	*mock.Call
+}
+
+// RecordRebalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - amount model.Balance
+//   - fee model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordRebalance(ctx interface{}, amount interface{}, fee interface{}, reference interface{}) *MockLedgerServicePort_RecordRebalance_Call {
+	return &MockLedgerServicePort_RecordRebalance_Call{Call: _e.mock.On("RecordRebalance", ctx, amount, fee, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordRebalance_Call) Run(run func(ctx context.Context, amount model.Balance, fee model.Amount, reference string)) *MockLedgerServicePort_RecordRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.Balance), args[2].(model.Amount), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordRebalance_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordRebalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordRebalance_Call) RunAndReturn(run func(context.Context, model.Balance, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordRebalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordSweep provides a mock function with given fields: ctx, accountID, amount, fee, reference
+func (_m *MockLedgerServicePort) RecordSweep(ctx context.Context, accountID string, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, accountID, amount, fee, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSweep")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, accountID, amount, fee, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Balance, model.Amount, string) error); ok {
+		r1 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordSweep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordSweep'
+type MockLedgerServicePort_RecordSweep_Call struct {
+	*mock.Call
+}
+
+// RecordSweep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount model.Balance
+//   - fee model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordSweep(ctx interface{}, accountID interface{}, amount interface{}, fee interface{}, reference interface{}) *MockLedgerServicePort_RecordSweep_Call {
+	return &MockLedgerServicePort_RecordSweep_Call{Call: _e.mock.On("RecordSweep", ctx, accountID, amount, fee, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordSweep_Call) Run(run func(ctx context.Context, accountID string, amount model.Balance, fee model.Amount, reference string)) *MockLedgerServicePort_RecordSweep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Balance), args[3].(model.Amount), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordSweep_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordSweep_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordSweep_Call) RunAndReturn(run func(context.Context, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordSweep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordTopUp provides a mock function with given fields: ctx, accountID, amount, fee, reference
+func (_m *MockLedgerServicePort) RecordTopUp(ctx context.Context, accountID string, amount model.Amount, fee model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, accountID, amount, fee, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordTopUp")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Amount, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, accountID, amount, fee, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Amount, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Amount, model.Amount, string) error); ok {
+		r1 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordTopUp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordTopUp'
+type MockLedgerServicePort_RecordTopUp_Call struct {
+	*mock.Call
+}
+
+// RecordTopUp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount model.Amount
+//   - fee model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordTopUp(ctx interface{}, accountID interface{}, amount interface{}, fee interface{}, reference interface{}) *MockLedgerServicePort_RecordTopUp_Call {
+	return &MockLedgerServicePort_RecordTopUp_Call{Call: _e.mock.On("RecordTopUp", ctx, accountID, amount, fee, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordTopUp_Call) Run(run func(ctx context.Context, accountID string, amount model.Amount, fee model.Amount, reference string)) *MockLedgerServicePort_RecordTopUp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Amount), args[3].(model.Amount), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordTopUp_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordTopUp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordTopUp_Call) RunAndReturn(run func(context.Context, string, model.Amount, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordTopUp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordWithdrawal provides a mock function with given fields: ctx, accountID, amount, fee, reference
+func (_m *MockLedgerServicePort) RecordWithdrawal(ctx context.Context, accountID string, amount model.Balance, fee model.Amount, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, accountID, amount, fee, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordWithdrawal")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, accountID, amount, fee, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Balance, model.Amount, string) error); ok {
+		r1 = rf(ctx, accountID, amount, fee, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_RecordWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordWithdrawal'
+type MockLedgerServicePort_RecordWithdrawal_Call struct {
+	*mock.Call
+}
+
+// RecordWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount model.Balance
+//   - fee model.Amount
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) RecordWithdrawal(ctx interface{}, accountID interface{}, amount interface{}, fee interface{}, reference interface{}) *MockLedgerServicePort_RecordWithdrawal_Call {
+	return &MockLedgerServicePort_RecordWithdrawal_Call{Call: _e.mock.On("RecordWithdrawal", ctx, accountID, amount, fee, reference)}
+}
+
+func (_c *MockLedgerServicePort_RecordWithdrawal_Call) Run(run func(ctx context.Context, accountID string, amount model.Balance, fee model.Amount, reference string)) *MockLedgerServicePort_RecordWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Balance), args[3].(model.Amount), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordWithdrawal_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_RecordWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_RecordWithdrawal_Call) RunAndReturn(run func(context.Context, string, model.Balance, model.Amount, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_RecordWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transfer provides a mock function with given fields: ctx, from, to, amount, reference
+func (_m *MockLedgerServicePort) Transfer(ctx context.Context, from string, to string, amount model.Balance, reference string) (*model.LedgerEntry, error) {
+	ret := _m.Called(ctx, from, to, amount, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transfer")
+	}
+
+	var r0 *model.LedgerEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Balance, string) (*model.LedgerEntry, error)); ok {
+		return rf(ctx, from, to, amount, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.Balance, string) *model.LedgerEntry); ok {
+		r0 = rf(ctx, from, to, amount, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.Balance, string) error); ok {
+		r1 = rf(ctx, from, to, amount, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLedgerServicePort_Transfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transfer'
+type MockLedgerServicePort_Transfer_Call struct {
+	*mock.Call
+}
+
+// Transfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from string
+//   - to string
+//   - amount model.Balance
+//   - reference string
+func (_e *MockLedgerServicePort_Expecter) Transfer(ctx interface{}, from interface{}, to interface{}, amount interface{}, reference interface{}) *MockLedgerServicePort_Transfer_Call {
+	return &MockLedgerServicePort_Transfer_Call{Call: _e.mock.On("Transfer", ctx, from, to, amount, reference)}
+}
+
+func (_c *MockLedgerServicePort_Transfer_Call) Run(run func(ctx context.Context, from string, to string, amount model.Balance, reference string)) *MockLedgerServicePort_Transfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(model.Balance), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockLedgerServicePort_Transfer_Call) Return(_a0 *model.LedgerEntry, _a1 error) *MockLedgerServicePort_Transfer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLedgerServicePort_Transfer_Call) RunAndReturn(run func(context.Context, string, string, model.Balance, string) (*model.LedgerEntry, error)) *MockLedgerServicePort_Transfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLedgerServicePort creates a new instance of MockLedgerServicePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLedgerServicePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLedgerServicePort {
+	mock := &MockLedgerServicePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
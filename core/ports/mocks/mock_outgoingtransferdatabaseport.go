@@ -0,0 +1,383 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockOutgoingTransferDatabasePort is an autogenerated mock type for the OutgoingTransferDatabasePort type
+type MockOutgoingTransferDatabasePort struct {
+	mock.Mock
+}
+
+type MockOutgoingTransferDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOutgoingTransferDatabasePort) EXPECT() *MockOutgoingTransferDatabasePort_Expecter {
+	return &MockOutgoingTransferDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// GetBouncedTransferCandidate provides a mock function with given fields: ctx, from, to, bounceHash, exclude
+func (_m *MockOutgoingTransferDatabasePort) GetBouncedTransferCandidate(ctx context.Context, from string, to string, bounceHash string, exclude []int64) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, from, to, bounceHash, exclude)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBouncedTransferCandidate")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []int64) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, from, to, bounceHash, exclude)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []int64) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, from, to, bounceHash, exclude)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []int64) error); ok {
+		r1 = rf(ctx, from, to, bounceHash, exclude)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBouncedTransferCandidate'
+type MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call struct {
+	*mock.Call
+}
+
+// GetBouncedTransferCandidate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from string
+//   - to string
+//   - bounceHash string
+//   - exclude []int64
+func (_e *MockOutgoingTransferDatabasePort_Expecter) GetBouncedTransferCandidate(ctx interface{}, from interface{}, to interface{}, bounceHash interface{}, exclude interface{}) *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call {
+	return &MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call{Call: _e.mock.On("GetBouncedTransferCandidate", ctx, from, to, bounceHash, exclude)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call) Run(run func(ctx context.Context, from string, to string, bounceHash string, exclude []int64)) *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].([]int64))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call) RunAndReturn(run func(context.Context, string, string, string, []int64) (*model.OutgoingTransfer, error)) *MockOutgoingTransferDatabasePort_GetBouncedTransferCandidate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOutgoingTransferByJettonQueryID provides a mock function with given fields: ctx, queryID
+func (_m *MockOutgoingTransferDatabasePort) GetOutgoingTransferByJettonQueryID(ctx context.Context, queryID uint64) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, queryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingTransferByJettonQueryID")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, queryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, queryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, queryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOutgoingTransferByJettonQueryID'
+type MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call struct {
+	*mock.Call
+}
+
+// GetOutgoingTransferByJettonQueryID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queryID uint64
+func (_e *MockOutgoingTransferDatabasePort_Expecter) GetOutgoingTransferByJettonQueryID(ctx interface{}, queryID interface{}) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	return &MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call{Call: _e.mock.On("GetOutgoingTransferByJettonQueryID", ctx, queryID)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call) Run(run func(ctx context.Context, queryID uint64)) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call) RunAndReturn(run func(context.Context, uint64) (*model.OutgoingTransfer, error)) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByJettonQueryID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOutgoingTransferByMessageHash provides a mock function with given fields: ctx, messageHash
+func (_m *MockOutgoingTransferDatabasePort) GetOutgoingTransferByMessageHash(ctx context.Context, messageHash string) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, messageHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOutgoingTransferByMessageHash")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, messageHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, messageHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOutgoingTransferByMessageHash'
+type MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call struct {
+	*mock.Call
+}
+
+// GetOutgoingTransferByMessageHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageHash string
+func (_e *MockOutgoingTransferDatabasePort_Expecter) GetOutgoingTransferByMessageHash(ctx interface{}, messageHash interface{}) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	return &MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call{Call: _e.mock.On("GetOutgoingTransferByMessageHash", ctx, messageHash)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call) Run(run func(ctx context.Context, messageHash string)) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call) RunAndReturn(run func(context.Context, string) (*model.OutgoingTransfer, error)) *MockOutgoingTransferDatabasePort_GetOutgoingTransferByMessageHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertOutgoingTransfer provides a mock function with given fields: ctx, transfer
+func (_m *MockOutgoingTransferDatabasePort) InsertOutgoingTransfer(ctx context.Context, transfer *model.OutgoingTransfer) (*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOutgoingTransfer")
+	}
+
+	var r0 *model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer) (*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer) *model.OutgoingTransfer); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.OutgoingTransfer) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertOutgoingTransfer'
+type MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call struct {
+	*mock.Call
+}
+
+// InsertOutgoingTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *model.OutgoingTransfer
+func (_e *MockOutgoingTransferDatabasePort_Expecter) InsertOutgoingTransfer(ctx interface{}, transfer interface{}) *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call {
+	return &MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call{Call: _e.mock.On("InsertOutgoingTransfer", ctx, transfer)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call) Run(run func(ctx context.Context, transfer *model.OutgoingTransfer)) *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.OutgoingTransfer))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call) Return(_a0 *model.OutgoingTransfer, _a1 error) *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call) RunAndReturn(run func(context.Context, *model.OutgoingTransfer) (*model.OutgoingTransfer, error)) *MockOutgoingTransferDatabasePort_InsertOutgoingTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOutgoingTransfersByMessageHash provides a mock function with given fields: ctx, messageHash
+func (_m *MockOutgoingTransferDatabasePort) ListOutgoingTransfersByMessageHash(ctx context.Context, messageHash string) ([]*model.OutgoingTransfer, error) {
+	ret := _m.Called(ctx, messageHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOutgoingTransfersByMessageHash")
+	}
+
+	var r0 []*model.OutgoingTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.OutgoingTransfer, error)); ok {
+		return rf(ctx, messageHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.OutgoingTransfer); ok {
+		r0 = rf(ctx, messageHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OutgoingTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOutgoingTransfersByMessageHash'
+type MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call struct {
+	*mock.Call
+}
+
+// ListOutgoingTransfersByMessageHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageHash string
+func (_e *MockOutgoingTransferDatabasePort_Expecter) ListOutgoingTransfersByMessageHash(ctx interface{}, messageHash interface{}) *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	return &MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call{Call: _e.mock.On("ListOutgoingTransfersByMessageHash", ctx, messageHash)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call) Run(run func(ctx context.Context, messageHash string)) *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call) Return(_a0 []*model.OutgoingTransfer, _a1 error) *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call) RunAndReturn(run func(context.Context, string) ([]*model.OutgoingTransfer, error)) *MockOutgoingTransferDatabasePort_ListOutgoingTransfersByMessageHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateOutgoingTransferStatus provides a mock function with given fields: ctx, transfer, from
+func (_m *MockOutgoingTransferDatabasePort) UpdateOutgoingTransferStatus(ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus) error {
+	ret := _m.Called(ctx, transfer, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOutgoingTransferStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OutgoingTransfer, model.TransferStatus) error); ok {
+		r0 = rf(ctx, transfer, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateOutgoingTransferStatus'
+type MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateOutgoingTransferStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *model.OutgoingTransfer
+//   - from model.TransferStatus
+func (_e *MockOutgoingTransferDatabasePort_Expecter) UpdateOutgoingTransferStatus(ctx interface{}, transfer interface{}, from interface{}) *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call {
+	return &MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call{Call: _e.mock.On("UpdateOutgoingTransferStatus", ctx, transfer, from)}
+}
+
+func (_c *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call) Run(run func(ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus)) *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.OutgoingTransfer), args[2].(model.TransferStatus))
+	})
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call) Return(_a0 error) *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call) RunAndReturn(run func(context.Context, *model.OutgoingTransfer, model.TransferStatus) error) *MockOutgoingTransferDatabasePort_UpdateOutgoingTransferStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOutgoingTransferDatabasePort creates a new instance of MockOutgoingTransferDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutgoingTransferDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutgoingTransferDatabasePort {
+	mock := &MockOutgoingTransferDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
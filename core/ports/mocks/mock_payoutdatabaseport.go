@@ -0,0 +1,92 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPayoutDatabasePort is an autogenerated mock type for the PayoutDatabasePort type
+type MockPayoutDatabasePort struct {
+	mock.Mock
+}
+
+type MockPayoutDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPayoutDatabasePort) EXPECT() *MockPayoutDatabasePort_Expecter {
+	return &MockPayoutDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// NextPayoutQueryID provides a mock function with given fields: ctx
+func (_m *MockPayoutDatabasePort) NextPayoutQueryID(ctx context.Context) (uint32, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextPayoutQueryID")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint32, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint32); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayoutDatabasePort_NextPayoutQueryID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextPayoutQueryID'
+type MockPayoutDatabasePort_NextPayoutQueryID_Call struct {
+	*mock.Call
+}
+
+// NextPayoutQueryID is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockPayoutDatabasePort_Expecter) NextPayoutQueryID(ctx interface{}) *MockPayoutDatabasePort_NextPayoutQueryID_Call {
+	return &MockPayoutDatabasePort_NextPayoutQueryID_Call{Call: _e.mock.On("NextPayoutQueryID", ctx)}
+}
+
+func (_c *MockPayoutDatabasePort_NextPayoutQueryID_Call) Run(run func(ctx context.Context)) *MockPayoutDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockPayoutDatabasePort_NextPayoutQueryID_Call) Return(_a0 uint32, _a1 error) *MockPayoutDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayoutDatabasePort_NextPayoutQueryID_Call) RunAndReturn(run func(context.Context) (uint32, error)) *MockPayoutDatabasePort_NextPayoutQueryID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPayoutDatabasePort creates a new instance of MockPayoutDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPayoutDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPayoutDatabasePort {
+	mock := &MockPayoutDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,201 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPayoutWalletPort is an autogenerated mock type for the PayoutWalletPort type
+type MockPayoutWalletPort struct {
+	mock.Mock
+}
+
+type MockPayoutWalletPort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPayoutWalletPort) EXPECT() *MockPayoutWalletPort_Expecter {
+	return &MockPayoutWalletPort_Expecter{mock: &_m.Mock}
+}
+
+// IsQueryProcessed provides a mock function with given fields: ctx, queryID
+func (_m *MockPayoutWalletPort) IsQueryProcessed(ctx context.Context, queryID uint32) (bool, error) {
+	ret := _m.Called(ctx, queryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsQueryProcessed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32) (bool, error)); ok {
+		return rf(ctx, queryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32) bool); ok {
+		r0 = rf(ctx, queryID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32) error); ok {
+		r1 = rf(ctx, queryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayoutWalletPort_IsQueryProcessed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsQueryProcessed'
+type MockPayoutWalletPort_IsQueryProcessed_Call struct {
+	*mock.Call
+}
+
+// IsQueryProcessed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queryID uint32
+func (_e *MockPayoutWalletPort_Expecter) IsQueryProcessed(ctx interface{}, queryID interface{}) *MockPayoutWalletPort_IsQueryProcessed_Call {
+	return &MockPayoutWalletPort_IsQueryProcessed_Call{Call: _e.mock.On("IsQueryProcessed", ctx, queryID)}
+}
+
+func (_c *MockPayoutWalletPort_IsQueryProcessed_Call) Run(run func(ctx context.Context, queryID uint32)) *MockPayoutWalletPort_IsQueryProcessed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32))
+	})
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_IsQueryProcessed_Call) Return(_a0 bool, _a1 error) *MockPayoutWalletPort_IsQueryProcessed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_IsQueryProcessed_Call) RunAndReturn(run func(context.Context, uint32) (bool, error)) *MockPayoutWalletPort_IsQueryProcessed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PreparePayouts provides a mock function with given fields: ctx, queryID, payouts
+func (_m *MockPayoutWalletPort) PreparePayouts(ctx context.Context, queryID uint32, payouts []model.Payout) ([]*model.WalletMessage, error) {
+	ret := _m.Called(ctx, queryID, payouts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreparePayouts")
+	}
+
+	var r0 []*model.WalletMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, []model.Payout) ([]*model.WalletMessage, error)); ok {
+		return rf(ctx, queryID, payouts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, []model.Payout) []*model.WalletMessage); ok {
+		r0 = rf(ctx, queryID, payouts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WalletMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, []model.Payout) error); ok {
+		r1 = rf(ctx, queryID, payouts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPayoutWalletPort_PreparePayouts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreparePayouts'
+type MockPayoutWalletPort_PreparePayouts_Call struct {
+	*mock.Call
+}
+
+// PreparePayouts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - queryID uint32
+//   - payouts []model.Payout
+func (_e *MockPayoutWalletPort_Expecter) PreparePayouts(ctx interface{}, queryID interface{}, payouts interface{}) *MockPayoutWalletPort_PreparePayouts_Call {
+	return &MockPayoutWalletPort_PreparePayouts_Call{Call: _e.mock.On("PreparePayouts", ctx, queryID, payouts)}
+}
+
+func (_c *MockPayoutWalletPort_PreparePayouts_Call) Run(run func(ctx context.Context, queryID uint32, payouts []model.Payout)) *MockPayoutWalletPort_PreparePayouts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32), args[2].([]model.Payout))
+	})
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_PreparePayouts_Call) Return(_a0 []*model.WalletMessage, _a1 error) *MockPayoutWalletPort_PreparePayouts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_PreparePayouts_Call) RunAndReturn(run func(context.Context, uint32, []model.Payout) ([]*model.WalletMessage, error)) *MockPayoutWalletPort_PreparePayouts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendWalletMessage provides a mock function with given fields: ctx, message
+func (_m *MockPayoutWalletPort) SendWalletMessage(ctx context.Context, message *model.WalletMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendWalletMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPayoutWalletPort_SendWalletMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendWalletMessage'
+type MockPayoutWalletPort_SendWalletMessage_Call struct {
+	*mock.Call
+}
+
+// SendWalletMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *model.WalletMessage
+func (_e *MockPayoutWalletPort_Expecter) SendWalletMessage(ctx interface{}, message interface{}) *MockPayoutWalletPort_SendWalletMessage_Call {
+	return &MockPayoutWalletPort_SendWalletMessage_Call{Call: _e.mock.On("SendWalletMessage", ctx, message)}
+}
+
+func (_c *MockPayoutWalletPort_SendWalletMessage_Call) Run(run func(ctx context.Context, message *model.WalletMessage)) *MockPayoutWalletPort_SendWalletMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WalletMessage))
+	})
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_SendWalletMessage_Call) Return(_a0 error) *MockPayoutWalletPort_SendWalletMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPayoutWalletPort_SendWalletMessage_Call) RunAndReturn(run func(context.Context, *model.WalletMessage) error) *MockPayoutWalletPort_SendWalletMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPayoutWalletPort creates a new instance of MockPayoutWalletPort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPayoutWalletPort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPayoutWalletPort {
+	mock := &MockPayoutWalletPort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,202 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRebalanceDatabasePort is an autogenerated mock type for the RebalanceDatabasePort type
+type MockRebalanceDatabasePort struct {
+	mock.Mock
+}
+
+type MockRebalanceDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRebalanceDatabasePort) EXPECT() *MockRebalanceDatabasePort_Expecter {
+	return &MockRebalanceDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// InsertRebalance provides a mock function with given fields: ctx, rebalance
+func (_m *MockRebalanceDatabasePort) InsertRebalance(ctx context.Context, rebalance *model.Rebalance) (*model.Rebalance, error) {
+	ret := _m.Called(ctx, rebalance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRebalance")
+	}
+
+	var r0 *model.Rebalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance) (*model.Rebalance, error)); ok {
+		return rf(ctx, rebalance)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance) *model.Rebalance); ok {
+		r0 = rf(ctx, rebalance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Rebalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Rebalance) error); ok {
+		r1 = rf(ctx, rebalance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRebalanceDatabasePort_InsertRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertRebalance'
+type MockRebalanceDatabasePort_InsertRebalance_Call struct {
+	*mock.Call
+}
+
+// InsertRebalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rebalance *model.Rebalance
+func (_e *MockRebalanceDatabasePort_Expecter) InsertRebalance(ctx interface{}, rebalance interface{}) *MockRebalanceDatabasePort_InsertRebalance_Call {
+	return &MockRebalanceDatabasePort_InsertRebalance_Call{Call: _e.mock.On("InsertRebalance", ctx, rebalance)}
+}
+
+func (_c *MockRebalanceDatabasePort_InsertRebalance_Call) Run(run func(ctx context.Context, rebalance *model.Rebalance)) *MockRebalanceDatabasePort_InsertRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Rebalance))
+	})
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_InsertRebalance_Call) Return(_a0 *model.Rebalance, _a1 error) *MockRebalanceDatabasePort_InsertRebalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_InsertRebalance_Call) RunAndReturn(run func(context.Context, *model.Rebalance) (*model.Rebalance, error)) *MockRebalanceDatabasePort_InsertRebalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveRebalances provides a mock function with given fields: ctx
+func (_m *MockRebalanceDatabasePort) ListActiveRebalances(ctx context.Context) ([]*model.Rebalance, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveRebalances")
+	}
+
+	var r0 []*model.Rebalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Rebalance, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Rebalance); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Rebalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRebalanceDatabasePort_ListActiveRebalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveRebalances'
+type MockRebalanceDatabasePort_ListActiveRebalances_Call struct {
+	*mock.Call
+}
+
+// ListActiveRebalances is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockRebalanceDatabasePort_Expecter) ListActiveRebalances(ctx interface{}) *MockRebalanceDatabasePort_ListActiveRebalances_Call {
+	return &MockRebalanceDatabasePort_ListActiveRebalances_Call{Call: _e.mock.On("ListActiveRebalances", ctx)}
+}
+
+func (_c *MockRebalanceDatabasePort_ListActiveRebalances_Call) Run(run func(ctx context.Context)) *MockRebalanceDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_ListActiveRebalances_Call) Return(_a0 []*model.Rebalance, _a1 error) *MockRebalanceDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_ListActiveRebalances_Call) RunAndReturn(run func(context.Context) ([]*model.Rebalance, error)) *MockRebalanceDatabasePort_ListActiveRebalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRebalance provides a mock function with given fields: ctx, rebalance, from
+func (_m *MockRebalanceDatabasePort) UpdateRebalance(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus) error {
+	ret := _m.Called(ctx, rebalance, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRebalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Rebalance, model.RebalanceStatus) error); ok {
+		r0 = rf(ctx, rebalance, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRebalanceDatabasePort_UpdateRebalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRebalance'
+type MockRebalanceDatabasePort_UpdateRebalance_Call struct {
+	*mock.Call
+}
+
+// UpdateRebalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rebalance *model.Rebalance
+//   - from model.RebalanceStatus
+func (_e *MockRebalanceDatabasePort_Expecter) UpdateRebalance(ctx interface{}, rebalance interface{}, from interface{}) *MockRebalanceDatabasePort_UpdateRebalance_Call {
+	return &MockRebalanceDatabasePort_UpdateRebalance_Call{Call: _e.mock.On("UpdateRebalance", ctx, rebalance, from)}
+}
+
+func (_c *MockRebalanceDatabasePort_UpdateRebalance_Call) Run(run func(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus)) *MockRebalanceDatabasePort_UpdateRebalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Rebalance), args[2].(model.RebalanceStatus))
+	})
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_UpdateRebalance_Call) Return(_a0 error) *MockRebalanceDatabasePort_UpdateRebalance_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRebalanceDatabasePort_UpdateRebalance_Call) RunAndReturn(run func(context.Context, *model.Rebalance, model.RebalanceStatus) error) *MockRebalanceDatabasePort_UpdateRebalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRebalanceDatabasePort creates a new instance of MockRebalanceDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRebalanceDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRebalanceDatabasePort {
+	mock := &MockRebalanceDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
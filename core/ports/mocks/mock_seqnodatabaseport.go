@@ -0,0 +1,84 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockSeqnoDatabasePort is an autogenerated mock type for the SeqnoDatabasePort type
+type MockSeqnoDatabasePort struct {
+	mock.Mock
+}
+
+type MockSeqnoDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSeqnoDatabasePort) EXPECT() *MockSeqnoDatabasePort_Expecter {
+	return &MockSeqnoDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// ReserveSeqno provides a mock function with given fields: ctx, message
+func (_m *MockSeqnoDatabasePort) ReserveSeqno(ctx context.Context, message *model.WalletMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveSeqno")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSeqnoDatabasePort_ReserveSeqno_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveSeqno'
+type MockSeqnoDatabasePort_ReserveSeqno_Call struct {
+	*mock.Call
+}
+
+// ReserveSeqno is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *model.WalletMessage
+func (_e *MockSeqnoDatabasePort_Expecter) ReserveSeqno(ctx interface{}, message interface{}) *MockSeqnoDatabasePort_ReserveSeqno_Call {
+	return &MockSeqnoDatabasePort_ReserveSeqno_Call{Call: _e.mock.On("ReserveSeqno", ctx, message)}
+}
+
+func (_c *MockSeqnoDatabasePort_ReserveSeqno_Call) Run(run func(ctx context.Context, message *model.WalletMessage)) *MockSeqnoDatabasePort_ReserveSeqno_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WalletMessage))
+	})
+	return _c
+}
+
+func (_c *MockSeqnoDatabasePort_ReserveSeqno_Call) Return(_a0 error) *MockSeqnoDatabasePort_ReserveSeqno_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSeqnoDatabasePort_ReserveSeqno_Call) RunAndReturn(run func(context.Context, *model.WalletMessage) error) *MockSeqnoDatabasePort_ReserveSeqno_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSeqnoDatabasePort creates a new instance of MockSeqnoDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSeqnoDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSeqnoDatabasePort {
+	mock := &MockSeqnoDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
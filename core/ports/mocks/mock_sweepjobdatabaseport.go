@@ -0,0 +1,264 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockSweepJobDatabasePort is an autogenerated mock type for the SweepJobDatabasePort type
+type MockSweepJobDatabasePort struct {
+	mock.Mock
+}
+
+type MockSweepJobDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSweepJobDatabasePort) EXPECT() *MockSweepJobDatabasePort_Expecter {
+	return &MockSweepJobDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// GetLastSweepJob provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockSweepJobDatabasePort) GetLastSweepJob(ctx context.Context, accountID string, currency model.Currency) (*model.SweepJob, error) {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastSweepJob")
+	}
+
+	var r0 *model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) (*model.SweepJob, error)); ok {
+		return rf(ctx, accountID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) *model.SweepJob); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Currency) error); ok {
+		r1 = rf(ctx, accountID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSweepJobDatabasePort_GetLastSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastSweepJob'
+type MockSweepJobDatabasePort_GetLastSweepJob_Call struct {
+	*mock.Call
+}
+
+// GetLastSweepJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - currency model.Currency
+func (_e *MockSweepJobDatabasePort_Expecter) GetLastSweepJob(ctx interface{}, accountID interface{}, currency interface{}) *MockSweepJobDatabasePort_GetLastSweepJob_Call {
+	return &MockSweepJobDatabasePort_GetLastSweepJob_Call{Call: _e.mock.On("GetLastSweepJob", ctx, accountID, currency)}
+}
+
+func (_c *MockSweepJobDatabasePort_GetLastSweepJob_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockSweepJobDatabasePort_GetLastSweepJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
+	})
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_GetLastSweepJob_Call) Return(_a0 *model.SweepJob, _a1 error) *MockSweepJobDatabasePort_GetLastSweepJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_GetLastSweepJob_Call) RunAndReturn(run func(context.Context, string, model.Currency) (*model.SweepJob, error)) *MockSweepJobDatabasePort_GetLastSweepJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertSweepJob provides a mock function with given fields: ctx, job
+func (_m *MockSweepJobDatabasePort) InsertSweepJob(ctx context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertSweepJob")
+	}
+
+	var r0 *model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob) (*model.SweepJob, error)); ok {
+		return rf(ctx, job)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob) *model.SweepJob); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SweepJob) error); ok {
+		r1 = rf(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSweepJobDatabasePort_InsertSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertSweepJob'
+type MockSweepJobDatabasePort_InsertSweepJob_Call struct {
+	*mock.Call
+}
+
+// InsertSweepJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *model.SweepJob
+func (_e *MockSweepJobDatabasePort_Expecter) InsertSweepJob(ctx interface{}, job interface{}) *MockSweepJobDatabasePort_InsertSweepJob_Call {
+	return &MockSweepJobDatabasePort_InsertSweepJob_Call{Call: _e.mock.On("InsertSweepJob", ctx, job)}
+}
+
+func (_c *MockSweepJobDatabasePort_InsertSweepJob_Call) Run(run func(ctx context.Context, job *model.SweepJob)) *MockSweepJobDatabasePort_InsertSweepJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepJob))
+	})
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_InsertSweepJob_Call) Return(_a0 *model.SweepJob, _a1 error) *MockSweepJobDatabasePort_InsertSweepJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_InsertSweepJob_Call) RunAndReturn(run func(context.Context, *model.SweepJob) (*model.SweepJob, error)) *MockSweepJobDatabasePort_InsertSweepJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveSweepJobs provides a mock function with given fields: ctx, afterID, limit
+func (_m *MockSweepJobDatabasePort) ListActiveSweepJobs(ctx context.Context, afterID int64, limit int) ([]*model.SweepJob, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveSweepJobs")
+	}
+
+	var r0 []*model.SweepJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*model.SweepJob, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*model.SweepJob); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SweepJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSweepJobDatabasePort_ListActiveSweepJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveSweepJobs'
+type MockSweepJobDatabasePort_ListActiveSweepJobs_Call struct {
+	*mock.Call
+}
+
+// ListActiveSweepJobs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *MockSweepJobDatabasePort_Expecter) ListActiveSweepJobs(ctx interface{}, afterID interface{}, limit interface{}) *MockSweepJobDatabasePort_ListActiveSweepJobs_Call {
+	return &MockSweepJobDatabasePort_ListActiveSweepJobs_Call{Call: _e.mock.On("ListActiveSweepJobs", ctx, afterID, limit)}
+}
+
+func (_c *MockSweepJobDatabasePort_ListActiveSweepJobs_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *MockSweepJobDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_ListActiveSweepJobs_Call) Return(_a0 []*model.SweepJob, _a1 error) *MockSweepJobDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_ListActiveSweepJobs_Call) RunAndReturn(run func(context.Context, int64, int) ([]*model.SweepJob, error)) *MockSweepJobDatabasePort_ListActiveSweepJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSweepJob provides a mock function with given fields: ctx, job, from
+func (_m *MockSweepJobDatabasePort) UpdateSweepJob(ctx context.Context, job *model.SweepJob, from model.SweepStatus) error {
+	ret := _m.Called(ctx, job, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSweepJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepJob, model.SweepStatus) error); ok {
+		r0 = rf(ctx, job, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSweepJobDatabasePort_UpdateSweepJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSweepJob'
+type MockSweepJobDatabasePort_UpdateSweepJob_Call struct {
+	*mock.Call
+}
+
+// UpdateSweepJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *model.SweepJob
+//   - from model.SweepStatus
+func (_e *MockSweepJobDatabasePort_Expecter) UpdateSweepJob(ctx interface{}, job interface{}, from interface{}) *MockSweepJobDatabasePort_UpdateSweepJob_Call {
+	return &MockSweepJobDatabasePort_UpdateSweepJob_Call{Call: _e.mock.On("UpdateSweepJob", ctx, job, from)}
+}
+
+func (_c *MockSweepJobDatabasePort_UpdateSweepJob_Call) Run(run func(ctx context.Context, job *model.SweepJob, from model.SweepStatus)) *MockSweepJobDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepJob), args[2].(model.SweepStatus))
+	})
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_UpdateSweepJob_Call) Return(_a0 error) *MockSweepJobDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSweepJobDatabasePort_UpdateSweepJob_Call) RunAndReturn(run func(context.Context, *model.SweepJob, model.SweepStatus) error) *MockSweepJobDatabasePort_UpdateSweepJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSweepJobDatabasePort creates a new instance of MockSweepJobDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSweepJobDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSweepJobDatabasePort {
+	mock := &MockSweepJobDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,203 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockSweepPolicyDatabasePort is an autogenerated mock type for the SweepPolicyDatabasePort type
+type MockSweepPolicyDatabasePort struct {
+	mock.Mock
+}
+
+type MockSweepPolicyDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSweepPolicyDatabasePort) EXPECT() *MockSweepPolicyDatabasePort_Expecter {
+	return &MockSweepPolicyDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// DeleteSweepPolicy provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockSweepPolicyDatabasePort) DeleteSweepPolicy(ctx context.Context, accountID string, currency model.Currency) error {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSweepPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) error); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSweepPolicy'
+type MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call struct {
+	*mock.Call
+}
+
+// DeleteSweepPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - currency model.Currency
+func (_e *MockSweepPolicyDatabasePort_Expecter) DeleteSweepPolicy(ctx interface{}, accountID interface{}, currency interface{}) *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call {
+	return &MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call{Call: _e.mock.On("DeleteSweepPolicy", ctx, accountID, currency)}
+}
+
+func (_c *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
+	})
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call) Return(_a0 error) *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call) RunAndReturn(run func(context.Context, string, model.Currency) error) *MockSweepPolicyDatabasePort_DeleteSweepPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSweepPolicies provides a mock function with given fields: ctx, accountID
+func (_m *MockSweepPolicyDatabasePort) ListSweepPolicies(ctx context.Context, accountID *string) ([]*model.SweepPolicy, error) {
+	ret := _m.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSweepPolicies")
+	}
+
+	var r0 []*model.SweepPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) ([]*model.SweepPolicy, error)); ok {
+		return rf(ctx, accountID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) []*model.SweepPolicy); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SweepPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSweepPolicyDatabasePort_ListSweepPolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSweepPolicies'
+type MockSweepPolicyDatabasePort_ListSweepPolicies_Call struct {
+	*mock.Call
+}
+
+// ListSweepPolicies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID *string
+func (_e *MockSweepPolicyDatabasePort_Expecter) ListSweepPolicies(ctx interface{}, accountID interface{}) *MockSweepPolicyDatabasePort_ListSweepPolicies_Call {
+	return &MockSweepPolicyDatabasePort_ListSweepPolicies_Call{Call: _e.mock.On("ListSweepPolicies", ctx, accountID)}
+}
+
+func (_c *MockSweepPolicyDatabasePort_ListSweepPolicies_Call) Run(run func(ctx context.Context, accountID *string)) *MockSweepPolicyDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*string))
+	})
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_ListSweepPolicies_Call) Return(_a0 []*model.SweepPolicy, _a1 error) *MockSweepPolicyDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_ListSweepPolicies_Call) RunAndReturn(run func(context.Context, *string) ([]*model.SweepPolicy, error)) *MockSweepPolicyDatabasePort_ListSweepPolicies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertSweepPolicy provides a mock function with given fields: ctx, policy
+func (_m *MockSweepPolicyDatabasePort) UpsertSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertSweepPolicy")
+	}
+
+	var r0 *model.SweepPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepPolicy) (*model.SweepPolicy, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.SweepPolicy) *model.SweepPolicy); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SweepPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.SweepPolicy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertSweepPolicy'
+type MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call struct {
+	*mock.Call
+}
+
+// UpsertSweepPolicy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - policy *model.SweepPolicy
+func (_e *MockSweepPolicyDatabasePort_Expecter) UpsertSweepPolicy(ctx interface{}, policy interface{}) *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call {
+	return &MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call{Call: _e.mock.On("UpsertSweepPolicy", ctx, policy)}
+}
+
+func (_c *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call) Run(run func(ctx context.Context, policy *model.SweepPolicy)) *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.SweepPolicy))
+	})
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call) Return(_a0 *model.SweepPolicy, _a1 error) *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call) RunAndReturn(run func(context.Context, *model.SweepPolicy) (*model.SweepPolicy, error)) *MockSweepPolicyDatabasePort_UpsertSweepPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSweepPolicyDatabasePort creates a new instance of MockSweepPolicyDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSweepPolicyDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSweepPolicyDatabasePort {
+	mock := &MockSweepPolicyDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,125 @@ func (_m *MockTransactionalDatabasePort) EXPECT() *MockTransactionalDatabasePort
 	return &MockTransactionalDatabasePort_Expecter{mock: &_m.Mock}
 }
 
+// GetTransactionByHash provides a mock function with given fields: ctx, hash
+func (_m *MockTransactionalDatabasePort) GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByHash")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Transaction, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Transaction); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionalDatabasePort_GetTransactionByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByHash'
+type MockTransactionalDatabasePort_GetTransactionByHash_Call struct {
+	*mock.Call
+}
+
+// GetTransactionByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockTransactionalDatabasePort_Expecter) GetTransactionByHash(ctx interface{}, hash interface{}) *MockTransactionalDatabasePort_GetTransactionByHash_Call {
+	return &MockTransactionalDatabasePort_GetTransactionByHash_Call{Call: _e.mock.On("GetTransactionByHash", ctx, hash)}
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByHash_Call) Run(run func(ctx context.Context, hash string)) *MockTransactionalDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByHash_Call) Return(_a0 *model.Transaction, _a1 error) *MockTransactionalDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByHash_Call) RunAndReturn(run func(context.Context, string) (*model.Transaction, error)) *MockTransactionalDatabasePort_GetTransactionByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactionByLT provides a mock function with given fields: ctx, accountID, lt
+func (_m *MockTransactionalDatabasePort) GetTransactionByLT(ctx context.Context, accountID string, lt int64) (*model.Transaction, error) {
+	ret := _m.Called(ctx, accountID, lt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByLT")
+	}
+
+	var r0 *model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*model.Transaction, error)); ok {
+		return rf(ctx, accountID, lt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *model.Transaction); ok {
+		r0 = rf(ctx, accountID, lt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, accountID, lt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionalDatabasePort_GetTransactionByLT_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByLT'
+type MockTransactionalDatabasePort_GetTransactionByLT_Call struct {
+	*mock.Call
+}
+
+// GetTransactionByLT is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - lt int64
+func (_e *MockTransactionalDatabasePort_Expecter) GetTransactionByLT(ctx interface{}, accountID interface{}, lt interface{}) *MockTransactionalDatabasePort_GetTransactionByLT_Call {
+	return &MockTransactionalDatabasePort_GetTransactionByLT_Call{Call: _e.mock.On("GetTransactionByLT", ctx, accountID, lt)}
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByLT_Call) Run(run func(ctx context.Context, accountID string, lt int64)) *MockTransactionalDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByLT_Call) Return(_a0 *model.Transaction, _a1 error) *MockTransactionalDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_GetTransactionByLT_Call) RunAndReturn(run func(context.Context, string, int64) (*model.Transaction, error)) *MockTransactionalDatabasePort_GetTransactionByLT_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // InsertTransaction provides a mock function with given fields: ctx, tx
 func (_m *MockTransactionalDatabasePort) InsertTransaction(ctx context.Context, tx *model.Transaction) (*model.Transaction, error) {
 	ret := _m.Called(ctx, tx)
@@ -81,6 +200,124 @@ func (_c *MockTransactionalDatabasePort_InsertTransaction_Call) RunAndReturn(run
 	return _c
 }
 
+// InsertTransactions provides a mock function with given fields: ctx, txs
+func (_m *MockTransactionalDatabasePort) InsertTransactions(ctx context.Context, txs []*model.Transaction) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, txs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Transaction) ([]*model.Transaction, error)); ok {
+		return rf(ctx, txs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Transaction) []*model.Transaction); ok {
+		r0 = rf(ctx, txs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Transaction) error); ok {
+		r1 = rf(ctx, txs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionalDatabasePort_InsertTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertTransactions'
+type MockTransactionalDatabasePort_InsertTransactions_Call struct {
+	*mock.Call
+}
+
+// InsertTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - txs []*model.Transaction
+func (_e *MockTransactionalDatabasePort_Expecter) InsertTransactions(ctx interface{}, txs interface{}) *MockTransactionalDatabasePort_InsertTransactions_Call {
+	return &MockTransactionalDatabasePort_InsertTransactions_Call{Call: _e.mock.On("InsertTransactions", ctx, txs)}
+}
+
+func (_c *MockTransactionalDatabasePort_InsertTransactions_Call) Run(run func(ctx context.Context, txs []*model.Transaction)) *MockTransactionalDatabasePort_InsertTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.Transaction))
+	})
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_InsertTransactions_Call) Return(_a0 []*model.Transaction, _a1 error) *MockTransactionalDatabasePort_InsertTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_InsertTransactions_Call) RunAndReturn(run func(context.Context, []*model.Transaction) ([]*model.Transaction, error)) *MockTransactionalDatabasePort_InsertTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTransactions provides a mock function with given fields: ctx, filter
+func (_m *MockTransactionalDatabasePort) ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) ([]*model.Transaction, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 []*model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) ([]*model.Transaction, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListTransactionsFilter) []*model.Transaction); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListTransactionsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTransactionalDatabasePort_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type MockTransactionalDatabasePort_ListTransactions_Call struct {
+	*mock.Call
+}
+
+// ListTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListTransactionsFilter
+func (_e *MockTransactionalDatabasePort_Expecter) ListTransactions(ctx interface{}, filter interface{}) *MockTransactionalDatabasePort_ListTransactions_Call {
+	return &MockTransactionalDatabasePort_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, filter)}
+}
+
+func (_c *MockTransactionalDatabasePort_ListTransactions_Call) Run(run func(ctx context.Context, filter model.ListTransactionsFilter)) *MockTransactionalDatabasePort_ListTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListTransactionsFilter))
+	})
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_ListTransactions_Call) Return(_a0 []*model.Transaction, _a1 error) *MockTransactionalDatabasePort_ListTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTransactionalDatabasePort_ListTransactions_Call) RunAndReturn(run func(context.Context, model.ListTransactionsFilter) ([]*model.Transaction, error)) *MockTransactionalDatabasePort_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockTransactionalDatabasePort creates a new instance of MockTransactionalDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockTransactionalDatabasePort(t interface {
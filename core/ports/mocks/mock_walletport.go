@@ -197,6 +197,178 @@ func (_c *MockWalletPort_GetExtraCurrenciesBalance_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// GetJettonBalance provides a mock function with given fields: ctx, walletID, currency
+func (_m *MockWalletPort) GetJettonBalance(ctx context.Context, walletID uint32, currency model.Currency) (model.Balance, error) {
+	ret := _m.Called(ctx, walletID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJettonBalance")
+	}
+
+	var r0 model.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Currency) (model.Balance, error)); ok {
+		return rf(ctx, walletID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Currency) model.Balance); ok {
+		r0 = rf(ctx, walletID, currency)
+	} else {
+		r0 = ret.Get(0).(model.Balance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, model.Currency) error); ok {
+		r1 = rf(ctx, walletID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_GetJettonBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJettonBalance'
+type MockWalletPort_GetJettonBalance_Call struct {
+	*mock.Call
+}
+
+// GetJettonBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - walletID uint32
+//   - currency model.Currency
+func (_e *MockWalletPort_Expecter) GetJettonBalance(ctx interface{}, walletID interface{}, currency interface{}) *MockWalletPort_GetJettonBalance_Call {
+	return &MockWalletPort_GetJettonBalance_Call{Call: _e.mock.On("GetJettonBalance", ctx, walletID, currency)}
+}
+
+func (_c *MockWalletPort_GetJettonBalance_Call) Run(run func(ctx context.Context, walletID uint32, currency model.Currency)) *MockWalletPort_GetJettonBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32), args[2].(model.Currency))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_GetJettonBalance_Call) Return(_a0 model.Balance, _a1 error) *MockWalletPort_GetJettonBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_GetJettonBalance_Call) RunAndReturn(run func(context.Context, uint32, model.Currency) (model.Balance, error)) *MockWalletPort_GetJettonBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSeqno provides a mock function with given fields: ctx, walletID
+func (_m *MockWalletPort) GetSeqno(ctx context.Context, walletID uint32) (uint32, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeqno")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32) (uint32, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32) uint32); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_GetSeqno_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSeqno'
+type MockWalletPort_GetSeqno_Call struct {
+	*mock.Call
+}
+
+// GetSeqno is a helper method to define mock.On call
+//   - ctx context.Context
+//   - walletID uint32
+func (_e *MockWalletPort_Expecter) GetSeqno(ctx interface{}, walletID interface{}) *MockWalletPort_GetSeqno_Call {
+	return &MockWalletPort_GetSeqno_Call{Call: _e.mock.On("GetSeqno", ctx, walletID)}
+}
+
+func (_c *MockWalletPort_GetSeqno_Call) Run(run func(ctx context.Context, walletID uint32)) *MockWalletPort_GetSeqno_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_GetSeqno_Call) Return(_a0 uint32, _a1 error) *MockWalletPort_GetSeqno_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_GetSeqno_Call) RunAndReturn(run func(context.Context, uint32) (uint32, error)) *MockWalletPort_GetSeqno_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsMessageExecuted provides a mock function with given fields: ctx, message
+func (_m *MockWalletPort) IsMessageExecuted(ctx context.Context, message *model.WalletMessage) (bool, error) {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsMessageExecuted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) (bool, error)); ok {
+		return rf(ctx, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) bool); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WalletMessage) error); ok {
+		r1 = rf(ctx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_IsMessageExecuted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsMessageExecuted'
+type MockWalletPort_IsMessageExecuted_Call struct {
+	*mock.Call
+}
+
+// IsMessageExecuted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *model.WalletMessage
+func (_e *MockWalletPort_Expecter) IsMessageExecuted(ctx interface{}, message interface{}) *MockWalletPort_IsMessageExecuted_Call {
+	return &MockWalletPort_IsMessageExecuted_Call{Call: _e.mock.On("IsMessageExecuted", ctx, message)}
+}
+
+func (_c *MockWalletPort_IsMessageExecuted_Call) Run(run func(ctx context.Context, message *model.WalletMessage)) *MockWalletPort_IsMessageExecuted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WalletMessage))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_IsMessageExecuted_Call) Return(_a0 bool, _a1 error) *MockWalletPort_IsMessageExecuted_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_IsMessageExecuted_Call) RunAndReturn(run func(context.Context, *model.WalletMessage) (bool, error)) *MockWalletPort_IsMessageExecuted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // MasterWallet provides a mock function with given fields: ctx
 func (_m *MockWalletPort) MasterWallet(ctx context.Context) (model.WalletWrapper, error) {
 	ret := _m.Called(ctx)
@@ -255,6 +427,297 @@ func (_c *MockWalletPort_MasterWallet_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// PrepareJettonTransferToMainWallet provides a mock function with given fields: ctx, walletID, currency, amount, gas
+func (_m *MockWalletPort) PrepareJettonTransferToMainWallet(ctx context.Context, walletID uint32, currency model.Currency, amount model.Amount, gas model.Amount) (*model.WalletMessage, error) {
+	ret := _m.Called(ctx, walletID, currency, amount, gas)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareJettonTransferToMainWallet")
+	}
+
+	var r0 *model.WalletMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Currency, model.Amount, model.Amount) (*model.WalletMessage, error)); ok {
+		return rf(ctx, walletID, currency, amount, gas)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Currency, model.Amount, model.Amount) *model.WalletMessage); ok {
+		r0 = rf(ctx, walletID, currency, amount, gas)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WalletMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, model.Currency, model.Amount, model.Amount) error); ok {
+		r1 = rf(ctx, walletID, currency, amount, gas)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_PrepareJettonTransferToMainWallet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareJettonTransferToMainWallet'
+type MockWalletPort_PrepareJettonTransferToMainWallet_Call struct {
+	*mock.Call
+}
+
+// PrepareJettonTransferToMainWallet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - walletID uint32
+//   - currency model.Currency
+//   - amount model.Amount
+//   - gas model.Amount
+func (_e *MockWalletPort_Expecter) PrepareJettonTransferToMainWallet(ctx interface{}, walletID interface{}, currency interface{}, amount interface{}, gas interface{}) *MockWalletPort_PrepareJettonTransferToMainWallet_Call {
+	return &MockWalletPort_PrepareJettonTransferToMainWallet_Call{Call: _e.mock.On("PrepareJettonTransferToMainWallet", ctx, walletID, currency, amount, gas)}
+}
+
+func (_c *MockWalletPort_PrepareJettonTransferToMainWallet_Call) Run(run func(ctx context.Context, walletID uint32, currency model.Currency, amount model.Amount, gas model.Amount)) *MockWalletPort_PrepareJettonTransferToMainWallet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32), args[2].(model.Currency), args[3].(model.Amount), args[4].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareJettonTransferToMainWallet_Call) Return(_a0 *model.WalletMessage, _a1 error) *MockWalletPort_PrepareJettonTransferToMainWallet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareJettonTransferToMainWallet_Call) RunAndReturn(run func(context.Context, uint32, model.Currency, model.Amount, model.Amount) (*model.WalletMessage, error)) *MockWalletPort_PrepareJettonTransferToMainWallet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrepareTopUp provides a mock function with given fields: ctx, walletID, amount
+func (_m *MockWalletPort) PrepareTopUp(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error) {
+	ret := _m.Called(ctx, walletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareTopUp")
+	}
+
+	var r0 *model.WalletMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Amount) (*model.WalletMessage, error)); ok {
+		return rf(ctx, walletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Amount) *model.WalletMessage); ok {
+		r0 = rf(ctx, walletID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WalletMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, model.Amount) error); ok {
+		r1 = rf(ctx, walletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_PrepareTopUp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareTopUp'
+type MockWalletPort_PrepareTopUp_Call struct {
+	*mock.Call
+}
+
+// PrepareTopUp is a helper method to define mock.On call
+//   - ctx context.Context
+//   - walletID uint32
+//   - amount model.Amount
+func (_e *MockWalletPort_Expecter) PrepareTopUp(ctx interface{}, walletID interface{}, amount interface{}) *MockWalletPort_PrepareTopUp_Call {
+	return &MockWalletPort_PrepareTopUp_Call{Call: _e.mock.On("PrepareTopUp", ctx, walletID, amount)}
+}
+
+func (_c *MockWalletPort_PrepareTopUp_Call) Run(run func(ctx context.Context, walletID uint32, amount model.Amount)) *MockWalletPort_PrepareTopUp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32), args[2].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareTopUp_Call) Return(_a0 *model.WalletMessage, _a1 error) *MockWalletPort_PrepareTopUp_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareTopUp_Call) RunAndReturn(run func(context.Context, uint32, model.Amount) (*model.WalletMessage, error)) *MockWalletPort_PrepareTopUp_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrepareTransferToMainWallet provides a mock function with given fields: ctx, walletID, amount
+func (_m *MockWalletPort) PrepareTransferToMainWallet(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error) {
+	ret := _m.Called(ctx, walletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareTransferToMainWallet")
+	}
+
+	var r0 *model.WalletMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Amount) (*model.WalletMessage, error)); ok {
+		return rf(ctx, walletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, model.Amount) *model.WalletMessage); ok {
+		r0 = rf(ctx, walletID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WalletMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, model.Amount) error); ok {
+		r1 = rf(ctx, walletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_PrepareTransferToMainWallet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareTransferToMainWallet'
+type MockWalletPort_PrepareTransferToMainWallet_Call struct {
+	*mock.Call
+}
+
+// PrepareTransferToMainWallet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - walletID uint32
+//   - amount model.Amount
+func (_e *MockWalletPort_Expecter) PrepareTransferToMainWallet(ctx interface{}, walletID interface{}, amount interface{}) *MockWalletPort_PrepareTransferToMainWallet_Call {
+	return &MockWalletPort_PrepareTransferToMainWallet_Call{Call: _e.mock.On("PrepareTransferToMainWallet", ctx, walletID, amount)}
+}
+
+func (_c *MockWalletPort_PrepareTransferToMainWallet_Call) Run(run func(ctx context.Context, walletID uint32, amount model.Amount)) *MockWalletPort_PrepareTransferToMainWallet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint32), args[2].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareTransferToMainWallet_Call) Return(_a0 *model.WalletMessage, _a1 error) *MockWalletPort_PrepareTransferToMainWallet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareTransferToMainWallet_Call) RunAndReturn(run func(context.Context, uint32, model.Amount) (*model.WalletMessage, error)) *MockWalletPort_PrepareTransferToMainWallet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrepareWithdrawal provides a mock function with given fields: ctx, to, amount, gas, comment
+func (_m *MockWalletPort) PrepareWithdrawal(ctx context.Context, to string, amount model.Balance, gas model.Amount, comment string) (*model.WalletMessage, error) {
+	ret := _m.Called(ctx, to, amount, gas, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareWithdrawal")
+	}
+
+	var r0 *model.WalletMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) (*model.WalletMessage, error)); ok {
+		return rf(ctx, to, amount, gas, comment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Balance, model.Amount, string) *model.WalletMessage); ok {
+		r0 = rf(ctx, to, amount, gas, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WalletMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Balance, model.Amount, string) error); ok {
+		r1 = rf(ctx, to, amount, gas, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWalletPort_PrepareWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareWithdrawal'
+type MockWalletPort_PrepareWithdrawal_Call struct {
+	*mock.Call
+}
+
+// PrepareWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - to string
+//   - amount model.Balance
+//   - gas model.Amount
+//   - comment string
+func (_e *MockWalletPort_Expecter) PrepareWithdrawal(ctx interface{}, to interface{}, amount interface{}, gas interface{}, comment interface{}) *MockWalletPort_PrepareWithdrawal_Call {
+	return &MockWalletPort_PrepareWithdrawal_Call{Call: _e.mock.On("PrepareWithdrawal", ctx, to, amount, gas, comment)}
+}
+
+func (_c *MockWalletPort_PrepareWithdrawal_Call) Run(run func(ctx context.Context, to string, amount model.Balance, gas model.Amount, comment string)) *MockWalletPort_PrepareWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Balance), args[3].(model.Amount), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareWithdrawal_Call) Return(_a0 *model.WalletMessage, _a1 error) *MockWalletPort_PrepareWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWalletPort_PrepareWithdrawal_Call) RunAndReturn(run func(context.Context, string, model.Balance, model.Amount, string) (*model.WalletMessage, error)) *MockWalletPort_PrepareWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendWalletMessage provides a mock function with given fields: ctx, message
+func (_m *MockWalletPort) SendWalletMessage(ctx context.Context, message *model.WalletMessage) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendWalletMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WalletMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWalletPort_SendWalletMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendWalletMessage'
+type MockWalletPort_SendWalletMessage_Call struct {
+	*mock.Call
+}
+
+// SendWalletMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *model.WalletMessage
+func (_e *MockWalletPort_Expecter) SendWalletMessage(ctx interface{}, message interface{}) *MockWalletPort_SendWalletMessage_Call {
+	return &MockWalletPort_SendWalletMessage_Call{Call: _e.mock.On("SendWalletMessage", ctx, message)}
+}
+
+func (_c *MockWalletPort_SendWalletMessage_Call) Run(run func(ctx context.Context, message *model.WalletMessage)) *MockWalletPort_SendWalletMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WalletMessage))
+	})
+	return _c
+}
+
+func (_c *MockWalletPort_SendWalletMessage_Call) Return(_a0 error) *MockWalletPort_SendWalletMessage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWalletPort_SendWalletMessage_Call) RunAndReturn(run func(context.Context, *model.WalletMessage) error) *MockWalletPort_SendWalletMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockWalletPort creates a new instance of MockWalletPort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockWalletPort(t interface {
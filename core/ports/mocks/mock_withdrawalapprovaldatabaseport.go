@@ -0,0 +1,155 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockWithdrawalApprovalDatabasePort is an autogenerated mock type for the WithdrawalApprovalDatabasePort type
+type MockWithdrawalApprovalDatabasePort struct {
+	mock.Mock
+}
+
+type MockWithdrawalApprovalDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWithdrawalApprovalDatabasePort) EXPECT() *MockWithdrawalApprovalDatabasePort_Expecter {
+	return &MockWithdrawalApprovalDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// InsertWithdrawalApproval provides a mock function with given fields: ctx, approval
+func (_m *MockWithdrawalApprovalDatabasePort) InsertWithdrawalApproval(ctx context.Context, approval *model.WithdrawalApproval) (*model.WithdrawalApproval, error) {
+	ret := _m.Called(ctx, approval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertWithdrawalApproval")
+	}
+
+	var r0 *model.WithdrawalApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) (*model.WithdrawalApproval, error)); ok {
+		return rf(ctx, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) *model.WithdrawalApproval); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WithdrawalApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WithdrawalApproval) error); ok {
+		r1 = rf(ctx, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertWithdrawalApproval'
+type MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call struct {
+	*mock.Call
+}
+
+// InsertWithdrawalApproval is a helper method to define mock.On call
+//   - ctx context.Context
+//   - approval *model.WithdrawalApproval
+func (_e *MockWithdrawalApprovalDatabasePort_Expecter) InsertWithdrawalApproval(ctx interface{}, approval interface{}) *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call {
+	return &MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call{Call: _e.mock.On("InsertWithdrawalApproval", ctx, approval)}
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call) Run(run func(ctx context.Context, approval *model.WithdrawalApproval)) *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WithdrawalApproval))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call) Return(_a0 *model.WithdrawalApproval, _a1 error) *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call) RunAndReturn(run func(context.Context, *model.WithdrawalApproval) (*model.WithdrawalApproval, error)) *MockWithdrawalApprovalDatabasePort_InsertWithdrawalApproval_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawalApprovals provides a mock function with given fields: ctx, withdrawalID
+func (_m *MockWithdrawalApprovalDatabasePort) ListWithdrawalApprovals(ctx context.Context, withdrawalID int64) ([]*model.WithdrawalApproval, error) {
+	ret := _m.Called(ctx, withdrawalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawalApprovals")
+	}
+
+	var r0 []*model.WithdrawalApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*model.WithdrawalApproval, error)); ok {
+		return rf(ctx, withdrawalID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*model.WithdrawalApproval); ok {
+		r0 = rf(ctx, withdrawalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WithdrawalApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, withdrawalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawalApprovals'
+type MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawalApprovals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawalID int64
+func (_e *MockWithdrawalApprovalDatabasePort_Expecter) ListWithdrawalApprovals(ctx interface{}, withdrawalID interface{}) *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call {
+	return &MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call{Call: _e.mock.On("ListWithdrawalApprovals", ctx, withdrawalID)}
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call) Run(run func(ctx context.Context, withdrawalID int64)) *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call) Return(_a0 []*model.WithdrawalApproval, _a1 error) *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call) RunAndReturn(run func(context.Context, int64) ([]*model.WithdrawalApproval, error)) *MockWithdrawalApprovalDatabasePort_ListWithdrawalApprovals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWithdrawalApprovalDatabasePort creates a new instance of MockWithdrawalApprovalDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWithdrawalApprovalDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWithdrawalApprovalDatabasePort {
+	mock := &MockWithdrawalApprovalDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,499 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockWithdrawalDatabasePort is an autogenerated mock type for the WithdrawalDatabasePort type
+type MockWithdrawalDatabasePort struct {
+	mock.Mock
+}
+
+type MockWithdrawalDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWithdrawalDatabasePort) EXPECT() *MockWithdrawalDatabasePort_Expecter {
+	return &MockWithdrawalDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// GetPendingWithdrawalAmount provides a mock function with given fields: ctx, accountID, currency
+func (_m *MockWithdrawalDatabasePort) GetPendingWithdrawalAmount(ctx context.Context, accountID string, currency model.Currency) (model.Amount, error) {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingWithdrawalAmount")
+	}
+
+	var r0 model.Amount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) (model.Amount, error)); ok {
+		return rf(ctx, accountID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.Currency) model.Amount); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		r0 = ret.Get(0).(model.Amount)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.Currency) error); ok {
+		r1 = rf(ctx, accountID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPendingWithdrawalAmount'
+type MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call struct {
+	*mock.Call
+}
+
+// GetPendingWithdrawalAmount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - currency model.Currency
+func (_e *MockWithdrawalDatabasePort_Expecter) GetPendingWithdrawalAmount(ctx interface{}, accountID interface{}, currency interface{}) *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call {
+	return &MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call{Call: _e.mock.On("GetPendingWithdrawalAmount", ctx, accountID, currency)}
+}
+
+func (_c *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call) Run(run func(ctx context.Context, accountID string, currency model.Currency)) *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(model.Currency))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call) Return(_a0 model.Amount, _a1 error) *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call) RunAndReturn(run func(context.Context, string, model.Currency) (model.Amount, error)) *MockWithdrawalDatabasePort_GetPendingWithdrawalAmount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawal provides a mock function with given fields: ctx, id
+func (_m *MockWithdrawalDatabasePort) GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_GetWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawal'
+type MockWithdrawalDatabasePort_GetWithdrawal_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockWithdrawalDatabasePort_Expecter) GetWithdrawal(ctx interface{}, id interface{}) *MockWithdrawalDatabasePort_GetWithdrawal_Call {
+	return &MockWithdrawalDatabasePort_GetWithdrawal_Call{Call: _e.mock.On("GetWithdrawal", ctx, id)}
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawal_Call) Run(run func(ctx context.Context, id int64)) *MockWithdrawalDatabasePort_GetWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_GetWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawal_Call) RunAndReturn(run func(context.Context, int64) (*model.Withdrawal, error)) *MockWithdrawalDatabasePort_GetWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalByIdempotencyKey provides a mock function with given fields: ctx, accountID, key
+func (_m *MockWithdrawalDatabasePort) GetWithdrawalByIdempotencyKey(ctx context.Context, accountID string, key string) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, accountID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalByIdempotencyKey")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Withdrawal, error)); ok {
+		return rf(ctx, accountID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Withdrawal); ok {
+		r0 = rf(ctx, accountID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accountID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalByIdempotencyKey'
+type MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalByIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - key string
+func (_e *MockWithdrawalDatabasePort_Expecter) GetWithdrawalByIdempotencyKey(ctx interface{}, accountID interface{}, key interface{}) *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	return &MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call{Call: _e.mock.On("GetWithdrawalByIdempotencyKey", ctx, accountID, key)}
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call) Run(run func(ctx context.Context, accountID string, key string)) *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call) RunAndReturn(run func(context.Context, string, string) (*model.Withdrawal, error)) *MockWithdrawalDatabasePort_GetWithdrawalByIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawalForUpdate provides a mock function with given fields: ctx, id
+func (_m *MockWithdrawalDatabasePort) GetWithdrawalForUpdate(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawalForUpdate")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawalForUpdate'
+type MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawalForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockWithdrawalDatabasePort_Expecter) GetWithdrawalForUpdate(ctx interface{}, id interface{}) *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call {
+	return &MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call{Call: _e.mock.On("GetWithdrawalForUpdate", ctx, id)}
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call) Run(run func(ctx context.Context, id int64)) *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call) RunAndReturn(run func(context.Context, int64) (*model.Withdrawal, error)) *MockWithdrawalDatabasePort_GetWithdrawalForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertWithdrawal provides a mock function with given fields: ctx, withdrawal
+func (_m *MockWithdrawalDatabasePort) InsertWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, withdrawal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)); ok {
+		return rf(ctx, withdrawal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) *model.Withdrawal); ok {
+		r0 = rf(ctx, withdrawal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Withdrawal) error); ok {
+		r1 = rf(ctx, withdrawal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_InsertWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertWithdrawal'
+type MockWithdrawalDatabasePort_InsertWithdrawal_Call struct {
+	*mock.Call
+}
+
+// InsertWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawal *model.Withdrawal
+func (_e *MockWithdrawalDatabasePort_Expecter) InsertWithdrawal(ctx interface{}, withdrawal interface{}) *MockWithdrawalDatabasePort_InsertWithdrawal_Call {
+	return &MockWithdrawalDatabasePort_InsertWithdrawal_Call{Call: _e.mock.On("InsertWithdrawal", ctx, withdrawal)}
+}
+
+func (_c *MockWithdrawalDatabasePort_InsertWithdrawal_Call) Run(run func(ctx context.Context, withdrawal *model.Withdrawal)) *MockWithdrawalDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Withdrawal))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_InsertWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_InsertWithdrawal_Call) RunAndReturn(run func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)) *MockWithdrawalDatabasePort_InsertWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveWithdrawals provides a mock function with given fields: ctx, afterID, limit
+func (_m *MockWithdrawalDatabasePort) ListActiveWithdrawals(ctx context.Context, afterID int64, limit int) ([]*model.Withdrawal, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveWithdrawals")
+	}
+
+	var r0 []*model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*model.Withdrawal, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*model.Withdrawal); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_ListActiveWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveWithdrawals'
+type MockWithdrawalDatabasePort_ListActiveWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListActiveWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID int64
+//   - limit int
+func (_e *MockWithdrawalDatabasePort_Expecter) ListActiveWithdrawals(ctx interface{}, afterID interface{}, limit interface{}) *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call {
+	return &MockWithdrawalDatabasePort_ListActiveWithdrawals_Call{Call: _e.mock.On("ListActiveWithdrawals", ctx, afterID, limit)}
+}
+
+func (_c *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call) Run(run func(ctx context.Context, afterID int64, limit int)) *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call) Return(_a0 []*model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call) RunAndReturn(run func(context.Context, int64, int) ([]*model.Withdrawal, error)) *MockWithdrawalDatabasePort_ListActiveWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawals provides a mock function with given fields: ctx, filter
+func (_m *MockWithdrawalDatabasePort) ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) ([]*model.Withdrawal, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawals")
+	}
+
+	var r0 []*model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) ([]*model.Withdrawal, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) []*model.Withdrawal); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListWithdrawalsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalDatabasePort_ListWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawals'
+type MockWithdrawalDatabasePort_ListWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListWithdrawalsFilter
+func (_e *MockWithdrawalDatabasePort_Expecter) ListWithdrawals(ctx interface{}, filter interface{}) *MockWithdrawalDatabasePort_ListWithdrawals_Call {
+	return &MockWithdrawalDatabasePort_ListWithdrawals_Call{Call: _e.mock.On("ListWithdrawals", ctx, filter)}
+}
+
+func (_c *MockWithdrawalDatabasePort_ListWithdrawals_Call) Run(run func(ctx context.Context, filter model.ListWithdrawalsFilter)) *MockWithdrawalDatabasePort_ListWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListWithdrawalsFilter))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_ListWithdrawals_Call) Return(_a0 []*model.Withdrawal, _a1 error) *MockWithdrawalDatabasePort_ListWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_ListWithdrawals_Call) RunAndReturn(run func(context.Context, model.ListWithdrawalsFilter) ([]*model.Withdrawal, error)) *MockWithdrawalDatabasePort_ListWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateWithdrawal provides a mock function with given fields: ctx, withdrawal, from
+func (_m *MockWithdrawalDatabasePort) UpdateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus) error {
+	ret := _m.Called(ctx, withdrawal, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWithdrawal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal, model.WithdrawalStatus) error); ok {
+		r0 = rf(ctx, withdrawal, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWithdrawalDatabasePort_UpdateWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateWithdrawal'
+type MockWithdrawalDatabasePort_UpdateWithdrawal_Call struct {
+	*mock.Call
+}
+
+// UpdateWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawal *model.Withdrawal
+//   - from model.WithdrawalStatus
+func (_e *MockWithdrawalDatabasePort_Expecter) UpdateWithdrawal(ctx interface{}, withdrawal interface{}, from interface{}) *MockWithdrawalDatabasePort_UpdateWithdrawal_Call {
+	return &MockWithdrawalDatabasePort_UpdateWithdrawal_Call{Call: _e.mock.On("UpdateWithdrawal", ctx, withdrawal, from)}
+}
+
+func (_c *MockWithdrawalDatabasePort_UpdateWithdrawal_Call) Run(run func(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus)) *MockWithdrawalDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Withdrawal), args[2].(model.WithdrawalStatus))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_UpdateWithdrawal_Call) Return(_a0 error) *MockWithdrawalDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWithdrawalDatabasePort_UpdateWithdrawal_Call) RunAndReturn(run func(context.Context, *model.Withdrawal, model.WithdrawalStatus) error) *MockWithdrawalDatabasePort_UpdateWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWithdrawalDatabasePort creates a new instance of MockWithdrawalDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWithdrawalDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWithdrawalDatabasePort {
+	mock := &MockWithdrawalDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
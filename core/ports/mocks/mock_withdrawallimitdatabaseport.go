@@ -0,0 +1,134 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockWithdrawalLimitDatabasePort is an autogenerated mock type for the WithdrawalLimitDatabasePort type
+type MockWithdrawalLimitDatabasePort struct {
+	mock.Mock
+}
+
+type MockWithdrawalLimitDatabasePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWithdrawalLimitDatabasePort) EXPECT() *MockWithdrawalLimitDatabasePort_Expecter {
+	return &MockWithdrawalLimitDatabasePort_Expecter{mock: &_m.Mock}
+}
+
+// AddWithdrawalLimitUsage provides a mock function with given fields: ctx, counter, amount, limit
+func (_m *MockWithdrawalLimitDatabasePort) AddWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount, limit model.Amount) error {
+	ret := _m.Called(ctx, counter, amount, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWithdrawalLimitUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WithdrawalLimitCounter, model.Amount, model.Amount) error); ok {
+		r0 = rf(ctx, counter, amount, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddWithdrawalLimitUsage'
+type MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call struct {
+	*mock.Call
+}
+
+// AddWithdrawalLimitUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - counter model.WithdrawalLimitCounter
+//   - amount model.Amount
+//   - limit model.Amount
+func (_e *MockWithdrawalLimitDatabasePort_Expecter) AddWithdrawalLimitUsage(ctx interface{}, counter interface{}, amount interface{}, limit interface{}) *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call {
+	return &MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call{Call: _e.mock.On("AddWithdrawalLimitUsage", ctx, counter, amount, limit)}
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call) Run(run func(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount, limit model.Amount)) *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.WithdrawalLimitCounter), args[2].(model.Amount), args[3].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call) Return(_a0 error) *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call) RunAndReturn(run func(context.Context, model.WithdrawalLimitCounter, model.Amount, model.Amount) error) *MockWithdrawalLimitDatabasePort_AddWithdrawalLimitUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseWithdrawalLimitUsage provides a mock function with given fields: ctx, counter, amount
+func (_m *MockWithdrawalLimitDatabasePort) ReleaseWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount) error {
+	ret := _m.Called(ctx, counter, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseWithdrawalLimitUsage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WithdrawalLimitCounter, model.Amount) error); ok {
+		r0 = rf(ctx, counter, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseWithdrawalLimitUsage'
+type MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call struct {
+	*mock.Call
+}
+
+// ReleaseWithdrawalLimitUsage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - counter model.WithdrawalLimitCounter
+//   - amount model.Amount
+func (_e *MockWithdrawalLimitDatabasePort_Expecter) ReleaseWithdrawalLimitUsage(ctx interface{}, counter interface{}, amount interface{}) *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	return &MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call{Call: _e.mock.On("ReleaseWithdrawalLimitUsage", ctx, counter, amount)}
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call) Run(run func(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount)) *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.WithdrawalLimitCounter), args[2].(model.Amount))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call) Return(_a0 error) *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call) RunAndReturn(run func(context.Context, model.WithdrawalLimitCounter, model.Amount) error) *MockWithdrawalLimitDatabasePort_ReleaseWithdrawalLimitUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWithdrawalLimitDatabasePort creates a new instance of MockWithdrawalLimitDatabasePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWithdrawalLimitDatabasePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWithdrawalLimitDatabasePort {
+	mock := &MockWithdrawalLimitDatabasePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
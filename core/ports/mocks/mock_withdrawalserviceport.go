@@ -0,0 +1,332 @@
+// Code generated by mockery v2.52.3. DO NOT EDIT.
+
+package portsmocks
+
+import (
+	context "context"
+
+	model "github.com/kriuchkov/tonbeacon/core/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockWithdrawalServicePort is an autogenerated mock type for the WithdrawalServicePort type
+type MockWithdrawalServicePort struct {
+	mock.Mock
+}
+
+type MockWithdrawalServicePort_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWithdrawalServicePort) EXPECT() *MockWithdrawalServicePort_Expecter {
+	return &MockWithdrawalServicePort_Expecter{mock: &_m.Mock}
+}
+
+// ApproveWithdrawal provides a mock function with given fields: ctx, approval
+func (_m *MockWithdrawalServicePort) ApproveWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) ([]*model.WithdrawalApproval, error) {
+	ret := _m.Called(ctx, approval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveWithdrawal")
+	}
+
+	var r0 []*model.WithdrawalApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) ([]*model.WithdrawalApproval, error)); ok {
+		return rf(ctx, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) []*model.WithdrawalApproval); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WithdrawalApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WithdrawalApproval) error); ok {
+		r1 = rf(ctx, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalServicePort_ApproveWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApproveWithdrawal'
+type MockWithdrawalServicePort_ApproveWithdrawal_Call struct {
+	*mock.Call
+}
+
+// ApproveWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - approval *model.WithdrawalApproval
+func (_e *MockWithdrawalServicePort_Expecter) ApproveWithdrawal(ctx interface{}, approval interface{}) *MockWithdrawalServicePort_ApproveWithdrawal_Call {
+	return &MockWithdrawalServicePort_ApproveWithdrawal_Call{Call: _e.mock.On("ApproveWithdrawal", ctx, approval)}
+}
+
+func (_c *MockWithdrawalServicePort_ApproveWithdrawal_Call) Run(run func(ctx context.Context, approval *model.WithdrawalApproval)) *MockWithdrawalServicePort_ApproveWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WithdrawalApproval))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_ApproveWithdrawal_Call) Return(_a0 []*model.WithdrawalApproval, _a1 error) *MockWithdrawalServicePort_ApproveWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_ApproveWithdrawal_Call) RunAndReturn(run func(context.Context, *model.WithdrawalApproval) ([]*model.WithdrawalApproval, error)) *MockWithdrawalServicePort_ApproveWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWithdrawal provides a mock function with given fields: ctx, withdrawal
+func (_m *MockWithdrawalServicePort) CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, withdrawal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)); ok {
+		return rf(ctx, withdrawal)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Withdrawal) *model.Withdrawal); ok {
+		r0 = rf(ctx, withdrawal)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Withdrawal) error); ok {
+		r1 = rf(ctx, withdrawal)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalServicePort_CreateWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWithdrawal'
+type MockWithdrawalServicePort_CreateWithdrawal_Call struct {
+	*mock.Call
+}
+
+// CreateWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - withdrawal *model.Withdrawal
+func (_e *MockWithdrawalServicePort_Expecter) CreateWithdrawal(ctx interface{}, withdrawal interface{}) *MockWithdrawalServicePort_CreateWithdrawal_Call {
+	return &MockWithdrawalServicePort_CreateWithdrawal_Call{Call: _e.mock.On("CreateWithdrawal", ctx, withdrawal)}
+}
+
+func (_c *MockWithdrawalServicePort_CreateWithdrawal_Call) Run(run func(ctx context.Context, withdrawal *model.Withdrawal)) *MockWithdrawalServicePort_CreateWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.Withdrawal))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_CreateWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalServicePort_CreateWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_CreateWithdrawal_Call) RunAndReturn(run func(context.Context, *model.Withdrawal) (*model.Withdrawal, error)) *MockWithdrawalServicePort_CreateWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawal provides a mock function with given fields: ctx, id
+func (_m *MockWithdrawalServicePort) GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Withdrawal, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Withdrawal); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalServicePort_GetWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawal'
+type MockWithdrawalServicePort_GetWithdrawal_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockWithdrawalServicePort_Expecter) GetWithdrawal(ctx interface{}, id interface{}) *MockWithdrawalServicePort_GetWithdrawal_Call {
+	return &MockWithdrawalServicePort_GetWithdrawal_Call{Call: _e.mock.On("GetWithdrawal", ctx, id)}
+}
+
+func (_c *MockWithdrawalServicePort_GetWithdrawal_Call) Run(run func(ctx context.Context, id int64)) *MockWithdrawalServicePort_GetWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_GetWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalServicePort_GetWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_GetWithdrawal_Call) RunAndReturn(run func(context.Context, int64) (*model.Withdrawal, error)) *MockWithdrawalServicePort_GetWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWithdrawals provides a mock function with given fields: ctx, filter
+func (_m *MockWithdrawalServicePort) ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) (*model.WithdrawalPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWithdrawals")
+	}
+
+	var r0 *model.WithdrawalPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) (*model.WithdrawalPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ListWithdrawalsFilter) *model.WithdrawalPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WithdrawalPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ListWithdrawalsFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalServicePort_ListWithdrawals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWithdrawals'
+type MockWithdrawalServicePort_ListWithdrawals_Call struct {
+	*mock.Call
+}
+
+// ListWithdrawals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.ListWithdrawalsFilter
+func (_e *MockWithdrawalServicePort_Expecter) ListWithdrawals(ctx interface{}, filter interface{}) *MockWithdrawalServicePort_ListWithdrawals_Call {
+	return &MockWithdrawalServicePort_ListWithdrawals_Call{Call: _e.mock.On("ListWithdrawals", ctx, filter)}
+}
+
+func (_c *MockWithdrawalServicePort_ListWithdrawals_Call) Run(run func(ctx context.Context, filter model.ListWithdrawalsFilter)) *MockWithdrawalServicePort_ListWithdrawals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.ListWithdrawalsFilter))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_ListWithdrawals_Call) Return(_a0 *model.WithdrawalPage, _a1 error) *MockWithdrawalServicePort_ListWithdrawals_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_ListWithdrawals_Call) RunAndReturn(run func(context.Context, model.ListWithdrawalsFilter) (*model.WithdrawalPage, error)) *MockWithdrawalServicePort_ListWithdrawals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RejectWithdrawal provides a mock function with given fields: ctx, approval
+func (_m *MockWithdrawalServicePort) RejectWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) (*model.Withdrawal, error) {
+	ret := _m.Called(ctx, approval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectWithdrawal")
+	}
+
+	var r0 *model.Withdrawal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) (*model.Withdrawal, error)); ok {
+		return rf(ctx, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WithdrawalApproval) *model.Withdrawal); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Withdrawal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WithdrawalApproval) error); ok {
+		r1 = rf(ctx, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWithdrawalServicePort_RejectWithdrawal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RejectWithdrawal'
+type MockWithdrawalServicePort_RejectWithdrawal_Call struct {
+	*mock.Call
+}
+
+// RejectWithdrawal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - approval *model.WithdrawalApproval
+func (_e *MockWithdrawalServicePort_Expecter) RejectWithdrawal(ctx interface{}, approval interface{}) *MockWithdrawalServicePort_RejectWithdrawal_Call {
+	return &MockWithdrawalServicePort_RejectWithdrawal_Call{Call: _e.mock.On("RejectWithdrawal", ctx, approval)}
+}
+
+func (_c *MockWithdrawalServicePort_RejectWithdrawal_Call) Run(run func(ctx context.Context, approval *model.WithdrawalApproval)) *MockWithdrawalServicePort_RejectWithdrawal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.WithdrawalApproval))
+	})
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_RejectWithdrawal_Call) Return(_a0 *model.Withdrawal, _a1 error) *MockWithdrawalServicePort_RejectWithdrawal_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWithdrawalServicePort_RejectWithdrawal_Call) RunAndReturn(run func(context.Context, *model.WithdrawalApproval) (*model.Withdrawal, error)) *MockWithdrawalServicePort_RejectWithdrawal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWithdrawalServicePort creates a new instance of MockWithdrawalServicePort. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWithdrawalServicePort(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWithdrawalServicePort {
+	mock := &MockWithdrawalServicePort{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
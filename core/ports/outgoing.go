@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
 )
@@ -11,6 +12,37 @@ type WalletPort interface {
 	MasterWallet(ctx context.Context) (model.WalletWrapper, error)
 	GetExtraCurrenciesBalance(ctx context.Context, walletID uint32) ([]model.Balance, error)
 	GetBalance(ctx context.Context, walletID uint32) (model.Balance, error)
+	// PrepareTransferToMainWallet signs the transfer of the amount from the subwallet to the master wallet
+	// without sending it.
+	PrepareTransferToMainWallet(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error)
+	// PrepareTopUp signs the transfer of the amount from the master wallet to the subwallet without sending it.
+	PrepareTopUp(ctx context.Context, walletID uint32, amount model.Amount) (*model.WalletMessage, error)
+	// PrepareJettonTransferToMainWallet signs the jetton transfer of the amount from the subwallet to the master
+	// wallet without sending it, the gas is attached to the transfer and its excess is returned to the master wallet.
+	PrepareJettonTransferToMainWallet(
+		ctx context.Context, walletID uint32, currency model.Currency, amount, gas model.Amount,
+	) (*model.WalletMessage, error)
+	// PrepareWithdrawal signs the transfer of the amount from the master wallet to the destination without sending it,
+	// the gas is attached to a jetton transfer and its excess is returned to the master wallet.
+	PrepareWithdrawal(ctx context.Context, to string, amount model.Balance, gas model.Amount, comment string) (*model.WalletMessage, error)
+	GetJettonBalance(ctx context.Context, walletID uint32, currency model.Currency) (model.Balance, error)
+	SendWalletMessage(ctx context.Context, message *model.WalletMessage) error
+	// GetSeqno returns the seqno of the subwallet or the master wallet for the walletID 0,
+	// 0 for a wallet that is not deployed.
+	GetSeqno(ctx context.Context, walletID uint32) (uint32, error)
+	// IsMessageExecuted reports whether the sending wallet executed the prepared message.
+	IsMessageExecuted(ctx context.Context, message *model.WalletMessage) (bool, error)
+}
+
+// PayoutWalletPort signs the batches of the highload payout wallet, a batch is signed with a query id the wallet
+// accepts once, so a batch is never executed twice however many times it is sent.
+type PayoutWalletPort interface {
+	// PreparePayouts signs the batch of the payouts without sending it, the messages of the payouts share
+	// the external message of the batch.
+	PreparePayouts(ctx context.Context, queryID uint32, payouts []model.Payout) ([]*model.WalletMessage, error)
+	// IsQueryProcessed reports whether the wallet executed the batch of the query id.
+	IsQueryProcessed(ctx context.Context, queryID uint32) (bool, error)
+	SendWalletMessage(ctx context.Context, message *model.WalletMessage) error
 }
 
 type DatabaseWithinTransactionPort interface {
@@ -29,6 +61,11 @@ type OutboxMessagePort interface {
 	Publish(ctx context.Context, eventType model.EventType, payload any) error
 }
 
+// OutboxArchivePort stores processed outbox events before they are purged from the outbox table.
+type OutboxArchivePort interface {
+	ArchiveEvents(ctx context.Context, events []model.OutboxEvent) error
+}
+
 type PublisherPort interface {
 	Publish(ctx context.Context, message any) error
 	Close() error
@@ -50,13 +87,146 @@ type (
 		MarkEventAsProcessed(ctx context.Context, eventID uint64) error
 	}
 
+	// OutboxStreamDatabasePort reads the outbox events in the order of their ids, processed events are
+	// read until they are purged by the retention.
+	OutboxStreamDatabasePort interface {
+		ListEventsAfter(ctx context.Context, afterID int64, limit int) ([]model.OutboxEvent, error)
+		GetFirstEventID(ctx context.Context) (int64, error)
+		GetLastEventID(ctx context.Context) (int64, error)
+	}
+
+	OutboxRetentionDatabasePort interface {
+		DeleteProcessedEvents(ctx context.Context, before time.Time, limit int) ([]model.OutboxEvent, error)
+	}
+
 	TransactionalDatabasePort interface {
 		InsertTransaction(ctx context.Context, tx *model.Transaction) (*model.Transaction, error)
+		InsertTransactions(ctx context.Context, txs []*model.Transaction) ([]*model.Transaction, error)
+		// ListTransactions returns up to filter.Limit transactions from the newest to the oldest.
+		ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) ([]*model.Transaction, error)
+		GetTransactionByHash(ctx context.Context, hash string) (*model.Transaction, error)
+		GetTransactionByLT(ctx context.Context, accountID model.AccountID, lt int64) (*model.Transaction, error)
+	}
+
+	// LedgerDatabasePort stores the ledger entries, the postings of an entry are stored atomically.
+	LedgerDatabasePort interface {
+		InsertLedgerEntry(ctx context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error)
+		GetLedgerBalances(ctx context.Context, account model.LedgerAccount) ([]model.Balance, error)
+		LockLedgerAccount(ctx context.Context, account model.LedgerAccount) error
+	}
+
+	// DepositDatabasePort stores the deposits, a status change is applied only if the deposit is still in the
+	// previous status, so concurrent transitions of the same deposit do not overwrite each other.
+	DepositDatabasePort interface {
+		InsertDeposit(ctx context.Context, deposit *model.Deposit) (*model.Deposit, error)
+		UpdateDepositStatus(ctx context.Context, deposit *model.Deposit, from model.DepositStatus) error
+	}
+
+	// OutgoingTransferDatabasePort registers the transfers sent by our wallets, a status change is applied
+	// only if the transfer is still in the previous status.
+	OutgoingTransferDatabasePort interface {
+		InsertOutgoingTransfer(ctx context.Context, transfer *model.OutgoingTransfer) (*model.OutgoingTransfer, error)
+		GetOutgoingTransferByMessageHash(ctx context.Context, messageHash string) (*model.OutgoingTransfer, error)
+		// ListOutgoingTransfersByMessageHash returns the transfers of the message, the transfers of a highload batch
+		// share the message.
+		ListOutgoingTransfersByMessageHash(ctx context.Context, messageHash string) ([]*model.OutgoingTransfer, error)
+		// GetOutgoingTransferByJettonQueryID returns the jetton transfer of the query id, the excess of its gas
+		// and its bounce carry the query id.
+		GetOutgoingTransferByJettonQueryID(ctx context.Context, queryID uint64) (*model.OutgoingTransfer, error)
+		// GetBouncedTransferCandidate returns the oldest confirmed TON transfer between the wallets with the bounce
+		// hash skipping the excluded ones, the bounced message returns only the prefix of the body of the transfer.
+		GetBouncedTransferCandidate(
+			ctx context.Context, from, to, bounceHash string, exclude []int64,
+		) (*model.OutgoingTransfer, error)
+		UpdateOutgoingTransferStatus(ctx context.Context, transfer *model.OutgoingTransfer, from model.TransferStatus) error
+	}
+
+	// SweepJobDatabasePort stores the sweep jobs, an account has at most one active job.
+	SweepJobDatabasePort interface {
+		InsertSweepJob(ctx context.Context, job *model.SweepJob) (*model.SweepJob, error)
+		// ListActiveSweepJobs returns the active jobs after the id from the oldest.
+		ListActiveSweepJobs(ctx context.Context, afterID int64, limit int) ([]*model.SweepJob, error)
+		// GetLastSweepJob returns the latest job of the account in the currency which did not fail.
+		GetLastSweepJob(ctx context.Context, accountID model.AccountID, currency model.Currency) (*model.SweepJob, error)
+		UpdateSweepJob(ctx context.Context, job *model.SweepJob, from model.SweepStatus) error
+	}
+
+	// SweepPolicyDatabasePort stores the sweep policies, an account and a currency have at most one policy.
+	SweepPolicyDatabasePort interface {
+		UpsertSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error)
+		// ListSweepPolicies returns the policies of the account, of all the accounts if it is nil.
+		ListSweepPolicies(ctx context.Context, accountID *model.AccountID) ([]*model.SweepPolicy, error)
+		DeleteSweepPolicy(ctx context.Context, accountID model.AccountID, currency model.Currency) error
+	}
+
+	// WithdrawalDatabasePort stores the withdrawals, an account has at most one withdrawal per idempotency key.
+	// A status change is applied only if the withdrawal is still in the previous status.
+	WithdrawalDatabasePort interface {
+		InsertWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error)
+		GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error)
+		// GetWithdrawalForUpdate returns the withdrawal locked until the end of the transaction of the context.
+		GetWithdrawalForUpdate(ctx context.Context, id int64) (*model.Withdrawal, error)
+		GetWithdrawalByIdempotencyKey(ctx context.Context, accountID model.AccountID, key string) (*model.Withdrawal, error)
+		// ListWithdrawals returns up to filter.Limit withdrawals from the newest to the oldest.
+		ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) ([]*model.Withdrawal, error)
+		// ListActiveWithdrawals returns the active withdrawals after the id from the oldest.
+		ListActiveWithdrawals(ctx context.Context, afterID int64, limit int) ([]*model.Withdrawal, error)
+		// GetPendingWithdrawalAmount returns the sum of the active withdrawals of the account in the currency.
+		GetPendingWithdrawalAmount(ctx context.Context, accountID model.AccountID, currency model.Currency) (model.Amount, error)
+		UpdateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal, from model.WithdrawalStatus) error
+	}
+
+	// WithdrawalApprovalDatabasePort stores the decisions of the operators on the withdrawals,
+	// an operator decides on a withdrawal once.
+	WithdrawalApprovalDatabasePort interface {
+		InsertWithdrawalApproval(ctx context.Context, approval *model.WithdrawalApproval) (*model.WithdrawalApproval, error)
+		ListWithdrawalApprovals(ctx context.Context, withdrawalID int64) ([]*model.WithdrawalApproval, error)
+	}
+
+	// WithdrawalLimitDatabasePort counts the amounts of the withdrawals approved on a day against the daily limits.
+	WithdrawalLimitDatabasePort interface {
+		// AddWithdrawalLimitUsage adds the amount to the counter, model.ErrWithdrawalLimitExceeded is returned
+		// if the counted amount would exceed the limit.
+		AddWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount, limit model.Amount) error
+		ReleaseWithdrawalLimitUsage(ctx context.Context, counter model.WithdrawalLimitCounter, amount model.Amount) error
+	}
+
+	// RebalanceDatabasePort stores the rebalances of the master wallet, a currency has at most one active rebalance.
+	RebalanceDatabasePort interface {
+		InsertRebalance(ctx context.Context, rebalance *model.Rebalance) (*model.Rebalance, error)
+		ListActiveRebalances(ctx context.Context) ([]*model.Rebalance, error)
+		UpdateRebalance(ctx context.Context, rebalance *model.Rebalance, from model.RebalanceStatus) error
+	}
+
+	// SeqnoDatabasePort reserves the seqnos of the wallets shared by the services, so two messages
+	// are not signed with the same seqno.
+	SeqnoDatabasePort interface {
+		// ReserveSeqno reserves the seqno of the message until its transfers are settled, model.ErrSeqnoReserved
+		// is returned if the seqno is reserved by another message with a transfer still sent.
+		ReserveSeqno(ctx context.Context, message *model.WalletMessage) error
+	}
+
+	// PayoutDatabasePort allocates the query ids of the highload payout wallet.
+	PayoutDatabasePort interface {
+		NextPayoutQueryID(ctx context.Context) (uint32, error)
 	}
 
 	DatabasePort interface {
 		AccountDatabasePort
 		OutboxMessageDatabasePort
+		OutboxRetentionDatabasePort
+		OutboxStreamDatabasePort
 		TransactionalDatabasePort
+		LedgerDatabasePort
+		DepositDatabasePort
+		OutgoingTransferDatabasePort
+		SweepJobDatabasePort
+		SweepPolicyDatabasePort
+		WithdrawalDatabasePort
+		WithdrawalApprovalDatabasePort
+		WithdrawalLimitDatabasePort
+		PayoutDatabasePort
+		RebalanceDatabasePort
+		SeqnoDatabasePort
 	}
 )
@@ -0,0 +1,22 @@
+-- Account, direction and currency of the transactions, they are set by the processor when a transaction is stored.
+ALTER TABLE transactions
+    ADD COLUMN account_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN direction TEXT NOT NULL DEFAULT '',
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'TON';
+
+-- Existing transactions are attributed to the accounts by the wallet addresses of their messages.
+UPDATE transactions t
+SET account_id = a.id::TEXT, direction = 'in'
+FROM accounts a
+WHERE t.receiver = a.ton_address AND t.sender <> '';
+
+UPDATE transactions t
+SET account_id = a.id::TEXT, direction = 'out'
+FROM accounts a
+WHERE t.account_id = '' AND (t.sender = a.ton_address OR (t.sender = '' AND t.receiver = a.ton_address));
+
+-- Listing is ordered by id from the newest transaction, the cursor is the id of the last listed one.
+CREATE INDEX idx_transactions_account_id ON transactions (account_id, id DESC);
+CREATE INDEX idx_transactions_account_lt ON transactions (account_id, lt);
+CREATE INDEX idx_transactions_hash ON transactions (hash);
+CREATE INDEX idx_transactions_created_at ON transactions (created_at, id);
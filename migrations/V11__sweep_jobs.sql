@@ -0,0 +1,28 @@
+-- Sweeps of the subwallets to the master wallet, the signed message is stored before it is broadcast.
+CREATE TABLE sweep_jobs (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    account_id TEXT NOT NULL,
+    wallet_id BIGINT NOT NULL,
+    from_addr TEXT NOT NULL,
+    to_addr TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    seqno BIGINT NOT NULL,
+    message_hash TEXT NOT NULL,
+    boc BYTEA NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP NULL,
+    confirmed_at TIMESTAMP NULL,
+    failed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_sweep_jobs_message_hash UNIQUE (message_hash),
+    CONSTRAINT chk_sweep_jobs_status CHECK (status IN ('planned', 'sent', 'confirmed', 'failed'))
+);
+
+-- An account has at most one sweep in flight.
+CREATE UNIQUE INDEX uq_sweep_jobs_active_account ON sweep_jobs (account_id) WHERE status IN ('planned', 'sent');
+CREATE INDEX idx_sweep_jobs_active ON sweep_jobs (id) WHERE status IN ('planned', 'sent');
@@ -0,0 +1,28 @@
+-- Jetton sweeps top up the gas of the subwallet from the master wallet before the jetton transfer is signed.
+ALTER TABLE outgoing_transfers
+    ADD COLUMN gas NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    DROP CONSTRAINT chk_outgoing_transfers_kind,
+    ADD CONSTRAINT chk_outgoing_transfers_kind CHECK (kind IN ('sweep', 'withdrawal', 'top_up'));
+
+ALTER TABLE sweep_jobs
+    ADD COLUMN gas NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    ADD COLUMN top_up_amount NUMERIC(40, 0) NULL,
+    ADD COLUMN top_up_from TEXT NULL,
+    ADD COLUMN top_up_to TEXT NULL,
+    ADD COLUMN top_up_seqno BIGINT NULL,
+    ADD COLUMN top_up_message_hash TEXT NULL,
+    ADD COLUMN top_up_boc BYTEA NULL,
+    ADD COLUMN top_up_expires_at TIMESTAMP NULL,
+    ADD COLUMN funded_at TIMESTAMP NULL,
+    ALTER COLUMN message_hash DROP NOT NULL,
+    ALTER COLUMN boc DROP NOT NULL,
+    ALTER COLUMN expires_at DROP NOT NULL,
+    DROP CONSTRAINT chk_sweep_jobs_status,
+    ADD CONSTRAINT chk_sweep_jobs_status CHECK (status IN ('planned', 'funding', 'funded', 'sent', 'confirmed', 'failed')),
+    ADD CONSTRAINT chk_sweep_jobs_message CHECK (status IN ('planned', 'funding', 'funded', 'failed') OR message_hash IS NOT NULL);
+
+DROP INDEX uq_sweep_jobs_active_account;
+DROP INDEX idx_sweep_jobs_active;
+
+CREATE UNIQUE INDEX uq_sweep_jobs_active_account ON sweep_jobs (account_id) WHERE status IN ('planned', 'funding', 'funded', 'sent');
+CREATE INDEX idx_sweep_jobs_active ON sweep_jobs (id) WHERE status IN ('planned', 'funding', 'funded', 'sent');
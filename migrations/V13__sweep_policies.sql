@@ -0,0 +1,18 @@
+-- Sweep policies of the accounts and the currencies, the empty account or currency applies to all of them.
+CREATE TABLE sweep_policies (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    account_id TEXT NOT NULL DEFAULT '',
+    currency TEXT NOT NULL DEFAULT '',
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    threshold NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    reserve NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    min_interval_seconds BIGINT NOT NULL DEFAULT 0,
+    schedule TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_sweep_policies_scope UNIQUE (account_id, currency),
+    CONSTRAINT chk_sweep_policies_values CHECK (threshold >= 0 AND reserve >= 0 AND min_interval_seconds >= 0)
+);
+
+-- The last sweep of an account in a currency is looked up for the minimal interval and the schedule of its policy.
+CREATE INDEX idx_sweep_jobs_account_currency ON sweep_jobs (account_id, currency, id DESC);
@@ -0,0 +1,36 @@
+-- Withdrawals of the custodial balances sent by the master wallet, the signed message is stored before it is broadcast.
+CREATE TABLE withdrawals (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    account_id TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    to_addr TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    gas NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    from_addr TEXT NULL,
+    seqno BIGINT NOT NULL DEFAULT 0,
+    message_hash TEXT NULL,
+    boc BYTEA NULL,
+    expires_at TIMESTAMP NULL,
+    tx_hash TEXT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    approved_at TIMESTAMP NULL,
+    signed_at TIMESTAMP NULL,
+    broadcast_at TIMESTAMP NULL,
+    confirmed_at TIMESTAMP NULL,
+    failed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_withdrawals_idempotency_key UNIQUE (account_id, idempotency_key),
+    CONSTRAINT uq_withdrawals_message_hash UNIQUE (message_hash),
+    CONSTRAINT chk_withdrawals_amount CHECK (amount > 0),
+    CONSTRAINT chk_withdrawals_status CHECK (
+        status IN ('requested', 'approved', 'signed', 'broadcast', 'confirmed', 'failed')
+    )
+);
+
+CREATE INDEX idx_withdrawals_account ON withdrawals (account_id, id DESC);
+CREATE INDEX idx_withdrawals_active ON withdrawals (id) WHERE status IN ('requested', 'approved', 'signed', 'broadcast');
@@ -0,0 +1,14 @@
+-- Query ids of the highload payout wallet, an id is reused only after the whole range, long after
+-- the wallet forgot it.
+CREATE SEQUENCE payout_query_ids AS BIGINT MINVALUE 1 MAXVALUE 8388607 CYCLE;
+
+-- The transfers of a highload batch share the hash of the batch message.
+ALTER TABLE outgoing_transfers
+    DROP CONSTRAINT uq_outgoing_transfers_message_hash,
+    ADD CONSTRAINT uq_outgoing_transfers_message_hash UNIQUE (message_hash, kind, reference);
+
+ALTER TABLE withdrawals
+    ADD COLUMN query_id BIGINT NULL,
+    DROP CONSTRAINT uq_withdrawals_message_hash;
+
+CREATE INDEX idx_withdrawals_message_hash ON withdrawals (message_hash) WHERE message_hash IS NOT NULL;
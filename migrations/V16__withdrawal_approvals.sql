@@ -0,0 +1,22 @@
+-- Decisions of the operators on the requested withdrawals, an operator decides on a withdrawal once.
+CREATE TABLE withdrawal_approvals (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    withdrawal_id BIGINT NOT NULL REFERENCES withdrawals (id),
+    operator TEXT NOT NULL,
+    decision TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_withdrawal_approvals_operator UNIQUE (withdrawal_id, operator),
+    CONSTRAINT chk_withdrawal_approvals_decision CHECK (decision IN ('approved', 'rejected'))
+);
+
+-- Amounts of the withdrawals approved on a UTC day per account and for all the accounts ('*').
+CREATE TABLE withdrawal_limit_counters (
+    scope TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    day DATE NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, currency, day),
+    CONSTRAINT chk_withdrawal_limit_counters_amount CHECK (amount >= 0)
+);
@@ -0,0 +1,31 @@
+-- Transfers of the master wallet to the cold storage, the signed message is stored before it is broadcast.
+CREATE TABLE rebalances (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    currency TEXT NOT NULL,
+    from_addr TEXT NOT NULL,
+    to_addr TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    gas NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    seqno BIGINT NOT NULL,
+    message_hash TEXT NOT NULL,
+    boc BYTEA NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP NULL,
+    confirmed_at TIMESTAMP NULL,
+    failed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_rebalances_message_hash UNIQUE (message_hash),
+    CONSTRAINT chk_rebalances_amount CHECK (amount > 0),
+    CONSTRAINT chk_rebalances_status CHECK (status IN ('planned', 'sent', 'confirmed', 'failed'))
+);
+
+-- A currency has at most one rebalance in flight.
+CREATE UNIQUE INDEX uq_rebalances_active_currency ON rebalances (currency) WHERE status IN ('planned', 'sent');
+
+ALTER TABLE outgoing_transfers
+    DROP CONSTRAINT chk_outgoing_transfers_kind,
+    ADD CONSTRAINT chk_outgoing_transfers_kind CHECK (kind IN ('sweep', 'withdrawal', 'top_up', 'rebalance'));
@@ -0,0 +1,7 @@
+-- Hash of the prefix of the body of a bounceable transfer, the bounce returns the prefix and is matched by the hash.
+ALTER TABLE outgoing_transfers ADD COLUMN bounce_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE withdrawals ADD COLUMN bounce_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE rebalances ADD COLUMN bounce_hash TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_outgoing_transfers_route;
+CREATE INDEX idx_outgoing_transfers_bounce ON outgoing_transfers (from_addr, to_addr, bounce_hash, id) WHERE status = 'confirmed';
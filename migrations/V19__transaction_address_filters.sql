@@ -0,0 +1,14 @@
+-- Senders and receivers are stored in the raw form (workchain:hex), so an address matches in any of its encodings.
+CREATE FUNCTION pg_temp.raw_address(addr TEXT) RETURNS TEXT LANGUAGE SQL IMMUTABLE AS $$
+    SELECT (CASE WHEN get_byte(d, 1) > 127 THEN get_byte(d, 1) - 256 ELSE get_byte(d, 1) END)::TEXT
+        || ':' || encode(substring(d FROM 3 FOR 32), 'hex')
+    FROM (SELECT decode(translate(addr, '-_', '+/'), 'base64') AS d) AS decoded
+$$;
+
+UPDATE transactions SET sender = pg_temp.raw_address(sender) WHERE sender ~ '^[A-Za-z0-9_+/-]{48}$';
+UPDATE transactions SET receiver = pg_temp.raw_address(receiver) WHERE receiver ~ '^[A-Za-z0-9_+/-]{48}$';
+
+CREATE INDEX idx_transactions_sender ON transactions (sender, id DESC);
+CREATE INDEX idx_transactions_receiver ON transactions (receiver, id DESC);
+CREATE INDEX idx_transactions_account_direction ON transactions (account_id, direction, currency, id DESC);
+CREATE INDEX idx_transactions_direction ON transactions (direction, currency, id DESC);
@@ -0,0 +1,6 @@
+-- A sweep whose wallet seqno advanced without the transfer being confirmed is left for an operator to reconcile,
+-- it no longer blocks the account.
+ALTER TABLE sweep_jobs
+    DROP CONSTRAINT chk_sweep_jobs_status,
+    ADD CONSTRAINT chk_sweep_jobs_status
+        CHECK (status IN ('planned', 'funding', 'funded', 'sent', 'confirmed', 'failed', 'unconfirmed'));
@@ -0,0 +1,8 @@
+-- The query id of a jetton transfer links the excess of its gas and its bounce to the transfer,
+-- a jetton transfer is confirmed by the excess returned by the jetton wallet of the destination.
+ALTER TABLE outgoing_transfers ADD COLUMN jetton_query_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE sweep_jobs ADD COLUMN jetton_query_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE withdrawals ADD COLUMN jetton_query_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE rebalances ADD COLUMN jetton_query_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_outgoing_transfers_jetton_query_id ON outgoing_transfers (jetton_query_id) WHERE jetton_query_id <> 0;
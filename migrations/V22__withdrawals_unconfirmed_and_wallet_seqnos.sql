@@ -0,0 +1,18 @@
+-- A withdrawal whose wallet seqno advanced without the transfer being confirmed keeps its amount reserved
+-- until the transfer is confirmed or fails.
+ALTER TABLE withdrawals
+    DROP CONSTRAINT chk_withdrawals_status,
+    ADD CONSTRAINT chk_withdrawals_status
+        CHECK (status IN ('requested', 'approved', 'signed', 'broadcast', 'confirmed', 'failed', 'unconfirmed'));
+
+DROP INDEX idx_withdrawals_active;
+CREATE INDEX idx_withdrawals_active ON withdrawals (id)
+    WHERE status IN ('requested', 'approved', 'signed', 'broadcast', 'unconfirmed');
+
+-- The last seqno reserved by a message of a wallet shared by the services, a newer seqno or the expiration
+-- of the message releases it.
+CREATE TABLE wallet_seqnos (
+    wallet TEXT PRIMARY KEY,
+    seqno BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,4 @@
+-- The reason a requested withdrawal is held is kept apart from its errors.
+ALTER TABLE withdrawals ADD COLUMN hold_reason TEXT NOT NULL DEFAULT '';
+
+UPDATE withdrawals SET hold_reason = error, error = '' WHERE status = 'requested' AND error <> '';
@@ -0,0 +1,5 @@
+-- A rebalance whose master wallet seqno advanced without the transfer being confirmed waits for its transfer,
+-- it does not hold the currency, so uq_rebalances_active_currency is unchanged.
+ALTER TABLE rebalances
+    DROP CONSTRAINT chk_rebalances_status,
+    ADD CONSTRAINT chk_rebalances_status CHECK (status IN ('planned', 'sent', 'confirmed', 'failed', 'unconfirmed'));
@@ -0,0 +1,2 @@
+-- The rows stored before the hashes get the hash of the same transaction when it is stored again.
+CREATE INDEX idx_transactions_legacy_account_lt ON transactions (account_addr, lt) WHERE hash = '';
@@ -0,0 +1,5 @@
+-- A reserved seqno is held until the transfers of the message reserving it are settled, the expiration
+-- of the message no longer releases it.
+ALTER TABLE wallet_seqnos
+    ADD COLUMN message_hash TEXT NOT NULL DEFAULT '',
+    DROP COLUMN expires_at;
@@ -0,0 +1,21 @@
+ALTER TABLE outbox_events ADD COLUMN processed_at TIMESTAMP NULL;
+
+UPDATE outbox_events SET processed_at = created_at WHERE processed = TRUE;
+
+-- The relay always reads the oldest pending events, the partial index stays small
+-- no matter how many processed rows are still waiting for the retention job.
+CREATE INDEX idx_outbox_events_pending ON outbox_events (id) WHERE processed = FALSE;
+
+-- The retention job selects processed rows by age.
+CREATE INDEX idx_outbox_events_processed_at ON outbox_events (processed_at) WHERE processed = TRUE;
+
+-- Monthly partitions are created by the retention job on demand.
+CREATE TABLE outbox_events_archive (
+	id BIGINT NOT NULL,
+	event_type TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	processed_at TIMESTAMP NULL,
+	archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
@@ -0,0 +1,9 @@
+ALTER TABLE outbox_events ADD COLUMN aggregate_id TEXT NULL;
+ALTER TABLE outbox_events_archive ADD COLUMN aggregate_id TEXT NULL;
+
+UPDATE outbox_events
+SET aggregate_id = payload::jsonb->>'account_id'
+WHERE event_type IN ('account_created', 'account_closed');
+
+-- Lookup of earlier pending events of the same aggregate by the relay.
+CREATE INDEX idx_outbox_events_pending_aggregate ON outbox_events (aggregate_id, id) WHERE processed = FALSE;
@@ -0,0 +1,12 @@
+ALTER TABLE transactions ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+
+-- Remove duplicates stored by redelivered messages before adding the unique key.
+DELETE FROM transactions t
+USING transactions d
+WHERE t.account_addr IS NOT DISTINCT FROM d.account_addr
+  AND t.lt = d.lt
+  AND t.hash = d.hash
+  AND t.id > d.id;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT uq_transactions_account_lt_hash UNIQUE NULLS NOT DISTINCT (account_addr, lt, hash);
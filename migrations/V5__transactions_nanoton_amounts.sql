@@ -0,0 +1,4 @@
+-- Amounts are stored as integer nanotons, existing values were stored in TON.
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE NUMERIC(40, 0) USING round(amount::NUMERIC * 1000000000),
+    ALTER COLUMN total_fees TYPE NUMERIC(40, 0) USING round(total_fees::NUMERIC * 1000000000);
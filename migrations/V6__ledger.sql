@@ -0,0 +1,40 @@
+CREATE TABLE ledger_entries (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    entry_type TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ledger_entries_type_reference UNIQUE (entry_type, reference)
+);
+
+-- Debits are positive and credits are negative, the postings of an entry sum to zero per currency.
+CREATE TABLE ledger_postings (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    entry_id BIGINT NOT NULL REFERENCES ledger_entries (id),
+    ledger_account TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL
+);
+
+CREATE INDEX idx_ledger_postings_entry ON ledger_postings (entry_id);
+CREATE INDEX idx_ledger_postings_account ON ledger_postings (ledger_account, currency);
+
+CREATE FUNCTION check_ledger_entry_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM ledger_postings
+        WHERE entry_id = NEW.entry_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'ledger entry % is unbalanced', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Checked on commit, so the postings of an entry may be inserted one by one.
+CREATE CONSTRAINT TRIGGER trg_ledger_postings_balanced
+    AFTER INSERT OR UPDATE ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_ledger_entry_balanced();
@@ -0,0 +1,24 @@
+CREATE TABLE deposits (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    account_id TEXT NOT NULL,
+    account_addr TEXT NOT NULL,
+    lt BIGINT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    sender TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    detected_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP NULL,
+    credited_at TIMESTAMP NULL,
+    swept_at TIMESTAMP NULL,
+    bounced_at TIMESTAMP NULL,
+    rejected_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_deposits_account_lt_hash UNIQUE (account_addr, lt, tx_hash),
+    CONSTRAINT chk_deposits_status CHECK (status IN ('detected', 'confirmed', 'credited', 'swept', 'bounced', 'rejected'))
+);
+
+CREATE INDEX idx_deposits_account ON deposits (account_id, id);
+CREATE INDEX idx_deposits_status ON deposits (status, id) WHERE status IN ('detected', 'confirmed', 'credited');
@@ -0,0 +1,8 @@
+-- Decoded message body, existing rows keep only the raw body.
+ALTER TABLE transactions
+    ADD COLUMN op_code BIGINT NULL,
+    ADD COLUMN query_id NUMERIC(20, 0) NULL,
+    ADD COLUMN comment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN encrypted_comment BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE deposits ADD COLUMN comment TEXT NOT NULL DEFAULT '';
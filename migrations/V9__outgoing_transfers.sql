@@ -0,0 +1,27 @@
+-- Transfers sent by our wallets, linked to the transactions executing and bouncing them.
+CREATE TABLE outgoing_transfers (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    kind TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    from_addr TEXT NOT NULL,
+    to_addr TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount NUMERIC(40, 0) NOT NULL,
+    message_hash TEXT NOT NULL,
+    status TEXT NOT NULL,
+    tx_hash TEXT NOT NULL DEFAULT '',
+    fee NUMERIC(40, 0) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT uq_outgoing_transfers_message_hash UNIQUE (message_hash),
+    CONSTRAINT chk_outgoing_transfers_kind CHECK (kind IN ('sweep', 'withdrawal')),
+    CONSTRAINT chk_outgoing_transfers_status CHECK (status IN ('sent', 'confirmed', 'bounced', 'failed'))
+);
+
+CREATE INDEX idx_outgoing_transfers_route ON outgoing_transfers (from_addr, to_addr, id) WHERE status = 'confirmed';
+
+ALTER TABLE transactions
+    ADD COLUMN kind TEXT NOT NULL DEFAULT '',
+    ADD COLUMN in_msg_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN transfer_id BIGINT NULL REFERENCES outgoing_transfers (id);
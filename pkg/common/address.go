@@ -0,0 +1,18 @@
+package common
+
+import (
+	"github.com/xssnick/tonutils-go/address"
+)
+
+// NormalizeAddress returns the raw form (workchain:hex) of a user-friendly or raw address,
+// so differently encoded addresses of the same account are equal. Unparsable values are returned as is.
+func NormalizeAddress(addr string) string {
+	if parsed, err := address.ParseAddr(addr); err == nil {
+		return parsed.StringRaw()
+	}
+
+	if parsed, err := address.ParseRawAddr(addr); err == nil {
+		return parsed.StringRaw()
+	}
+	return addr
+}
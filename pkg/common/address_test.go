@@ -0,0 +1,24 @@
+package common_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	const raw = "0:40a2bac1417a4af8d0b56c8fe4ebe7890f6122374c3e4f0a3d3b2c5ee2b0e63b"
+
+	for _, addr := range []string{
+		"EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z",
+		"UQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO-Kc",
+		"0QBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO1kW",
+		raw,
+	} {
+		require.Equal(t, raw, common.NormalizeAddress(addr), addr)
+	}
+
+	require.Equal(t, "not-an-address", common.NormalizeAddress("not-an-address"))
+}
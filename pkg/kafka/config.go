@@ -0,0 +1,190 @@
+// Package kafka contains the client configuration shared by the Kafka adapters.
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+// SASL mechanisms supported by the client.
+const (
+	SASLMechanismPlain       = "PLAIN"
+	SASLMechanismSCRAMSHA256 = "SCRAM-SHA-256"
+	SASLMechanismSCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+// Consumer initial offsets.
+const (
+	InitialOffsetNewest = "newest"
+	InitialOffsetOldest = "oldest"
+)
+
+type TLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`
+	CertFile           string `mapstructure:"cert_file" validate:"required_with=KeyFile"`
+	KeyFile            string `mapstructure:"key_file" validate:"required_with=CertFile"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+type SASLConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Mechanism string `mapstructure:"mechanism" validate:"omitempty,oneof=PLAIN SCRAM-SHA-256 SCRAM-SHA-512"`
+	User      string `mapstructure:"user" validate:"required_if=Enabled true"`
+	Password  string `mapstructure:"password" validate:"required_if=Enabled true"`
+}
+
+type ConsumerConfig struct {
+	InitialOffset     string        `mapstructure:"initial_offset" validate:"omitempty,oneof=newest oldest"`
+	SessionTimeout    time.Duration `mapstructure:"session_timeout" validate:"gte=0"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" validate:"gte=0"`
+	RebalanceTimeout  time.Duration `mapstructure:"rebalance_timeout" validate:"gte=0"`
+}
+
+// Config is the client configuration of a Kafka connection. Zero values keep the sarama defaults.
+type Config struct {
+	ClientID string         `mapstructure:"client_id"`
+	Version  string         `mapstructure:"version"`
+	TLS      TLSConfig      `mapstructure:"tls"`
+	SASL     SASLConfig     `mapstructure:"sasl"`
+	Consumer ConsumerConfig `mapstructure:"consumer"`
+}
+
+func (c *Config) Validate() error {
+	if err := validator.New().Struct(c); err != nil {
+		return errors.Wrap(err, "validate kafka config")
+	}
+	return nil
+}
+
+// SaramaConfig returns a new sarama config with the client settings applied.
+func (c *Config) SaramaConfig() (*sarama.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := sarama.NewConfig()
+	if c.ClientID != "" {
+		cfg.ClientID = c.ClientID
+	}
+
+	if c.Version != "" {
+		version, err := sarama.ParseKafkaVersion(c.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse kafka version")
+		}
+		cfg.Version = version
+	}
+
+	if c.TLS.Enabled {
+		tlsConfig, err := c.TLS.tlsConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "tls config")
+		}
+
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsConfig
+	}
+
+	if c.SASL.Enabled {
+		c.SASL.apply(cfg)
+	}
+
+	switch c.Consumer.InitialOffset {
+	case InitialOffsetOldest:
+		cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	case InitialOffsetNewest:
+		cfg.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+
+	if c.Consumer.SessionTimeout > 0 {
+		cfg.Consumer.Group.Session.Timeout = c.Consumer.SessionTimeout
+	}
+	if c.Consumer.HeartbeatInterval > 0 {
+		cfg.Consumer.Group.Heartbeat.Interval = c.Consumer.HeartbeatInterval
+	}
+	if c.Consumer.RebalanceTimeout > 0 {
+		cfg.Consumer.Group.Rebalance.Timeout = c.Consumer.RebalanceTimeout
+	}
+	return cfg, nil
+}
+
+func (t *TLSConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // explicitly configured
+	}
+
+	if t.CAFile != "" {
+		ca, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read ca file")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates in ca file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (s *SASLConfig) apply(cfg *sarama.Config) {
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.User = s.User
+	cfg.Net.SASL.Password = s.Password
+	cfg.Net.SASL.Handshake = true
+
+	switch s.Mechanism {
+	case SASLMechanismSCRAMSHA256:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGen: SHA256} }
+	case SASLMechanismSCRAMSHA512:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGen: SHA512} }
+	default:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	}
+}
+
+// BindEnv binds the environment variables of the client config nested under the prefix key.
+func BindEnv(v *viper.Viper, prefix string) {
+	for _, key := range []string{
+		"client_id",
+		"version",
+		"tls.enabled",
+		"tls.ca_file",
+		"tls.cert_file",
+		"tls.key_file",
+		"tls.server_name",
+		"tls.insecure_skip_verify",
+		"sasl.enabled",
+		"sasl.mechanism",
+		"sasl.user",
+		"sasl.password",
+		"consumer.initial_offset",
+		"consumer.session_timeout",
+		"consumer.heartbeat_interval",
+		"consumer.rebalance_timeout",
+	} {
+		v.BindEnv(prefix + "." + key) //nolint:errcheck
+	}
+}
@@ -0,0 +1,60 @@
+package kafka_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/pkg/kafka"
+)
+
+func TestConfig_SaramaConfig(t *testing.T) {
+	cfg := kafka.Config{
+		ClientID: "tonbeacon",
+		Version:  "3.6.0",
+		SASL: kafka.SASLConfig{
+			Enabled:   true,
+			Mechanism: kafka.SASLMechanismSCRAMSHA512,
+			User:      "user",
+			Password:  "password",
+		},
+		Consumer: kafka.ConsumerConfig{
+			InitialOffset:  kafka.InitialOffsetOldest,
+			SessionTimeout: 30 * time.Second,
+		},
+	}
+
+	saramaConfig, err := cfg.SaramaConfig()
+	require.NoError(t, err)
+	require.NoError(t, saramaConfig.Validate())
+
+	require.Equal(t, "tonbeacon", saramaConfig.ClientID)
+	require.Equal(t, sarama.V3_6_0_0, saramaConfig.Version)
+	require.True(t, saramaConfig.Net.SASL.Enable)
+	require.Equal(t, sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), saramaConfig.Net.SASL.Mechanism)
+	require.NotNil(t, saramaConfig.Net.SASL.SCRAMClientGeneratorFunc)
+	require.Equal(t, sarama.OffsetOldest, saramaConfig.Consumer.Offsets.Initial)
+	require.Equal(t, 30*time.Second, saramaConfig.Consumer.Group.Session.Timeout)
+}
+
+func TestConfig_SaramaConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  kafka.Config
+	}{
+		{name: "unknown mechanism", cfg: kafka.Config{SASL: kafka.SASLConfig{Enabled: true, Mechanism: "GSSAPI", User: "u", Password: "p"}}},
+		{name: "missing credentials", cfg: kafka.Config{SASL: kafka.SASLConfig{Enabled: true}}},
+		{name: "invalid version", cfg: kafka.Config{Version: "latest"}},
+		{name: "missing ca file", cfg: kafka.Config{TLS: kafka.TLSConfig{Enabled: true, CAFile: "/nonexistent/ca.pem"}}},
+		{name: "cert without key", cfg: kafka.Config{TLS: kafka.TLSConfig{Enabled: true, CertFile: "cert.pem"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cfg.SaramaConfig()
+			require.Error(t, err)
+		})
+	}
+}
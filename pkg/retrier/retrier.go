@@ -52,6 +52,7 @@ func NewRetrier(opts ...Options) *Retrier {
 	return retrier
 }
 
+// Wrap calls f until it succeeds or the attempts run out, the retries stop when the context is done.
 func (r *Retrier) Wrap(ctx context.Context, name string, f func() error) (err error) {
 	logger := log.Ctx(ctx).With().Str("name", name).Logger()
 
@@ -65,7 +66,11 @@ func (r *Retrier) Wrap(ctx context.Context, name string, f func() error) (err er
 		logger.Warn().Err(err).Msg("execution failed")
 
 		if i != r.policy.MaxAttempts {
-			time.Sleep(delay)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(delay):
+			}
 			delay = time.Duration(float32(delay) * r.policy.BackoffCoefficient)
 			if r.policy.MaxDelay != nil && delay > *r.policy.MaxDelay {
 				delay = *r.policy.MaxDelay
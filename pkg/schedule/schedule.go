@@ -0,0 +1,177 @@
+// Package schedule parses the cron expressions of five fields: minute, hour, day of month, month and day of week.
+// A field is a list of values, ranges and steps, e.g. "0", "*/15", "1-5" or "0,30". The day of week is 0-7,
+// both 0 and 7 are Sunday. The times are matched in UTC.
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// maxYears bounds the search of the next time, a schedule matching nothing in it never fires, e.g. "0 0 30 2 *".
+const maxYears = 5
+
+var macros = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = [5]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bit sets of the matching values
+
+	// The days match both the day of month and the day of week if one of them is "*", either of them otherwise.
+	domAny, dowAny bool
+}
+
+// Parse parses the cron expression or one of the macros @yearly, @monthly, @weekly, @daily and @hourly.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, errors.Errorf("expected %d fields, got %d", len(fields), len(parts))
+	}
+
+	var sets [5]uint64
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, errors.Wrap(err, fields[i].name)
+		}
+		sets[i] = set
+	}
+
+	// Sunday is both 0 and 7
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Schedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: parts[2] == "*", dowAny: parts[4] == "*",
+	}, nil
+}
+
+// Next returns the first time after t matching the schedule, the zero time if there is none in the next years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxYears
+
+	for t.Year() <= limit {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+
+		if !has(s.hour, t.Hour()) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// parseField parses the comma separated list of "*", values and ranges with optional steps.
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q", stepExpr)
+			}
+		}
+
+		from, to := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			fromExpr, toExpr, _ := strings.Cut(rangeExpr, "-")
+
+			var err error
+			if from, err = parseValue(fromExpr, f); err != nil {
+				return 0, err
+			}
+			if to, err = parseValue(toExpr, f); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, errors.Errorf("invalid range %q", rangeExpr)
+			}
+		default:
+			value, err := parseValue(rangeExpr, f)
+			if err != nil {
+				return 0, err
+			}
+
+			// a value with a step starts the range, e.g. "5/15" is "5-59/15"
+			from = value
+			if !hasStep {
+				to = value
+			}
+		}
+
+		for value := from; value <= to; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+func parseValue(expr string, f field) (int, error) {
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, errors.Errorf("invalid value %q, expected %d-%d", expr, f.min, f.max)
+	}
+	return value, nil
+}
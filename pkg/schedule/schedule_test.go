@@ -0,0 +1,53 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/pkg/schedule"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	from := time.Date(2025, 1, 1, 12, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{expr: "* * * * *", next: time.Date(2025, 1, 1, 12, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", next: time.Date(2025, 1, 1, 12, 45, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", next: time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{expr: "@daily", next: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "0,30 12 * * *", next: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 9-17/4 * * 1-5", next: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", next: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", next: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 15 * 1", next: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", next: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := schedule.Parse(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.next, s.Next(from))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := schedule.Parse(expr)
+		require.Error(t, err, expr)
+	}
+}
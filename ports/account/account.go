@@ -14,11 +14,17 @@ import (
 
 var _ ports.AccountServicePort = (*Account)(nil)
 
+const (
+	defaultTransactionLimit = 100
+	maxTransactionLimit     = 1000
+)
+
 type Account struct {
 	tx            ports.DatabaseWithinTransactionPort
 	walletManager ports.WalletPort
 	database      ports.DatabasePort
 	eventManager  ports.OutboxMessagePort
+	ledger        ports.LedgerServicePort
 	genAccountID  func() string
 }
 
@@ -34,6 +40,7 @@ func New(options Options) *Account {
 		tx:            options.TxManager,
 		database:      options.DatabaseManager,
 		eventManager:  options.EventManager,
+		ledger:        options.Ledger,
 		genAccountID:  uuid.NewString,
 	}
 }
@@ -69,7 +76,7 @@ func (a *Account) CreateAccount(ctx context.Context, accountID string) (*model.A
 			return errors.Wrap(err, "update account")
 		}
 
-		if err = a.eventManager.Publish(ctx, model.AccountCreated, account); err != nil {
+		if err = a.eventManager.Publish(ctx, model.AccountCreated, model.NewAccountCreatedPayload(account)); err != nil {
 			return errors.Wrap(err, "publish event")
 		}
 		return nil
@@ -81,30 +88,37 @@ func (a *Account) CreateAccount(ctx context.Context, accountID string) (*model.A
 	return account, nil
 }
 
-func (a *Account) GetBalance(ctx context.Context, accountID string) ([]model.Balance, error) {
+// GetBalance returns the custodial balances of the account from the ledger
+// and the balances of its wallet on chain, which drop once the funds are swept.
+func (a *Account) GetBalance(ctx context.Context, accountID string) (*model.AccountBalance, error) {
 	var walletID uint32
 	var err error
 
-	if accountID != "" && accountID != "master" {
+	if accountID != "" && accountID != model.MasterAccountID {
 		walletID, err = a.database.GetWalletIDByAccountID(ctx, accountID)
 		if err != nil {
 			return nil, errors.Wrap(err, "get wallet id by account id")
 		}
 	}
 
-	var balances []model.Balance
+	ledgerBalances, err := a.ledger.GetBalance(ctx, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get ledger balance")
+	}
+
+	var onChain []model.Balance
 	balance, err := a.walletManager.GetBalance(ctx, walletID)
 	if err != nil {
 		return nil, errors.Wrap(err, "get balance")
 	}
-	balances = append(balances, balance)
+	onChain = append(onChain, balance)
 
 	extraBalance, err := a.walletManager.GetExtraCurrenciesBalance(ctx, walletID)
 	if err != nil {
 		return nil, errors.Wrap(err, "get extra balance")
 	}
-	balances = append(balances, extraBalance...)
-	return balances, nil
+	onChain = append(onChain, extraBalance...)
+	return &model.AccountBalance{Ledger: ledgerBalances, OnChain: onChain}, nil
 }
 
 func (a *Account) CloseAccount(ctx context.Context, accountID string) error {
@@ -113,7 +127,7 @@ func (a *Account) CloseAccount(ctx context.Context, accountID string) error {
 			return errors.Wrap(err, "close account")
 		}
 
-		if err := a.eventManager.Publish(ctx, model.AccountClosed, accountID); err != nil {
+		if err := a.eventManager.Publish(ctx, model.AccountClosed, model.AccountClosedPayload{AccountID: accountID}); err != nil {
 			return errors.Wrap(err, "publish event")
 		}
 		return nil
@@ -129,5 +143,62 @@ func (a *Account) MasterAccount(ctx context.Context) (*model.Account, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "get master wallet address")
 	}
-	return &model.Account{ID: "master", Address: masterWallet.WalletAddress(), WalletID: 0}, nil
+	return &model.Account{ID: model.MasterAccountID, Address: masterWallet.WalletAddress(), WalletID: 0}, nil
+}
+
+// ListTransactions returns a page of the stored transactions matching the filter, the limit defaults
+// to defaultTransactionLimit and is capped at maxTransactionLimit.
+func (a *Account) ListTransactions(ctx context.Context, filter model.ListTransactionsFilter) (*model.TransactionPage, error) {
+	if filter.AccountID != nil && *filter.AccountID != model.MasterAccountID {
+		exists, err := a.database.IsAccountExists(ctx, *filter.AccountID)
+		if err != nil {
+			return nil, errors.Wrap(err, "check account exists")
+		}
+
+		if !exists {
+			return nil, model.ErrAccountNotFound
+		}
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransactionLimit
+	}
+	filter.Limit = min(filter.Limit, maxTransactionLimit)
+
+	limit := filter.Limit
+	filter.Limit++ // one more transaction tells whether there is a next page
+
+	transactions, err := a.database.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "list transactions")
+	}
+
+	page := &model.TransactionPage{Transactions: transactions}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		page.NextCursor = model.EncodeTransactionCursor(page.Transactions[limit-1].ID)
+	}
+	return page, nil
+}
+
+// GetTransaction returns the stored transaction by its hash or by the account and logical time.
+func (a *Account) GetTransaction(ctx context.Context, query model.GetTransactionQuery) (*model.Transaction, error) {
+	var (
+		tx  *model.Transaction
+		err error
+	)
+
+	switch {
+	case query.Hash != "":
+		tx, err = a.database.GetTransactionByHash(ctx, query.Hash)
+	case query.AccountID != "" && query.LT != 0:
+		tx, err = a.database.GetTransactionByLT(ctx, query.AccountID, query.LT)
+	default:
+		return nil, model.ErrInvalidTransactionQuery
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "get transaction")
+	}
+	return tx, nil
 }
@@ -2,8 +2,11 @@ package account_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
@@ -13,6 +16,8 @@ import (
 
 var _ model.WalletWrapper = (*TestWalletWrapper)(nil)
 
+var errPublish = errors.New("publish failed")
+
 func TestAccount_CreateAccount(t *testing.T) {
 	type isAccountExistsMock struct {
 		callCount     int
@@ -91,10 +96,40 @@ func TestAccount_CreateAccount(t *testing.T) {
 			publishEventMock: publishEventMock{
 				callCount: 1,
 				eventType: model.AccountCreated,
-				payload:   &model.Account{ID: "test-account", WalletID: 10, Address: "test-address"},
+				payload:   model.AccountCreatedPayload{AccountID: "test-account", WalletID: 10, Address: "test-address"},
 			},
 			expectedResult: &model.Account{ID: "test-account", WalletID: 10, Address: "test-address"},
 		},
+		{
+			name:              "publish event error rolls back account creation",
+			accountID:         "test-account",
+			expectedError:     errPublish,
+			withInTransaction: withInTransaction{callCount: 1},
+			isAccountExistsMock: isAccountExistsMock{
+				callCount: 1,
+				accountID: "test-account",
+			},
+			insertAccountMock: insertAccountMock{
+				callCount: 1,
+				accountID: "test-account",
+				result:    &model.Account{ID: "test-account", WalletID: 10},
+			},
+			createWalletMock: createWalletMock{
+				callCount: 1,
+				walletID:  10,
+				result:    &TestWalletWrapper{address: model.Address("test-address")},
+			},
+			updateAccountMock: updateAccountMock{
+				callCount: 1,
+				account:   &model.Account{ID: "test-account", WalletID: 10, Address: "test-address"},
+			},
+			publishEventMock: publishEventMock{
+				callCount:     1,
+				eventType:     model.AccountCreated,
+				payload:       model.AccountCreatedPayload{AccountID: "test-account", WalletID: 10, Address: "test-address"},
+				expectedError: errPublish,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -105,7 +140,9 @@ func TestAccount_CreateAccount(t *testing.T) {
 			mockTx := portsmocks.NewMockDatabaseTransactionPort(t)
 			if tt.withInTransaction.callCount > 0 {
 				param := tt.withInTransaction
-				mockTx.On("WithInTransaction", ctx).Return(context.Background(), param.expectedError).Times(param.callCount)
+				mockTx.On("WithInTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).
+					Times(param.callCount)
 			}
 
 			// Database manager mock
@@ -167,3 +204,139 @@ func (t *TestWalletWrapper) WalletAddress() model.Address {
 func (t *TestWalletWrapper) ToAccount() *model.Account {
 	return &model.Account{Address: t.address}
 }
+
+func TestAccount_ListTransactions(t *testing.T) {
+	stored := func(ids ...int64) []*model.Transaction {
+		transactions := make([]*model.Transaction, 0, len(ids))
+		for _, id := range ids {
+			transactions = append(transactions, &model.Transaction{ID: id})
+		}
+		return transactions
+	}
+
+	tests := []struct {
+		name          string
+		filter        model.ListTransactionsFilter
+		exists        *bool
+		queriedLimit  int
+		stored        []*model.Transaction
+		count         int
+		nextCursor    string
+		expectedError error
+	}{
+		{
+			name:         "next page",
+			filter:       model.ListTransactionsFilter{AccountID: lo.ToPtr("acc"), Limit: 2},
+			exists:       lo.ToPtr(true),
+			queriedLimit: 3,
+			stored:       stored(9, 7, 5),
+			count:        2,
+			nextCursor:   model.EncodeTransactionCursor(7),
+		},
+		{
+			name:         "last page with the default limit",
+			queriedLimit: 101,
+			stored:       stored(9),
+			count:        1,
+		},
+		{
+			name:         "limit is capped",
+			filter:       model.ListTransactionsFilter{Limit: 5000},
+			queriedLimit: 1001,
+		},
+		{
+			name:          "unknown account",
+			filter:        model.ListTransactionsFilter{AccountID: lo.ToPtr("unknown")},
+			exists:        lo.ToPtr(false),
+			expectedError: model.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := portsmocks.NewMockDatabasePort(t)
+			if tt.exists != nil {
+				mockDB.On("IsAccountExists", mock.Anything, *tt.filter.AccountID).Return(*tt.exists, nil).Once()
+			}
+
+			if tt.queriedLimit > 0 {
+				mockDB.On("ListTransactions", mock.Anything, mock.MatchedBy(func(filter model.ListTransactionsFilter) bool {
+					return filter.Limit == tt.queriedLimit
+				})).Return(tt.stored, nil).Once()
+			}
+
+			accountService := account.New(account.Options{
+				WalletManager:   portsmocks.NewMockWalletPort(t),
+				TxManager:       portsmocks.NewMockDatabaseTransactionPort(t),
+				DatabaseManager: mockDB,
+			})
+
+			page, err := accountService.ListTransactions(context.Background(), tt.filter)
+			require.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				require.Len(t, page.Transactions, tt.count)
+				require.Equal(t, tt.nextCursor, page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestAccount_GetTransaction(t *testing.T) {
+	stored := &model.Transaction{ID: 7, Hash: "0102", AccountID: "acc", LT: 11}
+
+	tests := []struct {
+		name          string
+		query         model.GetTransactionQuery
+		mock          func(mockDB *portsmocks.MockDatabasePort)
+		expectedError error
+	}{
+		{
+			name:  "by hash",
+			query: model.GetTransactionQuery{Hash: "0102", AccountID: "other", LT: 5},
+			mock: func(mockDB *portsmocks.MockDatabasePort) {
+				mockDB.On("GetTransactionByHash", mock.Anything, "0102").Return(stored, nil).Once()
+			},
+		},
+		{
+			name:  "by account and logical time",
+			query: model.GetTransactionQuery{AccountID: "acc", LT: 11},
+			mock: func(mockDB *portsmocks.MockDatabasePort) {
+				mockDB.On("GetTransactionByLT", mock.Anything, "acc", int64(11)).Return(stored, nil).Once()
+			},
+		},
+		{
+			name:  "not found",
+			query: model.GetTransactionQuery{Hash: "0303"},
+			mock: func(mockDB *portsmocks.MockDatabasePort) {
+				mockDB.On("GetTransactionByHash", mock.Anything, "0303").Return(nil, model.ErrTransactionNotFound).Once()
+			},
+			expectedError: model.ErrTransactionNotFound,
+		},
+		{
+			name:          "logical time without the account",
+			query:         model.GetTransactionQuery{LT: 11},
+			expectedError: model.ErrInvalidTransactionQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := portsmocks.NewMockDatabasePort(t)
+			if tt.mock != nil {
+				tt.mock(mockDB)
+			}
+
+			accountService := account.New(account.Options{
+				WalletManager:   portsmocks.NewMockWalletPort(t),
+				TxManager:       portsmocks.NewMockDatabaseTransactionPort(t),
+				DatabaseManager: mockDB,
+			})
+
+			tx, err := accountService.GetTransaction(context.Background(), tt.query)
+			require.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				require.Equal(t, stored, tx)
+			}
+		})
+	}
+}
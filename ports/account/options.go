@@ -5,6 +5,7 @@ import (
 
 	"github.com/kriuchkov/tonbeacon/core/model"
 	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
 )
 
 type Options struct {
@@ -12,12 +13,17 @@ type Options struct {
 	TxManager       ports.DatabaseTransactionPort `required:"true"`
 	DatabaseManager ports.DatabasePort            `required:"true"`
 	EventManager    ports.OutboxMessagePort
+	Ledger          ports.LedgerServicePort
 }
 
 func (o *Options) SetDefaults() {
 	if o.EventManager == nil {
 		o.EventManager = &emptyOutboxMessagePort{}
 	}
+
+	if o.Ledger == nil {
+		o.Ledger = ledger.New(&ledger.Options{Database: o.DatabaseManager, Withdrawals: o.DatabaseManager, TxManager: o.TxManager})
+	}
 }
 
 type emptyOutboxMessagePort struct{}
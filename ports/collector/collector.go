@@ -4,70 +4,672 @@ import (
 	"context"
 	"time"
 
+	"github.com/go-faster/errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
 	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/pkg/schedule"
 )
 
-const defaultCollectInterval = 10 * time.Millisecond
+const (
+	// defaultCollectInterval is the default interval between the collection runs.
+	defaultCollectInterval = time.Minute
+
+	// defaultConfirmTimeout is how long after the expiration of its message a sweep waits for the transaction
+	// processor before the transactions of the wallet are checked.
+	defaultConfirmTimeout = 5 * time.Minute
+
+	// defaultBatchSize is the number of accounts and sweep jobs listed at once.
+	defaultBatchSize = 500
+
+	// defaultSweepFee is the estimated fee of a sweep transaction, 0.005 TON.
+	defaultSweepFee = 5_000_000
+)
+
+var _ ports.CollectorServicePort = (*CollectorService)(nil)
 
 type Options struct {
-	WalletPort      ports.WalletPort          `required:"true"`
-	RepositoryPort  ports.AccountDatabasePort `required:"true"`
+	WalletPort     ports.WalletPort                    `validate:"required"`
+	RepositoryPort ports.AccountDatabasePort           `validate:"required"`
+	TransferPort   ports.OutgoingTransferDatabasePort  `validate:"required"`
+	SweepPort      ports.SweepJobDatabasePort          `validate:"required"`
+	TxPort         ports.DatabaseWithinTransactionPort `validate:"required"`
+	// Seqnos reserves the seqnos of the master wallet signing the top-ups, it is shared with the withdrawer
+	// and the rebalancer.
+	Seqnos ports.SeqnoDatabasePort `validate:"required"`
+	Events ports.OutboxMessagePort
+	// Policies override the threshold and the reserve per account and currency, see model.SweepPolicy.
+	Policies ports.SweepPolicyDatabasePort
+
+	// Threshold is the balance of a subwallet above which it is swept.
+	Threshold model.Amount
+	// Reserve is left on the subwallet to pay the storage and the gas of the sweep.
+	Reserve model.Amount
+
+	// Jettons are the jettons swept from the subwallets.
+	Jettons []Jetton `validate:"dive"`
+	// JettonGas is attached to a jetton transfer, the subwallet is topped up by the master wallet
+	// to hold it with the reserve.
+	JettonGas model.Amount
+	// SweepFee is the estimated fee of a sweep or a top-up transaction, it is only reported by PlanSweeps.
+	SweepFee model.Amount
+
 	CollectInterval time.Duration
+	ConfirmTimeout  time.Duration
+	BatchSize       int
 }
 
 func (o *Options) SetDefaults() {
 	if o.CollectInterval == 0 {
 		o.CollectInterval = defaultCollectInterval
 	}
+	if o.ConfirmTimeout == 0 {
+		o.ConfirmTimeout = defaultConfirmTimeout
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	if o.JettonGas.Sign() == 0 {
+		o.JettonGas = model.NewAmount(model.DefaultJettonGasNano)
+	}
+	if o.SweepFee.Sign() == 0 {
+		o.SweepFee = model.NewAmount(defaultSweepFee)
+	}
+}
+
+// Jetton is a jetton swept from the subwallets whose balance of it is above the threshold.
+type Jetton struct {
+	Currency  model.Currency `validate:"required"`
+	Threshold model.Amount
 }
 
-type collectorService struct {
-	walletPort ports.WalletPort
-	dbPort     ports.AccountDatabasePort
-	interval   time.Duration
+// CollectorService sweeps the TON and the jettons of the subwallets of the open accounts to the master wallet.
+//
+// Every sweep is a job stored with the signed message and the outgoing transfer of the message before the message
+// is broadcast. The transaction processor confirms the transfer and records the sweep in the ledger, the collector
+// follows the transfer to finish the job, a bounced transfer fails it. The message is broadcast again until it expires,
+// a job whose message expired without being executed fails and the subwallet is swept by a new job.
+// A job whose message was executed without the transfer being confirmed is moved to unconfirmed and published
+// as model.TransferUnconfirmedEvent for an operator to reconcile, so it does not block the account.
+//
+// A subwallet holding jettons without the TON for the gas is topped up by the master wallet first, the top-up
+// is followed the same way and the jetton transfer is signed once it is confirmed. The master wallet signs
+// its messages with its seqno, so one top-up is in flight at a time and its seqno is reserved against
+// the other services sending from the master wallet until its transfer is settled.
+//
+// The sweeps of an account in a currency follow the most specific sweep policy of them, the threshold and
+// the reserve of the options apply to the accounts and the currencies without a policy.
+type CollectorService struct {
+	walletPort     ports.WalletPort
+	dbPort         ports.AccountDatabasePort
+	transfers      ports.OutgoingTransferDatabasePort
+	sweeps         ports.SweepJobDatabasePort
+	txPort         ports.DatabaseWithinTransactionPort
+	seqnos         ports.SeqnoDatabasePort
+	events         ports.OutboxMessagePort
+	policies       ports.SweepPolicyDatabasePort
+	threshold      model.Amount
+	reserve        model.Amount
+	jettons        []Jetton
+	jettonGas      model.Amount
+	sweepFee       model.Amount
+	interval       time.Duration
+	confirmTimeout time.Duration
+	batchSize      int
+	now            func() time.Time
 }
 
-func NewCollectorService(opts *Options) ports.CollectorServicePort {
+func NewCollectorService(opts *Options) *CollectorService {
+	opts.SetDefaults()
+
 	if err := validator.New().Struct(opts); err != nil {
 		log.Panic().Err(err).Msg("invalid options")
 	}
 
-	opts.SetDefaults()
+	if opts.Threshold.Sign() < 0 || opts.Reserve.Sign() < 0 || opts.JettonGas.Sign() < 0 || opts.SweepFee.Sign() < 0 {
+		log.Panic().Msg("threshold, reserve, jetton gas and sweep fee must not be negative")
+	}
+
+	for _, jetton := range opts.Jettons {
+		if jetton.Currency == model.CurrencyTON || jetton.Threshold.Sign() < 0 {
+			log.Panic().Str("currency", string(jetton.Currency)).Msg("invalid jetton")
+		}
+	}
 
-	s := &collectorService{
-		walletPort: opts.WalletPort,
-		dbPort:     opts.RepositoryPort,
+	return &CollectorService{
+		walletPort:     opts.WalletPort,
+		dbPort:         opts.RepositoryPort,
+		transfers:      opts.TransferPort,
+		sweeps:         opts.SweepPort,
+		txPort:         opts.TxPort,
+		seqnos:         opts.Seqnos,
+		events:         opts.Events,
+		policies:       opts.Policies,
+		threshold:      opts.Threshold,
+		reserve:        opts.Reserve,
+		jettons:        opts.Jettons,
+		jettonGas:      opts.JettonGas,
+		sweepFee:       opts.SweepFee,
+		interval:       opts.CollectInterval,
+		confirmTimeout: opts.ConfirmTimeout,
+		batchSize:      opts.BatchSize,
+		now:            time.Now,
 	}
-	return s
 }
 
-func (s *collectorService) Run(ctx context.Context) {
+// Run collects the funds on every tick until the context is done.
+func (s *CollectorService) Run(ctx context.Context) {
 	ticker := time.NewTicker(s.interval)
 	defer ticker.Stop()
 
 	for {
+		if err := s.CollectFunds(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("collect funds")
+		}
+
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			if err := s.CollectFunds(context.Background()); err != nil {
-				log.Warn().Err(err).Msg("collect funds")
+		case <-ticker.C:
+		}
+	}
+}
+
+// CollectFunds advances the active sweep jobs and plans the sweeps of the other subwallets due by their policies,
+// the failure of a job or a subwallet is logged and does not stop the others.
+func (s *CollectorService) CollectFunds(ctx context.Context) error {
+	active, funding, err := s.reconcile(ctx)
+	if err != nil {
+		return err
+	}
+
+	policies, err := s.listPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter := model.ListAccountFilter{IsClosed: lo.ToPtr(false), Limit: s.batchSize}
+
+	for {
+		accounts, err := s.dbPort.ListAccounts(ctx, filter)
+		if err != nil {
+			return errors.Wrap(err, "list accounts")
+		}
+
+		for _, account := range accounts {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if _, ok := active[account.ID]; ok || account.WalletID == 0 {
+				continue
+			}
+
+			if s.sweepAccount(ctx, account, policies, funding) {
+				funding = true
+			}
+		}
+
+		if len(accounts) < filter.Limit {
+			return nil
+		}
+		filter.Offset += len(accounts)
+	}
+}
+
+// sweepAccount plans the sweep of the TON or the first jetton of the account due by its policy and reports
+// whether the planned sweep waits for a top-up.
+func (s *CollectorService) sweepAccount(
+	ctx context.Context, account model.Account, policies []*model.SweepPolicy, funding bool,
+) bool {
+	logger := log.With().Str("account_id", account.ID).Uint32("wallet_id", account.WalletID).Logger()
+
+	tonPolicy := s.tonPolicy(policies, account.ID)
+
+	var job *model.SweepJob
+	due, err := s.due(ctx, account.ID, model.CurrencyTON, tonPolicy)
+	if err == nil && due {
+		job, err = s.collect(ctx, account, tonPolicy)
+	}
+	if err != nil {
+		logger.Warn().Err(err).Msg("sweep")
+	}
+
+	for _, jetton := range s.jettons {
+		if job != nil {
+			break
+		}
+
+		policy := s.jettonPolicy(policies, account.ID, jetton)
+
+		due, err = s.due(ctx, account.ID, jetton.Currency, policy)
+		if err == nil && due {
+			job, err = s.collectJetton(ctx, account, jetton.Currency, policy, tonPolicy.Reserve, funding)
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("currency", string(jetton.Currency)).Msg("jetton sweep")
+		}
+	}
+	return job != nil && job.Funding()
+}
+
+// due reports whether the policy lets the account be swept in the currency: the policy is not disabled,
+// its minimal interval passed since the last sweep and a time of its schedule passed since the last sweep
+// or the update of the policy.
+func (s *CollectorService) due(
+	ctx context.Context, accountID model.AccountID, currency model.Currency, policy *model.SweepPolicy,
+) (bool, error) {
+	if policy.Disabled {
+		return false, nil
+	}
+
+	if policy.MinInterval == 0 && policy.Schedule == "" {
+		return true, nil
+	}
+
+	now, since := s.now().UTC(), policy.UpdatedAt
+
+	last, err := s.sweeps.GetLastSweepJob(ctx, accountID, currency)
+	switch {
+	case errors.Is(err, model.ErrSweepJobNotFound):
+	case err != nil:
+		return false, errors.Wrap(err, "get last sweep job")
+	default:
+		if now.Before(last.CreatedAt.Add(policy.MinInterval)) {
+			return false, nil
+		}
+
+		if last.CreatedAt.After(since) {
+			since = last.CreatedAt
+		}
+	}
+
+	if policy.Schedule == "" {
+		return true, nil
+	}
+
+	sched, err := schedule.Parse(policy.Schedule)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse schedule of sweep policy %d", policy.ID)
+	}
+
+	next := sched.Next(since)
+	return !next.IsZero() && !next.After(now), nil
+}
+
+// reconcile advances the active jobs and returns the accounts whose jobs are still active,
+// and whether a top-up of the master wallet is in flight.
+func (s *CollectorService) reconcile(ctx context.Context) (map[model.AccountID]struct{}, bool, error) {
+	active := make(map[model.AccountID]struct{})
+	funding := false
+
+	var afterID int64
+	for {
+		jobs, err := s.sweeps.ListActiveSweepJobs(ctx, afterID, s.batchSize)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "list sweep jobs")
+		}
+
+		for _, job := range jobs {
+			if ctx.Err() != nil {
+				return nil, false, ctx.Err()
+			}
+
+			afterID = job.ID
+			if err = s.advance(ctx, job); err != nil {
+				log.Warn().Err(err).Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Msg("advance sweep")
+			}
+
+			if lo.Contains(model.ActiveSweepStatuses, job.Status) {
+				active[job.AccountID] = struct{}{}
 			}
+			funding = funding || job.Funding()
+		}
 
-			<-ticker.C
+		if len(jobs) < s.batchSize {
+			return active, funding, nil
 		}
 	}
 }
 
-func (s *collectorService) CollectFunds(ctx context.Context) error {
-	_, err := s.dbPort.ListAccounts(ctx, model.ListAccountFilter{})
+// advance moves the job after its outgoing transfer, broadcasts its message while it is valid
+// and fails the job whose message expired without being executed. A funded jetton sweep is signed first.
+func (s *CollectorService) advance(ctx context.Context, job *model.SweepJob) error {
+	switch {
+	case job.Funding():
+		return s.advanceTopUp(ctx, job)
+	case !job.Signed():
+		return s.signJetton(ctx, job)
+	}
+
+	transfer, err := s.transfers.GetOutgoingTransferByMessageHash(ctx, job.MessageHash)
+	if err != nil {
+		return errors.Wrap(err, "get outgoing transfer")
+	}
+
+	switch transfer.Status {
+	case model.TransferConfirmed:
+		return s.finish(ctx, job, model.SweepConfirmed, "")
+	case model.TransferBounced:
+		return s.finish(ctx, job, model.SweepFailed, "transfer bounced")
+	case model.TransferFailed:
+		return s.finish(ctx, job, model.SweepFailed, "transaction failed")
+	case model.TransferSent:
+	}
+
+	now := s.now().UTC()
+	if now.Before(job.ExpiresAt) {
+		return s.broadcast(ctx, job, job.Message(), model.SweepSent)
+	}
+
+	if now.Before(job.ExpiresAt.Add(s.confirmTimeout)) {
+		return nil
+	}
+
+	executed, err := s.walletPort.IsMessageExecuted(ctx, job.Message())
+	if err != nil {
+		return errors.Wrap(err, "check sweep message")
+	}
+
+	if executed {
+		log.Error().Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Str("message_hash", job.MessageHash).
+			Msg("sweep is executed but not confirmed by the transaction processor")
+		return s.unconfirmed(ctx, job, transfer, "sweep executed but not confirmed")
+	}
+
+	log.Error().Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Str("message_hash", job.MessageHash).
+		Msg("sweep message expired")
+	return s.expire(ctx, job, transfer)
+}
+
+// advanceTopUp follows the top-up of the job like advance follows its message, the job is funded
+// once the top-up is confirmed.
+func (s *CollectorService) advanceTopUp(ctx context.Context, job *model.SweepJob) error {
+	transfer, err := s.transfers.GetOutgoingTransferByMessageHash(ctx, job.TopUp.MessageHash)
+	if err != nil {
+		return errors.Wrap(err, "get top-up transfer")
+	}
+
+	switch transfer.Status {
+	case model.TransferConfirmed:
+		return s.fund(ctx, job)
+	case model.TransferBounced:
+		return s.finish(ctx, job, model.SweepFailed, "top-up bounced")
+	case model.TransferFailed:
+		return s.finish(ctx, job, model.SweepFailed, "top-up failed")
+	case model.TransferSent:
+	}
+
+	now := s.now().UTC()
+	if now.Before(job.TopUp.ExpiresAt) {
+		return s.broadcast(ctx, job, job.TopUp, model.SweepFunding)
+	}
+
+	if now.Before(job.TopUp.ExpiresAt.Add(s.confirmTimeout)) {
+		return nil
+	}
+
+	executed, err := s.walletPort.IsMessageExecuted(ctx, job.TopUp)
+	if err != nil {
+		return errors.Wrap(err, "check top-up message")
+	}
+
+	if executed {
+		log.Error().Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Str("message_hash", job.TopUp.MessageHash).
+			Msg("top-up is executed but not confirmed by the transaction processor")
+		return s.unconfirmed(ctx, job, transfer, "top-up executed but not confirmed")
+	}
+
+	log.Error().Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Str("message_hash", job.TopUp.MessageHash).
+		Msg("top-up message expired")
+	return s.expire(ctx, job, transfer)
+}
+
+// fund moves the job with the confirmed top-up to funded and signs its jetton transfer.
+func (s *CollectorService) fund(ctx context.Context, job *model.SweepJob) error {
+	from, now := job.Status, s.now().UTC()
+
+	if job.Status == model.SweepPlanned {
+		if err := job.Transition(model.SweepFunding, now); err != nil {
+			return err
+		}
+	}
+
+	if err := job.Transition(model.SweepFunded, now); err != nil {
+		return err
+	}
+
+	if err := s.sweeps.UpdateSweepJob(ctx, job, from); err != nil {
+		return errors.Wrap(err, "update sweep job to funded")
+	}
+	return s.signJetton(ctx, job)
+}
+
+// signJetton signs the jetton transfer of the funded job, stores it with its outgoing transfer and broadcasts it.
+func (s *CollectorService) signJetton(ctx context.Context, job *model.SweepJob) error {
+	message, err := s.walletPort.PrepareJettonTransferToMainWallet(ctx, job.WalletID, job.Currency, job.Amount, job.Gas)
+	if err != nil {
+		return errors.Wrap(err, "prepare jetton transfer")
+	}
+
+	job.SetMessage(message)
+	job.UpdatedAt = s.now().UTC()
+
+	err = s.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.sweeps.UpdateSweepJob(ctx, job, job.Status); err != nil {
+			return errors.Wrap(err, "update sweep job")
+		}
+
+		if _, err := s.transfers.InsertOutgoingTransfer(ctx, job.Transfer()); err != nil {
+			return errors.Wrap(err, "register transfer")
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	return s.broadcast(ctx, job, job.Message(), model.SweepSent)
+}
+
+// broadcast sends the message of the job and moves the job to the status of the sent message,
+// the wallet executes the message once however many times it is sent.
+func (s *CollectorService) broadcast(
+	ctx context.Context, job *model.SweepJob, message *model.WalletMessage, sent model.SweepStatus,
+) error {
+	from := job.Status
+	job.Attempts++
+	job.UpdatedAt = s.now().UTC()
+
+	sendErr := s.walletPort.SendWalletMessage(ctx, message)
+	if sendErr != nil {
+		job.Error = sendErr.Error()
+	} else {
+		job.Error = ""
+		if job.Status != sent {
+			if err := job.Transition(sent, job.UpdatedAt); err != nil {
+				return err
+			}
+		}
+	}
 
+	if err := s.sweeps.UpdateSweepJob(ctx, job, from); err != nil {
+		return errors.Wrap(err, "update sweep job")
+	}
+	return errors.Wrap(sendErr, "send sweep message")
+}
+
+// finish moves the job to the final status of its transfer, a planned or funded job was sent if the transfer
+// of its message is known.
+func (s *CollectorService) finish(ctx context.Context, job *model.SweepJob, to model.SweepStatus, reason string) error {
+	from, now := job.Status, s.now().UTC()
+
+	if !job.Funding() && (job.Status == model.SweepPlanned || job.Status == model.SweepFunded) {
+		if err := job.Transition(model.SweepSent, now); err != nil {
+			return err
+		}
+	}
+
+	if err := job.Transition(to, now); err != nil {
+		return err
+	}
+	job.Error = reason
+
+	if err := s.sweeps.UpdateSweepJob(ctx, job, from); err != nil {
+		return errors.Wrapf(err, "update sweep job to %s", to)
+	}
+
+	log.Info().Int64("sweep_id", job.ID).Str("account_id", job.AccountID).Str("status", string(to)).Msg("sweep finished")
 	return nil
 }
+
+// expire fails the job and its transfer together, the failure of the transfer is published as if
+// the transaction processor failed it.
+func (s *CollectorService) expire(ctx context.Context, job *model.SweepJob, transfer *model.OutgoingTransfer) error {
+	return s.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.finish(ctx, job, model.SweepFailed, "message expired"); err != nil {
+			return err
+		}
+
+		if err := transfer.Transition(model.TransferFailed, s.now().UTC()); err != nil {
+			return err
+		}
+
+		if err := s.transfers.UpdateOutgoingTransferStatus(ctx, transfer, model.TransferSent); err != nil {
+			return errors.Wrap(err, "update transfer to failed")
+		}
+
+		if s.events != nil {
+			eventType := model.TransferEventType(transfer.Kind, transfer.Status)
+			if err := s.events.Publish(ctx, eventType, model.NewTransferPayload(transfer)); err != nil {
+				return errors.Wrapf(err, "publish %s", eventType)
+			}
+		}
+		return nil
+	})
+}
+
+// unconfirmed moves the job whose transfer is executed without a confirmation to unconfirmed and publishes
+// the transfer for an operator to reconcile, the transfer stays sent for the transaction processor to confirm it.
+func (s *CollectorService) unconfirmed(
+	ctx context.Context, job *model.SweepJob, transfer *model.OutgoingTransfer, reason string,
+) error {
+	return s.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		from := job.Status
+		if err := job.Transition(model.SweepUnconfirmed, s.now().UTC()); err != nil {
+			return err
+		}
+		job.Error = reason
+
+		if err := s.sweeps.UpdateSweepJob(ctx, job, from); err != nil {
+			return errors.Wrap(err, "update sweep job to unconfirmed")
+		}
+
+		if s.events != nil {
+			if err := s.events.Publish(ctx, model.TransferUnconfirmedEvent, model.NewTransferPayload(transfer)); err != nil {
+				return errors.Wrapf(err, "publish %s", model.TransferUnconfirmedEvent)
+			}
+		}
+		return nil
+	})
+}
+
+// collect plans the sweep of the TON of the account selected by the policy and broadcasts it.
+func (s *CollectorService) collect(ctx context.Context, account model.Account, policy *model.SweepPolicy) (*model.SweepJob, error) {
+	sweep, err := s.selectTON(ctx, account, policy)
+	if err != nil || sweep == nil {
+		return nil, err
+	}
+
+	message, err := s.walletPort.PrepareTransferToMainWallet(ctx, account.WalletID, sweep.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "prepare transfer")
+	}
+
+	job, err := s.plan(ctx, model.NewSweepJob(account.ID, message, s.now().UTC()))
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	log.Info().Str("account_id", account.ID).Str("amount", sweep.Amount.String()).Int64("sweep_id", job.ID).Msg("sweep planned")
+	return job, s.broadcast(ctx, job, job.Message(), model.SweepSent)
+}
+
+// collectJetton plans the sweep of the jetton of the account selected by the policy, the sweep waits
+// for the top-up in flight before it tops up.
+func (s *CollectorService) collectJetton(
+	ctx context.Context, account model.Account, currency model.Currency, policy *model.SweepPolicy,
+	tonReserve model.Amount, funding bool,
+) (*model.SweepJob, error) {
+	sweep, err := s.selectJetton(ctx, account, currency, policy, tonReserve)
+	if err != nil || sweep == nil {
+		return nil, err
+	}
+
+	var topUp *model.WalletMessage
+	if sweep.TopUp.Sign() > 0 {
+		if funding {
+			return nil, nil
+		}
+
+		if topUp, err = s.walletPort.PrepareTopUp(ctx, account.WalletID, sweep.TopUp); err != nil {
+			return nil, errors.Wrap(err, "prepare top-up")
+		}
+	}
+
+	master, err := s.walletPort.MasterWallet(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get master wallet")
+	}
+
+	job, err := s.plan(ctx, model.NewJettonSweepJob(
+		account, string(master.WalletAddress()), model.Balance{Currency: currency, Amount: sweep.Amount}, s.jettonGas, topUp, s.now().UTC(),
+	))
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	log.Info().Str("account_id", account.ID).Str("currency", string(currency)).Str("amount", sweep.Amount.String()).
+		Bool("top_up", topUp != nil).Int64("sweep_id", job.ID).Msg("jetton sweep planned")
+
+	if job.Funding() {
+		return job, s.broadcast(ctx, job, job.TopUp, model.SweepFunding)
+	}
+	return job, s.signJetton(ctx, job)
+}
+
+// plan stores the job and registers the outgoing transfers of its signed messages, so the transactions
+// executing the messages are recognized, the seqno of the master wallet signing the top-up is reserved with them.
+// model.ErrSweepJobExists is ignored, the account is swept by its active job.
+func (s *CollectorService) plan(ctx context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+	err := s.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if job, err = s.sweeps.InsertSweepJob(ctx, job); err != nil {
+			return errors.Wrap(err, "insert sweep job")
+		}
+
+		if job.Signed() {
+			if _, err = s.transfers.InsertOutgoingTransfer(ctx, job.Transfer()); err != nil {
+				return errors.Wrap(err, "register transfer")
+			}
+		}
+
+		if job.TopUp != nil {
+			if err = s.seqnos.ReserveSeqno(ctx, job.TopUp); err != nil {
+				return errors.Wrap(err, "reserve top-up seqno")
+			}
+
+			if _, err = s.transfers.InsertOutgoingTransfer(ctx, job.TopUpTransfer()); err != nil {
+				return errors.Wrap(err, "register top-up transfer")
+			}
+		}
+		return nil
+	})
+
+	if errors.Is(err, model.ErrSweepJobExists) {
+		return nil, nil
+	}
+	return job, err
+}
@@ -0,0 +1,676 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+type collectorMocks struct {
+	wallet    *portsmocks.MockWalletPort
+	database  *portsmocks.MockDatabasePort
+	txPort    *portsmocks.MockDatabaseTransactionPort
+	transfers *portsmocks.MockOutgoingTransferDatabasePort
+	sweeps    *portsmocks.MockSweepJobDatabasePort
+	events    *portsmocks.MockOutboxMessagePort
+	policies  *portsmocks.MockSweepPolicyDatabasePort
+}
+
+func newCollector(t *testing.T, now time.Time, options ...func(opts *Options)) (*CollectorService, collectorMocks) {
+	m := collectorMocks{
+		wallet:    portsmocks.NewMockWalletPort(t),
+		database:  portsmocks.NewMockDatabasePort(t),
+		txPort:    portsmocks.NewMockDatabaseTransactionPort(t),
+		transfers: portsmocks.NewMockOutgoingTransferDatabasePort(t),
+		sweeps:    portsmocks.NewMockSweepJobDatabasePort(t),
+		events:    portsmocks.NewMockOutboxMessagePort(t),
+		policies:  portsmocks.NewMockSweepPolicyDatabasePort(t),
+	}
+	m.txPort.On("WithInTransaction", mock.Anything, mock.Anything).Maybe().
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+
+	opts := &Options{
+		WalletPort:     m.wallet,
+		RepositoryPort: m.database,
+		TransferPort:   m.transfers,
+		SweepPort:      m.sweeps,
+		TxPort:         m.txPort,
+		Seqnos:         m.database,
+		Events:         m.events,
+		Threshold:      model.NewAmount(500),
+		Reserve:        model.NewAmount(100),
+		ConfirmTimeout: 5 * time.Minute,
+		BatchSize:      2,
+	}
+	for _, option := range options {
+		option(opts)
+	}
+
+	collector := NewCollectorService(opts)
+	collector.now = func() time.Time { return now }
+	return collector, m
+}
+
+func TestCollectorService_CollectFunds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	account := model.Account{ID: "1", WalletID: 1, Address: "sub-wallet"}
+	balance := func(nano int64) model.Balance {
+		return model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}
+	}
+	message := &model.WalletMessage{
+		WalletID: 1, From: "0:01", To: "0:02", Currency: model.CurrencyTON, Amount: model.NewAmount(900), Seqno: 3,
+		MessageHash: "hash", ExpiresAt: now.Add(3 * time.Minute),
+	}
+
+	tests := []struct {
+		name string
+		mock func(m collectorMocks)
+	}{
+		{
+			name: "balance above the threshold is planned and sent",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance(1000), nil).Once()
+				m.wallet.On("PrepareTransferToMainWallet", mock.Anything, uint32(1), model.NewAmount(900)).Return(message, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepPlanned && job.Seqno == 3 && job.MessageHash == "hash" && job.Amount.Nano() == "900"
+				})).Return(func(_ context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+					job.ID = 7
+					return job, nil
+				}).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferSweep && transfer.Reference == "7" && transfer.MessageHash == "hash" &&
+						transfer.Status == model.TransferSent
+				})).Return(&model.OutgoingTransfer{ID: 1}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, message).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepSent && job.Attempts == 1 && job.SentAt != nil
+				}), model.SweepPlanned).Return(nil).Once()
+			},
+		},
+		{
+			name: "balance at the threshold is kept",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance(500), nil).Once()
+			},
+		},
+		{
+			name: "active sweep of another collector",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance(1000), nil).Once()
+				m.wallet.On("PrepareTransferToMainWallet", mock.Anything, uint32(1), model.NewAmount(900)).Return(message, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.Anything).Return(nil, model.ErrSweepJobExists).Once()
+			},
+		},
+		{
+			name: "failed broadcast keeps the job planned",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance(1000), nil).Once()
+				m.wallet.On("PrepareTransferToMainWallet", mock.Anything, uint32(1), model.NewAmount(900)).Return(message, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.Anything).
+					Return(func(_ context.Context, job *model.SweepJob) (*model.SweepJob, error) { return job, nil }).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.Anything).Return(&model.OutgoingTransfer{}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, message).Return(errors.New("liteserver")).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepPlanned && job.Attempts == 1 && job.Error == "liteserver"
+				}), model.SweepPlanned).Return(nil).Once()
+			},
+		},
+		{
+			name: "failed balance read skips the account",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(model.Balance{}, errors.New("liteserver")).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector, m := newCollector(t, now)
+			m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return(nil, nil).Once()
+			m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool {
+				return filter.IsClosed != nil && !*filter.IsClosed && filter.Limit == 2
+			})).Return([]model.Account{account}, nil).Once()
+			tt.mock(m)
+
+			require.NoError(t, collector.CollectFunds(context.Background()))
+		})
+	}
+}
+
+func TestCollectorService_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := func(status model.SweepStatus, expiresAt time.Time) *model.SweepJob {
+		return &model.SweepJob{
+			ID: 7, AccountID: "1", WalletID: 1, Amount: model.NewAmount(900), Seqno: 3, MessageHash: "hash",
+			ExpiresAt: expiresAt, Status: status,
+		}
+	}
+	transfer := func(status model.TransferStatus) *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{ID: 1, Kind: model.TransferSweep, Reference: "7", AccountID: "1", Status: status}
+	}
+
+	tests := []struct {
+		name   string
+		job    *model.SweepJob
+		active bool
+		mock   func(m collectorMocks)
+	}{
+		{
+			name: "confirmed transfer confirms the job",
+			job:  job(model.SweepSent, now.Add(time.Minute)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferConfirmed), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepConfirmed && job.ConfirmedAt != nil
+				}), model.SweepSent).Return(nil).Once()
+			},
+		},
+		{
+			name: "confirmed transfer of a job planned before a restart",
+			job:  job(model.SweepPlanned, now.Add(-time.Hour)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferConfirmed), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepConfirmed && job.SentAt != nil
+				}), model.SweepPlanned).Return(nil).Once()
+			},
+		},
+		{
+			name: "failed transaction fails the job",
+			job:  job(model.SweepSent, now.Add(time.Minute)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferFailed), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "transaction failed"
+				}), model.SweepSent).Return(nil).Once()
+			},
+		},
+		{
+			name:   "valid message is broadcast again",
+			job:    job(model.SweepSent, now.Add(time.Minute)),
+			active: true,
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.Anything).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepSent && job.Attempts == 1
+				}), model.SweepSent).Return(nil).Once()
+			},
+		},
+		{
+			name:   "expired message waits for the processor",
+			job:    job(model.SweepSent, now.Add(-time.Minute)),
+			active: true,
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferSent), nil).Once()
+			},
+		},
+		{
+			name: "bounced transfer fails the job",
+			job:  job(model.SweepSent, now.Add(time.Minute)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferBounced), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "transfer bounced"
+				}), model.SweepSent).Return(nil).Once()
+			},
+		},
+		{
+			name: "executed message is not failed but unconfirmed",
+			job:  job(model.SweepSent, now.Add(-time.Hour)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "hash"
+				})).Return(true, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepUnconfirmed && job.Error == "sweep executed but not confirmed"
+				}), model.SweepSent).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.TransferUnconfirmedEvent, mock.MatchedBy(func(payload model.TransferPayload) bool {
+					return payload.TransferID == 1 && payload.Status == model.TransferSent
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "expired message fails the job and the transfer",
+			job:  job(model.SweepSent, now.Add(-time.Hour)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "hash"
+				})).Return(false, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "message expired"
+				}), model.SweepSent).Return(nil).Once()
+				m.transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.SweepFailedEvent, mock.AnythingOfType("model.TransferPayload")).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector, m := newCollector(t, now)
+			m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return([]*model.SweepJob{tt.job}, nil).Once()
+			tt.mock(m)
+
+			active, _, err := collector.reconcile(context.Background())
+			require.NoError(t, err)
+
+			_, ok := active["1"]
+			require.Equal(t, tt.active, ok)
+		})
+	}
+}
+
+func TestCollectorService_CollectFundsPages(t *testing.T) {
+	t.Parallel()
+
+	collector, m := newCollector(t, time.Now())
+	m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).
+		Return([]*model.SweepJob{
+			{ID: 3, AccountID: "3", MessageHash: "3", Status: model.SweepConfirmed},
+			{ID: 5, AccountID: "3", MessageHash: "5", Status: model.SweepConfirmed},
+		}, nil).Once()
+	m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(5), 2).Return(nil, nil).Once()
+	m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, mock.Anything).Return(nil, errors.New("db")).Twice()
+
+	m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool { return filter.Offset == 0 })).
+		Return([]model.Account{{ID: "master"}, {ID: "1", WalletID: 1}}, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool { return filter.Offset == 2 })).
+		Return([]model.Account{{ID: "2", WalletID: 2}}, nil).Once()
+
+	m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(model.Balance{}, nil).Once()
+	m.wallet.On("GetBalance", mock.Anything, uint32(2)).Return(model.Balance{}, nil).Once()
+
+	require.NoError(t, collector.CollectFunds(context.Background()))
+}
+
+type testWalletWrapper struct {
+	address model.Address
+}
+
+func (w *testWalletWrapper) WalletAddress() model.Address {
+	return w.address
+}
+
+// withJettons sweeps USDT above 1000 with 300 of gas.
+func withJettons(opts *Options) {
+	opts.Jettons = []Jetton{{Currency: model.CurrencyUSDT, Threshold: model.NewAmount(1000)}}
+	opts.JettonGas = model.NewAmount(300)
+}
+
+func TestCollectorService_CollectJettons(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	account := model.Account{ID: "1", WalletID: 1, Address: "0:01"}
+	ton := func(nano int64) model.Balance {
+		return model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}
+	}
+	usdt := func(nano int64) model.Balance {
+		return model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(nano)}
+	}
+	topUp := &model.WalletMessage{
+		From: "0:02", To: "0:01", Currency: model.CurrencyTON, Amount: model.NewAmount(350), Seqno: 9,
+		MessageHash: "top-up", ExpiresAt: now.Add(3 * time.Minute),
+	}
+	transfer := &model.WalletMessage{
+		WalletID: 1, From: "0:01", To: "0:02", Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000),
+		Gas: model.NewAmount(300), Seqno: 2, MessageHash: "hash", ExpiresAt: now.Add(3 * time.Minute),
+	}
+
+	tests := []struct {
+		name string
+		mock func(m collectorMocks)
+	}{
+		{
+			name: "subwallet without the gas is topped up",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(ton(50), nil).Twice()
+				m.wallet.On("GetJettonBalance", mock.Anything, uint32(1), model.CurrencyUSDT).Return(usdt(5000), nil).Once()
+				m.wallet.On("PrepareTopUp", mock.Anything, uint32(1), model.NewAmount(350)).Return(topUp, nil).Once()
+				m.wallet.On("MasterWallet", mock.Anything).Return(&testWalletWrapper{address: "0:02"}, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepPlanned && job.Currency == model.CurrencyUSDT && job.Amount.Nano() == "5000" &&
+						job.Gas.Nano() == "300" && job.TopUp == topUp && !job.Signed()
+				})).Return(func(_ context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+					job.ID = 7
+					return job, nil
+				}).Once()
+				m.database.On("ReserveSeqno", mock.Anything, topUp).Return(nil).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferTopUp && transfer.Reference == "7" && transfer.MessageHash == "top-up" &&
+						transfer.To == "0:01" && transfer.Amount.Nano() == "350"
+				})).Return(&model.OutgoingTransfer{ID: 1}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, topUp).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunding && job.Attempts == 1
+				}), model.SweepPlanned).Return(nil).Once()
+			},
+		},
+		{
+			name: "top-up waits for the master wallet seqno reserved by another service",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(ton(50), nil).Twice()
+				m.wallet.On("GetJettonBalance", mock.Anything, uint32(1), model.CurrencyUSDT).Return(usdt(5000), nil).Once()
+				m.wallet.On("PrepareTopUp", mock.Anything, uint32(1), model.NewAmount(350)).Return(topUp, nil).Once()
+				m.wallet.On("MasterWallet", mock.Anything).Return(&testWalletWrapper{address: "0:02"}, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.Anything).Return(func(_ context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+					job.ID = 7
+					return job, nil
+				}).Once()
+				m.database.On("ReserveSeqno", mock.Anything, topUp).Return(model.ErrSeqnoReserved).Once()
+			},
+		},
+		{
+			name: "subwallet holding the gas is swept at once",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(ton(450), nil).Twice()
+				m.wallet.On("GetJettonBalance", mock.Anything, uint32(1), model.CurrencyUSDT).Return(usdt(5000), nil).Once()
+				m.wallet.On("MasterWallet", mock.Anything).Return(&testWalletWrapper{address: "0:02"}, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunded && job.TopUp == nil
+				})).Return(func(_ context.Context, job *model.SweepJob) (*model.SweepJob, error) {
+					job.ID = 7
+					return job, nil
+				}).Once()
+				m.wallet.On("PrepareJettonTransferToMainWallet", mock.Anything, uint32(1), model.CurrencyUSDT, model.NewAmount(5000), model.NewAmount(300)).
+					Return(transfer, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunded && job.MessageHash == "hash" && job.Seqno == 2
+				}), model.SweepFunded).Return(nil).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferSweep && transfer.Currency == model.CurrencyUSDT && transfer.Gas.Nano() == "300" &&
+						transfer.MessageHash == "hash"
+				})).Return(&model.OutgoingTransfer{ID: 2}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, transfer).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepSent && job.Attempts == 1
+				}), model.SweepFunded).Return(nil).Once()
+			},
+		},
+		{
+			name: "jetton balance at the threshold is kept",
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(ton(50), nil).Once()
+				m.wallet.On("GetJettonBalance", mock.Anything, uint32(1), model.CurrencyUSDT).Return(usdt(1000), nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector, m := newCollector(t, now, withJettons)
+			m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return(nil, nil).Once()
+			m.database.On("ListAccounts", mock.Anything, mock.Anything).Return([]model.Account{account}, nil).Once()
+			tt.mock(m)
+
+			require.NoError(t, collector.CollectFunds(context.Background()))
+		})
+	}
+}
+
+func TestCollectorService_CollectJettonWaitsForTopUp(t *testing.T) {
+	t.Parallel()
+
+	collector, m := newCollector(t, time.Now(), withJettons)
+	m.wallet.On("GetJettonBalance", mock.Anything, uint32(1), model.CurrencyUSDT).
+		Return(model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}, nil).Once()
+	m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(model.Balance{Currency: model.CurrencyTON}, nil).Once()
+
+	policy := &model.SweepPolicy{Threshold: collector.jettons[0].Threshold}
+	job, err := collector.collectJetton(
+		context.Background(), model.Account{ID: "1", WalletID: 1}, model.CurrencyUSDT, policy, collector.reserve, true,
+	)
+	require.NoError(t, err)
+	require.Nil(t, job)
+}
+
+func TestCollectorService_ReconcileJetton(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := func(status model.SweepStatus, expiresAt time.Time) *model.SweepJob {
+		return &model.SweepJob{
+			ID: 7, AccountID: "1", WalletID: 1, Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000),
+			Gas: model.NewAmount(300), Status: status,
+			TopUp: &model.WalletMessage{
+				From: "0:02", To: "0:01", Amount: model.NewAmount(350), Seqno: 9, MessageHash: "top-up", ExpiresAt: expiresAt,
+			},
+		}
+	}
+	transfer := func(status model.TransferStatus) *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{ID: 1, Kind: model.TransferTopUp, Reference: "7", AccountID: "1", Status: status}
+	}
+
+	tests := []struct {
+		name    string
+		job     *model.SweepJob
+		active  bool
+		funding bool
+		mock    func(m collectorMocks)
+	}{
+		{
+			name:   "confirmed top-up funds the job and sends the jetton transfer",
+			job:    job(model.SweepFunding, now.Add(time.Minute)),
+			active: true,
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferConfirmed), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunded && job.FundedAt != nil
+				}), model.SweepFunding).Return(nil).Once()
+				m.wallet.On("PrepareJettonTransferToMainWallet", mock.Anything, uint32(1), model.CurrencyUSDT, model.NewAmount(5000), model.NewAmount(300)).
+					Return(&model.WalletMessage{WalletID: 1, Seqno: 2, MessageHash: "hash"}, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunded && job.MessageHash == "hash"
+				}), model.SweepFunded).Return(nil).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferSweep && transfer.MessageHash == "hash"
+				})).Return(&model.OutgoingTransfer{ID: 2}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.Anything).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepSent
+				}), model.SweepFunded).Return(nil).Once()
+			},
+		},
+		{
+			name:    "valid top-up is broadcast again",
+			job:     job(model.SweepFunding, now.Add(time.Minute)),
+			active:  true,
+			funding: true,
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "top-up"
+				})).Return(nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFunding && job.Attempts == 1
+				}), model.SweepFunding).Return(nil).Once()
+			},
+		},
+		{
+			name: "failed top-up fails the job",
+			job:  job(model.SweepPlanned, now.Add(time.Minute)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferFailed), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "top-up failed" && job.SentAt == nil
+				}), model.SweepPlanned).Return(nil).Once()
+			},
+		},
+		{
+			name: "bounced top-up fails the job",
+			job:  job(model.SweepFunding, now.Add(time.Minute)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferBounced), nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "top-up bounced"
+				}), model.SweepFunding).Return(nil).Once()
+			},
+		},
+		{
+			name: "executed top-up is unconfirmed",
+			job:  job(model.SweepFunding, now.Add(-time.Hour)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "top-up"
+				})).Return(true, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepUnconfirmed && job.Error == "top-up executed but not confirmed"
+				}), model.SweepFunding).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.TransferUnconfirmedEvent, mock.AnythingOfType("model.TransferPayload")).Return(nil).Once()
+			},
+		},
+		{
+			name: "expired top-up fails the job and the transfer",
+			job:  job(model.SweepFunding, now.Add(-time.Hour)),
+			mock: func(m collectorMocks) {
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "top-up").Return(transfer(model.TransferSent), nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "top-up"
+				})).Return(false, nil).Once()
+				m.sweeps.On("UpdateSweepJob", mock.Anything, mock.MatchedBy(func(job *model.SweepJob) bool {
+					return job.Status == model.SweepFailed && job.Error == "message expired"
+				}), model.SweepFunding).Return(nil).Once()
+				m.transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.TopUpFailedEvent, mock.AnythingOfType("model.TransferPayload")).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector, m := newCollector(t, now, withJettons)
+			m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return([]*model.SweepJob{tt.job}, nil).Once()
+			tt.mock(m)
+
+			active, funding, err := collector.reconcile(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.funding, funding)
+
+			_, ok := active["1"]
+			require.Equal(t, tt.active, ok)
+		})
+	}
+}
+
+func TestCollectorService_CollectFundsPolicies(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	account := model.Account{ID: "1", WalletID: 1, Address: "sub-wallet"}
+	balance := model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(1000)}
+	lastSweep := func(at time.Time) *model.SweepJob {
+		return &model.SweepJob{ID: 3, AccountID: "1", Currency: model.CurrencyTON, Status: model.SweepConfirmed, CreatedAt: at}
+	}
+
+	tests := []struct {
+		name     string
+		policies []*model.SweepPolicy
+		mock     func(m collectorMocks)
+	}{
+		{
+			name: "disabled account is not swept",
+			policies: []*model.SweepPolicy{
+				{Threshold: model.NewAmount(0)},
+				{AccountID: "1", Disabled: true},
+			},
+		},
+		{
+			name:     "threshold and reserve of the account",
+			policies: []*model.SweepPolicy{{AccountID: "1", Currency: model.CurrencyTON, Threshold: model.NewAmount(800), Reserve: model.NewAmount(300)}},
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance, nil).Once()
+				m.wallet.On("PrepareTransferToMainWallet", mock.Anything, uint32(1), model.NewAmount(700)).
+					Return(&model.WalletMessage{}, nil).Once()
+				m.sweeps.On("InsertSweepJob", mock.Anything, mock.Anything).Return(nil, model.ErrSweepJobExists).Once()
+			},
+		},
+		{
+			name:     "threshold of the currency is not reached",
+			policies: []*model.SweepPolicy{{Currency: model.CurrencyTON, Threshold: model.NewAmount(2000)}},
+			mock: func(m collectorMocks) {
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance, nil).Once()
+			},
+		},
+		{
+			name:     "min interval since the last sweep did not pass",
+			policies: []*model.SweepPolicy{{MinInterval: time.Hour}},
+			mock: func(m collectorMocks) {
+				m.sweeps.On("GetLastSweepJob", mock.Anything, "1", model.CurrencyTON).Return(lastSweep(now.Add(-30*time.Minute)), nil).Once()
+			},
+		},
+		{
+			name:     "min interval since the last sweep passed",
+			policies: []*model.SweepPolicy{{MinInterval: time.Hour, Threshold: model.NewAmount(2000)}},
+			mock: func(m collectorMocks) {
+				m.sweeps.On("GetLastSweepJob", mock.Anything, "1", model.CurrencyTON).Return(lastSweep(now.Add(-2*time.Hour)), nil).Once()
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance, nil).Once()
+			},
+		},
+		{
+			name:     "scheduled time passed since the last sweep",
+			policies: []*model.SweepPolicy{{Schedule: "@daily", Threshold: model.NewAmount(2000)}},
+			mock: func(m collectorMocks) {
+				m.sweeps.On("GetLastSweepJob", mock.Anything, "1", model.CurrencyTON).Return(lastSweep(now.Add(-13*time.Hour)), nil).Once()
+				m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(balance, nil).Once()
+			},
+		},
+		{
+			name:     "no scheduled time since the last sweep",
+			policies: []*model.SweepPolicy{{Schedule: "@daily"}},
+			mock: func(m collectorMocks) {
+				m.sweeps.On("GetLastSweepJob", mock.Anything, "1", model.CurrencyTON).Return(lastSweep(now.Add(-11*time.Hour)), nil).Once()
+			},
+		},
+		{
+			name:     "no scheduled time since the update of the policy",
+			policies: []*model.SweepPolicy{{Schedule: "@daily", UpdatedAt: now.Add(-time.Hour)}},
+			mock: func(m collectorMocks) {
+				m.sweeps.On("GetLastSweepJob", mock.Anything, "1", model.CurrencyTON).Return(nil, model.ErrSweepJobNotFound).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector, m := newCollector(t, now)
+			collector.policies = m.policies
+			m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return(nil, nil).Once()
+			m.policies.On("ListSweepPolicies", mock.Anything, (*model.AccountID)(nil)).Return(tt.policies, nil).Once()
+			m.database.On("ListAccounts", mock.Anything, mock.Anything).Return([]model.Account{account}, nil).Once()
+			if tt.mock != nil {
+				tt.mock(m)
+			}
+
+			require.NoError(t, collector.CollectFunds(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// PlanSweeps runs the selection of CollectFunds in the current balances without signing or sending anything:
+// the accounts with an active sweep are skipped and an account is swept in the TON or the first of the jettons
+// due by their policies. A jetton sweep waiting for the top-up in flight is planned as if there were none.
+// model.ErrAccountNotFound is returned if one of the accounts is not an open account.
+func (s *CollectorService) PlanSweeps(ctx context.Context, accountIDs []model.AccountID) ([]*model.PlannedSweep, error) {
+	active, err := s.activeAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := s.listPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sweeps []*model.PlannedSweep
+		found  = make(map[model.AccountID]struct{}, len(accountIDs))
+	)
+	filter := model.ListAccountFilter{IsClosed: lo.ToPtr(false), Limit: s.batchSize}
+
+	for {
+		accounts, err := s.dbPort.ListAccounts(ctx, filter)
+		if err != nil {
+			return nil, errors.Wrap(err, "list accounts")
+		}
+
+		for _, account := range accounts {
+			if len(accountIDs) > 0 {
+				if !lo.Contains(accountIDs, account.ID) {
+					continue
+				}
+				found[account.ID] = struct{}{}
+			}
+
+			if _, ok := active[account.ID]; ok || account.WalletID == 0 {
+				continue
+			}
+
+			sweep, err := s.planAccount(ctx, account, policies)
+			if err != nil {
+				return nil, errors.Wrapf(err, "plan sweep of account %s", account.ID)
+			}
+
+			if sweep != nil {
+				sweeps = append(sweeps, sweep)
+			}
+		}
+
+		if len(accounts) < filter.Limit {
+			break
+		}
+		filter.Offset += len(accounts)
+	}
+
+	for _, accountID := range accountIDs {
+		if _, ok := found[accountID]; !ok {
+			return nil, errors.Wrapf(model.ErrAccountNotFound, "account %s", accountID)
+		}
+	}
+	return sweeps, nil
+}
+
+// planAccount selects the sweep of the TON or the first jetton of the account due by its policy like sweepAccount.
+func (s *CollectorService) planAccount(
+	ctx context.Context, account model.Account, policies []*model.SweepPolicy,
+) (*model.PlannedSweep, error) {
+	tonPolicy := s.tonPolicy(policies, account.ID)
+
+	due, err := s.due(ctx, account.ID, model.CurrencyTON, tonPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if due {
+		if sweep, err := s.selectTON(ctx, account, tonPolicy); err != nil || sweep != nil {
+			return sweep, err
+		}
+	}
+
+	for _, jetton := range s.jettons {
+		policy := s.jettonPolicy(policies, account.ID, jetton)
+
+		if due, err = s.due(ctx, account.ID, jetton.Currency, policy); err != nil {
+			return nil, err
+		}
+
+		if !due {
+			continue
+		}
+
+		if sweep, err := s.selectJetton(ctx, account, jetton.Currency, policy, tonPolicy.Reserve); err != nil || sweep != nil {
+			return sweep, err
+		}
+	}
+	return nil, nil
+}
+
+// selectTON selects the sweep of the TON balance of the account subwallet minus the reserve of the policy,
+// nil is returned if the balance is not above the threshold of the policy. The sweep is flagged if the reserve
+// does not cover its fee.
+func (s *CollectorService) selectTON(
+	ctx context.Context, account model.Account, policy *model.SweepPolicy,
+) (*model.PlannedSweep, error) {
+	balance, err := s.walletPort.GetBalance(ctx, account.WalletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	if balance.Amount.Cmp(policy.Threshold) <= 0 {
+		return nil, nil
+	}
+
+	amount := balance.Amount.Sub(policy.Reserve)
+	if amount.Sign() <= 0 {
+		return nil, nil
+	}
+
+	sweep := &model.PlannedSweep{
+		AccountID: account.ID,
+		WalletID:  account.WalletID,
+		Address:   account.Address,
+		Currency:  model.CurrencyTON,
+		Balance:   balance.Amount,
+		Amount:    amount,
+		Fee:       s.sweepFee,
+		Reserve:   policy.Reserve.Sub(s.sweepFee),
+	}
+
+	if sweep.Reserve.Sign() < 0 {
+		sweep.Reserve, sweep.UnableToPayFee = model.NewAmount(0), true
+	}
+	return sweep, nil
+}
+
+// selectJetton selects the sweep of the jetton balance of the account subwallet minus the reserve of the policy,
+// nil is returned if the balance is not above the threshold of the policy. The subwallet is topped up to hold
+// the gas and the TON reserve.
+func (s *CollectorService) selectJetton(
+	ctx context.Context, account model.Account, currency model.Currency, policy *model.SweepPolicy, tonReserve model.Amount,
+) (*model.PlannedSweep, error) {
+	balance, err := s.walletPort.GetJettonBalance(ctx, account.WalletID, currency)
+	if err != nil {
+		return nil, errors.Wrap(err, "get jetton balance")
+	}
+
+	if balance.Amount.Cmp(policy.Threshold) <= 0 {
+		return nil, nil
+	}
+
+	amount := balance.Amount.Sub(policy.Reserve)
+	if amount.Sign() <= 0 {
+		return nil, nil
+	}
+
+	tonBalance, err := s.walletPort.GetBalance(ctx, account.WalletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get balance")
+	}
+
+	sweep := &model.PlannedSweep{
+		AccountID: account.ID,
+		WalletID:  account.WalletID,
+		Address:   account.Address,
+		Currency:  currency,
+		Balance:   balance.Amount,
+		Amount:    amount,
+		Fee:       s.sweepFee.Add(s.jettonGas),
+		Reserve:   policy.Reserve,
+	}
+
+	required := s.jettonGas.Add(tonReserve)
+	if topUp := required.Sub(tonBalance.Amount); topUp.Sign() > 0 {
+		sweep.TopUp = topUp
+		sweep.Fee = sweep.Fee.Add(s.sweepFee)
+	}
+	return sweep, nil
+}
+
+// activeAccounts returns the accounts with an active sweep job without advancing the jobs.
+func (s *CollectorService) activeAccounts(ctx context.Context) (map[model.AccountID]struct{}, error) {
+	active := make(map[model.AccountID]struct{})
+
+	var afterID int64
+	for {
+		jobs, err := s.sweeps.ListActiveSweepJobs(ctx, afterID, s.batchSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "list sweep jobs")
+		}
+
+		for _, job := range jobs {
+			afterID = job.ID
+			active[job.AccountID] = struct{}{}
+		}
+
+		if len(jobs) < s.batchSize {
+			return active, nil
+		}
+	}
+}
+
+// listPolicies returns all the sweep policies, none if the policies are not configured.
+func (s *CollectorService) listPolicies(ctx context.Context) ([]*model.SweepPolicy, error) {
+	if s.policies == nil {
+		return nil, nil
+	}
+
+	policies, err := s.policies.ListSweepPolicies(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "list sweep policies")
+	}
+	return policies, nil
+}
+
+// tonPolicy resolves the TON policy of the account, the threshold and the reserve of the options apply without one.
+func (s *CollectorService) tonPolicy(policies []*model.SweepPolicy, accountID model.AccountID) *model.SweepPolicy {
+	return model.ResolveSweepPolicy(policies, accountID, model.CurrencyTON, &model.SweepPolicy{
+		Threshold: s.threshold, Reserve: s.reserve,
+	})
+}
+
+// jettonPolicy resolves the policy of the account in the jetton, the threshold of the jetton applies without one.
+func (s *CollectorService) jettonPolicy(
+	policies []*model.SweepPolicy, accountID model.AccountID, jetton Jetton,
+) *model.SweepPolicy {
+	return model.ResolveSweepPolicy(policies, accountID, jetton.Currency, &model.SweepPolicy{
+		Threshold: jetton.Threshold,
+	})
+}
@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func TestCollectorService_PlanSweeps(t *testing.T) {
+	t.Parallel()
+
+	withSweepFee := func(opts *Options) { opts.SweepFee = model.NewAmount(10) }
+	collector, m := newCollector(t, time.Now(), withJettons, withSweepFee)
+
+	accounts := []model.Account{
+		{ID: "ton", WalletID: 1, Address: "0:01"},
+		{ID: "jetton", WalletID: 2, Address: "0:02"},
+	}
+	ton := func(nano int64) model.Balance {
+		return model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}
+	}
+
+	m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).
+		Return([]*model.SweepJob{{ID: 4, AccountID: "active"}}, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool { return filter.Offset == 0 })).
+		Return([]model.Account{{ID: "active", WalletID: 3}, {ID: "master", WalletID: 0}}, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool { return filter.Offset == 2 })).
+		Return(accounts, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.MatchedBy(func(filter model.ListAccountFilter) bool { return filter.Offset == 4 })).
+		Return(nil, nil).Once()
+
+	m.wallet.On("GetBalance", mock.Anything, uint32(1)).Return(ton(1000), nil).Once()
+	m.wallet.On("GetBalance", mock.Anything, uint32(2)).Return(ton(50), nil).Twice()
+	m.wallet.On("GetJettonBalance", mock.Anything, uint32(2), model.CurrencyUSDT).
+		Return(model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}, nil).Once()
+
+	sweeps, err := collector.PlanSweeps(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []*model.PlannedSweep{
+		{
+			AccountID: "ton", WalletID: 1, Address: "0:01", Currency: model.CurrencyTON, Balance: model.NewAmount(1000),
+			Amount: model.NewAmount(900), Fee: model.NewAmount(10), Reserve: model.NewAmount(90),
+		},
+		{
+			AccountID: "jetton", WalletID: 2, Address: "0:02", Currency: model.CurrencyUSDT, Balance: model.NewAmount(5000),
+			Amount: model.NewAmount(5000), Fee: model.NewAmount(320), TopUp: model.NewAmount(350), Reserve: model.Amount{},
+		},
+	}, sweeps)
+}
+
+func TestCollectorService_PlanSweepsOfAccounts(t *testing.T) {
+	t.Parallel()
+
+	collector, m := newCollector(t, time.Now())
+
+	m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return(nil, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.Anything).
+		Return([]model.Account{{ID: "1", WalletID: 1}}, nil).Once()
+
+	sweeps, err := collector.PlanSweeps(context.Background(), []model.AccountID{"2"})
+	require.ErrorIs(t, err, model.ErrAccountNotFound)
+	require.Nil(t, sweeps)
+}
+
+func TestCollectorService_PlanSweepsUnableToPayFee(t *testing.T) {
+	t.Parallel()
+
+	withSweepFee := func(opts *Options) { opts.SweepFee = model.NewAmount(150) }
+	collector, m := newCollector(t, time.Now(), withSweepFee)
+
+	m.sweeps.On("ListActiveSweepJobs", mock.Anything, int64(0), 2).Return(nil, nil).Once()
+	m.database.On("ListAccounts", mock.Anything, mock.Anything).
+		Return([]model.Account{{ID: "1", WalletID: 1, Address: "0:01"}}, nil).Once()
+	m.wallet.On("GetBalance", mock.Anything, uint32(1)).
+		Return(model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(1000)}, nil).Once()
+
+	sweeps, err := collector.PlanSweeps(context.Background(), []model.AccountID{"1"})
+	require.NoError(t, err)
+	require.Equal(t, []*model.PlannedSweep{
+		{
+			AccountID: "1", WalletID: 1, Address: "0:01", Currency: model.CurrencyTON, Balance: model.NewAmount(1000),
+			Amount: model.NewAmount(900), Fee: model.NewAmount(150), Reserve: model.NewAmount(0), UnableToPayFee: true,
+		},
+	}, sweeps)
+}
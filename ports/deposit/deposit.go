@@ -0,0 +1,147 @@
+package deposit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+var _ ports.DepositServicePort = (*Deposits)(nil)
+
+type Options struct {
+	Database ports.DepositDatabasePort `validate:"required"`
+	Events   ports.OutboxMessagePort   `validate:"required"`
+	Ledger   ports.LedgerServicePort   `validate:"required"`
+}
+
+// Deposits persists the deposit transitions and publishes them as outbox events.
+// It does not open transactions, the caller runs the transitions within the transaction of the context,
+// so a transition, its event and its ledger entry are committed together.
+type Deposits struct {
+	database ports.DepositDatabasePort
+	events   ports.OutboxMessagePort
+	ledger   ports.LedgerServicePort
+	now      func() time.Time
+}
+
+func New(opts *Options) *Deposits {
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Deposits{
+		database: opts.Database,
+		events:   opts.Events,
+		ledger:   opts.Ledger,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Detect stores the deposit of the incoming transaction, model.ErrDepositExists is returned
+// if the transaction was already detected.
+func (d *Deposits) Detect(ctx context.Context, accountID model.AccountID, tx *model.Transaction) (*model.Deposit, error) {
+	now := d.now()
+
+	deposit, err := d.database.InsertDeposit(ctx, &model.Deposit{
+		AccountID:   accountID,
+		AccountAddr: tx.AccountAddr,
+		LT:          tx.LT,
+		TxHash:      tx.Hash,
+		Sender:      tx.Sender,
+		Currency:    model.CurrencyTON,
+		Amount:      tx.Amount,
+		Comment:     tx.Comment,
+		Status:      model.DepositDetected,
+		DetectedAt:  now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "insert deposit")
+	}
+
+	if err = d.publish(ctx, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+// Confirm marks the deposit as final on chain.
+func (d *Deposits) Confirm(ctx context.Context, deposit *model.Deposit) error {
+	return d.transition(ctx, deposit, model.DepositConfirmed, "")
+}
+
+// Credit records the deposit in the ledger and marks it as credited to the custodial balance.
+func (d *Deposits) Credit(ctx context.Context, deposit *model.Deposit) error {
+	if !deposit.CanTransition(model.DepositCredited) {
+		return errors.Wrapf(model.ErrInvalidDepositTransition, "%s to %s", deposit.Status, model.DepositCredited)
+	}
+
+	amount := model.Balance{Currency: deposit.Currency, Amount: deposit.Amount}
+	if _, err := d.ledger.RecordDeposit(ctx, deposit.AccountID, amount, ledgerReference(deposit)); err != nil {
+		if !errors.Is(err, model.ErrLedgerEntryExists) {
+			return errors.Wrap(err, "record deposit")
+		}
+		log.Debug().Int64("deposit_id", deposit.ID).Msg("deposit already recorded in the ledger")
+	}
+	return d.transition(ctx, deposit, model.DepositCredited, "")
+}
+
+// MarkSwept marks the credited deposit as moved to the master wallet.
+func (d *Deposits) MarkSwept(ctx context.Context, deposit *model.Deposit) error {
+	return d.transition(ctx, deposit, model.DepositSwept, "")
+}
+
+// Bounce marks the deposit whose value was returned to the sender.
+func (d *Deposits) Bounce(ctx context.Context, deposit *model.Deposit, reason string) error {
+	return d.transition(ctx, deposit, model.DepositBounced, reason)
+}
+
+// Reject marks the deposit that is not credited, e.g. because it is below the minimal amount.
+func (d *Deposits) Reject(ctx context.Context, deposit *model.Deposit, reason string) error {
+	return d.transition(ctx, deposit, model.DepositRejected, reason)
+}
+
+func (d *Deposits) transition(ctx context.Context, deposit *model.Deposit, to model.DepositStatus, reason string) error {
+	from := deposit.Status
+
+	next := *deposit
+	if err := next.Transition(to, d.now()); err != nil {
+		return err
+	}
+	next.Reason = reason
+
+	if err := d.database.UpdateDepositStatus(ctx, &next, from); err != nil {
+		return errors.Wrapf(err, "update deposit to %s", to)
+	}
+
+	if err := d.publish(ctx, &next); err != nil {
+		return err
+	}
+
+	*deposit = next
+	log.Debug().Int64("deposit_id", deposit.ID).Str("from", string(from)).Str("to", string(to)).Msg("deposit transition")
+	return nil
+}
+
+func (d *Deposits) publish(ctx context.Context, deposit *model.Deposit) error {
+	eventType := model.DepositEventType(deposit.Status)
+	if err := d.events.Publish(ctx, eventType, model.NewDepositPayload(deposit)); err != nil {
+		return errors.Wrapf(err, "publish %s", eventType)
+	}
+	return nil
+}
+
+// ledgerReference identifies the deposit in the ledger by its transaction.
+func ledgerReference(deposit *model.Deposit) string {
+	if deposit.TxHash != "" {
+		return deposit.TxHash
+	}
+	return deposit.AccountAddr + ":" + strconv.FormatInt(deposit.LT, 10)
+}
@@ -0,0 +1,78 @@
+package deposit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+	"github.com/kriuchkov/tonbeacon/ports/deposit"
+)
+
+func TestDeposits_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tx := &model.Transaction{AccountAddr: "ours", LT: 7, Hash: "0102", Sender: "other", Amount: model.NewAmount(1_500_000_000), Comment: "user-42"}
+
+	database := portsmocks.NewMockDatabasePort(t)
+	database.On("InsertDeposit", ctx, mock.MatchedBy(func(d *model.Deposit) bool {
+		return d.Status == model.DepositDetected && d.TxHash == "0102" && d.Amount.Nano() == "1500000000" && d.Comment == "user-42"
+	})).Return(func(_ context.Context, d *model.Deposit) (*model.Deposit, error) {
+		inserted := *d
+		inserted.ID = 10
+		return &inserted, nil
+	}).Once()
+	database.On("UpdateDepositStatus", ctx, mock.MatchedBy(func(d *model.Deposit) bool {
+		return d.Status == model.DepositConfirmed && d.ConfirmedAt != nil
+	}), model.DepositDetected).Return(nil).Once()
+	database.On("UpdateDepositStatus", ctx, mock.MatchedBy(func(d *model.Deposit) bool {
+		return d.Status == model.DepositCredited && d.CreditedAt != nil
+	}), model.DepositConfirmed).Return(nil).Once()
+
+	events := portsmocks.NewMockOutboxMessagePort(t)
+	for _, eventType := range []model.EventType{model.DepositDetectedEvent, model.DepositConfirmedEvent, model.DepositCreditedEvent} {
+		events.On("Publish", ctx, eventType, mock.MatchedBy(func(p model.DepositPayload) bool {
+			return p.DepositID == 10 && p.AccountID == "1" && p.AggregateID() == "1"
+		})).Return(nil).Once()
+	}
+
+	ledger := portsmocks.NewMockLedgerServicePort(t)
+	ledger.On("RecordDeposit", ctx, "1", model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(1_500_000_000)}, "0102").
+		Return(nil, model.ErrLedgerEntryExists).Once()
+
+	deposits := deposit.New(&deposit.Options{Database: database, Events: events, Ledger: ledger})
+
+	d, err := deposits.Detect(ctx, "1", tx)
+	require.NoError(t, err)
+	require.NoError(t, deposits.Confirm(ctx, d))
+	require.NoError(t, deposits.Credit(ctx, d), "a deposit recorded in the ledger before is credited once")
+	require.Equal(t, model.DepositCredited, d.Status)
+
+	require.ErrorIs(t, deposits.Bounce(ctx, d, "late bounce"), model.ErrInvalidDepositTransition)
+}
+
+func TestDeposits_TransitionConflict(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errConflict := errors.New("conflict")
+
+	database := portsmocks.NewMockDatabasePort(t)
+	database.On("UpdateDepositStatus", ctx, mock.Anything, model.DepositDetected).Return(errConflict).Once()
+
+	deposits := deposit.New(&deposit.Options{
+		Database: database,
+		Events:   portsmocks.NewMockOutboxMessagePort(t),
+		Ledger:   portsmocks.NewMockLedgerServicePort(t),
+	})
+
+	d := &model.Deposit{ID: 10, AccountID: "1", Status: model.DepositDetected}
+	require.ErrorIs(t, deposits.Reject(ctx, d, "too small"), errConflict)
+	require.Equal(t, model.DepositDetected, d.Status, "the deposit is not changed if the transition is not stored")
+	require.Nil(t, d.RejectedAt)
+}
@@ -0,0 +1,301 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+var _ ports.LedgerServicePort = (*Ledger)(nil)
+
+type Options struct {
+	Database ports.LedgerDatabasePort `validate:"required"`
+	// Withdrawals reserve the balance of the accounts until they are settled.
+	Withdrawals ports.WithdrawalDatabasePort        `validate:"required"`
+	TxManager   ports.DatabaseWithinTransactionPort `validate:"required"`
+}
+
+// Ledger records the custodial operations as balanced double-entry postings.
+//
+// Deposits move coins from the outside world to the deposit wallet and owe them to the customer,
+// sweeps move the coins between the wallets and keep the custodial balance intact, network fees
+// are an expense of the custodian, withdrawals settle the custodial balance from the master wallet
+// and transfers move the custodial balance between customers.
+type Ledger struct {
+	database    ports.LedgerDatabasePort
+	withdrawals ports.WithdrawalDatabasePort
+	tx          ports.DatabaseWithinTransactionPort
+}
+
+func New(opts *Options) *Ledger {
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Ledger{database: opts.Database, withdrawals: opts.Withdrawals, tx: opts.TxManager}
+}
+
+// RecordDeposit credits the custodial balance of the account with the coins received by its deposit wallet.
+func (l *Ledger) RecordDeposit(
+	ctx context.Context, accountID model.AccountID, amount model.Balance, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Amount.Sign() <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	return l.record(ctx, model.LedgerDeposit, reference, "deposit to "+accountID,
+		posting(model.WalletLedgerAccount(accountID), amount.Currency, amount.Amount),
+		posting(model.CustomerLedgerAccount(accountID), amount.Currency, amount.Amount.Neg()),
+	)
+}
+
+// RecordSweep moves the coins of the deposit wallet to the master wallet, the network fee paid by the deposit
+// wallet is booked as an expense, so the custodial balance of the account does not change. The fee of a jetton
+// sweep includes the gas attached to the transfer.
+func (l *Ledger) RecordSweep(
+	ctx context.Context, accountID model.AccountID, amount model.Balance, fee model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Amount.Sign() <= 0 || fee.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	postings := []model.LedgerPosting{
+		posting(model.LedgerMasterWallet, amount.Currency, amount.Amount),
+		posting(model.WalletLedgerAccount(accountID), amount.Currency, amount.Amount.Neg()),
+	}
+	postings = append(postings, feePostings(model.WalletLedgerAccount(accountID), fee)...)
+	return l.record(ctx, model.LedgerSweep, reference, "sweep of "+accountID, postings...)
+}
+
+// RecordTopUp moves the coins of the master wallet to the deposit wallet of the account to pay the gas of its
+// jetton sweep, the network fee paid by the master wallet is booked as an expense.
+func (l *Ledger) RecordTopUp(
+	ctx context.Context, accountID model.AccountID, amount model.Amount, fee model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Sign() <= 0 || fee.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	postings := []model.LedgerPosting{
+		posting(model.WalletLedgerAccount(accountID), model.CurrencyTON, amount),
+		posting(model.LedgerMasterWallet, model.CurrencyTON, amount.Neg()),
+	}
+	postings = append(postings, feePostings(model.LedgerMasterWallet, fee)...)
+	return l.record(ctx, model.LedgerTopUp, reference, "top-up of "+accountID, postings...)
+}
+
+// RecordFee books a network fee paid by the deposit wallet of the account, e.g. for its deployment.
+func (l *Ledger) RecordFee(
+	ctx context.Context, accountID model.AccountID, fee model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if fee.Sign() <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	return l.record(ctx, model.LedgerFee, reference, "network fee of "+accountID,
+		feePostings(model.WalletLedgerAccount(accountID), fee)...)
+}
+
+// RecordWithdrawal debits the custodial balance of the account with the coins sent from the master wallet,
+// the network fee of the withdrawal is booked as an expense.
+func (l *Ledger) RecordWithdrawal(
+	ctx context.Context, accountID model.AccountID, amount model.Balance, fee model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Amount.Sign() <= 0 || fee.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	postings := []model.LedgerPosting{
+		posting(model.CustomerLedgerAccount(accountID), amount.Currency, amount.Amount),
+		posting(model.LedgerMasterWallet, amount.Currency, amount.Amount.Neg()),
+	}
+	postings = append(postings, feePostings(model.LedgerMasterWallet, fee)...)
+	return l.record(ctx, model.LedgerWithdrawal, reference, "withdrawal of "+accountID, postings...)
+}
+
+// RecordRebalance moves the coins sent from the master wallet to the cold storage, the custodial balances
+// do not change. The network fee of the rebalance is booked as an expense.
+func (l *Ledger) RecordRebalance(
+	ctx context.Context, amount model.Balance, fee model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Amount.Sign() <= 0 || fee.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	postings := []model.LedgerPosting{
+		posting(model.LedgerColdWallet, amount.Currency, amount.Amount),
+		posting(model.LedgerMasterWallet, amount.Currency, amount.Amount.Neg()),
+	}
+	postings = append(postings, feePostings(model.LedgerMasterWallet, fee)...)
+	return l.record(ctx, model.LedgerRebalance, reference, "rebalance of "+string(amount.Currency), postings...)
+}
+
+// RecordBounce reverses the sweep, withdrawal or rebalance whose transfer was bounced back to the sending wallet.
+// The wallet receives the value returned by the bounce, the rest of the sent value is lost to the network
+// and booked as an expense, the network fee of the original transfer stays booked.
+func (l *Ledger) RecordBounce(
+	ctx context.Context, kind model.TransferKind, accountID model.AccountID, sent model.Balance, returned model.Amount, reference string,
+) (*model.LedgerEntry, error) {
+	if sent.Currency != model.CurrencyTON || sent.Amount.Sign() <= 0 || returned.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	lost := sent.Amount.Add(returned.Neg())
+	if lost.Sign() < 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	var postings []model.LedgerPosting
+	switch kind {
+	case model.TransferSweep:
+		postings = []model.LedgerPosting{
+			posting(model.LedgerMasterWallet, sent.Currency, sent.Amount.Neg()),
+			posting(model.WalletLedgerAccount(accountID), sent.Currency, returned),
+		}
+	case model.TransferWithdrawal:
+		postings = []model.LedgerPosting{
+			posting(model.CustomerLedgerAccount(accountID), sent.Currency, sent.Amount.Neg()),
+			posting(model.LedgerMasterWallet, sent.Currency, returned),
+		}
+	case model.TransferRebalance:
+		postings = []model.LedgerPosting{
+			posting(model.LedgerColdWallet, sent.Currency, sent.Amount.Neg()),
+			posting(model.LedgerMasterWallet, sent.Currency, returned),
+		}
+	default:
+		return nil, errors.Errorf("unknown transfer kind %q", kind)
+	}
+
+	postings = append(postings, posting(model.LedgerNetworkFees, model.CurrencyTON, lost))
+	postings = lo.Filter(postings, func(p model.LedgerPosting, _ int) bool { return p.Amount.Sign() != 0 })
+	return l.record(ctx, model.LedgerBounce, reference, "bounced "+string(kind)+" of "+accountID, postings...)
+}
+
+// Transfer moves the custodial balance between the accounts, model.ErrInsufficientFunds is returned
+// if the balance of the source account without its active withdrawals is lower than the amount.
+func (l *Ledger) Transfer(
+	ctx context.Context, from, to model.AccountID, amount model.Balance, reference string,
+) (*model.LedgerEntry, error) {
+	if amount.Amount.Sign() <= 0 || from == to {
+		return nil, model.ErrInvalidAmount
+	}
+
+	var entry *model.LedgerEntry
+	err := l.tx.WithInTransaction(ctx, func(ctx context.Context) error {
+		source := model.CustomerLedgerAccount(from)
+		if err := l.database.LockLedgerAccount(ctx, source); err != nil {
+			return errors.Wrap(err, "lock ledger account")
+		}
+
+		balances, err := l.GetBalance(ctx, from)
+		if err != nil {
+			return errors.Wrap(err, "get balance")
+		}
+
+		pending, err := l.withdrawals.GetPendingWithdrawalAmount(ctx, from, amount.Currency)
+		if err != nil {
+			return errors.Wrap(err, "get pending withdrawals")
+		}
+
+		balance := balanceOf(balances, amount.Currency)
+		available := balance.Sub(pending)
+		if available.Cmp(amount.Amount) < 0 {
+			return model.ErrInsufficientFunds
+		}
+
+		entry, err = l.record(ctx, model.LedgerTransfer, reference, "transfer from "+from+" to "+to,
+			posting(source, amount.Currency, amount.Amount),
+			posting(model.CustomerLedgerAccount(to), amount.Currency, amount.Amount.Neg()),
+		)
+		return err
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "transfer")
+	}
+	return entry, nil
+}
+
+// GetBalance returns the custodial balances of the account, TON is always present.
+// The balances of the master account are the coins held by the master wallet.
+func (l *Ledger) GetBalance(ctx context.Context, accountID model.AccountID) ([]model.Balance, error) {
+	account, sign := model.CustomerLedgerAccount(accountID), -1
+	if accountID == "" || accountID == model.MasterAccountID {
+		account, sign = model.LedgerMasterWallet, 1
+	}
+
+	sums, err := l.database.GetLedgerBalances(ctx, account)
+	if err != nil {
+		return nil, errors.Wrap(err, "get ledger balances")
+	}
+
+	balances := []model.Balance{{Currency: model.CurrencyTON, Amount: model.NewAmount(0)}}
+	for _, sum := range sums {
+		if sign < 0 {
+			sum.Amount = sum.Amount.Neg()
+		}
+
+		if sum.Currency == model.CurrencyTON {
+			balances[0] = sum
+			continue
+		}
+		balances = append(balances, sum)
+	}
+	return balances, nil
+}
+
+func (l *Ledger) record(
+	ctx context.Context, entryType model.LedgerEntryType, reference, description string, postings ...model.LedgerPosting,
+) (*model.LedgerEntry, error) {
+	entry := &model.LedgerEntry{
+		Type:        entryType,
+		Reference:   reference,
+		Description: description,
+		Postings:    postings,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if !entry.IsBalanced() {
+		return nil, model.ErrLedgerEntryUnbalanced
+	}
+
+	inserted, err := l.database.InsertLedgerEntry(ctx, entry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "insert %s entry", entryType)
+	}
+
+	log.Debug().Str("type", string(entryType)).Str("reference", reference).Int64("id", inserted.ID).Msg("ledger entry recorded")
+	return inserted, nil
+}
+
+func posting(account model.LedgerAccount, currency model.Currency, amount model.Amount) model.LedgerPosting {
+	return model.LedgerPosting{Account: account, Currency: currency, Amount: amount}
+}
+
+// feePostings books the network fee paid in TON by the wallet as an expense.
+func feePostings(wallet model.LedgerAccount, fee model.Amount) []model.LedgerPosting {
+	if fee.Sign() == 0 {
+		return nil
+	}
+
+	return []model.LedgerPosting{
+		posting(model.LedgerNetworkFees, model.CurrencyTON, fee),
+		posting(wallet, model.CurrencyTON, fee.Neg()),
+	}
+}
+
+func balanceOf(balances []model.Balance, currency model.Currency) model.Amount {
+	for _, balance := range balances {
+		if balance.Currency == currency {
+			return balance.Amount
+		}
+	}
+	return model.NewAmount(0)
+}
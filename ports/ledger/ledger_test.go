@@ -0,0 +1,250 @@
+package ledger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+	"github.com/kriuchkov/tonbeacon/ports/ledger"
+)
+
+func ton(nano int64) model.Balance {
+	return model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}
+}
+
+// postingsOf returns the sums of the postings of the entry by ledger account and currency in nano units.
+func postingsOf(entry *model.LedgerEntry) map[string]string {
+	sums := make(map[string]model.Amount, len(entry.Postings))
+	for _, posting := range entry.Postings {
+		key := string(posting.Account) + "/" + posting.Currency.String()
+		sum := sums[key]
+		sums[key] = sum.Add(posting.Amount)
+	}
+
+	result := make(map[string]string, len(sums))
+	for key, sum := range sums {
+		result[key] = sum.Nano()
+	}
+	return result
+}
+
+func TestLedger_Record(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		record        func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error)
+		entryType     model.LedgerEntryType
+		postings      map[string]string
+		expectedError error
+	}{
+		{
+			name: "deposit",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordDeposit(ctx, "acc", ton(5_000), "tx-hash")
+			},
+			entryType: model.LedgerDeposit,
+			postings: map[string]string{
+				"wallet:acc/TON":   "5000",
+				"customer:acc/TON": "-5000",
+			},
+		},
+		{
+			name: "sweep keeps the custodial balance",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordSweep(ctx, "acc", ton(4_000), model.NewAmount(100), "sweep-1")
+			},
+			entryType: model.LedgerSweep,
+			postings: map[string]string{
+				"wallet:master/TON":        "4000",
+				"wallet:acc/TON":           "-4100",
+				"expense:network_fees/TON": "100",
+			},
+		},
+		{
+			name: "rebalance moves the coins to the cold storage",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordRebalance(ctx, ton(9_000), model.NewAmount(30), "rebalance:1")
+			},
+			entryType: model.LedgerRebalance,
+			postings: map[string]string{
+				"wallet:cold/TON":          "9000",
+				"wallet:master/TON":        "-9030",
+				"expense:network_fees/TON": "30",
+			},
+		},
+		{
+			name: "withdrawal in jettons with the fee in TON",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				usdt := model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(7)}
+				return l.RecordWithdrawal(ctx, "acc", usdt, model.NewAmount(50), "withdrawal-1")
+			},
+			entryType: model.LedgerWithdrawal,
+			postings: map[string]string{
+				"customer:acc/USDT":        "7",
+				"wallet:master/USDT":       "-7",
+				"expense:network_fees/TON": "50",
+				"wallet:master/TON":        "-50",
+			},
+		},
+		{
+			name: "top-up pays the gas of the deposit wallet",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordTopUp(ctx, "acc", model.NewAmount(300), model.NewAmount(20), "top_up:1")
+			},
+			entryType: model.LedgerTopUp,
+			postings: map[string]string{
+				"wallet:acc/TON":           "300",
+				"wallet:master/TON":        "-320",
+				"expense:network_fees/TON": "20",
+			},
+		},
+		{
+			name: "fee",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordFee(ctx, "acc", model.NewAmount(30), "deploy-acc")
+			},
+			entryType: model.LedgerFee,
+			postings: map[string]string{
+				"expense:network_fees/TON": "30",
+				"wallet:acc/TON":           "-30",
+			},
+		},
+		{
+			name: "bounced withdrawal restores the custodial balance",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordBounce(ctx, model.TransferWithdrawal, "acc", ton(1_000), model.NewAmount(990), "withdrawal:1")
+			},
+			entryType: model.LedgerBounce,
+			postings: map[string]string{
+				"customer:acc/TON":         "-1000",
+				"wallet:master/TON":        "990",
+				"expense:network_fees/TON": "10",
+			},
+		},
+		{
+			name: "bounced sweep returns to the deposit wallet",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordBounce(ctx, model.TransferSweep, "acc", ton(4_000), model.NewAmount(4_000), "sweep:1")
+			},
+			entryType: model.LedgerBounce,
+			postings: map[string]string{
+				"wallet:master/TON": "-4000",
+				"wallet:acc/TON":    "4000",
+			},
+		},
+		{
+			name: "bounce returns more than sent",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordBounce(ctx, model.TransferSweep, "acc", ton(1_000), model.NewAmount(1_001), "sweep:1")
+			},
+			expectedError: model.ErrInvalidAmount,
+		},
+		{
+			name: "non-positive amount",
+			record: func(ctx context.Context, l *ledger.Ledger) (*model.LedgerEntry, error) {
+				return l.RecordDeposit(ctx, "acc", ton(0), "tx-hash")
+			},
+			expectedError: model.ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			database := portsmocks.NewMockDatabasePort(t)
+			if tt.expectedError == nil {
+				database.On("InsertLedgerEntry", ctx, mock.MatchedBy(func(entry *model.LedgerEntry) bool {
+					return entry.Type == tt.entryType && entry.IsBalanced()
+				})).Return(func(_ context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+					return entry, nil
+				}).Once()
+			}
+
+			l := ledger.New(&ledger.Options{Database: database, Withdrawals: database, TxManager: portsmocks.NewMockDatabaseTransactionPort(t)})
+
+			entry, err := tt.record(ctx, l)
+			require.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				require.Equal(t, tt.postings, postingsOf(entry))
+			}
+		})
+	}
+}
+
+func TestLedger_Transfer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		balance       int64
+		pending       int64
+		expectedError error
+	}{
+		{name: "successful", balance: 1_500, pending: 500},
+		{name: "insufficient funds", balance: 999, expectedError: model.ErrInsufficientFunds},
+		{name: "reserved by withdrawals", balance: 1_000, pending: 1, expectedError: model.ErrInsufficientFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			txManager := portsmocks.NewMockDatabaseTransactionPort(t)
+			txManager.On("WithInTransaction", ctx, mock.Anything).
+				Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+
+			database := portsmocks.NewMockDatabasePort(t)
+			database.On("LockLedgerAccount", ctx, model.CustomerLedgerAccount("from")).Return(nil).Once()
+			database.On("GetLedgerBalances", ctx, model.CustomerLedgerAccount("from")).
+				Return([]model.Balance{ton(-tt.balance)}, nil).Once()
+			database.On("GetPendingWithdrawalAmount", ctx, "from", model.CurrencyTON).Return(model.NewAmount(tt.pending), nil).Once()
+
+			if tt.expectedError == nil {
+				database.On("InsertLedgerEntry", ctx, mock.MatchedBy(func(entry *model.LedgerEntry) bool {
+					return entry.Type == model.LedgerTransfer && entry.IsBalanced()
+				})).Return(func(_ context.Context, entry *model.LedgerEntry) (*model.LedgerEntry, error) {
+					return entry, nil
+				}).Once()
+			}
+
+			l := ledger.New(&ledger.Options{Database: database, Withdrawals: database, TxManager: txManager})
+
+			entry, err := l.Transfer(ctx, "from", "to", ton(1_000), "transfer-1")
+			require.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				require.Equal(t, map[string]string{"customer:from/TON": "1000", "customer:to/TON": "-1000"}, postingsOf(entry))
+			}
+		})
+	}
+}
+
+func TestLedger_GetBalance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	database := portsmocks.NewMockDatabasePort(t)
+	database.On("GetLedgerBalances", ctx, model.CustomerLedgerAccount("acc")).
+		Return([]model.Balance{{Currency: model.CurrencyUSDT, Amount: model.NewAmount(-7)}}, nil).Once()
+	database.On("GetLedgerBalances", ctx, model.LedgerMasterWallet).
+		Return([]model.Balance{ton(4_000)}, nil).Once()
+
+	l := ledger.New(&ledger.Options{Database: database, Withdrawals: database, TxManager: portsmocks.NewMockDatabaseTransactionPort(t)})
+
+	balances, err := l.GetBalance(ctx, "acc")
+	require.NoError(t, err)
+	require.Equal(t, []model.Balance{ton(0), {Currency: model.CurrencyUSDT, Amount: model.NewAmount(7)}}, balances)
+
+	balances, err = l.GetBalance(ctx, "master")
+	require.NoError(t, err)
+	require.Equal(t, []model.Balance{ton(4_000)}, balances)
+}
@@ -14,6 +14,7 @@ import (
 // Outbox is a service that allows to store events that should be processed by external services.
 // It is used to implement the outbox pattern.
 var _ ports.OutboxServicePort = (*Outbox)(nil)
+var _ ports.OutboxMessagePort = (*Outbox)(nil)
 
 type Outbox struct {
 	database ports.OutboxMessageDatabasePort
@@ -23,19 +24,29 @@ func New(db ports.OutboxMessageDatabasePort) *Outbox {
 	return &Outbox{database: db}
 }
 
-func (s *Outbox) AddEvent(ctx context.Context, eventType model.EventType, payload any) error {
-	var payloadBytes []byte
-	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
-		return err
+// Publish serializes the payload and stores it as a pending event.
+// The event is saved through the transaction from the context, so it is committed together with the caller's changes.
+func (s *Outbox) Publish(ctx context.Context, eventType model.EventType, payload any) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "marshal payload")
 	}
 
 	event := model.OutboxEvent{
 		EventType: eventType,
 		Payload:   payloadBytes,
-		CreatedAt: time.Now(),
+		CreatedAt: time.Now().UTC(),
 		Processed: false,
 	}
-	return s.database.SaveEvent(ctx, event)
+
+	if aggregate, ok := payload.(model.AggregatePayload); ok {
+		event.AggregateID = aggregate.AggregateID()
+	}
+
+	if err = s.database.SaveEvent(ctx, event); err != nil {
+		return errors.Wrap(err, "save event")
+	}
+	return nil
 }
 
 func (s *Outbox) GetPendingEvent(ctx context.Context) (*model.OutboxEvent, error) {
@@ -50,6 +61,15 @@ func (s *Outbox) GetPendingEvent(ctx context.Context) (*model.OutboxEvent, error
 	return &events[0], nil
 }
 
+// GetPendingEvents returns up to limit pending events ordered by id.
+func (s *Outbox) GetPendingEvents(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	events, err := s.database.GetEvents(ctx, int64(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "get events")
+	}
+	return events, nil
+}
+
 func (s *Outbox) MarkEventAsProcessed(ctx context.Context, eventID int64) error {
 	return s.database.MarkEventAsProcessed(ctx, uint64(eventID))
 }
@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+const (
+	// defaultRetentionMaxAge is how long processed events are kept in the outbox table.
+	defaultRetentionMaxAge = 7 * 24 * time.Hour
+
+	// defaultRetentionBatchSize is the number of events removed per database transaction.
+	defaultRetentionBatchSize = 1000
+
+	// defaultRetentionInterval is the default interval between retention runs.
+	defaultRetentionInterval = time.Hour
+)
+
+var _ ports.OutboxRetentionServicePort = (*Retention)(nil)
+
+type RetentionOptions struct {
+	Database  ports.OutboxRetentionDatabasePort   `validate:"required"`
+	TxManager ports.DatabaseWithinTransactionPort `validate:"required"`
+	Archive   ports.OutboxArchivePort
+	MaxAge    time.Duration
+	BatchSize int
+	Interval  time.Duration
+}
+
+func (o *RetentionOptions) SetDefaults() {
+	if o.MaxAge == 0 {
+		o.MaxAge = defaultRetentionMaxAge
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultRetentionBatchSize
+	}
+	if o.Interval == 0 {
+		o.Interval = defaultRetentionInterval
+	}
+}
+
+// Retention archives and purges processed outbox events in bounded batches.
+// Each batch is deleted and archived within one transaction, so an event is never removed without being archived.
+type Retention struct {
+	database  ports.OutboxRetentionDatabasePort
+	tx        ports.DatabaseWithinTransactionPort
+	archive   ports.OutboxArchivePort
+	maxAge    time.Duration
+	batchSize int
+	interval  time.Duration
+}
+
+func NewRetention(options *RetentionOptions) *Retention {
+	options.SetDefaults()
+
+	if err := validator.New().Struct(options); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Retention{
+		database:  options.Database,
+		tx:        options.TxManager,
+		archive:   options.Archive,
+		maxAge:    options.MaxAge,
+		batchSize: options.BatchSize,
+		interval:  options.Interval,
+	}
+}
+
+func (r *Retention) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := r.Purge(ctx)
+		if err != nil {
+			log.Warn().Err(err).Msg("purge outbox events")
+		} else if purged > 0 {
+			log.Info().Int("purged", purged).Msg("outbox events purged")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes processed events older than the configured age and returns the number of purged events.
+func (r *Retention) Purge(ctx context.Context) (int, error) {
+	before := time.Now().UTC().Add(-r.maxAge)
+
+	var total int
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, nil //nolint:nilerr // stopped by the caller
+		}
+
+		var events []model.OutboxEvent
+		err := r.tx.WithInTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if events, err = r.database.DeleteProcessedEvents(ctx, before, r.batchSize); err != nil {
+				return errors.Wrap(err, "delete processed events")
+			}
+
+			if r.archive == nil || len(events) == 0 {
+				return nil
+			}
+
+			if err = r.archive.ArchiveEvents(ctx, events); err != nil {
+				return errors.Wrap(err, "archive events")
+			}
+			return nil
+		})
+		if err != nil {
+			return total, errors.Wrap(err, "purge batch")
+		}
+
+		total += len(events)
+		log.Debug().Int("batch", len(events)).Int("total", total).Time("before", before).Msg("outbox batch purged")
+
+		if len(events) < r.batchSize {
+			return total, nil
+		}
+	}
+}
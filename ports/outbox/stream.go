@@ -0,0 +1,154 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+const (
+	// defaultStreamPollInterval is the default interval between reads of the new events.
+	defaultStreamPollInterval = time.Second
+
+	// defaultStreamBatchSize is the number of events read at once.
+	defaultStreamBatchSize = 500
+
+	// defaultStreamGapTimeout is how long a gap in the event ids is waited for to be filled.
+	defaultStreamGapTimeout = 10 * time.Second
+)
+
+var _ ports.EventStreamServicePort = (*Stream)(nil)
+
+type StreamOptions struct {
+	Database     ports.OutboxStreamDatabasePort `validate:"required"`
+	PollInterval time.Duration
+	BatchSize    int
+	GapTimeout   time.Duration
+}
+
+func (o *StreamOptions) SetDefaults() {
+	if o.PollInterval == 0 {
+		o.PollInterval = defaultStreamPollInterval
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultStreamBatchSize
+	}
+	if o.GapTimeout == 0 {
+		o.GapTimeout = defaultStreamGapTimeout
+	}
+}
+
+// Stream delivers the outbox events to the subscribers by reading the outbox table, the id of the last
+// delivered event is the cursor a subscriber resumes from after a reconnect.
+//
+// Event ids are allocated when the events are inserted, not when they are committed, so an event may become
+// visible after an event with a greater id. The stream does not pass a gap in the ids until the event after
+// the gap is older than the gap timeout, the ids of rolled back transactions are never filled.
+type Stream struct {
+	database     ports.OutboxStreamDatabasePort
+	pollInterval time.Duration
+	batchSize    int
+	gapTimeout   time.Duration
+	now          func() time.Time
+}
+
+func NewStream(options *StreamOptions) *Stream {
+	options.SetDefaults()
+
+	if err := validator.New().Struct(options); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Stream{
+		database:     options.Database,
+		pollInterval: options.PollInterval,
+		batchSize:    options.BatchSize,
+		gapTimeout:   options.GapTimeout,
+		now:          time.Now,
+	}
+}
+
+// Subscribe calls send for the events of the subscribed accounts until the context is done or send fails,
+// the context error is not returned. model.ErrCursorExpired is returned if the events after the cursor
+// may have been purged by the retention.
+func (s *Stream) Subscribe(ctx context.Context, subscription model.EventSubscription, send func(event model.OutboxEvent) error) error {
+	var cursor int64
+	if subscription.AfterID != nil {
+		cursor = *subscription.AfterID
+
+		first, err := s.database.GetFirstEventID(ctx)
+		if err != nil {
+			return errors.Wrap(err, "get first event id")
+		}
+		if first > cursor+1 {
+			return errors.Wrapf(model.ErrCursorExpired, "cursor %d, first event %d", cursor, first)
+		}
+	} else {
+		last, err := s.database.GetLastEventID(ctx)
+		if err != nil {
+			return errors.Wrap(err, "get last event id")
+		}
+		cursor = last
+	}
+
+	accounts := make(map[model.AccountID]struct{}, len(subscription.AccountIDs))
+	for _, accountID := range subscription.AccountIDs {
+		accounts[accountID] = struct{}{}
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		next, full, err := s.deliver(ctx, cursor, accounts, send)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		cursor = next
+
+		// a full batch is followed by more events, they are read without waiting
+		if full {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver sends the events after the cursor and returns the new cursor and whether the whole batch was delivered.
+func (s *Stream) deliver(
+	ctx context.Context, cursor int64, accounts map[model.AccountID]struct{}, send func(event model.OutboxEvent) error,
+) (int64, bool, error) {
+	events, err := s.database.ListEventsAfter(ctx, cursor, s.batchSize)
+	if err != nil {
+		return cursor, false, errors.Wrap(err, "list events")
+	}
+
+	for _, event := range events {
+		if event.ID != cursor+1 && s.now().Sub(event.CreatedAt) < s.gapTimeout {
+			log.Debug().Int64("cursor", cursor).Int64("event_id", event.ID).Msg("waiting for the events in the gap")
+			return cursor, false, nil
+		}
+		cursor = event.ID
+
+		if _, ok := accounts[event.AggregateID]; ok || len(accounts) == 0 {
+			if err = send(event); err != nil {
+				return cursor, false, errors.Wrap(err, "send event")
+			}
+		}
+	}
+	return cursor, len(events) == s.batchSize, nil
+}
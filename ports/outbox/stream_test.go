@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestStream_Deliver(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(id int64, accountID string, age time.Duration) model.OutboxEvent {
+		return model.OutboxEvent{ID: id, AggregateID: accountID, EventType: model.DepositCreditedEvent, CreatedAt: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name      string
+		events    []model.OutboxEvent
+		accounts  []model.AccountID
+		batchSize int
+		delivered []int64
+		cursor    int64
+		full      bool
+	}{
+		{
+			name:      "events of the subscribed accounts",
+			events:    []model.OutboxEvent{event(11, "a", 0), event(12, "b", 0), event(13, "a", 0)},
+			accounts:  []model.AccountID{"a"},
+			delivered: []int64{11, 13},
+			cursor:    13,
+		},
+		{
+			name:      "all accounts",
+			events:    []model.OutboxEvent{event(11, "a", 0), event(12, "b", 0)},
+			delivered: []int64{11, 12},
+			cursor:    12,
+			batchSize: 2,
+			full:      true,
+		},
+		{
+			name:      "recent gap is waited for",
+			events:    []model.OutboxEvent{event(11, "a", 0), event(13, "a", time.Second)},
+			delivered: []int64{11},
+			cursor:    11,
+		},
+		{
+			name:      "old gap is passed",
+			events:    []model.OutboxEvent{event(12, "a", time.Minute), event(13, "a", 0)},
+			delivered: []int64{12, 13},
+			cursor:    13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			batchSize := tt.batchSize
+			if batchSize == 0 {
+				batchSize = 10
+			}
+
+			database := portsmocks.NewMockDatabasePort(t)
+			database.On("ListEventsAfter", ctx, int64(10), batchSize).Return(tt.events, nil).Once()
+
+			stream := NewStream(&StreamOptions{Database: database, BatchSize: batchSize})
+			stream.now = func() time.Time { return now }
+
+			accounts := make(map[model.AccountID]struct{})
+			for _, accountID := range tt.accounts {
+				accounts[accountID] = struct{}{}
+			}
+
+			var delivered []int64
+			cursor, full, err := stream.deliver(ctx, 10, accounts, func(event model.OutboxEvent) error {
+				delivered = append(delivered, event.ID)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.delivered, delivered)
+			require.Equal(t, tt.cursor, cursor)
+			require.Equal(t, tt.full, full)
+		})
+	}
+}
+
+func TestStream_SubscribeFromCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		first         int64
+		expectedError error
+	}{
+		{name: "cursor event retained", first: 40},
+		{name: "next event retained", first: 42},
+		{name: "empty outbox", first: 0},
+		{name: "events after the cursor purged", first: 43, expectedError: model.ErrCursorExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			database := portsmocks.NewMockDatabasePort(t)
+			database.On("GetFirstEventID", mock.Anything).Return(tt.first, nil).Once()
+			if tt.expectedError == nil {
+				database.On("ListEventsAfter", mock.Anything, int64(41), defaultStreamBatchSize).
+					Return(func(context.Context, int64, int) ([]model.OutboxEvent, error) {
+						cancel()
+						return nil, nil
+					}).Once()
+			}
+
+			stream := NewStream(&StreamOptions{Database: database, PollInterval: time.Hour})
+
+			err := stream.Subscribe(ctx, model.EventSubscription{AfterID: lo.ToPtr(int64(41))}, func(model.OutboxEvent) error {
+				return nil
+			})
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestStream_SubscribeFromLastEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	database := portsmocks.NewMockDatabasePort(t)
+	database.On("GetLastEventID", mock.Anything).Return(int64(41), nil).Once()
+	database.On("ListEventsAfter", mock.Anything, int64(41), defaultStreamBatchSize).
+		Return([]model.OutboxEvent{{ID: 42, AggregateID: "a", CreatedAt: time.Now()}}, nil).Once()
+
+	stream := NewStream(&StreamOptions{Database: database, PollInterval: time.Hour})
+
+	var delivered []int64
+	err := stream.Subscribe(ctx, model.EventSubscription{}, func(event model.OutboxEvent) error {
+		delivered = append(delivered, event.ID)
+		cancel()
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{42}, delivered)
+}
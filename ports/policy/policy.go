@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/pkg/schedule"
+)
+
+var _ ports.SweepPolicyServicePort = (*Policies)(nil)
+
+type Options struct {
+	Database ports.SweepPolicyDatabasePort `validate:"required"`
+	Accounts ports.AccountDatabasePort     `validate:"required"`
+}
+
+// Policies validates and stores the sweep policies, the collector reads them from the database on every run.
+type Policies struct {
+	database ports.SweepPolicyDatabasePort
+	accounts ports.AccountDatabasePort
+	now      func() time.Time
+}
+
+func New(opts *Options) *Policies {
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Policies{
+		database: opts.Database,
+		accounts: opts.Accounts,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// SetSweepPolicy replaces the policy of the account and the currency of the policy,
+// model.ErrInvalidSweepPolicy is returned if the policy is not valid.
+func (p *Policies) SetSweepPolicy(ctx context.Context, policy *model.SweepPolicy) (*model.SweepPolicy, error) {
+	if err := validate(policy); err != nil {
+		return nil, err
+	}
+
+	if policy.AccountID != "" {
+		exists, err := p.accounts.IsAccountExists(ctx, policy.AccountID)
+		if err != nil {
+			return nil, errors.Wrap(err, "check account")
+		}
+		if !exists {
+			return nil, model.ErrAccountNotFound
+		}
+	}
+
+	now := p.now()
+	policy.CreatedAt, policy.UpdatedAt = now, now
+
+	stored, err := p.database.UpsertSweepPolicy(ctx, policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "upsert sweep policy")
+	}
+	return stored, nil
+}
+
+func (p *Policies) ListSweepPolicies(ctx context.Context, accountID *model.AccountID) ([]*model.SweepPolicy, error) {
+	policies, err := p.database.ListSweepPolicies(ctx, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list sweep policies")
+	}
+	return policies, nil
+}
+
+// DeleteSweepPolicy deletes the policy of the account and the currency, the less specific policies apply to them then.
+func (p *Policies) DeleteSweepPolicy(ctx context.Context, accountID model.AccountID, currency model.Currency) error {
+	if err := p.database.DeleteSweepPolicy(ctx, accountID, currency); err != nil {
+		return errors.Wrap(err, "delete sweep policy")
+	}
+	return nil
+}
+
+func validate(policy *model.SweepPolicy) error {
+	if policy.Threshold.Sign() < 0 || policy.Reserve.Sign() < 0 {
+		return errors.Wrap(model.ErrInvalidSweepPolicy, "negative amount")
+	}
+
+	if policy.MinInterval < 0 || policy.MinInterval%time.Second != 0 {
+		return errors.Wrap(model.ErrInvalidSweepPolicy, "min interval is not a whole number of seconds")
+	}
+
+	if policy.Schedule != "" {
+		if _, err := schedule.Parse(policy.Schedule); err != nil {
+			return errors.Wrapf(model.ErrInvalidSweepPolicy, "schedule: %v", err)
+		}
+	}
+	return nil
+}
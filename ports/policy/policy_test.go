@@ -0,0 +1,62 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+	"github.com/kriuchkov/tonbeacon/ports/policy"
+)
+
+func TestPolicies_SetSweepPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	database := portsmocks.NewMockSweepPolicyDatabasePort(t)
+	database.On("UpsertSweepPolicy", ctx, mock.MatchedBy(func(p *model.SweepPolicy) bool {
+		return p.AccountID == "vip" && p.Disabled && !p.UpdatedAt.IsZero()
+	})).Return(func(_ context.Context, p *model.SweepPolicy) (*model.SweepPolicy, error) {
+		stored := *p
+		stored.ID = 3
+		return &stored, nil
+	}).Once()
+
+	accounts := portsmocks.NewMockDatabasePort(t)
+	accounts.On("IsAccountExists", ctx, "vip").Return(true, nil).Once()
+	accounts.On("IsAccountExists", ctx, "unknown").Return(false, nil).Once()
+
+	policies := policy.New(&policy.Options{Database: database, Accounts: accounts})
+
+	stored, err := policies.SetSweepPolicy(ctx, &model.SweepPolicy{AccountID: "vip", Disabled: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stored.ID)
+
+	_, err = policies.SetSweepPolicy(ctx, &model.SweepPolicy{AccountID: "unknown", Currency: model.CurrencyTON})
+	require.ErrorIs(t, err, model.ErrAccountNotFound)
+}
+
+func TestPolicies_SetSweepPolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	policies := policy.New(&policy.Options{
+		Database: portsmocks.NewMockSweepPolicyDatabasePort(t),
+		Accounts: portsmocks.NewMockDatabasePort(t),
+	})
+
+	for name, p := range map[string]*model.SweepPolicy{
+		"negative threshold": {Threshold: model.NewAmount(-1)},
+		"negative reserve":   {Reserve: model.NewAmount(-1)},
+		"negative interval":  {MinInterval: -time.Second},
+		"partial second":     {MinInterval: 1500 * time.Millisecond},
+		"invalid schedule":   {Schedule: "0 25 * * *"},
+	} {
+		_, err := policies.SetSweepPolicy(context.Background(), p)
+		require.ErrorIs(t, err, model.ErrInvalidSweepPolicy, name)
+	}
+}
@@ -0,0 +1,444 @@
+package rebalance
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+const (
+	// defaultInterval is the default interval between the runs of the rebalancer.
+	defaultInterval = 10 * time.Minute
+
+	// defaultConfirmTimeout is how long after the expiration of its message a rebalance waits for the transaction
+	// processor before the transactions of the master wallet are checked.
+	defaultConfirmTimeout = 5 * time.Minute
+)
+
+type Options struct {
+	WalletPort   ports.WalletPort                    `validate:"required"`
+	Database     ports.RebalanceDatabasePort         `validate:"required"`
+	TransferPort ports.OutgoingTransferDatabasePort  `validate:"required"`
+	TxPort       ports.DatabaseWithinTransactionPort `validate:"required"`
+	Events       ports.OutboxMessagePort             `validate:"required"`
+	// Seqnos reserve the seqnos of the master wallet shared with the collector and the withdrawals.
+	Seqnos ports.SeqnoDatabasePort `validate:"required"`
+
+	// ColdAddress receives the balance of the master wallet above the ceilings, it is required by a ceiling.
+	ColdAddress string
+	// Limits bound the balance of the master wallet per currency.
+	Limits []model.HotWalletLimit
+	// JettonGas is attached to a jetton rebalance, its excess is returned to the master wallet.
+	JettonGas model.Amount
+
+	Interval       time.Duration
+	ConfirmTimeout time.Duration
+}
+
+func (o *Options) SetDefaults() {
+	if o.Interval == 0 {
+		o.Interval = defaultInterval
+	}
+	if o.ConfirmTimeout == 0 {
+		o.ConfirmTimeout = defaultConfirmTimeout
+	}
+	if o.JettonGas.Sign() == 0 {
+		o.JettonGas = model.NewAmount(model.DefaultJettonGasNano)
+	}
+}
+
+// Rebalancer keeps the balance of the master wallet between the floor and the ceiling of every currency.
+// The balance above the ceiling is moved to the cold storage like the collector sweeps a subwallet: the signed
+// message is stored with its outgoing transfer before it is broadcast, it is broadcast again until it expires
+// and the rebalance whose message expired without being executed fails. The transaction processor confirms
+// the transfer and records the rebalance in the ledger, a bounced transfer fails the rebalance. The rebalance
+// whose message was executed without a confirmation is moved to unconfirmed and published as
+// model.TransferUnconfirmedEvent for an operator to reconcile.
+//
+// The balance below the floor is published as a HotWalletLowEvent once until it recovers, a restart of
+// the rebalancer publishes it again. One rebalance is in flight at a time, the master wallet signs with its seqno
+// reserved against the other senders of the master wallet until its transfer is settled.
+type Rebalancer struct {
+	walletPort     ports.WalletPort
+	database       ports.RebalanceDatabasePort
+	transfers      ports.OutgoingTransferDatabasePort
+	txPort         ports.DatabaseWithinTransactionPort
+	events         ports.OutboxMessagePort
+	seqnos         ports.SeqnoDatabasePort
+	coldAddress    string
+	limits         []model.HotWalletLimit
+	jettonGas      model.Amount
+	interval       time.Duration
+	confirmTimeout time.Duration
+	low            map[model.Currency]bool
+	now            func() time.Time
+}
+
+func New(opts *Options) *Rebalancer {
+	opts.SetDefaults()
+
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	if opts.JettonGas.Sign() < 0 {
+		log.Panic().Msg("jetton gas must not be negative")
+	}
+
+	currencies := make(map[model.Currency]struct{}, len(opts.Limits))
+	for _, limit := range opts.Limits {
+		if _, ok := currencies[limit.Currency]; ok {
+			log.Panic().Str("currency", string(limit.Currency)).Msg("duplicate hot wallet limit")
+		}
+		currencies[limit.Currency] = struct{}{}
+
+		if limit.Ceiling.Sign() < 0 || limit.Floor.Sign() < 0 {
+			log.Panic().Str("currency", string(limit.Currency)).Msg("hot wallet limits must not be negative")
+		}
+
+		if limit.Ceiling.Sign() == 0 {
+			continue
+		}
+
+		if limit.Ceiling.Cmp(limit.Floor) < 0 {
+			log.Panic().Str("currency", string(limit.Currency)).Msg("hot wallet ceiling is below the floor")
+		}
+
+		if _, err := address.ParseAddr(opts.ColdAddress); err != nil {
+			log.Panic().Err(err).Str("currency", string(limit.Currency)).Msg("invalid cold address")
+		}
+	}
+
+	return &Rebalancer{
+		walletPort:     opts.WalletPort,
+		database:       opts.Database,
+		transfers:      opts.TransferPort,
+		txPort:         opts.TxPort,
+		events:         opts.Events,
+		seqnos:         opts.Seqnos,
+		coldAddress:    opts.ColdAddress,
+		limits:         opts.Limits,
+		jettonGas:      opts.JettonGas,
+		interval:       opts.Interval,
+		confirmTimeout: opts.ConfirmTimeout,
+		low:            make(map[model.Currency]bool, len(opts.Limits)),
+		now:            time.Now,
+	}
+}
+
+// Run rebalances the master wallet on every tick until the context is done.
+func (r *Rebalancer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Rebalance(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("rebalance")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebalance advances the rebalances in flight and checks the balance of the master wallet in every currency:
+// the balance below the floor is alerted and the balance above the ceiling is moved to the cold storage
+// if no rebalance is in flight. The failure of a currency is logged and does not stop the others.
+func (r *Rebalancer) Rebalance(ctx context.Context) error {
+	active, err := r.database.ListActiveRebalances(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list rebalances")
+	}
+
+	inFlight := false
+	for _, rebalance := range active {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err = r.advance(ctx, rebalance); err != nil {
+			log.Warn().Err(err).Int64("rebalance_id", rebalance.ID).Str("message_hash", rebalance.MessageHash).
+				Msg("advance rebalance")
+		}
+		inFlight = inFlight || isInFlight(rebalance)
+	}
+
+	for _, limit := range r.limits {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		balance, err := r.balance(ctx, limit.Currency)
+		if err != nil {
+			log.Warn().Err(err).Str("currency", string(limit.Currency)).Msg("get hot wallet balance")
+			continue
+		}
+
+		if err = r.checkFloor(ctx, limit, balance); err != nil {
+			log.Warn().Err(err).Str("currency", string(limit.Currency)).Msg("check hot wallet floor")
+		}
+
+		if inFlight || limit.Ceiling.Sign() == 0 || balance.Cmp(limit.Ceiling) <= 0 {
+			continue
+		}
+
+		inFlight = true
+		if err = r.plan(ctx, limit.Currency, balance.Sub(limit.Ceiling)); err != nil {
+			log.Warn().Err(err).Str("currency", string(limit.Currency)).Msg("plan rebalance")
+		}
+	}
+	return nil
+}
+
+// balance returns the balance of the master wallet in the currency.
+func (r *Rebalancer) balance(ctx context.Context, currency model.Currency) (model.Amount, error) {
+	var (
+		balance model.Balance
+		err     error
+	)
+
+	if currency == model.CurrencyTON {
+		balance, err = r.walletPort.GetBalance(ctx, 0)
+	} else {
+		balance, err = r.walletPort.GetJettonBalance(ctx, 0, currency)
+	}
+	return balance.Amount, err
+}
+
+// checkFloor publishes the alert when the balance drops below the floor, the alert is not repeated
+// until the balance recovers.
+func (r *Rebalancer) checkFloor(ctx context.Context, limit model.HotWalletLimit, balance model.Amount) error {
+	if limit.Floor.Sign() == 0 || balance.Cmp(limit.Floor) >= 0 {
+		r.low[limit.Currency] = false
+		return nil
+	}
+
+	if r.low[limit.Currency] {
+		return nil
+	}
+
+	payload := model.HotWalletLowPayload{
+		Currency: limit.Currency,
+		Balance:  balance.Nano(),
+		Floor:    limit.Floor.Nano(),
+		At:       r.now().UTC(),
+	}
+	if err := r.events.Publish(ctx, model.HotWalletLowEvent, payload); err != nil {
+		return errors.Wrapf(err, "publish %s", model.HotWalletLowEvent)
+	}
+
+	r.low[limit.Currency] = true
+	log.Warn().Str("currency", string(limit.Currency)).Str("balance", balance.String()).Str("floor", limit.Floor.String()).
+		Msg("hot wallet is below the floor")
+	return nil
+}
+
+// gas returns the TON attached to the transfer of the currency.
+func (r *Rebalancer) gas(currency model.Currency) model.Amount {
+	if currency == model.CurrencyTON {
+		return model.NewAmount(0)
+	}
+	return r.jettonGas
+}
+
+// plan signs the transfer of the amount to the cold storage, reserves its seqno, stores the rebalance with
+// its outgoing transfer and broadcasts it.
+func (r *Rebalancer) plan(ctx context.Context, currency model.Currency, amount model.Amount) error {
+	message, err := r.walletPort.PrepareWithdrawal(ctx, r.coldAddress, model.Balance{Currency: currency, Amount: amount}, r.gas(currency), "")
+	if err != nil {
+		return errors.Wrap(err, "prepare rebalance")
+	}
+
+	rebalance := model.NewRebalance(message, r.now().UTC())
+	err = r.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := r.seqnos.ReserveSeqno(ctx, message); err != nil {
+			return errors.Wrap(err, "reserve seqno")
+		}
+
+		inserted, err := r.database.InsertRebalance(ctx, rebalance)
+		if err != nil {
+			return errors.Wrap(err, "insert rebalance")
+		}
+		rebalance = inserted
+
+		if _, err := r.transfers.InsertOutgoingTransfer(ctx, rebalance.Transfer()); err != nil {
+			return errors.Wrap(err, "register transfer")
+		}
+		return nil
+	})
+	if errors.Is(err, model.ErrRebalanceExists) {
+		log.Debug().Str("currency", string(currency)).Msg("rebalance is already in flight")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int64("rebalance_id", rebalance.ID).Str("currency", string(currency)).Str("amount", amount.String()).
+		Uint32("seqno", rebalance.Seqno).Str("message_hash", rebalance.MessageHash).Msg("rebalance planned")
+	return r.broadcast(ctx, rebalance)
+}
+
+// broadcast sends the message of the rebalance, the first successful broadcast moves it to sent.
+// The wallet executes the message once however many times it is sent.
+func (r *Rebalancer) broadcast(ctx context.Context, rebalance *model.Rebalance) error {
+	from := rebalance.Status
+	rebalance.Attempts++
+	rebalance.UpdatedAt = r.now().UTC()
+
+	sendErr := r.walletPort.SendWalletMessage(ctx, rebalance.Message())
+	if sendErr != nil {
+		rebalance.Error = sendErr.Error()
+	} else {
+		rebalance.Error = ""
+		if rebalance.Status == model.RebalancePlanned {
+			if err := rebalance.Transition(model.RebalanceSent, rebalance.UpdatedAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.database.UpdateRebalance(ctx, rebalance, from); err != nil {
+		return errors.Wrap(err, "update rebalance")
+	}
+	return errors.Wrap(sendErr, "send rebalance message")
+}
+
+// advance moves the rebalance after its outgoing transfer, broadcasts its message while it is valid
+// and fails the rebalance whose message expired without being executed. An unconfirmed rebalance waits
+// for its transfer only.
+func (r *Rebalancer) advance(ctx context.Context, rebalance *model.Rebalance) error {
+	transfer, err := r.transfers.GetOutgoingTransferByMessageHash(ctx, rebalance.MessageHash)
+	if err != nil {
+		return errors.Wrap(err, "get outgoing transfer")
+	}
+
+	switch transfer.Status {
+	case model.TransferConfirmed:
+		return r.finish(ctx, rebalance, model.RebalanceConfirmed, "")
+	case model.TransferBounced:
+		return r.finish(ctx, rebalance, model.RebalanceFailed, "transfer bounced")
+	case model.TransferFailed:
+		return r.finish(ctx, rebalance, model.RebalanceFailed, "transaction failed")
+	case model.TransferSent:
+	}
+
+	if rebalance.Status == model.RebalanceUnconfirmed {
+		return nil
+	}
+
+	now := r.now().UTC()
+	if now.Before(rebalance.ExpiresAt) {
+		return r.broadcast(ctx, rebalance)
+	}
+
+	if now.Before(rebalance.ExpiresAt.Add(r.confirmTimeout)) {
+		return nil
+	}
+
+	executed, err := r.walletPort.IsMessageExecuted(ctx, rebalance.Message())
+	if err != nil {
+		return errors.Wrap(err, "check rebalance message")
+	}
+
+	if executed {
+		log.Error().Int64("rebalance_id", rebalance.ID).Str("message_hash", rebalance.MessageHash).
+			Msg("rebalance is executed but not confirmed by the transaction processor")
+		return r.unconfirmed(ctx, rebalance, transfer)
+	}
+
+	log.Error().Int64("rebalance_id", rebalance.ID).Str("message_hash", rebalance.MessageHash).Msg("rebalance message expired")
+	return r.expire(ctx, rebalance, transfer)
+}
+
+// finish moves the rebalance to the final status of its transfer, a planned rebalance was sent
+// if the transfer of its message is known.
+func (r *Rebalancer) finish(ctx context.Context, rebalance *model.Rebalance, to model.RebalanceStatus, reason string) error {
+	from, now := rebalance.Status, r.now().UTC()
+
+	if rebalance.Status == model.RebalancePlanned && to == model.RebalanceConfirmed {
+		if err := rebalance.Transition(model.RebalanceSent, now); err != nil {
+			return err
+		}
+	}
+
+	if err := rebalance.Transition(to, now); err != nil {
+		return err
+	}
+	rebalance.Error = reason
+
+	if err := r.database.UpdateRebalance(ctx, rebalance, from); err != nil {
+		return errors.Wrapf(err, "update rebalance to %s", to)
+	}
+
+	log.Info().Int64("rebalance_id", rebalance.ID).Str("currency", string(rebalance.Currency)).Str("status", string(to)).
+		Msg("rebalance finished")
+	return nil
+}
+
+// unconfirmed moves the rebalance whose message is executed without a confirmation to unconfirmed and publishes
+// its transfer as model.TransferUnconfirmedEvent.
+func (r *Rebalancer) unconfirmed(ctx context.Context, rebalance *model.Rebalance, transfer *model.OutgoingTransfer) error {
+	from, next := rebalance.Status, *rebalance
+	if err := next.Transition(model.RebalanceUnconfirmed, r.now().UTC()); err != nil {
+		return err
+	}
+	next.Error = "rebalance executed but not confirmed"
+
+	err := r.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := r.database.UpdateRebalance(ctx, &next, from); err != nil {
+			return errors.Wrapf(err, "update rebalance to %s", model.RebalanceUnconfirmed)
+		}
+
+		if err := r.events.Publish(ctx, model.TransferUnconfirmedEvent, model.NewTransferPayload(transfer)); err != nil {
+			return errors.Wrapf(err, "publish %s", model.TransferUnconfirmedEvent)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*rebalance = next
+	return nil
+}
+
+// expire fails the rebalance and its transfer together, the failure of the transfer is published as if
+// the transaction processor failed it.
+func (r *Rebalancer) expire(ctx context.Context, rebalance *model.Rebalance, transfer *model.OutgoingTransfer) error {
+	return r.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := r.finish(ctx, rebalance, model.RebalanceFailed, "message expired"); err != nil {
+			return err
+		}
+
+		if err := transfer.Transition(model.TransferFailed, r.now().UTC()); err != nil {
+			return err
+		}
+
+		if err := r.transfers.UpdateOutgoingTransferStatus(ctx, transfer, model.TransferSent); err != nil {
+			return errors.Wrap(err, "update transfer to failed")
+		}
+
+		eventType := model.TransferEventType(transfer.Kind, transfer.Status)
+		if err := r.events.Publish(ctx, eventType, model.NewTransferPayload(transfer)); err != nil {
+			return errors.Wrapf(err, "publish %s", eventType)
+		}
+		return nil
+	})
+}
+
+// isInFlight reports whether the message of the rebalance may still be executed or expire.
+func isInFlight(rebalance *model.Rebalance) bool {
+	return rebalance.Status == model.RebalancePlanned || rebalance.Status == model.RebalanceSent
+}
@@ -0,0 +1,334 @@
+package rebalance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+const coldAddress = "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z"
+
+type rebalancerMocks struct {
+	wallet     *portsmocks.MockWalletPort
+	rebalances *portsmocks.MockRebalanceDatabasePort
+	transfers  *portsmocks.MockOutgoingTransferDatabasePort
+	events     *portsmocks.MockOutboxMessagePort
+	seqnos     *portsmocks.MockSeqnoDatabasePort
+}
+
+func newRebalancer(t *testing.T, now time.Time, limits ...model.HotWalletLimit) (*Rebalancer, rebalancerMocks) {
+	m := rebalancerMocks{
+		wallet:     portsmocks.NewMockWalletPort(t),
+		rebalances: portsmocks.NewMockRebalanceDatabasePort(t),
+		transfers:  portsmocks.NewMockOutgoingTransferDatabasePort(t),
+		events:     portsmocks.NewMockOutboxMessagePort(t),
+		seqnos:     portsmocks.NewMockSeqnoDatabasePort(t),
+	}
+
+	txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+	txPort.On("WithInTransaction", mock.Anything, mock.Anything).Maybe().
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+
+	rebalancer := New(&Options{
+		WalletPort:     m.wallet,
+		Database:       m.rebalances,
+		TransferPort:   m.transfers,
+		TxPort:         txPort,
+		Events:         m.events,
+		Seqnos:         m.seqnos,
+		ColdAddress:    coldAddress,
+		Limits:         limits,
+		ConfirmTimeout: 5 * time.Minute,
+	})
+	rebalancer.now = func() time.Time { return now }
+	return rebalancer, m
+}
+
+func TestRebalancer_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ton := model.HotWalletLimit{Currency: model.CurrencyTON, Ceiling: model.NewAmount(1000), Floor: model.NewAmount(100)}
+	message := &model.WalletMessage{
+		From: "0:02", To: "0:40", Currency: model.CurrencyTON, Amount: model.NewAmount(500), Seqno: 12,
+		MessageHash: "hash", BOC: []byte{1}, ExpiresAt: now.Add(3 * time.Minute),
+	}
+
+	rebalance := func(status model.RebalanceStatus, expiresAt time.Time) *model.Rebalance {
+		r := model.NewRebalance(message, now.Add(-time.Hour))
+		r.ID, r.Status, r.ExpiresAt = 3, status, expiresAt
+		return r
+	}
+
+	balance := func(m rebalancerMocks, nano int64) {
+		m.wallet.On("GetBalance", mock.Anything, uint32(0)).
+			Return(model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}, nil).Once()
+	}
+
+	tests := []struct {
+		name   string
+		limits []model.HotWalletLimit
+		mock   func(m rebalancerMocks)
+	}{
+		{
+			name:   "balance above the ceiling is moved to the cold storage",
+			limits: []model.HotWalletLimit{ton},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).Return(nil, nil).Once()
+				balance(m, 1500)
+
+				amount := model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(500)}
+				m.wallet.On("PrepareWithdrawal", mock.Anything, coldAddress, amount, model.NewAmount(0), "").Return(message, nil).Once()
+				m.seqnos.On("ReserveSeqno", mock.Anything, message).Return(nil).Once()
+				m.rebalances.On("InsertRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalancePlanned && r.MessageHash == "hash" && r.Seqno == 12
+				})).Return(func(_ context.Context, r *model.Rebalance) (*model.Rebalance, error) {
+					inserted := *r
+					inserted.ID = 3
+					return &inserted, nil
+				}).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferRebalance && transfer.Reference == "3" &&
+						transfer.AccountID == model.MasterAccountID && transfer.MessageHash == "hash"
+				})).Return(&model.OutgoingTransfer{ID: 1}, nil).Once()
+
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.MatchedBy(func(sent *model.WalletMessage) bool {
+					return sent.MessageHash == "hash"
+				})).Return(nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceSent && r.Attempts == 1 && r.SentAt != nil
+				}), model.RebalancePlanned).Return(nil).Once()
+			},
+		},
+		{
+			name:   "seqno reserved by another message of the master wallet",
+			limits: []model.HotWalletLimit{ton},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).Return(nil, nil).Once()
+				balance(m, 1500)
+
+				amount := model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(500)}
+				m.wallet.On("PrepareWithdrawal", mock.Anything, coldAddress, amount, model.NewAmount(0), "").Return(message, nil).Once()
+				m.seqnos.On("ReserveSeqno", mock.Anything, message).Return(model.ErrSeqnoReserved).Once()
+			},
+		},
+		{
+			name:   "balance below the ceiling stays",
+			limits: []model.HotWalletLimit{ton},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).Return(nil, nil).Once()
+				balance(m, 1000)
+			},
+		},
+		{
+			name:   "balance below the floor is alerted",
+			limits: []model.HotWalletLimit{ton},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).Return(nil, nil).Once()
+				balance(m, 99)
+				m.events.On("Publish", mock.Anything, model.HotWalletLowEvent, model.HotWalletLowPayload{
+					Currency: model.CurrencyTON, Balance: "99", Floor: "100", At: now,
+				}).Return(nil).Once()
+			},
+		},
+		{
+			name:   "jetton above the ceiling waits for the rebalance in flight",
+			limits: []model.HotWalletLimit{{Currency: "USDT", Ceiling: model.NewAmount(10)}},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(-time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferSent}, nil).Once()
+				m.wallet.On("GetJettonBalance", mock.Anything, uint32(0), model.Currency("USDT")).
+					Return(model.Balance{Currency: "USDT", Amount: model.NewAmount(50)}, nil).Once()
+			},
+		},
+		{
+			name: "confirmed transfer confirms the rebalance",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferConfirmed}, nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceConfirmed && r.ConfirmedAt != nil
+				}), model.RebalanceSent).Return(nil).Once()
+			},
+		},
+		{
+			name: "bounced transfer fails the rebalance",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferBounced}, nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceFailed && r.FailedAt != nil && r.Error == "transfer bounced"
+				}), model.RebalanceSent).Return(nil).Once()
+			},
+		},
+		{
+			name: "executed message without a confirmation parks the rebalance",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(-10*time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(&model.OutgoingTransfer{
+					ID: 1, Kind: model.TransferRebalance, Reference: "3", AccountID: model.MasterAccountID, Status: model.TransferSent,
+				}, nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "hash"
+				})).Return(true, nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceUnconfirmed && r.Error == "rebalance executed but not confirmed"
+				}), model.RebalanceSent).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.TransferUnconfirmedEvent, mock.MatchedBy(func(p model.TransferPayload) bool {
+					return p.TransferID == 1
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:   "unconfirmed rebalance waits for its transfer without holding the master wallet",
+			limits: []model.HotWalletLimit{ton},
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceUnconfirmed, now.Add(-10*time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferSent}, nil).Once()
+				balance(m, 1500)
+
+				amount := model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(500)}
+				m.wallet.On("PrepareWithdrawal", mock.Anything, coldAddress, amount, model.NewAmount(0), "").Return(message, nil).Once()
+				m.seqnos.On("ReserveSeqno", mock.Anything, message).Return(model.ErrSeqnoReserved).Once()
+			},
+		},
+		{
+			name: "confirmed transfer confirms the unconfirmed rebalance",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceUnconfirmed, now.Add(-10*time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferConfirmed}, nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceConfirmed && r.ConfirmedAt != nil
+				}), model.RebalanceUnconfirmed).Return(nil).Once()
+			},
+		},
+		{
+			name: "valid message of the rebalance is broadcast again",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").
+					Return(&model.OutgoingTransfer{ID: 1, Status: model.TransferSent}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.Anything).Return(nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceSent && r.Attempts == 1
+				}), model.RebalanceSent).Return(nil).Once()
+			},
+		},
+		{
+			name: "expired message fails the rebalance and its transfer",
+			mock: func(m rebalancerMocks) {
+				m.rebalances.On("ListActiveRebalances", mock.Anything).
+					Return([]*model.Rebalance{rebalance(model.RebalanceSent, now.Add(-10*time.Minute))}, nil).Once()
+				m.transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, "hash").Return(&model.OutgoingTransfer{
+					ID: 1, Kind: model.TransferRebalance, Reference: "3", AccountID: model.MasterAccountID, Status: model.TransferSent,
+				}, nil).Once()
+				m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+					return message.MessageHash == "hash"
+				})).Return(false, nil).Once()
+				m.rebalances.On("UpdateRebalance", mock.Anything, mock.MatchedBy(func(r *model.Rebalance) bool {
+					return r.Status == model.RebalanceFailed && r.Error == "message expired"
+				}), model.RebalanceSent).Return(nil).Once()
+				m.transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.RebalanceFailedEvent, mock.AnythingOfType("model.TransferPayload")).
+					Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rebalancer, m := newRebalancer(t, now, tt.limits...)
+			tt.mock(m)
+
+			require.NoError(t, rebalancer.Rebalance(context.Background()))
+		})
+	}
+}
+
+func TestRebalancer_Rebalance_AlertsOnce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rebalancer, m := newRebalancer(t, now, model.HotWalletLimit{Currency: model.CurrencyTON, Floor: model.NewAmount(100)})
+
+	m.rebalances.On("ListActiveRebalances", mock.Anything).Return(nil, nil).Times(4)
+	for _, nano := range []int64{50, 60, 150, 40} {
+		m.wallet.On("GetBalance", mock.Anything, uint32(0)).
+			Return(model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(nano)}, nil).Once()
+	}
+	m.events.On("Publish", mock.Anything, model.HotWalletLowEvent, mock.AnythingOfType("model.HotWalletLowPayload")).
+		Return(nil).Twice()
+
+	for range 4 {
+		require.NoError(t, rebalancer.Rebalance(context.Background()))
+	}
+}
+
+func TestNew_InvalidLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		coldAddress string
+		limits      []model.HotWalletLimit
+	}{
+		{
+			name:   "ceiling without the cold address",
+			limits: []model.HotWalletLimit{{Currency: model.CurrencyTON, Ceiling: model.NewAmount(10)}},
+		},
+		{
+			name:        "ceiling below the floor",
+			coldAddress: coldAddress,
+			limits:      []model.HotWalletLimit{{Currency: model.CurrencyTON, Ceiling: model.NewAmount(10), Floor: model.NewAmount(20)}},
+		},
+		{
+			name:        "duplicate currency",
+			coldAddress: coldAddress,
+			limits: []model.HotWalletLimit{
+				{Currency: model.CurrencyTON, Floor: model.NewAmount(10)},
+				{Currency: model.CurrencyTON, Floor: model.NewAmount(20)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Panics(t, func() {
+				New(&Options{
+					WalletPort:   portsmocks.NewMockWalletPort(t),
+					Database:     portsmocks.NewMockRebalanceDatabasePort(t),
+					TransferPort: portsmocks.NewMockOutgoingTransferDatabasePort(t),
+					TxPort:       portsmocks.NewMockDatabaseTransactionPort(t),
+					Events:       portsmocks.NewMockOutboxMessagePort(t),
+					Seqnos:       portsmocks.NewMockSeqnoDatabasePort(t),
+					ColdAddress:  tt.coldAddress,
+					Limits:       tt.limits,
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package transaction
+
+import (
+	"sync"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// accountChange is an account event applied to the index.
+type accountChange struct {
+	account model.Account
+	closed  bool
+}
+
+// accountIndex keeps the open accounts by their normalized address.
+// It is updated by account events and fully replaced by the periodic resync.
+type accountIndex struct {
+	mx        sync.RWMutex
+	byAddress map[string]model.Account
+	byID      map[model.AccountID]string
+
+	// resyncing is set while the full account list is loaded, changes applied
+	// meanwhile are recorded and replayed on top of the loaded list.
+	resyncing bool
+	changes   []accountChange
+}
+
+func newAccountIndex(accounts ...model.Account) *accountIndex {
+	idx := &accountIndex{
+		byAddress: make(map[string]model.Account),
+		byID:      make(map[model.AccountID]string),
+	}
+
+	for _, account := range accounts {
+		idx.add(account)
+	}
+	return idx
+}
+
+// Contains reports whether the address belongs to an open account in any address format.
+func (i *accountIndex) Contains(addr string) bool {
+	_, ok := i.Lookup(addr)
+	return ok
+}
+
+// Lookup returns the open account of the address in any address format.
+func (i *accountIndex) Lookup(addr string) (model.Account, bool) {
+	if addr == "" {
+		return model.Account{}, false
+	}
+
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	account, ok := i.byAddress[common.NormalizeAddress(addr)]
+	return account, ok
+}
+
+// Len returns the number of indexed accounts.
+func (i *accountIndex) Len() int {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+	return len(i.byAddress)
+}
+
+// Add indexes the account of the account_created event.
+func (i *accountIndex) Add(account model.Account) {
+	i.apply(accountChange{account: account})
+}
+
+// Remove evicts the account of the account_closed event.
+func (i *accountIndex) Remove(id model.AccountID) {
+	i.apply(accountChange{account: model.Account{ID: id}, closed: true})
+}
+
+// BeginResync starts recording changes that must survive the following Replace.
+func (i *accountIndex) BeginResync() {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	i.resyncing, i.changes = true, nil
+}
+
+// Replace swaps the index with the loaded accounts, so closed accounts missing from the list are evicted,
+// and replays the changes applied since BeginResync, as they may be newer than the list.
+func (i *accountIndex) Replace(accounts []model.Account) {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	i.byAddress = make(map[string]model.Account, len(accounts))
+	i.byID = make(map[model.AccountID]string, len(accounts))
+
+	for _, account := range accounts {
+		i.add(account)
+	}
+
+	for _, change := range i.changes {
+		i.applyLocked(change)
+	}
+	i.resyncing, i.changes = false, nil
+}
+
+// AbortResync stops recording changes after a failed resync.
+func (i *accountIndex) AbortResync() {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	i.resyncing, i.changes = false, nil
+}
+
+func (i *accountIndex) apply(change accountChange) {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	if i.resyncing {
+		i.changes = append(i.changes, change)
+	}
+	i.applyLocked(change)
+}
+
+func (i *accountIndex) applyLocked(change accountChange) {
+	if change.closed {
+		i.remove(change.account.ID)
+		return
+	}
+	i.add(change.account)
+}
+
+func (i *accountIndex) add(account model.Account) {
+	if account.Address == "" {
+		return
+	}
+
+	// the address of the account may change from empty to the deployed one
+	i.remove(account.ID)
+
+	addr := common.NormalizeAddress(string(account.Address))
+	i.byAddress[addr] = account
+	i.byID[account.ID] = addr
+}
+
+func (i *accountIndex) remove(id model.AccountID) {
+	if addr, ok := i.byID[id]; ok {
+		delete(i.byAddress, addr)
+		delete(i.byID, id)
+	}
+}
@@ -0,0 +1,366 @@
+package transaction
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
+)
+
+// wallet returns the account of our wallet with the address, the master and the payout wallets belong
+// to the master account.
+func (t *Transaction) wallet(addr string) (model.AccountID, bool) {
+	if addr == "" {
+		return "", false
+	}
+
+	if t.master != "" || t.payout != "" {
+		if normalized := common.NormalizeAddress(addr); normalized == t.master || normalized == t.payout {
+			return model.MasterAccountID, true
+		}
+	}
+
+	account, ok := t.accounts.Lookup(addr)
+	return account.ID, ok
+}
+
+// classify sets the account, direction and kind of the transaction and returns the outgoing transfers it executes or bounces.
+func (t *Transaction) classify(
+	ctx context.Context, tx *model.Transaction, bounced []int64,
+) ([]*model.OutgoingTransfer, error) {
+	sender, senderIsOurs := t.wallet(tx.Sender)
+	receiver, receiverIsOurs := t.wallet(tx.Receiver)
+
+	switch {
+	case receiverIsOurs && tx.MessageType == model.MessageTypeExternalIn:
+		tx.AccountID, tx.Direction = receiver, model.DirectionOut
+	case receiverIsOurs:
+		tx.AccountID, tx.Direction = receiver, model.DirectionIn
+	case senderIsOurs:
+		tx.AccountID, tx.Direction = sender, model.DirectionOut
+	}
+
+	var (
+		transfer  *model.OutgoingTransfer
+		transfers []*model.OutgoingTransfer
+		err       error
+	)
+
+	switch {
+	case tx.MessageType == model.MessageTypeExternalIn:
+		if !receiverIsOurs || t.transfers == nil {
+			return nil, nil
+		}
+
+		tx.Kind = model.TxKindFee
+		if tx.InMsgHash == "" {
+			return nil, nil
+		}
+
+		transfer, err = t.transfers.GetOutgoingTransferByMessageHash(ctx, tx.InMsgHash)
+		if err != nil {
+			if errors.Is(err, model.ErrTransferNotFound) {
+				return nil, nil
+			}
+			return nil, errors.Wrap(err, "get outgoing transfer")
+		}
+		tx.Kind, tx.Currency = model.TransactionKind(transfer.Kind), transfer.Currency
+	case tx.Bounced:
+		if !receiverIsOurs {
+			return nil, nil
+		}
+
+		tx.Kind = model.TxKindBounce
+		if t.transfers == nil {
+			return nil, nil
+		}
+
+		if queryID, ok := model.BouncedJettonQueryID(tx.Body); ok {
+			if transfer, err = t.jettonTransfer(ctx, queryID); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		var bounceHash string
+		if bounceHash, err = model.BouncedBodyHash(tx.Body); err != nil {
+			log.Warn().Err(err).Str("account", tx.AccountAddr).Int64("lt", tx.LT).Msg("decode bounced body")
+			return nil, nil
+		}
+
+		transfer, err = t.transfers.GetBouncedTransferCandidate(ctx, tx.Receiver, tx.Sender, bounceHash, bounced)
+		if err != nil {
+			if errors.Is(err, model.ErrTransferNotFound) {
+				return nil, nil
+			}
+			return nil, errors.Wrap(err, "get bounced transfer")
+		}
+	case tx.IsHighloadBatch() && receiverIsOurs:
+		tx.Kind = model.TxKindInternal
+		if t.transfers == nil || tx.InMsgHash == "" {
+			return nil, nil
+		}
+
+		transfers, err = t.transfers.ListOutgoingTransfersByMessageHash(ctx, tx.InMsgHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "list batch transfers")
+		}
+
+		if len(transfers) == 0 {
+			return nil, nil
+		}
+		tx.Direction, tx.Kind, tx.Currency = model.DirectionOut, model.TransactionKind(transfers[0].Kind), transfers[0].Currency
+	case receiverIsOurs && tx.IsJettonExcess():
+		// the excess of a jetton transfer that is not ours is a deposit like any other value
+		tx.Kind = model.TxKindDeposit
+		if t.transfers == nil {
+			return nil, nil
+		}
+
+		if transfer, err = t.jettonTransfer(ctx, *tx.QueryID); err != nil || transfer == nil {
+			return nil, err
+		}
+		tx.Kind = model.TransactionKind(transfer.Kind)
+	case senderIsOurs && receiverIsOurs:
+		tx.Kind = model.TxKindInternal
+	case receiverIsOurs && tx.Sender != "":
+		tx.Kind = model.TxKindDeposit
+	}
+
+	if transfer != nil {
+		transfers = []*model.OutgoingTransfer{transfer}
+	}
+
+	if len(transfers) == 1 {
+		tx.TransferID = &transfers[0].ID
+	}
+	return transfers, nil
+}
+
+// jettonTransfer returns the jetton transfer of the query id, nil if there is none.
+func (t *Transaction) jettonTransfer(ctx context.Context, queryID uint64) (*model.OutgoingTransfer, error) {
+	transfer, err := t.transfers.GetOutgoingTransferByJettonQueryID(ctx, queryID)
+	if err != nil {
+		if errors.Is(err, model.ErrTransferNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "get jetton transfer")
+	}
+	return transfer, nil
+}
+
+// process applies the effects of the stored transaction according to its kind.
+func (t *Transaction) process(ctx context.Context, tx *model.Transaction, transfers []*model.OutgoingTransfer) error {
+	switch tx.Kind {
+	case model.TxKindDeposit:
+		return t.processDeposit(ctx, tx)
+	case model.TxKindSweep, model.TxKindWithdrawal, model.TxKindTopUp, model.TxKindRebalance:
+		switch {
+		case tx.IsJettonExcess():
+			return t.processExcess(ctx, tx, transfers[0])
+		case tx.IsHighloadBatch():
+			return t.processBatch(ctx, tx, transfers)
+		}
+		return t.processOutgoing(ctx, tx, transfers[0])
+	case model.TxKindBounce:
+		return t.processBounce(ctx, tx, lo.FirstOrEmpty(transfers))
+	case model.TxKindFee:
+		return t.processFee(ctx, tx)
+	case model.TxKindInternal:
+	}
+	return nil
+}
+
+// processOutgoing confirms the transfer executed by the transaction and records the sweep, withdrawal, top-up
+// or rebalance with its network fee in the ledger, a transfer the wallet failed to execute only costs the fee.
+// A jetton transfer sent by the wallet is not executed yet, the wallet only paid the fee and the transfer
+// is confirmed by the excess of its gas, see processExcess.
+func (t *Transaction) processOutgoing(ctx context.Context, tx *model.Transaction, transfer *model.OutgoingTransfer) error {
+	if !tx.Success {
+		if err := t.moveTransfer(ctx, tx, transfer, model.TransferFailed); err != nil {
+			return err
+		}
+		return t.processFee(ctx, tx)
+	}
+
+	if transfer.JettonQueryID != 0 {
+		return t.processFee(ctx, tx)
+	}
+
+	if err := t.moveTransfer(ctx, tx, transfer, model.TransferConfirmed); err != nil {
+		return err
+	}
+	return t.recordOutgoing(ctx, transfer, tx.TotalFees)
+}
+
+// processBatch confirms or fails the transfers of the highload batch executed by the transaction, a failed batch
+// fails all of them. A TON transfer is confirmed by the outgoing message of the transaction sending its amount
+// to its destination, a transfer without one stays sent. The network fee of the batch is booked once
+// for the wallet, the transfers are recorded with the gas attached to them only. The jetton transfers of the batch
+// are confirmed by the excess of their gas like the jetton transfers of the other wallets.
+func (t *Transaction) processBatch(ctx context.Context, tx *model.Transaction, transfers []*model.OutgoingTransfer) error {
+	sent := make([]bool, len(tx.OutMessages))
+
+	for _, transfer := range transfers {
+		if !tx.Success {
+			if err := t.moveTransfer(ctx, tx, transfer, model.TransferFailed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if transfer.JettonQueryID != 0 {
+			continue
+		}
+
+		i := -1
+		for j, message := range tx.OutMessages {
+			if !sent[j] && message.Amount.Cmp(transfer.Amount) == 0 &&
+				common.NormalizeAddress(message.Receiver) == common.NormalizeAddress(transfer.To) {
+				i = j
+				break
+			}
+		}
+
+		if i < 0 {
+			log.Warn().Int64("transfer_id", transfer.ID).Str("tx_hash", tx.Hash).Msg("transfer not sent by the batch")
+			continue
+		}
+		sent[i] = true
+
+		if err := t.moveTransfer(ctx, tx, transfer, model.TransferConfirmed); err != nil {
+			return err
+		}
+
+		if err := t.recordOutgoing(ctx, transfer, model.NewAmount(0)); err != nil {
+			return err
+		}
+	}
+	return t.processFee(ctx, tx)
+}
+
+// processExcess confirms the jetton transfer whose excess of the gas is returned by the transaction and records it
+// in the ledger with the gas attached to it, the network fee of the sending wallet is booked with its transaction.
+// The excess itself is not recorded.
+func (t *Transaction) processExcess(ctx context.Context, tx *model.Transaction, transfer *model.OutgoingTransfer) error {
+	if transfer.Status != model.TransferSent {
+		log.Debug().Int64("transfer_id", transfer.ID).Str("status", string(transfer.Status)).Msg("jetton transfer already finished")
+		return nil
+	}
+
+	if err := t.moveTransfer(ctx, tx, transfer, model.TransferConfirmed); err != nil {
+		return err
+	}
+	return t.recordOutgoing(ctx, transfer, model.NewAmount(0))
+}
+
+// recordOutgoing records the confirmed transfer in the ledger with the network fee of its transaction.
+func (t *Transaction) recordOutgoing(ctx context.Context, transfer *model.OutgoingTransfer, networkFee model.Amount) error {
+	amount := model.Balance{Currency: transfer.Currency, Amount: transfer.Amount}
+
+	var err error
+	switch transfer.Kind {
+	case model.TransferSweep:
+		_, err = t.ledger.RecordSweep(ctx, transfer.AccountID, amount, networkFee.Add(transfer.Gas), transfer.LedgerReference())
+	case model.TransferWithdrawal:
+		_, err = t.ledger.RecordWithdrawal(ctx, transfer.AccountID, amount, networkFee.Add(transfer.Gas), transfer.LedgerReference())
+	case model.TransferTopUp:
+		_, err = t.ledger.RecordTopUp(ctx, transfer.AccountID, transfer.Amount, networkFee, transfer.LedgerReference())
+	case model.TransferRebalance:
+		_, err = t.ledger.RecordRebalance(ctx, amount, networkFee.Add(transfer.Gas), transfer.LedgerReference())
+	}
+	return ignoreRecorded(err, "record "+string(transfer.Kind))
+}
+
+// processBounce reverses the ledger entry of the transfer whose value was returned by the bounce. A jetton transfer
+// returned by a jetton wallet was not executed and is not recorded, it fails. A bounce not matching any transfer
+// is published for an operator to reconcile.
+func (t *Transaction) processBounce(ctx context.Context, tx *model.Transaction, transfer *model.OutgoingTransfer) error {
+	if transfer == nil {
+		log.Warn().Str("account", tx.AccountAddr).Int64("lt", tx.LT).Msg("bounced transfer not found")
+		if t.events == nil {
+			return nil
+		}
+
+		if err := t.events.Publish(ctx, model.BounceUnmatchedEvent, model.NewBounceUnmatchedPayload(tx)); err != nil {
+			return errors.Wrapf(err, "publish %s", model.BounceUnmatchedEvent)
+		}
+		return nil
+	}
+
+	if transfer.JettonQueryID != 0 {
+		if transfer.Status != model.TransferSent {
+			log.Debug().Int64("transfer_id", transfer.ID).Str("status", string(transfer.Status)).Msg("jetton transfer already finished")
+			return nil
+		}
+		return t.moveTransfer(ctx, tx, transfer, model.TransferFailed)
+	}
+
+	if err := t.moveTransfer(ctx, tx, transfer, model.TransferBounced); err != nil {
+		return err
+	}
+
+	sent := model.Balance{Currency: transfer.Currency, Amount: transfer.Amount}
+	_, err := t.ledger.RecordBounce(ctx, transfer.Kind, transfer.AccountID, sent, tx.Amount, transfer.LedgerReference())
+	return ignoreRecorded(err, "record bounce")
+}
+
+// processFee books the network fee paid by our wallet for the transaction.
+func (t *Transaction) processFee(ctx context.Context, tx *model.Transaction) error {
+	accountID, ok := t.wallet(tx.Receiver)
+	if !ok || tx.TotalFees.Sign() <= 0 {
+		return nil
+	}
+
+	reference := tx.Hash
+	if reference == "" {
+		reference = tx.AccountAddr + ":" + strconv.FormatInt(tx.LT, 10)
+	}
+
+	_, err := t.ledger.RecordFee(ctx, accountID, tx.TotalFees, reference)
+	return ignoreRecorded(err, "record fee")
+}
+
+// moveTransfer moves the transfer to the status and publishes the transition, the executing transaction
+// and its fee are kept when the transfer is confirmed or failed. The confirmation and the failure of
+// a withdrawal transfer are published by the withdrawal worker with the withdrawal instead.
+func (t *Transaction) moveTransfer(
+	ctx context.Context, tx *model.Transaction, transfer *model.OutgoingTransfer, to model.TransferStatus,
+) error {
+	from := transfer.Status
+	if err := transfer.Transition(to, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if to != model.TransferBounced {
+		transfer.TxHash, transfer.Fee = tx.Hash, tx.TotalFees
+	}
+
+	if err := t.transfers.UpdateOutgoingTransferStatus(ctx, transfer, from); err != nil {
+		return errors.Wrapf(err, "update transfer to %s", to)
+	}
+
+	if t.events != nil && (transfer.Kind != model.TransferWithdrawal || to == model.TransferBounced) {
+		eventType := model.TransferEventType(transfer.Kind, to)
+		if err := t.events.Publish(ctx, eventType, model.NewTransferPayload(transfer)); err != nil {
+			return errors.Wrapf(err, "publish %s", eventType)
+		}
+	}
+
+	log.Debug().Int64("transfer_id", transfer.ID).Str("from", string(from)).Str("to", string(to)).Msg("transfer transition")
+	return nil
+}
+
+// ignoreRecorded drops the error of an entry recorded before, e.g. by a replayed transaction.
+func ignoreRecorded(err error, msg string) error {
+	if err == nil || errors.Is(err, model.ErrLedgerEntryExists) {
+		return nil
+	}
+	return errors.Wrap(err, msg)
+}
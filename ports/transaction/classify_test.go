@@ -0,0 +1,389 @@
+package transaction
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTransaction_Classify(t *testing.T) {
+	t.Parallel()
+
+	body := cell.BeginCell().MustStoreUInt(1, 32).MustStoreUInt(100, 32).EndCell()
+	encodedBody := base64.StdEncoding.EncodeToString(body.ToBOC())
+	messageHash := hex.EncodeToString(body.Hash())
+
+	external := func(wallet string, success bool) string {
+		phase := `{"Success": true}`
+		if !success {
+			phase = `{"Success": false, "Details": {"ExitCode": 37}}`
+		}
+		return `{"AccountAddr": "` + wallet + `", "LT": 7, "Hash": "AQI=", "TotalFees": {"Coins": "5"},
+			"IO": {"In": {"MsgType": "EXTERNAL_IN", "Msg": {"DstAddr": "` + wallet + `", "Body": "` + encodedBody + `"}}},
+			"Description": {"ComputePhase": {"Phase": ` + phase + `}}}`
+	}
+
+	batchBody := cell.BeginCell().MustStoreUInt(uint64(model.OpHighloadBatch), 32).MustStoreUInt(9, 64).
+		MustStoreRef(cell.BeginCell().EndCell()).EndCell()
+	batchHash := hex.EncodeToString(batchBody.Hash())
+
+	batch := func(success bool, outAmount string) string {
+		return `{"AccountAddr": "payout-wallet", "LT": 7, "Hash": "AQI=", "TotalFees": {"Coins": "5"},
+			"IO": {"In": {"MsgType": "INTERNAL", "Msg": {"SrcAddr": "payout-wallet", "DstAddr": "payout-wallet",
+			"Body": "` + base64.StdEncoding.EncodeToString(batchBody.ToBOC()) + `"}}},
+			"OutMsgs": [{"DstAddr": "receiver", "Amount": "` + outAmount + `"}, {"DstAddr": "jetton-wallet", "Amount": "300"}],
+			"Description": {"ComputePhase": {"Phase": {"Success": true}}, "ActionPhase": {"Success": ` + strconv.FormatBool(success) + `}}}`
+	}
+
+	batchWithdrawals := func() []*model.OutgoingTransfer {
+		return []*model.OutgoingTransfer{
+			{
+				ID: 5, Kind: model.TransferWithdrawal, Reference: "w2", AccountID: "1", To: "receiver",
+				Currency: model.CurrencyTON, Amount: model.NewAmount(1000), Status: model.TransferSent,
+			},
+			{
+				ID: 6, Kind: model.TransferWithdrawal, Reference: "w3", AccountID: "2", Currency: model.CurrencyUSDT,
+				Amount: model.NewAmount(5000), Gas: model.NewAmount(300), JettonQueryID: 78, Status: model.TransferSent,
+			},
+		}
+	}
+
+	sweep := func() *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: 1, Kind: model.TransferSweep, Reference: "s1", AccountID: "1",
+			Currency: model.CurrencyTON, Amount: model.NewAmount(1000), Status: model.TransferSent,
+		}
+	}
+
+	jettonSweep := func() *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: 3, Kind: model.TransferSweep, Reference: "s2", AccountID: "1", Currency: model.CurrencyUSDT,
+			Amount: model.NewAmount(5000), Gas: model.NewAmount(300), Status: model.TransferSent,
+		}
+	}
+
+	jettonQuerySweep := func() *model.OutgoingTransfer {
+		transfer := jettonSweep()
+		transfer.JettonQueryID = 77
+		return transfer
+	}
+
+	excess := `{"AccountAddr": "master-wallet", "LT": 9, "Hash": "AwQ=", "TotalFees": {"Coins": "2"},
+		"IO": {"In": {"MsgType": "INTERNAL", "Msg": {"SrcAddr": "master-jetton-wallet", "DstAddr": "master-wallet",
+		"Amount": "250", "Body": "` + base64.StdEncoding.EncodeToString(cell.BeginCell().
+		MustStoreUInt(uint64(model.OpExcesses), 32).MustStoreUInt(77, 64).EndCell().ToBOC()) + `"}}},
+		"Description": {"ComputePhase": {"Phase": {"Success": true}}}}`
+
+	returnedJettonTransfer := base64.StdEncoding.EncodeToString(cell.BeginCell().MustStoreUInt(uint64(model.OpBounce), 32).
+		MustStoreUInt(uint64(model.OpJettonTransfer), 32).MustStoreUInt(77, 64).MustStoreCoins(5000).EndCell().ToBOC())
+
+	topUp := func() *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: 4, Kind: model.TransferTopUp, Reference: "s2", AccountID: "1",
+			Currency: model.CurrencyTON, Amount: model.NewAmount(350), Status: model.TransferSent,
+		}
+	}
+
+	comment := cell.BeginCell().MustStoreUInt(0, 32).MustStoreStringSnake("invoice 42").EndCell()
+	bounceHash, err := model.BounceHash(comment)
+	require.NoError(t, err)
+
+	commentSlice := comment.BeginParse()
+	bouncedBody := base64.StdEncoding.EncodeToString(cell.BeginCell().MustStoreUInt(uint64(model.OpBounce), 32).
+		MustStoreSlice(commentSlice.MustLoadSlice(commentSlice.BitsLeft()), comment.BitsSize()).EndCell().ToBOC())
+
+	withdrawal := func(status model.TransferStatus) *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: 2, Kind: model.TransferWithdrawal, Reference: "w1", AccountID: "1",
+			Currency: model.CurrencyTON, Amount: model.NewAmount(1000), Status: status,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		kind    model.TransactionKind
+		event   model.EventType
+		payload string // type of the payload of the event, model.TransferPayload by default
+		silent  bool   // the events are set, but the transition is not published
+		mock    func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort)
+	}{
+		{
+			name:    "sweep sent by the deposit wallet",
+			message: external("ours", true),
+			kind:    model.TxKindSweep,
+			event:   model.SweepConfirmedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(sweep(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferConfirmed && transfer.TxHash == "0102" && transfer.Fee.Nano() == "5"
+				}), model.TransferSent).Return(nil).Once()
+				ledger.On("RecordSweep", mock.Anything, "1",
+					model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(1000)}, model.NewAmount(5), "sweep:s1",
+				).Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "jetton sweep without a query id books the gas with the fee",
+			message: external("ours", true),
+			kind:    model.TxKindSweep,
+			event:   model.SweepConfirmedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(jettonSweep(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.Anything, model.TransferSent).Return(nil).Once()
+				ledger.On("RecordSweep", mock.Anything, "1",
+					model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}, model.NewAmount(305), "sweep:s2",
+				).Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "jetton sweep waits for the excess of its gas",
+			message: external("ours", true),
+			kind:    model.TxKindSweep,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(jettonQuerySweep(), nil).Once()
+				ledger.On("RecordFee", mock.Anything, "1", model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "excess of the gas confirms the jetton sweep",
+			message: excess,
+			kind:    model.TxKindSweep,
+			event:   model.SweepConfirmedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByJettonQueryID", mock.Anything, uint64(77)).Return(jettonQuerySweep(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferConfirmed && transfer.TxHash == "0304"
+				}), model.TransferSent).Return(nil).Once()
+				ledger.On("RecordSweep", mock.Anything, "1",
+					model.Balance{Currency: model.CurrencyUSDT, Amount: model.NewAmount(5000)}, model.NewAmount(300), "sweep:s2",
+				).Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "excess of an unknown jetton transfer",
+			message: excess,
+			kind:    model.TxKindDeposit,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, _ *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByJettonQueryID", mock.Anything, uint64(77)).Return(nil, model.ErrTransferNotFound).Once()
+			},
+		},
+		{
+			name: "jetton transfer returned by the jetton wallet fails the sweep",
+			message: `{"AccountAddr": "ours", "LT": 8, "IO": {"In": {"MsgType": "INTERNAL",
+				"Msg": {"SrcAddr": "jetton-wallet", "DstAddr": "ours", "Amount": "290", "Bounced": true,
+				"Body": "` + returnedJettonTransfer + `"}}}}`,
+			kind:  model.TxKindBounce,
+			event: model.SweepFailedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, _ *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByJettonQueryID", mock.Anything, uint64(77)).Return(jettonQuerySweep(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+			},
+		},
+		{
+			name:    "top-up sent by the master wallet",
+			message: external("master-wallet", true),
+			kind:    model.TxKindTopUp,
+			event:   model.TopUpConfirmedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(topUp(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.Anything, model.TransferSent).Return(nil).Once()
+				ledger.On("RecordTopUp", mock.Anything, "1", model.NewAmount(350), model.NewAmount(5), "top_up:s2").
+					Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "rebalance sent to the cold storage",
+			message: external("master-wallet", true),
+			kind:    model.TxKindRebalance,
+			event:   model.RebalanceConfirmedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(&model.OutgoingTransfer{
+					ID: 7, Kind: model.TransferRebalance, Reference: "r1", AccountID: model.MasterAccountID,
+					Currency: model.CurrencyTON, Amount: model.NewAmount(9000), Status: model.TransferSent,
+				}, nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.Anything, model.TransferSent).Return(nil).Once()
+				ledger.On("RecordRebalance", mock.Anything,
+					model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(9000)}, model.NewAmount(5), "rebalance:r1",
+				).Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "withdrawal sent by the master wallet",
+			message: external("master-wallet", true),
+			kind:    model.TxKindWithdrawal,
+			silent:  true,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(withdrawal(model.TransferSent), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.Anything, model.TransferSent).Return(nil).Once()
+				ledger.On("RecordWithdrawal", mock.Anything, "1", mock.Anything, model.NewAmount(5), "withdrawal:w1").
+					Return(nil, model.ErrLedgerEntryExists).Once()
+			},
+		},
+		{
+			name:    "withdrawal batch sent by the payout wallet",
+			message: batch(true, "1000"),
+			kind:    model.TxKindWithdrawal,
+			silent:  true,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, batchHash).Return(batchWithdrawals(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.ID == 5 && transfer.Status == model.TransferConfirmed && transfer.TxHash == "0102"
+				}), model.TransferSent).Return(nil).Once()
+				ledger.On("RecordWithdrawal", mock.Anything, "1", mock.Anything, model.NewAmount(0), "withdrawal:w2").
+					Return(&model.LedgerEntry{}, nil).Once()
+				ledger.On("RecordFee", mock.Anything, model.MasterAccountID, model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "batch transfer without its message stays sent",
+			message: batch(true, "999"),
+			kind:    model.TxKindWithdrawal,
+			silent:  true,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, batchHash).Return(batchWithdrawals(), nil).Once()
+				ledger.On("RecordFee", mock.Anything, model.MasterAccountID, model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "failed batch fails all its transfers",
+			message: batch(false, "1000"),
+			kind:    model.TxKindWithdrawal,
+			silent:  true,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, batchHash).Return(batchWithdrawals(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Twice()
+				ledger.On("RecordFee", mock.Anything, model.MasterAccountID, model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "failed transfer only costs the fee",
+			message: external("ours", false),
+			kind:    model.TxKindSweep,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(sweep(), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+				ledger.On("RecordFee", mock.Anything, "1", model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name:    "fee-only external message",
+			message: external("ours", true),
+			kind:    model.TxKindFee,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetOutgoingTransferByMessageHash", mock.Anything, messageHash).Return(nil, model.ErrTransferNotFound).Once()
+				ledger.On("RecordFee", mock.Anything, "1", model.NewAmount(5), "0102").Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name: "bounce-back of a withdrawal",
+			message: `{"AccountAddr": "master-wallet", "LT": 8, "IO": {"In": {"MsgType": "INTERNAL",
+				"Msg": {"SrcAddr": "other", "DstAddr": "master-wallet", "Amount": "990", "Bounced": true,
+				"Body": "` + bouncedBody + `"}}}}`,
+			kind:  model.TxKindBounce,
+			event: model.WithdrawalBouncedEvent,
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, ledger *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetBouncedTransferCandidate", mock.Anything, "master-wallet", "other", bounceHash, []int64(nil)).
+					Return(withdrawal(model.TransferConfirmed), nil).Once()
+				transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferBounced
+				}), model.TransferConfirmed).Return(nil).Once()
+				ledger.On("RecordBounce", mock.Anything, model.TransferWithdrawal, "1",
+					model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(1000)}, model.NewAmount(990), "withdrawal:w1",
+				).Return(&model.LedgerEntry{}, nil).Once()
+			},
+		},
+		{
+			name: "bounce of an unknown transfer",
+			message: `{"AccountAddr": "ours", "LT": 8, "IO": {"In": {"MsgType": "INTERNAL",
+				"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "990", "Bounced": true,
+				"Body": "` + bouncedBody + `"}}}}`,
+			kind:    model.TxKindBounce,
+			event:   model.BounceUnmatchedEvent,
+			payload: "model.BounceUnmatchedPayload",
+			mock: func(transfers *portsmocks.MockOutgoingTransferDatabasePort, _ *portsmocks.MockLedgerServicePort) {
+				transfers.On("GetBouncedTransferCandidate", mock.Anything, "ours", "other", bounceHash, []int64(nil)).
+					Return(nil, model.ErrTransferNotFound).Once()
+			},
+		},
+		{
+			name: "bounce without the returned body",
+			message: `{"AccountAddr": "ours", "LT": 8, "IO": {"In": {"MsgType": "INTERNAL",
+				"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "990", "Bounced": true}}}}`,
+			kind:    model.TxKindBounce,
+			event:   model.BounceUnmatchedEvent,
+			payload: "model.BounceUnmatchedPayload",
+		},
+		{
+			name: "sweep arriving at the master wallet",
+			message: `{"AccountAddr": "master-wallet", "LT": 9, "IO": {"In": {"MsgType": "INTERNAL",
+				"Msg": {"SrcAddr": "ours", "DstAddr": "master-wallet", "Amount": "1000"}}}}`,
+			kind: model.TxKindInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+			txPort.On("WithInTransaction", ctx, mock.Anything).
+				Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+
+			transactionPort := portsmocks.NewMockTransactionalDatabasePort(t)
+			transactionPort.On("InsertTransaction", ctx, mock.MatchedBy(func(tx *model.Transaction) bool {
+				return tx.Kind == tt.kind && tx.AccountID != "" && tx.Direction != ""
+			})).Return(func(_ context.Context, tx *model.Transaction) (*model.Transaction, error) { return tx, nil }).Once()
+
+			transfers := portsmocks.NewMockOutgoingTransferDatabasePort(t)
+			ledger := portsmocks.NewMockLedgerServicePort(t)
+			if tt.mock != nil {
+				tt.mock(transfers, ledger)
+			}
+
+			transaction := &Transaction{
+				dbPort:      portsmocks.NewMockDatabasePort(t),
+				txPort:      txPort,
+				transaction: transactionPort,
+				transfers:   transfers,
+				ledger:      ledger,
+				master:      "master-wallet",
+				payout:      "payout-wallet",
+				accounts:    newAccountIndex(model.Account{ID: "1", WalletID: 1, Address: "ours"}),
+				interval:    1 * time.Minute,
+			}
+
+			if tt.event != "" || tt.silent {
+				events := portsmocks.NewMockOutboxMessagePort(t)
+				if tt.event != "" {
+					payload := "model.TransferPayload"
+					if tt.payload != "" {
+						payload = tt.payload
+					}
+					events.On("Publish", mock.Anything, tt.event, mock.AnythingOfType(payload)).Return(nil).Once()
+				}
+				transaction.events = events
+			}
+
+			require.NoError(t, transaction.Handle(ctx, []byte(tt.message)))
+		})
+	}
+}
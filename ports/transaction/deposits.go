@@ -0,0 +1,68 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-faster/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// processDeposit drives the deposit of the incoming transfer to one of our accounts through its lifecycle.
+// The scanner delivers transactions of committed masterchain blocks only, they are final once detected,
+// so the deposit is confirmed and credited right away unless its value was bounced or it is too small.
+func (t *Transaction) processDeposit(ctx context.Context, tx *model.Transaction) error {
+	// bounced messages return the value of our own outgoing messages, they are not deposits
+	if t.deposits == nil || tx.Sender == "" || tx.Bounced || tx.Amount.Sign() <= 0 {
+		return nil
+	}
+
+	account, ok := t.accounts.Lookup(tx.Receiver)
+	if !ok {
+		return nil
+	}
+
+	deposit, err := t.deposits.Detect(ctx, account.ID, tx)
+	if err != nil {
+		if errors.Is(err, model.ErrDepositExists) {
+			log.Debug().Str("account", tx.AccountAddr).Int64("lt", tx.LT).Msg("deposit already detected")
+			return nil
+		}
+		return errors.Wrap(err, "detect deposit")
+	}
+
+	if reason, bounced := bounceReason(tx); bounced {
+		if err = t.deposits.Bounce(ctx, deposit, reason); err != nil {
+			return errors.Wrap(err, "bounce deposit")
+		}
+		return nil
+	}
+
+	if tx.Amount.BigInt().Cmp(t.minDeposit.BigInt()) < 0 {
+		reason := fmt.Sprintf("amount %s is below the minimal deposit %s", tx.Amount.String(), t.minDeposit.String())
+		if err = t.deposits.Reject(ctx, deposit, reason); err != nil {
+			return errors.Wrap(err, "reject deposit")
+		}
+		return nil
+	}
+
+	if err = t.deposits.Confirm(ctx, deposit); err != nil {
+		return errors.Wrap(err, "confirm deposit")
+	}
+
+	if err = t.deposits.Credit(ctx, deposit); err != nil {
+		return errors.Wrap(err, "credit deposit")
+	}
+	return nil
+}
+
+// bounceReason reports whether the value of the incoming message was returned to the sender:
+// a bounceable message is bounced back if its processing by the wallet failed.
+func bounceReason(tx *model.Transaction) (string, bool) {
+	if tx.Bounce && !tx.Success {
+		return fmt.Sprintf("processing failed with exit code %d", tx.ExitCode), true
+	}
+	return "", false
+}
@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+func TestTransaction_ProcessDeposit(t *testing.T) {
+	t.Parallel()
+
+	deposit := &model.Deposit{ID: 1, AccountID: "1", Status: model.DepositDetected}
+
+	tests := []struct {
+		name       string
+		message    string
+		minDeposit int64
+		detectErr  error
+		calls      []string
+	}{
+		{
+			name: "credited",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "1500000000"}}},
+				"Description": {"ComputePhase": {"Phase": {"Success": true}}}}`,
+			calls: []string{"Detect", "Confirm", "Credit"},
+		},
+		{
+			name: "already detected",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "1500000000"}}},
+				"Description": {"ComputePhase": {"Phase": {"Success": true}}}}`,
+			detectErr: model.ErrDepositExists,
+			calls:     []string{"Detect"},
+		},
+		{
+			name: "bounced back to the sender",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "1500000000", "Bounce": true}}},
+				"Description": {"ComputePhase": {"Phase": {"Success": false, "Details": {"ExitCode": 33}}}}}`,
+			calls: []string{"Detect", "Bounce"},
+		},
+		{
+			name: "rejected below the minimal amount",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "1000"}}},
+				"Description": {"ComputePhase": {"Phase": {"Success": true}}}}`,
+			minDeposit: 1_000_000,
+			calls:      []string{"Detect", "Reject"},
+		},
+		{
+			name:    "outgoing transfer is not a deposit",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"DstAddr": "ours"}}}}`,
+		},
+		{
+			name:    "bounce of our message is not a deposit",
+			message: `{"AccountAddr": "ours", "LT": 7, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours", "Amount": "1000", "Bounced": true}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+			txPort.On("WithInTransaction", ctx, mock.Anything).
+				Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+
+			transactionPort := portsmocks.NewMockTransactionalDatabasePort(t)
+			transactionPort.On("InsertTransaction", ctx, mock.Anything).Return(&model.Transaction{}, nil).Once()
+
+			var calls []string
+
+			deposits := portsmocks.NewMockDepositServicePort(t)
+			deposits.On("Detect", ctx, "1", mock.Anything).Return(deposit, tt.detectErr).Maybe().
+				Run(func(mock.Arguments) { calls = append(calls, "Detect") })
+			deposits.On("Confirm", ctx, deposit).Return(nil).Maybe().Run(func(mock.Arguments) { calls = append(calls, "Confirm") })
+			deposits.On("Credit", ctx, deposit).Return(nil).Maybe().Run(func(mock.Arguments) { calls = append(calls, "Credit") })
+			deposits.On("Bounce", ctx, deposit, "processing failed with exit code 33").Return(nil).Maybe().
+				Run(func(mock.Arguments) { calls = append(calls, "Bounce") })
+			deposits.On("Reject", ctx, deposit, mock.Anything).Return(nil).Maybe().
+				Run(func(mock.Arguments) { calls = append(calls, "Reject") })
+
+			transaction := &Transaction{
+				dbPort:      portsmocks.NewMockDatabasePort(t),
+				txPort:      txPort,
+				transaction: transactionPort,
+				deposits:    deposits,
+				minDeposit:  model.NewAmount(tt.minDeposit),
+				accounts:    newAccountIndex(model.Account{ID: "1", WalletID: 1, Address: "ours"}),
+				interval:    1 * time.Minute,
+			}
+
+			require.NoError(t, transaction.Handle(ctx, []byte(tt.message)))
+			require.Equal(t, tt.calls, calls)
+		})
+	}
+}
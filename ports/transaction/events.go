@@ -0,0 +1,52 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+// AccountEvents handles the account outbox events and updates the account index of the transaction processor.
+type AccountEvents struct {
+	accounts *accountIndex
+}
+
+// AccountEvents returns the handler of the account outbox events.
+func (t *Transaction) AccountEvents() *AccountEvents {
+	return &AccountEvents{accounts: t.accounts}
+}
+
+// Handle applies account_created and account_closed events, other events are ignored.
+// Malformed events are dropped as the periodic resync repairs the index anyway.
+func (e *AccountEvents) Handle(_ context.Context, message []byte) error {
+	var event model.OutboxMessage
+	if err := json.Unmarshal(message, &event); err != nil {
+		log.Warn().Err(err).Msg("unmarshal outbox message")
+		return nil
+	}
+
+	switch event.EventType {
+	case model.AccountCreated:
+		var payload model.AccountCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Warn().Err(err).Int64("event_id", event.EventID).Msg("unmarshal account created payload")
+			return nil
+		}
+
+		e.accounts.Add(model.Account{ID: payload.AccountID, WalletID: payload.WalletID, Address: payload.Address})
+		log.Debug().Str("account_id", payload.AccountID).Str("address", string(payload.Address)).Msg("account indexed")
+	case model.AccountClosed:
+		var payload model.AccountClosedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Warn().Err(err).Int64("event_id", event.EventID).Msg("unmarshal account closed payload")
+			return nil
+		}
+
+		e.accounts.Remove(payload.AccountID)
+		log.Debug().Str("account_id", payload.AccountID).Msg("account evicted")
+	}
+	return nil
+}
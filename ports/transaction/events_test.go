@@ -0,0 +1,79 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+)
+
+func accountEvent(t *testing.T, eventType model.EventType, payload any) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	message, err := model.OutboxEvent{ID: 1, EventType: eventType, Payload: data, CreatedAt: time.Now()}.Message()
+	require.NoError(t, err)
+	return message
+}
+
+func TestAccountEvents_Handle(t *testing.T) {
+	t.Parallel()
+
+	const (
+		friendly = "EQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iIMp"
+		raw      = "0:cda1734a49746f3b387796951c69e890635771cc6eb013ab989ff0b0a21f3488"
+	)
+
+	ctx := context.Background()
+	transaction := &Transaction{accounts: newAccountIndex()}
+	events := transaction.AccountEvents()
+
+	created := model.AccountCreatedPayload{AccountID: "1", WalletID: 1, Address: friendly}
+	require.NoError(t, events.Handle(ctx, accountEvent(t, model.AccountCreated, created)))
+	require.True(t, transaction.isRelevant(&model.Transaction{Receiver: raw}))
+
+	require.NoError(t, events.Handle(ctx, accountEvent(t, "wallet_created", created)))
+	require.NoError(t, events.Handle(ctx, []byte("not json")))
+	require.Equal(t, 1, transaction.accounts.Len())
+
+	closed := model.AccountClosedPayload{AccountID: "1"}
+	require.NoError(t, events.Handle(ctx, accountEvent(t, model.AccountClosed, closed)))
+	require.False(t, transaction.isRelevant(&model.Transaction{Receiver: friendly}))
+}
+
+func TestAccountIndex_Resync(t *testing.T) {
+	t.Parallel()
+
+	idx := newAccountIndex(
+		model.Account{ID: "1", WalletID: 1, Address: "addr1"},
+		model.Account{ID: "2", WalletID: 2, Address: "addr2"},
+	)
+
+	idx.BeginResync()
+
+	// events observed while the account list is loaded
+	idx.Add(model.Account{ID: "3", WalletID: 3, Address: "addr3"})
+	idx.Remove("1")
+
+	// the loaded list misses the account closed since the last resync and the new one,
+	// but still contains the one closed during the resync
+	idx.Replace([]model.Account{
+		{ID: "1", WalletID: 1, Address: "addr1"},
+	})
+
+	require.Equal(t, map[string]model.Account{
+		"addr3": {ID: "3", WalletID: 3, Address: "addr3"},
+	}, idx.byAddress)
+
+	idx.Add(model.Account{ID: "4", WalletID: 4, Address: "addr4"})
+	idx.Replace([]model.Account{{ID: "5", WalletID: 5, Address: "addr5"}})
+
+	require.True(t, idx.Contains("addr5"))
+	require.False(t, idx.Contains("addr4"), "changes outside of a resync are not replayed")
+}
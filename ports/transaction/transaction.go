@@ -2,7 +2,7 @@ package transaction
 
 import (
 	"context"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/go-faster/errors"
@@ -12,10 +12,12 @@ import (
 
 	"github.com/kriuchkov/tonbeacon/core/model"
 	"github.com/kriuchkov/tonbeacon/core/ports"
+	"github.com/kriuchkov/tonbeacon/pkg/common"
 )
 
 const (
-	// defaultUpdateInterval is the default interval for updating accounts.
+	// defaultUpdateInterval is the default interval of the full account resync,
+	// account events update the index in between.
 	defaultUpdateInterval = 5 * time.Second
 )
 
@@ -24,6 +26,23 @@ type Options struct {
 	TxPort          ports.DatabaseTransactionPort   `validate:"required"`
 	TransactionPort ports.TransactionalDatabasePort `validate:"required"`
 	Interval        time.Duration
+
+	// Deposits drives the lifecycle of the incoming transfers, transactions are only stored if it is not set.
+	Deposits ports.DepositServicePort
+	// MinDepositAmount is the minimal credited amount in nanotons, smaller deposits are rejected.
+	MinDepositAmount model.Amount
+
+	// Transfers and Ledger link the transactions of our wallets to the outgoing transfers they execute or bounce
+	// and record their ledger effects, outgoing transactions are not classified if they are not set.
+	Transfers ports.OutgoingTransferDatabasePort `validate:"required_with=Ledger"`
+	Ledger    ports.LedgerServicePort            `validate:"required_with=Transfers"`
+	// MasterAddress is the address of the master wallet sending the withdrawals.
+	MasterAddress string
+	// PayoutAddress is the address of the highload wallet sending the withdrawals in batches,
+	// it belongs to the master account like the master wallet.
+	PayoutAddress string
+	// Events publishes the transitions of the outgoing transfers.
+	Events ports.OutboxMessagePort
 }
 
 func (o *Options) SetDefaults() {
@@ -33,14 +52,20 @@ func (o *Options) SetDefaults() {
 }
 
 type Transaction struct {
-	mx          sync.RWMutex
-	accountList map[model.Address]*model.Account
-	interval    time.Duration
+	accounts *accountIndex
+	interval time.Duration
 
 	// ports
 	txPort      ports.DatabaseTransactionPort
 	dbPort      ports.AccountDatabasePort
 	transaction ports.TransactionalDatabasePort
+	deposits    ports.DepositServicePort
+	minDeposit  model.Amount
+	transfers   ports.OutgoingTransferDatabasePort
+	ledger      ports.LedgerServicePort
+	events      ports.OutboxMessagePort
+	master      string // Raw address of the master wallet
+	payout      string // Raw address of the payout wallet
 }
 
 func New(ctx context.Context, opts *Options) *Transaction {
@@ -54,10 +79,23 @@ func New(ctx context.Context, opts *Options) *Transaction {
 		dbPort:      opts.DatabasePort,
 		txPort:      opts.TxPort,
 		transaction: opts.TransactionPort,
-		accountList: make(map[model.Address]*model.Account),
+		deposits:    opts.Deposits,
+		minDeposit:  opts.MinDepositAmount,
+		transfers:   opts.Transfers,
+		ledger:      opts.Ledger,
+		events:      opts.Events,
+		accounts:    newAccountIndex(),
 		interval:    opts.Interval,
 	}
 
+	if opts.MasterAddress != "" {
+		t.master = common.NormalizeAddress(opts.MasterAddress)
+	}
+
+	if opts.PayoutAddress != "" {
+		t.payout = common.NormalizeAddress(opts.PayoutAddress)
+	}
+
 	done := make(chan struct{})
 	defer close(done)
 
@@ -94,16 +132,16 @@ func (t *Transaction) update(ctx context.Context, ch chan struct{}) error {
 }
 
 func (t *Transaction) updateAccounts(ctx context.Context) error {
+	t.accounts.BeginResync()
+
 	accountList, err := t.dbPort.ListAccounts(ctx, model.ListAccountFilter{IsClosed: lo.ToPtr(false)})
 	if err != nil {
+		t.accounts.AbortResync()
 		return errors.Wrap(err, "list accounts")
 	}
 
-	t.mx.Lock()
-	defer t.mx.Unlock()
-	for _, account := range accountList {
-		t.accountList[account.Address] = &account
-	}
+	t.accounts.Replace(accountList)
+	log.Debug().Int("count", t.accounts.Len()).Msg("accounts resynced")
 	return nil
 }
 
@@ -113,25 +151,26 @@ func (t *Transaction) Handle(ctx context.Context, message []byte) error {
 		return errors.Wrap(err, "unmarshal tx")
 	}
 
-	t.mx.RLock()
-	defer t.mx.RUnlock()
-
-	accounts := []*model.Account{
-		t.accountList[model.Address(tx.Sender)],
-		t.accountList[model.Address(tx.Receiver)],
-	}
-
-	if ok := lo.ContainsBy(accounts, func(i *model.Account) bool { return i != nil }); !ok {
+	if !t.isRelevant(tx) {
 		return model.ErrAccountNotFound
 	}
 
 	log.Debug().Any("tx", tx).Msg("processing relevant transaction")
 
 	err = t.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		transfers, err := t.classify(ctx, tx, nil)
+		if err != nil {
+			return errors.Wrap(err, "classify tx")
+		}
+
 		if _, err = t.transaction.InsertTransaction(ctx, tx); err != nil {
+			if errors.Is(err, model.ErrTransactionExists) {
+				log.Debug().Str("account", tx.AccountAddr).Int64("lt", tx.LT).Msg("transaction already stored")
+				return nil
+			}
 			return errors.Wrap(err, "save tx")
 		}
-		return nil
+		return t.process(ctx, tx, transfers)
 	})
 
 	if err != nil {
@@ -139,3 +178,92 @@ func (t *Transaction) Handle(ctx context.Context, message []byte) error {
 	}
 	return nil
 }
+
+// Key returns the account address of the transaction, messages with the same key are handled in order.
+func (t *Transaction) Key(message []byte) string {
+	tx, err := model.UnmarshalTransaction(message)
+	if err != nil {
+		return ""
+	}
+	return tx.AccountAddr
+}
+
+// HandleBatch stores the relevant transactions of the messages with a single insert.
+func (t *Transaction) HandleBatch(ctx context.Context, messages [][]byte) error {
+	txs := make([]*model.Transaction, 0, len(messages))
+	for _, message := range messages {
+		tx, err := model.UnmarshalTransaction(message)
+		if err != nil {
+			return errors.Wrap(err, "unmarshal tx")
+		}
+
+		if t.isRelevant(tx) {
+			txs = append(txs, tx)
+		}
+	}
+
+	if len(txs) == 0 {
+		return nil
+	}
+
+	log.Debug().Int("count", len(txs)).Msg("processing relevant transactions")
+
+	err := t.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		var (
+			received  = make(map[string]*model.Transaction, len(txs))
+			transfers = make(map[string][]*model.OutgoingTransfer)
+			bounced   []int64
+		)
+
+		for _, tx := range txs {
+			received[txKey(tx)] = tx
+
+			txTransfers, err := t.classify(ctx, tx, bounced)
+			if err != nil {
+				return errors.Wrap(err, "classify tx")
+			}
+
+			if len(txTransfers) > 0 {
+				transfers[txKey(tx)] = txTransfers
+			}
+
+			if tx.Kind == model.TxKindBounce && tx.TransferID != nil {
+				bounced = append(bounced, *tx.TransferID)
+			}
+		}
+
+		inserted, err := t.transaction.InsertTransactions(ctx, txs)
+		if err != nil {
+			return errors.Wrap(err, "save txs")
+		}
+
+		if skipped := len(txs) - len(inserted); skipped > 0 {
+			log.Debug().Int("count", skipped).Msg("transactions already stored")
+		}
+
+		// the transactions are processed as received, like a single one, the stored ones have the raw addresses
+		for _, stored := range inserted {
+			key := txKey(stored)
+			if err = t.process(ctx, received[key], transfers[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return errors.Wrap(err, "handle txs")
+	}
+	return nil
+}
+
+func (t *Transaction) isRelevant(tx *model.Transaction) bool {
+	_, senderIsOurs := t.wallet(tx.Sender)
+	_, receiverIsOurs := t.wallet(tx.Receiver)
+	return senderIsOurs || receiverIsOurs
+}
+
+// txKey identifies the transaction within a batch.
+func txKey(tx *model.Transaction) string {
+	return tx.AccountAddr + ":" + strconv.FormatInt(tx.LT, 10) + ":" + tx.Hash
+}
@@ -2,12 +2,15 @@ package transaction
 
 import (
 	"context"
+	"encoding/base64"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/xssnick/tonutils-go/tvm/cell"
 
 	"github.com/kriuchkov/tonbeacon/core/model"
 	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
@@ -25,13 +28,13 @@ func TestTransaction_Update(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		accountList map[model.Address]*model.Account
+		accountList map[string]model.Account
 
 		mockListAccountsCall
 	}{
 		{
 			name: "successful",
-			accountList: map[model.Address]*model.Account{
+			accountList: map[string]model.Account{
 				"addr1": {ID: "1", WalletID: 1, Address: "addr1"},
 				"addr2": {ID: "2", WalletID: 2, Address: "addr2"},
 			},
@@ -69,7 +72,7 @@ func TestTransaction_Update(t *testing.T) {
 				Interval:        1 * time.Minute,
 			})
 
-			require.Equal(t, tt.accountList, transactionPort.accountList)
+			require.Equal(t, tt.accountList, transactionPort.accounts.byAddress)
 			dbPort.AssertExpectations(t)
 			txPort.AssertExpectations(t)
 		})
@@ -87,14 +90,14 @@ func TestTransaction_UpdateAccounts(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		accountList map[model.Address]*model.Account
+		accountList map[string]model.Account
 		expectError error
 
 		mockListAccountsCall
 	}{
 		{
 			name: "successful update",
-			accountList: map[model.Address]*model.Account{
+			accountList: map[string]model.Account{
 				"addr1": {ID: "1", WalletID: 1, Address: "addr1"},
 				"addr2": {ID: "2", WalletID: 2, Address: "addr2"},
 			},
@@ -123,16 +126,16 @@ func TestTransaction_UpdateAccounts(t *testing.T) {
 			}
 
 			transaction := &Transaction{
-				dbPort:      dbPort,
-				txPort:      portsmocks.NewMockDatabaseTransactionPort(t),
-				accountList: make(map[model.Address]*model.Account),
-				interval:    1 * time.Minute,
+				dbPort:   dbPort,
+				txPort:   portsmocks.NewMockDatabaseTransactionPort(t),
+				accounts: newAccountIndex(),
+				interval: 1 * time.Minute,
 			}
 
 			err := transaction.updateAccounts(ctx)
 
 			require.Equal(t, tt.expectError, err)
-			require.Equal(t, tt.accountList, transaction.accountList)
+			require.Equal(t, tt.accountList, transaction.accounts.byAddress)
 
 			dbPort.AssertExpectations(t)
 		})
@@ -259,7 +262,7 @@ func TestTransaction_Handle(t *testing.T) {
 	tests := []struct {
 		name        string
 		message     []byte
-		accountList map[model.Address]*model.Account
+		accountList map[string]model.Account
 		expectError error
 
 		mockInsertTransactionCall
@@ -267,7 +270,7 @@ func TestTransaction_Handle(t *testing.T) {
 		{
 			name:    "successful",
 			message: testTransactionMsg,
-			accountList: map[model.Address]*model.Account{
+			accountList: map[string]model.Account{
 				"EQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iIMp": {
 					ID:       "1",
 					WalletID: 1,
@@ -280,9 +283,41 @@ func TestTransaction_Handle(t *testing.T) {
 					AccountAddr: "tx1",
 					Sender:      "EQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iIMp",
 					Receiver:    "EQDXfHeRvIZwfxO3bjZl8jbUh2h0fV_Zzy_F5EErQcVHyz3R",
-					Amount:      100,
+					Amount:      model.NewAmount(100),
+				},
+			},
+		},
+		{
+			name:    "account address in another format",
+			message: testTransactionMsg,
+			accountList: map[string]model.Account{
+				"0:cda1734a49746f3b387796951c69e890635771cc6eb013ab989ff0b0a21f3488": {
+					ID:       "1",
+					WalletID: 1,
+					Address:  "UQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iN7s",
 				},
 			},
+			mockInsertTransactionCall: mockInsertTransactionCall{calls: 1},
+		},
+		{
+			name:        "unknown account",
+			message:     testTransactionMsg,
+			expectError: model.ErrAccountNotFound,
+		},
+		{
+			name:    "already stored transaction is skipped",
+			message: testTransactionMsg,
+			accountList: map[string]model.Account{
+				"EQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iIMp": {
+					ID:       "1",
+					WalletID: 1,
+					Address:  "EQDNoXNKSXRvOzh3lpUcaeiQY1dxzG6wE6uYn_Cwoh80iIMp",
+				},
+			},
+			mockInsertTransactionCall: mockInsertTransactionCall{
+				calls:         1,
+				responseError: model.ErrTransactionExists,
+			},
 		},
 	}
 
@@ -294,8 +329,10 @@ func TestTransaction_Handle(t *testing.T) {
 
 			// TxPort mock
 			txPort := portsmocks.NewMockDatabaseTransactionPort(t)
-			txPort.On("WithInTransaction", ctx, mock.Anything).
-				Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+			if tt.mockInsertTransactionCall.calls > 0 {
+				txPort.On("WithInTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+			}
 
 			// TransactionPort mock
 			transactionPort := portsmocks.NewMockTransactionalDatabasePort(t)
@@ -311,7 +348,7 @@ func TestTransaction_Handle(t *testing.T) {
 				dbPort:      portsmocks.NewMockDatabasePort(t),
 				txPort:      txPort,
 				transaction: transactionPort,
-				accountList: tt.accountList,
+				accounts:    newAccountIndex(lo.Values(tt.accountList)...),
 				interval:    1 * time.Minute,
 			}
 
@@ -323,3 +360,95 @@ func TestTransaction_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestTransaction_HandleBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := [][]byte{
+		[]byte(`{"AccountAddr": "ours", "LT": 1, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "ours"}}}}`),
+		[]byte(`{"AccountAddr": "other", "LT": 2, "IO": {"In": {"Msg": {"SrcAddr": "other", "DstAddr": "unknown"}}}}`),
+		[]byte(`{"AccountAddr": "ours", "LT": 3, "IO": {"In": {"Msg": {"SrcAddr": "ours", "DstAddr": "other"}}}}`),
+	}
+
+	txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+	txPort.On("WithInTransaction", ctx, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+
+	transactionPort := portsmocks.NewMockTransactionalDatabasePort(t)
+	transactionPort.On("InsertTransactions", ctx, mock.MatchedBy(func(txs []*model.Transaction) bool {
+		return len(txs) == 2 && txs[0].LT == 1 && txs[1].LT == 3
+	})).Return([]*model.Transaction{}, nil).Once()
+
+	transaction := &Transaction{
+		dbPort:      portsmocks.NewMockDatabasePort(t),
+		txPort:      txPort,
+		transaction: transactionPort,
+		accounts:    newAccountIndex(model.Account{ID: "1", WalletID: 1, Address: "ours"}),
+		interval:    1 * time.Minute,
+	}
+
+	require.NoError(t, transaction.HandleBatch(ctx, messages))
+	require.Equal(t, "ours", transaction.Key(messages[0]))
+}
+
+func TestTransaction_HandleBatchBounces(t *testing.T) {
+	t.Parallel()
+
+	comment := cell.BeginCell().MustStoreUInt(0, 32).MustStoreStringSnake("invoice 42").EndCell()
+	bounceHash, err := model.BounceHash(comment)
+	require.NoError(t, err)
+
+	bouncedBody := base64.StdEncoding.EncodeToString(cell.BeginCell().MustStoreUInt(uint64(model.OpBounce), 32).
+		MustStoreBuilder(comment.ToBuilder()).EndCell().ToBOC())
+
+	bounce := func(lt int) []byte {
+		return []byte(`{"AccountAddr": "master-wallet", "LT": ` + strconv.Itoa(lt) + `, "IO": {"In": {"MsgType": "INTERNAL",
+			"Msg": {"SrcAddr": "other", "DstAddr": "master-wallet", "Amount": "990", "Bounced": true, "Body": "` + bouncedBody + `"}}}}`)
+	}
+
+	withdrawal := func(id int64, reference string) *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: id, Kind: model.TransferWithdrawal, Reference: reference, AccountID: "1",
+			Currency: model.CurrencyTON, Amount: model.NewAmount(1000), Status: model.TransferConfirmed,
+		}
+	}
+
+	ctx := context.Background()
+
+	txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+	txPort.On("WithInTransaction", ctx, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+
+	transactionPort := portsmocks.NewMockTransactionalDatabasePort(t)
+	transactionPort.On("InsertTransactions", ctx, mock.MatchedBy(func(txs []*model.Transaction) bool {
+		return len(txs) == 2 && *txs[0].TransferID == 2 && *txs[1].TransferID == 3
+	})).Return(func(_ context.Context, txs []*model.Transaction) ([]*model.Transaction, error) { return txs, nil }).Once()
+
+	// the second bounce of the batch skips the transfer matched by the first one
+	transfers := portsmocks.NewMockOutgoingTransferDatabasePort(t)
+	transfers.On("GetBouncedTransferCandidate", ctx, "master-wallet", "other", bounceHash, []int64(nil)).
+		Return(withdrawal(2, "w1"), nil).Once()
+	transfers.On("GetBouncedTransferCandidate", ctx, "master-wallet", "other", bounceHash, []int64{2}).
+		Return(withdrawal(3, "w2"), nil).Once()
+	transfers.On("UpdateOutgoingTransferStatus", ctx, mock.Anything, model.TransferConfirmed).Return(nil).Twice()
+
+	ledger := portsmocks.NewMockLedgerServicePort(t)
+	for _, reference := range []string{"withdrawal:w1", "withdrawal:w2"} {
+		ledger.On("RecordBounce", ctx, model.TransferWithdrawal, "1", mock.Anything, model.NewAmount(990), reference).
+			Return(&model.LedgerEntry{}, nil).Once()
+	}
+
+	transaction := &Transaction{
+		dbPort:      portsmocks.NewMockDatabasePort(t),
+		txPort:      txPort,
+		transaction: transactionPort,
+		transfers:   transfers,
+		ledger:      ledger,
+		master:      "master-wallet",
+		accounts:    newAccountIndex(),
+		interval:    1 * time.Minute,
+	}
+
+	require.NoError(t, transaction.HandleBatch(ctx, [][]byte{bounce(8), bounce(9)}))
+}
@@ -0,0 +1,307 @@
+package withdrawal
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"github.com/xssnick/tonutils-go/address"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+const (
+	defaultWithdrawalLimit = 100
+	maxWithdrawalLimit     = 1000
+)
+
+var _ ports.WithdrawalServicePort = (*Withdrawals)(nil)
+
+type Options struct {
+	Database  ports.WithdrawalDatabasePort         `validate:"required"`
+	Approvals ports.WithdrawalApprovalDatabasePort `validate:"required"`
+	Accounts  ports.AccountDatabasePort            `validate:"required"`
+	Ledger    ports.LedgerServicePort              `validate:"required"`
+	Locks     ports.LedgerDatabasePort             `validate:"required"`
+	TxPort    ports.DatabaseWithinTransactionPort  `validate:"required"`
+	Events    ports.OutboxMessagePort              `validate:"required"`
+
+	// Jettons are the jettons which may be withdrawn besides TON.
+	Jettons []model.Currency
+}
+
+// Withdrawals accepts the withdrawals of the custodial balances. The amount of a withdrawal is reserved
+// on the custodial balance until the withdrawal is confirmed and recorded in the ledger or fails,
+// so the active withdrawals of an account never exceed its balance. The withdrawals are sent by the Worker.
+type Withdrawals struct {
+	database  ports.WithdrawalDatabasePort
+	approvals ports.WithdrawalApprovalDatabasePort
+	accounts  ports.AccountDatabasePort
+	ledger    ports.LedgerServicePort
+	locks     ports.LedgerDatabasePort
+	txPort    ports.DatabaseWithinTransactionPort
+	events    ports.OutboxMessagePort
+	jettons   []model.Currency
+	now       func() time.Time
+}
+
+func New(opts *Options) *Withdrawals {
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	return &Withdrawals{
+		database:  opts.Database,
+		approvals: opts.Approvals,
+		accounts:  opts.Accounts,
+		ledger:    opts.Ledger,
+		locks:     opts.Locks,
+		txPort:    opts.TxPort,
+		events:    opts.Events,
+		jettons:   opts.Jettons,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateWithdrawal stores the requested withdrawal and publishes it. model.ErrInsufficientFunds is returned
+// if the custodial balance without the active withdrawals is below the amount. A repeated request returns
+// the withdrawal with its idempotency key, model.ErrWithdrawalExists is returned if the requests differ.
+func (w *Withdrawals) CreateWithdrawal(ctx context.Context, withdrawal *model.Withdrawal) (*model.Withdrawal, error) {
+	if err := w.validate(withdrawal); err != nil {
+		return nil, err
+	}
+
+	stored, err := w.database.GetWithdrawalByIdempotencyKey(ctx, withdrawal.AccountID, withdrawal.IdempotencyKey)
+	switch {
+	case err == nil:
+		return repeated(stored, withdrawal)
+	case !errors.Is(err, model.ErrWithdrawalNotFound):
+		return nil, errors.Wrap(err, "get withdrawal")
+	}
+
+	exists, err := w.accounts.IsAccountExists(ctx, withdrawal.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "check account")
+	}
+	if !exists {
+		return nil, model.ErrAccountNotFound
+	}
+
+	now := w.now()
+	requested := *withdrawal
+	requested.Status, requested.CreatedAt, requested.UpdatedAt = model.WithdrawalRequested, now, now
+
+	err = w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := w.reserve(ctx, &requested); err != nil {
+			return err
+		}
+
+		if stored, err = w.database.InsertWithdrawal(ctx, &requested); err != nil {
+			return errors.Wrap(err, "insert withdrawal")
+		}
+		return publish(ctx, w.events, stored)
+	})
+
+	if errors.Is(err, model.ErrWithdrawalExists) {
+		if stored, err = w.database.GetWithdrawalByIdempotencyKey(ctx, withdrawal.AccountID, withdrawal.IdempotencyKey); err != nil {
+			return nil, errors.Wrap(err, "get withdrawal")
+		}
+		return repeated(stored, withdrawal)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("withdrawal_id", stored.ID).Str("account_id", stored.AccountID).
+		Str("currency", string(stored.Currency)).Str("amount", stored.Amount.String()).Msg("withdrawal requested")
+	return stored, nil
+}
+
+func (w *Withdrawals) GetWithdrawal(ctx context.Context, id int64) (*model.Withdrawal, error) {
+	withdrawal, err := w.database.GetWithdrawal(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "get withdrawal")
+	}
+	return withdrawal, nil
+}
+
+// ListWithdrawals returns a page of the withdrawals matching the filter, the limit defaults
+// to defaultWithdrawalLimit and is capped at maxWithdrawalLimit.
+func (w *Withdrawals) ListWithdrawals(ctx context.Context, filter model.ListWithdrawalsFilter) (*model.WithdrawalPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultWithdrawalLimit
+	}
+	filter.Limit = min(filter.Limit, maxWithdrawalLimit)
+
+	limit := filter.Limit
+	filter.Limit++ // one more withdrawal tells whether there is a next page
+
+	withdrawals, err := w.database.ListWithdrawals(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "list withdrawals")
+	}
+
+	page := &model.WithdrawalPage{Withdrawals: withdrawals}
+	if len(withdrawals) > limit {
+		page.Withdrawals = withdrawals[:limit]
+		page.NextCursor = model.EncodeWithdrawalCursor(page.Withdrawals[limit-1].ID)
+	}
+	return page, nil
+}
+
+// ApproveWithdrawal stores the approval of the requested withdrawal by the operator and returns the decisions
+// on the withdrawal. model.ErrWithdrawalNotPending is returned if the withdrawal is not requested anymore and
+// model.ErrWithdrawalApprovalExists if the operator already decided on it. The withdrawal is locked while
+// the approval is stored, so the worker does not approve it in the meantime.
+func (w *Withdrawals) ApproveWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) ([]*model.WithdrawalApproval, error) {
+	if approval.Operator == "" {
+		return nil, errors.Wrap(model.ErrInvalidWithdrawal, "operator is required")
+	}
+
+	var approvals []*model.WithdrawalApproval
+	err := w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		withdrawal, err := w.database.GetWithdrawalForUpdate(ctx, approval.WithdrawalID)
+		if err != nil {
+			return errors.Wrap(err, "get withdrawal")
+		}
+
+		if withdrawal.Status != model.WithdrawalRequested {
+			return errors.Wrapf(model.ErrWithdrawalNotPending, "withdrawal %d is %s", withdrawal.ID, withdrawal.Status)
+		}
+
+		approved := *approval
+		approved.Decision, approved.CreatedAt = model.ApprovalApproved, w.now()
+		if _, err = w.approvals.InsertWithdrawalApproval(ctx, &approved); err != nil {
+			return errors.Wrap(err, "insert approval")
+		}
+
+		if approvals, err = w.approvals.ListWithdrawalApprovals(ctx, withdrawal.ID); err != nil {
+			return errors.Wrap(err, "list approvals")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("withdrawal_id", approval.WithdrawalID).Str("operator", approval.Operator).Msg("withdrawal approved by operator")
+	return approvals, nil
+}
+
+// RejectWithdrawal stores the rejection of the requested withdrawal by the operator and fails the withdrawal,
+// which releases its reserved amount. model.ErrWithdrawalNotPending is returned if the withdrawal is not
+// requested anymore, e.g. the worker approved it in the meantime.
+func (w *Withdrawals) RejectWithdrawal(ctx context.Context, approval *model.WithdrawalApproval) (*model.Withdrawal, error) {
+	if approval.Operator == "" {
+		return nil, errors.Wrap(model.ErrInvalidWithdrawal, "operator is required")
+	}
+
+	withdrawal, err := w.database.GetWithdrawal(ctx, approval.WithdrawalID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get withdrawal")
+	}
+
+	if withdrawal.Status != model.WithdrawalRequested {
+		return nil, errors.Wrapf(model.ErrWithdrawalNotPending, "withdrawal %d is %s", withdrawal.ID, withdrawal.Status)
+	}
+
+	now := w.now()
+	rejected := *approval
+	rejected.Decision, rejected.CreatedAt = model.ApprovalRejected, now
+
+	failed := *withdrawal
+	if err = failed.Transition(model.WithdrawalFailed, now); err != nil {
+		return nil, err
+	}
+
+	failed.Error = "rejected by " + approval.Operator
+	if approval.Comment != "" {
+		failed.Error += ": " + approval.Comment
+	}
+
+	err = w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if _, err := w.approvals.InsertWithdrawalApproval(ctx, &rejected); err != nil {
+			return errors.Wrap(err, "insert rejection")
+		}
+
+		if err := w.database.UpdateWithdrawal(ctx, &failed, model.WithdrawalRequested); err != nil {
+			if errors.Is(err, model.ErrInvalidWithdrawalTransition) {
+				return errors.Wrapf(model.ErrWithdrawalNotPending, "withdrawal %d", withdrawal.ID)
+			}
+			return errors.Wrap(err, "update withdrawal")
+		}
+		return publish(ctx, w.events, &failed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("withdrawal_id", withdrawal.ID).Str("operator", approval.Operator).Msg("withdrawal rejected")
+	return &failed, nil
+}
+
+// reserve checks the custodial balance of the account without its active withdrawals covers the withdrawal,
+// the ledger account is locked until the end of the transaction, so concurrent withdrawals are checked in turn.
+func (w *Withdrawals) reserve(ctx context.Context, withdrawal *model.Withdrawal) error {
+	if err := w.locks.LockLedgerAccount(ctx, model.CustomerLedgerAccount(withdrawal.AccountID)); err != nil {
+		return errors.Wrap(err, "lock ledger account")
+	}
+
+	balances, err := w.ledger.GetBalance(ctx, withdrawal.AccountID)
+	if err != nil {
+		return errors.Wrap(err, "get balance")
+	}
+
+	pending, err := w.database.GetPendingWithdrawalAmount(ctx, withdrawal.AccountID, withdrawal.Currency)
+	if err != nil {
+		return errors.Wrap(err, "get pending withdrawals")
+	}
+
+	balance, _ := lo.Find(balances, func(b model.Balance) bool { return b.Currency == withdrawal.Currency })
+	available := balance.Amount.Sub(pending)
+	if available.Cmp(withdrawal.Amount) < 0 {
+		return model.ErrInsufficientFunds
+	}
+	return nil
+}
+
+func (w *Withdrawals) validate(withdrawal *model.Withdrawal) error {
+	switch {
+	case withdrawal.AccountID == "" || withdrawal.AccountID == model.MasterAccountID:
+		return errors.Wrap(model.ErrInvalidWithdrawal, "customer account is required")
+	case withdrawal.IdempotencyKey == "":
+		return errors.Wrap(model.ErrInvalidWithdrawal, "idempotency key is required")
+	case withdrawal.Amount.Sign() <= 0:
+		return errors.Wrap(model.ErrInvalidWithdrawal, "amount must be positive")
+	case withdrawal.Currency != model.CurrencyTON && !lo.Contains(w.jettons, withdrawal.Currency):
+		return errors.Wrapf(model.ErrInvalidWithdrawal, "unsupported currency %s", withdrawal.Currency)
+	}
+
+	if _, err := address.ParseAddr(withdrawal.To); err != nil {
+		return errors.Wrapf(model.ErrInvalidWithdrawal, "destination: %v", err)
+	}
+	return nil
+}
+
+// repeated returns the stored withdrawal of a repeated request, model.ErrWithdrawalExists is returned
+// if the idempotency key was used for another withdrawal.
+func repeated(stored, requested *model.Withdrawal) (*model.Withdrawal, error) {
+	if stored.To != requested.To || stored.Currency != requested.Currency ||
+		stored.Amount.Cmp(requested.Amount) != 0 || stored.Comment != requested.Comment {
+		return nil, errors.Wrapf(model.ErrWithdrawalExists, "idempotency key %s", requested.IdempotencyKey)
+	}
+	return stored, nil
+}
+
+func publish(ctx context.Context, events ports.OutboxMessagePort, withdrawal *model.Withdrawal) error {
+	eventType := model.WithdrawalEventType(withdrawal.Status)
+	if err := events.Publish(ctx, eventType, model.NewWithdrawalPayload(withdrawal)); err != nil {
+		return errors.Wrapf(err, "publish %s", eventType)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package withdrawal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+	"github.com/kriuchkov/tonbeacon/ports/withdrawal"
+)
+
+const destination = "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z"
+
+func newWithdrawals(t *testing.T) (*withdrawal.Withdrawals, *portsmocks.MockDatabasePort, *portsmocks.MockLedgerServicePort, *portsmocks.MockOutboxMessagePort) {
+	database := portsmocks.NewMockDatabasePort(t)
+	ledger := portsmocks.NewMockLedgerServicePort(t)
+	events := portsmocks.NewMockOutboxMessagePort(t)
+
+	txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+	txPort.On("WithInTransaction", mock.Anything, mock.Anything).Maybe().
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+
+	withdrawals := withdrawal.New(&withdrawal.Options{
+		Database:  database,
+		Approvals: database,
+		Accounts:  database,
+		Ledger:    ledger,
+		Locks:     database,
+		TxPort:    txPort,
+		Events:    events,
+		Jettons:   []model.Currency{model.CurrencyUSDT},
+	})
+	return withdrawals, database, ledger, events
+}
+
+func request(amount int64) *model.Withdrawal {
+	return &model.Withdrawal{
+		AccountID: "1", IdempotencyKey: "payout-1", To: destination,
+		Currency: model.CurrencyTON, Amount: model.NewAmount(amount), Comment: "invoice 42",
+	}
+}
+
+func TestWithdrawals_CreateWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	withdrawals, database, ledger, events := newWithdrawals(t)
+
+	database.On("GetWithdrawalByIdempotencyKey", ctx, "1", "payout-1").Return(nil, model.ErrWithdrawalNotFound).Twice()
+	database.On("IsAccountExists", ctx, "1").Return(true, nil).Twice()
+	database.On("LockLedgerAccount", ctx, model.CustomerLedgerAccount("1")).Return(nil).Twice()
+	ledger.On("GetBalance", ctx, "1").Return([]model.Balance{
+		{Currency: model.CurrencyTON, Amount: model.NewAmount(1000)},
+	}, nil).Twice()
+	database.On("GetPendingWithdrawalAmount", ctx, "1", model.CurrencyTON).Return(model.NewAmount(300), nil).Twice()
+	database.On("InsertWithdrawal", ctx, mock.MatchedBy(func(w *model.Withdrawal) bool {
+		return w.Status == model.WithdrawalRequested && w.Amount.Nano() == "700" && !w.CreatedAt.IsZero()
+	})).Return(func(_ context.Context, w *model.Withdrawal) (*model.Withdrawal, error) {
+		stored := *w
+		stored.ID = 5
+		return &stored, nil
+	}).Once()
+	events.On("Publish", ctx, model.WithdrawalRequestedEvent, mock.MatchedBy(func(p model.WithdrawalPayload) bool {
+		return p.WithdrawalID == 5 && p.Status == model.WithdrawalRequested && p.Amount == "700"
+	})).Return(nil).Once()
+
+	stored, err := withdrawals.CreateWithdrawal(ctx, request(700))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), stored.ID)
+
+	_, err = withdrawals.CreateWithdrawal(ctx, request(701))
+	require.ErrorIs(t, err, model.ErrInsufficientFunds)
+}
+
+func TestWithdrawals_CreateWithdrawal_Repeated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	withdrawals, database, _, _ := newWithdrawals(t)
+
+	stored := request(700)
+	stored.ID, stored.Status = 5, model.WithdrawalBroadcast
+	database.On("GetWithdrawalByIdempotencyKey", ctx, "1", "payout-1").Return(stored, nil).Twice()
+
+	repeated, err := withdrawals.CreateWithdrawal(ctx, request(700))
+	require.NoError(t, err)
+	require.Equal(t, stored, repeated)
+
+	_, err = withdrawals.CreateWithdrawal(ctx, request(800))
+	require.ErrorIs(t, err, model.ErrWithdrawalExists)
+}
+
+func TestWithdrawals_CreateWithdrawal_Invalid(t *testing.T) {
+	t.Parallel()
+
+	withdrawals, _, _, _ := newWithdrawals(t)
+
+	for name, modify := range map[string]func(w *model.Withdrawal){
+		"master account":       func(w *model.Withdrawal) { w.AccountID = model.MasterAccountID },
+		"no idempotency key":   func(w *model.Withdrawal) { w.IdempotencyKey = "" },
+		"zero amount":          func(w *model.Withdrawal) { w.Amount = model.NewAmount(0) },
+		"unsupported currency": func(w *model.Withdrawal) { w.Currency = "NOT" },
+		"invalid destination":  func(w *model.Withdrawal) { w.To = "0:zz" },
+	} {
+		w := request(700)
+		modify(w)
+
+		_, err := withdrawals.CreateWithdrawal(context.Background(), w)
+		require.ErrorIs(t, err, model.ErrInvalidWithdrawal, name)
+	}
+}
+
+func TestWithdrawals_ListWithdrawals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	withdrawals, database, _, _ := newWithdrawals(t)
+
+	database.On("ListWithdrawals", ctx, mock.MatchedBy(func(filter model.ListWithdrawalsFilter) bool {
+		return filter.Limit == 3
+	})).Return([]*model.Withdrawal{{ID: 9}, {ID: 8}, {ID: 7}}, nil).Once()
+
+	page, err := withdrawals.ListWithdrawals(ctx, model.ListWithdrawalsFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Withdrawals, 2)
+	require.Equal(t, model.EncodeWithdrawalCursor(8), page.NextCursor)
+}
+
+func TestWithdrawals_ApproveWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	withdrawals, database, _, _ := newWithdrawals(t)
+
+	requested := request(700)
+	requested.ID, requested.Status = 5, model.WithdrawalRequested
+	database.On("GetWithdrawalForUpdate", ctx, int64(5)).Return(requested, nil).Once()
+	database.On("InsertWithdrawalApproval", ctx, mock.MatchedBy(func(a *model.WithdrawalApproval) bool {
+		return a.WithdrawalID == 5 && a.Operator == "alice" && a.Decision == model.ApprovalApproved && !a.CreatedAt.IsZero()
+	})).Return(&model.WithdrawalApproval{ID: 1}, nil).Once()
+	database.On("ListWithdrawalApprovals", ctx, int64(5)).Return([]*model.WithdrawalApproval{{ID: 1, Operator: "alice"}}, nil).Once()
+
+	approvals, err := withdrawals.ApproveWithdrawal(ctx, &model.WithdrawalApproval{WithdrawalID: 5, Operator: "alice"})
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+
+	signed := request(700)
+	signed.ID, signed.Status = 6, model.WithdrawalSigned
+	database.On("GetWithdrawalForUpdate", ctx, int64(6)).Return(signed, nil).Once()
+
+	_, err = withdrawals.ApproveWithdrawal(ctx, &model.WithdrawalApproval{WithdrawalID: 6, Operator: "alice"})
+	require.ErrorIs(t, err, model.ErrWithdrawalNotPending)
+
+	_, err = withdrawals.ApproveWithdrawal(ctx, &model.WithdrawalApproval{WithdrawalID: 5})
+	require.ErrorIs(t, err, model.ErrInvalidWithdrawal)
+}
+
+func TestWithdrawals_RejectWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	withdrawals, database, _, events := newWithdrawals(t)
+
+	requested := request(700)
+	requested.ID, requested.Status = 5, model.WithdrawalRequested
+	database.On("GetWithdrawal", ctx, int64(5)).Return(requested, nil).Twice()
+	database.On("InsertWithdrawalApproval", ctx, mock.MatchedBy(func(a *model.WithdrawalApproval) bool {
+		return a.Operator == "bob" && a.Decision == model.ApprovalRejected && a.Comment == "sanctioned address"
+	})).Return(&model.WithdrawalApproval{ID: 2}, nil).Twice()
+	database.On("UpdateWithdrawal", ctx, mock.MatchedBy(func(w *model.Withdrawal) bool {
+		return w.Status == model.WithdrawalFailed && w.FailedAt != nil && w.Error == "rejected by bob: sanctioned address"
+	}), model.WithdrawalRequested).Return(nil).Once()
+	events.On("Publish", ctx, model.WithdrawalFailedEvent, mock.MatchedBy(func(p model.WithdrawalPayload) bool {
+		return p.WithdrawalID == 5 && p.Status == model.WithdrawalFailed
+	})).Return(nil).Once()
+
+	rejection := &model.WithdrawalApproval{WithdrawalID: 5, Operator: "bob", Comment: "sanctioned address"}
+	rejected, err := withdrawals.RejectWithdrawal(ctx, rejection)
+	require.NoError(t, err)
+	require.Equal(t, model.WithdrawalFailed, rejected.Status)
+	require.Equal(t, model.WithdrawalRequested, requested.Status)
+
+	// the worker approved the withdrawal in the meantime
+	database.On("UpdateWithdrawal", ctx, mock.Anything, model.WithdrawalRequested).
+		Return(model.ErrInvalidWithdrawalTransition).Once()
+
+	_, err = withdrawals.RejectWithdrawal(ctx, rejection)
+	require.ErrorIs(t, err, model.ErrWithdrawalNotPending)
+}
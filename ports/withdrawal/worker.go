@@ -0,0 +1,720 @@
+package withdrawal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	"github.com/kriuchkov/tonbeacon/core/ports"
+)
+
+const (
+	// defaultInterval is the default interval between the runs of the worker.
+	defaultInterval = 10 * time.Second
+
+	// defaultConfirmTimeout is how long after the expiration of its message a withdrawal waits for the transaction
+	// processor before the transactions of the master wallet are checked, the query id of a batch is checked at once.
+	defaultConfirmTimeout = 5 * time.Minute
+
+	// defaultBatchSize is the number of withdrawals listed at once.
+	defaultBatchSize = 500
+)
+
+type WorkerOptions struct {
+	WalletPort   ports.WalletPort                    `validate:"required"`
+	Database     ports.WithdrawalDatabasePort        `validate:"required"`
+	TransferPort ports.OutgoingTransferDatabasePort  `validate:"required"`
+	TxPort       ports.DatabaseWithinTransactionPort `validate:"required"`
+	Events       ports.OutboxMessagePort             `validate:"required"`
+	// Seqnos reserves the seqnos of the master wallet shared with the collector and the rebalancer.
+	Seqnos ports.SeqnoDatabasePort `validate:"required"`
+
+	// Payouts sends the withdrawals in batches of the highload payout wallet instead of one at a time
+	// from the master wallet, QueryIDs numbers the batches.
+	Payouts  ports.PayoutWalletPort
+	QueryIDs ports.PayoutDatabasePort `validate:"required_with=Payouts"`
+	// MaxBatch is the largest number of withdrawals sent in one batch.
+	MaxBatch int `validate:"gte=0,lte=253"`
+
+	// Policies require the approvals of the operators and limit the daily amounts of the withdrawals
+	// in their currencies, the withdrawals in the other currencies are approved at once.
+	Policies  []model.WithdrawalPolicy
+	Approvals ports.WithdrawalApprovalDatabasePort `validate:"required_with=Policies"`
+	Limits    ports.WithdrawalLimitDatabasePort    `validate:"required_with=Policies"`
+
+	// JettonGas is attached to a jetton withdrawal, its excess is returned to the sending wallet.
+	JettonGas model.Amount
+
+	Interval       time.Duration
+	ConfirmTimeout time.Duration
+	BatchSize      int
+}
+
+func (o *WorkerOptions) SetDefaults() {
+	if o.Interval == 0 {
+		o.Interval = defaultInterval
+	}
+	if o.ConfirmTimeout == 0 {
+		o.ConfirmTimeout = defaultConfirmTimeout
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	if o.JettonGas.Sign() == 0 {
+		o.JettonGas = model.NewAmount(model.DefaultJettonGasNano)
+	}
+	if o.MaxBatch == 0 {
+		o.MaxBatch = model.MaxPayoutBatch
+	}
+}
+
+// Worker approves, signs and sends the withdrawals and follows their outgoing transfers until they are settled.
+type Worker struct {
+	walletPort     ports.WalletPort
+	payouts        ports.PayoutWalletPort
+	queryIDs       ports.PayoutDatabasePort
+	policies       map[model.Currency]*model.WithdrawalPolicy
+	approvals      ports.WithdrawalApprovalDatabasePort
+	limits         ports.WithdrawalLimitDatabasePort
+	database       ports.WithdrawalDatabasePort
+	transfers      ports.OutgoingTransferDatabasePort
+	txPort         ports.DatabaseWithinTransactionPort
+	events         ports.OutboxMessagePort
+	seqnos         ports.SeqnoDatabasePort
+	jettonGas      model.Amount
+	maxBatch       int
+	interval       time.Duration
+	confirmTimeout time.Duration
+	batchSize      int
+	now            func() time.Time
+}
+
+func NewWorker(opts *WorkerOptions) *Worker {
+	opts.SetDefaults()
+
+	if err := validator.New().Struct(opts); err != nil {
+		log.Panic().Err(err).Msg("invalid options")
+	}
+
+	if opts.JettonGas.Sign() < 0 {
+		log.Panic().Msg("jetton gas must not be negative")
+	}
+
+	policies := make(map[model.Currency]*model.WithdrawalPolicy, len(opts.Policies))
+	for i, policy := range opts.Policies {
+		if _, ok := policies[policy.Currency]; ok {
+			log.Panic().Str("currency", string(policy.Currency)).Msg("duplicate withdrawal policy")
+		}
+
+		if policy.AccountDailyLimit.Sign() < 0 || policy.GlobalDailyLimit.Sign() < 0 {
+			log.Panic().Str("currency", string(policy.Currency)).Msg("withdrawal limits must not be negative")
+		}
+		policies[policy.Currency] = &opts.Policies[i]
+	}
+
+	return &Worker{
+		walletPort:     opts.WalletPort,
+		payouts:        opts.Payouts,
+		queryIDs:       opts.QueryIDs,
+		policies:       policies,
+		approvals:      opts.Approvals,
+		limits:         opts.Limits,
+		database:       opts.Database,
+		transfers:      opts.TransferPort,
+		txPort:         opts.TxPort,
+		events:         opts.Events,
+		seqnos:         opts.Seqnos,
+		jettonGas:      opts.JettonGas,
+		maxBatch:       opts.MaxBatch,
+		interval:       opts.Interval,
+		confirmTimeout: opts.ConfirmTimeout,
+		batchSize:      opts.BatchSize,
+		now:            time.Now,
+	}
+}
+
+// Run processes the withdrawals on every tick until the context is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.ProcessWithdrawals(ctx); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("process withdrawals")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessWithdrawals advances the messages in flight and approves the requested withdrawals. The approved
+// withdrawals are signed in batches of the payout wallet, or the oldest one is signed by the master wallet
+// if no withdrawal is in flight. The failure of a withdrawal is logged and does not stop the others.
+func (w *Worker) ProcessWithdrawals(ctx context.Context) error {
+	withdrawals, err := w.listActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	inFlight := false
+	for _, message := range inFlightMessages(withdrawals) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err = w.advance(ctx, message); err != nil {
+			logger(message[0]).Warn().Err(err).Str("message_hash", message[0].MessageHash).Msg("advance withdrawals")
+		}
+		inFlight = inFlight || lo.SomeBy(message, isInFlight)
+	}
+
+	for _, withdrawal := range withdrawals {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if withdrawal.Status != model.WithdrawalRequested {
+			continue
+		}
+
+		if err = w.approve(ctx, withdrawal); err != nil {
+			logger(withdrawal).Warn().Err(err).Msg("approve withdrawal")
+		}
+	}
+
+	if w.payouts != nil {
+		w.signBatches(ctx, withdrawals)
+		return nil
+	}
+
+	for _, withdrawal := range withdrawals {
+		if inFlight || ctx.Err() != nil {
+			break
+		}
+
+		if withdrawal.Status != model.WithdrawalApproved {
+			continue
+		}
+
+		if err = w.sign(ctx, withdrawal); err != nil {
+			logger(withdrawal).Warn().Err(err).Msg("sign withdrawal")
+		}
+		inFlight = isInFlight(withdrawal)
+	}
+	return nil
+}
+
+// approve approves the requested withdrawal if it has the approvals its policy requires and its amount fits
+// in the daily limits of the policy, the amount is counted together with the approval. Otherwise the withdrawal
+// stays requested with the reason and is checked again on the next run.
+func (w *Worker) approve(ctx context.Context, withdrawal *model.Withdrawal) error {
+	policy, ok := w.policies[withdrawal.Currency]
+	if !ok {
+		return w.move(ctx, withdrawal, model.WithdrawalApproved, "")
+	}
+
+	if required := policy.RequiredApprovals(withdrawal.Amount); required > 0 {
+		approvals, err := w.approvals.ListWithdrawalApprovals(ctx, withdrawal.ID)
+		if err != nil {
+			return errors.Wrap(err, "list approvals")
+		}
+
+		approved := lo.CountBy(approvals, func(approval *model.WithdrawalApproval) bool {
+			return approval.Decision == model.ApprovalApproved
+		})
+		if approved < required {
+			return w.hold(ctx, withdrawal, fmt.Sprintf("awaiting approvals: %d of %d", approved, required))
+		}
+	}
+
+	err := w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		for _, usage := range limitUsages(policy, withdrawal, w.now()) {
+			if err := w.limits.AddWithdrawalLimitUsage(ctx, usage.counter, withdrawal.Amount, usage.limit); err != nil {
+				return err
+			}
+		}
+		return w.transition(ctx, withdrawal, model.WithdrawalApproved, "")
+	})
+
+	if errors.Is(err, model.ErrWithdrawalLimitExceeded) {
+		return w.hold(ctx, withdrawal, err.Error())
+	}
+	return err
+}
+
+// hold keeps the withdrawal requested with the reason, the unchanged reason is not stored again.
+func (w *Worker) hold(ctx context.Context, withdrawal *model.Withdrawal, reason string) error {
+	if withdrawal.HoldReason == reason {
+		return nil
+	}
+
+	withdrawal.HoldReason, withdrawal.UpdatedAt = reason, w.now().UTC()
+	if err := w.database.UpdateWithdrawal(ctx, withdrawal, withdrawal.Status); err != nil {
+		return errors.Wrap(err, "update withdrawal")
+	}
+
+	logger(withdrawal).Info().Str("reason", reason).Msg("withdrawal held")
+	return nil
+}
+
+// release returns the amount of the approved withdrawal that failed to the daily limits of the day it was approved.
+func (w *Worker) release(ctx context.Context, withdrawal *model.Withdrawal) error {
+	policy, ok := w.policies[withdrawal.Currency]
+	if !ok || withdrawal.ApprovedAt == nil {
+		return nil
+	}
+
+	for _, usage := range limitUsages(policy, withdrawal, *withdrawal.ApprovedAt) {
+		if err := w.limits.ReleaseWithdrawalLimitUsage(ctx, usage.counter, withdrawal.Amount); err != nil {
+			return errors.Wrap(err, "release limit usage")
+		}
+	}
+	return nil
+}
+
+type limitUsage struct {
+	counter model.WithdrawalLimitCounter
+	limit   model.Amount
+}
+
+// limitUsages returns the counters of the daily limits of the policy the withdrawal approved at the time counts in,
+// the account counter goes first.
+func limitUsages(policy *model.WithdrawalPolicy, withdrawal *model.Withdrawal, at time.Time) []limitUsage {
+	var usages []limitUsage
+
+	day := model.LimitDay(at)
+	if policy.AccountDailyLimit.Sign() > 0 {
+		usages = append(usages, limitUsage{
+			counter: model.WithdrawalLimitCounter{Scope: withdrawal.AccountID, Currency: withdrawal.Currency, Day: day},
+			limit:   policy.AccountDailyLimit,
+		})
+	}
+
+	if policy.GlobalDailyLimit.Sign() > 0 {
+		usages = append(usages, limitUsage{
+			counter: model.WithdrawalLimitCounter{Scope: model.GlobalLimitScope, Currency: withdrawal.Currency, Day: day},
+			limit:   policy.GlobalDailyLimit,
+		})
+	}
+	return usages
+}
+
+// listActive returns the active withdrawals from the oldest.
+func (w *Worker) listActive(ctx context.Context) ([]*model.Withdrawal, error) {
+	var (
+		result  []*model.Withdrawal
+		afterID int64
+	)
+
+	for {
+		withdrawals, err := w.database.ListActiveWithdrawals(ctx, afterID, w.batchSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "list withdrawals")
+		}
+
+		result = append(result, withdrawals...)
+		if len(withdrawals) < w.batchSize {
+			return result, nil
+		}
+		afterID = withdrawals[len(withdrawals)-1].ID
+	}
+}
+
+// inFlightMessages groups the signed and the unconfirmed withdrawals by their message, the messages are ordered by their oldest withdrawal.
+func inFlightMessages(withdrawals []*model.Withdrawal) [][]*model.Withdrawal {
+	var (
+		messages [][]*model.Withdrawal
+		index    = make(map[string]int)
+	)
+
+	for _, withdrawal := range withdrawals {
+		if !isInFlight(withdrawal) && withdrawal.Status != model.WithdrawalUnconfirmed {
+			continue
+		}
+
+		i, ok := index[withdrawal.MessageHash]
+		if !ok {
+			i = len(messages)
+			index[withdrawal.MessageHash] = i
+			messages = append(messages, nil)
+		}
+		messages[i] = append(messages[i], withdrawal)
+	}
+	return messages
+}
+
+// gas returns the TON attached to the transfer of the withdrawal.
+func (w *Worker) gas(withdrawal *model.Withdrawal) model.Amount {
+	if withdrawal.Currency == model.CurrencyTON {
+		return model.NewAmount(0)
+	}
+	return w.jettonGas
+}
+
+// sign signs the message of the approved withdrawal, stores it with its outgoing transfer and the reservation
+// of its seqno and broadcasts it. The withdrawal stays approved with the error if the master wallet cannot sign it,
+// e.g. without the balance, and it stays approved without the error if another service reserved the seqno.
+func (w *Worker) sign(ctx context.Context, withdrawal *model.Withdrawal) error {
+	amount := model.Balance{Currency: withdrawal.Currency, Amount: withdrawal.Amount}
+	message, err := w.walletPort.PrepareWithdrawal(ctx, withdrawal.To, amount, w.gas(withdrawal), withdrawal.Comment)
+	if err != nil {
+		if updateErr := w.keepApproved(ctx, []*model.Withdrawal{withdrawal}, err); updateErr != nil {
+			return updateErr
+		}
+		return errors.Wrap(err, "prepare withdrawal")
+	}
+
+	signed := *withdrawal
+	signed.SetMessage(message)
+
+	err = w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := w.seqnos.ReserveSeqno(ctx, message); err != nil {
+			return errors.Wrap(err, "reserve seqno")
+		}
+
+		if err := w.transition(ctx, &signed, model.WithdrawalSigned, ""); err != nil {
+			return err
+		}
+
+		if _, err := w.transfers.InsertOutgoingTransfer(ctx, signed.Transfer()); err != nil {
+			return errors.Wrap(err, "register transfer")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*withdrawal = signed
+	logger(withdrawal).Info().Uint32("seqno", withdrawal.Seqno).Str("message_hash", withdrawal.MessageHash).Msg("withdrawal signed")
+	return w.broadcast(ctx, []*model.Withdrawal{withdrawal})
+}
+
+// signBatches signs the approved withdrawals in batches of the payout wallet from the oldest.
+func (w *Worker) signBatches(ctx context.Context, withdrawals []*model.Withdrawal) {
+	approved := lo.Filter(withdrawals, func(withdrawal *model.Withdrawal, _ int) bool {
+		return withdrawal.Status == model.WithdrawalApproved
+	})
+
+	for _, batch := range lo.Chunk(approved, w.maxBatch) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := w.signBatch(ctx, batch); err != nil {
+			log.Warn().Err(err).Int64("first_withdrawal_id", batch[0].ID).Int("withdrawals", len(batch)).Msg("sign withdrawal batch")
+		}
+	}
+}
+
+// signBatch signs the batch of the approved withdrawals with the next query id, stores the message with the outgoing
+// transfer of every withdrawal and broadcasts it. The withdrawals stay approved with the error if the payout wallet
+// cannot sign the batch, e.g. without the balance.
+func (w *Worker) signBatch(ctx context.Context, batch []*model.Withdrawal) error {
+	payouts := make([]model.Payout, 0, len(batch))
+	for _, withdrawal := range batch {
+		payouts = append(payouts, model.Payout{
+			To:      withdrawal.To,
+			Amount:  model.Balance{Currency: withdrawal.Currency, Amount: withdrawal.Amount},
+			Gas:     w.gas(withdrawal),
+			Comment: withdrawal.Comment,
+		})
+	}
+
+	queryID, err := w.queryIDs.NextPayoutQueryID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "next query id")
+	}
+
+	messages, err := w.payouts.PreparePayouts(ctx, queryID, payouts)
+	if err == nil && len(messages) != len(batch) {
+		err = errors.Errorf("%d messages prepared for %d payouts", len(messages), len(batch))
+	}
+
+	if err != nil {
+		if updateErr := w.keepApproved(ctx, batch, err); updateErr != nil {
+			return updateErr
+		}
+		return errors.Wrap(err, "prepare payouts")
+	}
+
+	signed := make([]model.Withdrawal, len(batch))
+	err = w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		for i, withdrawal := range batch {
+			signed[i] = *withdrawal
+			signed[i].SetMessage(messages[i])
+
+			if err := w.transition(ctx, &signed[i], model.WithdrawalSigned, ""); err != nil {
+				return err
+			}
+
+			if _, err := w.transfers.InsertOutgoingTransfer(ctx, signed[i].Transfer()); err != nil {
+				return errors.Wrapf(err, "register transfer of withdrawal %d", withdrawal.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, withdrawal := range batch {
+		*withdrawal = signed[i]
+	}
+
+	log.Info().Uint32("query_id", queryID).Str("message_hash", messages[0].MessageHash).Int("withdrawals", len(batch)).
+		Msg("withdrawal batch signed")
+	return w.broadcast(ctx, batch)
+}
+
+// keepApproved stores the error of signing the approved withdrawals.
+func (w *Worker) keepApproved(ctx context.Context, withdrawals []*model.Withdrawal, signErr error) error {
+	for _, withdrawal := range withdrawals {
+		withdrawal.Error, withdrawal.UpdatedAt = signErr.Error(), w.now().UTC()
+		if err := w.database.UpdateWithdrawal(ctx, withdrawal, withdrawal.Status); err != nil {
+			return errors.Wrap(err, "update withdrawal")
+		}
+	}
+	return nil
+}
+
+// broadcast sends the message shared by the withdrawals, the first successful broadcast moves them to broadcast.
+// The wallet executes the message once however many times it is sent.
+func (w *Worker) broadcast(ctx context.Context, withdrawals []*model.Withdrawal) error {
+	message := withdrawals[0].Message()
+
+	var sendErr error
+	if message.QueryID != 0 && w.payouts != nil {
+		sendErr = w.payouts.SendWalletMessage(ctx, message)
+	} else {
+		sendErr = w.walletPort.SendWalletMessage(ctx, message)
+	}
+
+	for _, withdrawal := range withdrawals {
+		withdrawal.Attempts++
+
+		if sendErr == nil && withdrawal.Status == model.WithdrawalSigned {
+			if err := w.move(ctx, withdrawal, model.WithdrawalBroadcast, ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		withdrawal.Error, withdrawal.UpdatedAt = "", w.now().UTC()
+		if sendErr != nil {
+			withdrawal.Error = sendErr.Error()
+		}
+
+		if err := w.database.UpdateWithdrawal(ctx, withdrawal, withdrawal.Status); err != nil {
+			return errors.Wrap(err, "update withdrawal")
+		}
+	}
+	return errors.Wrap(sendErr, "send withdrawal message")
+}
+
+// advance moves the withdrawals of the message after their outgoing transfers, broadcasts the message while
+// it is valid and fails the withdrawals whose message expired without being executed. The withdrawals whose
+// message was executed without a confirmation are moved to unconfirmed, they wait for their transfers only.
+func (w *Worker) advance(ctx context.Context, withdrawals []*model.Withdrawal) error {
+	messageHash := withdrawals[0].MessageHash
+	transfers, err := w.transfers.ListOutgoingTransfersByMessageHash(ctx, messageHash)
+	if err != nil {
+		return errors.Wrap(err, "list outgoing transfers")
+	}
+
+	references := make(map[string]*model.OutgoingTransfer, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.Kind == model.TransferWithdrawal {
+			references[transfer.Reference] = transfer
+		}
+	}
+
+	var (
+		pending   []*model.Withdrawal
+		unsettled []*model.OutgoingTransfer
+	)
+
+	for _, withdrawal := range withdrawals {
+		transfer, ok := references[withdrawal.Reference()]
+		if !ok {
+			return errors.Wrapf(model.ErrTransferNotFound, "transfer of withdrawal %d", withdrawal.ID)
+		}
+
+		switch transfer.Status {
+		case model.TransferConfirmed:
+			withdrawal.TxHash = transfer.TxHash
+			err = w.move(ctx, withdrawal, model.WithdrawalConfirmed, "")
+		case model.TransferBounced:
+			withdrawal.TxHash = transfer.TxHash
+			err = w.move(ctx, withdrawal, model.WithdrawalFailed, "transfer bounced")
+		case model.TransferFailed:
+			withdrawal.TxHash = transfer.TxHash
+			err = w.move(ctx, withdrawal, model.WithdrawalFailed, "transaction failed")
+		case model.TransferSent:
+			if withdrawal.Status != model.WithdrawalUnconfirmed {
+				pending, unsettled = append(pending, withdrawal), append(unsettled, transfer)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	now, message := w.now().UTC(), pending[0].Message()
+	if now.Before(message.ExpiresAt) {
+		return w.broadcast(ctx, pending)
+	}
+
+	// the transaction processor confirms the message of the master wallet before its transactions are checked, while the payout
+	// wallet remembers the query id of the batch only for a while after it expires, so it is checked at once
+	if message.QueryID == 0 && now.Before(message.ExpiresAt.Add(w.confirmTimeout)) {
+		return nil
+	}
+
+	executed, err := w.executed(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	if executed {
+		if now.Before(message.ExpiresAt.Add(w.confirmTimeout)) {
+			return nil
+		}
+
+		logger(pending[0]).Error().Str("message_hash", messageHash).Int("withdrawals", len(pending)).
+			Msg("withdrawal is executed but not confirmed by the transaction processor")
+		for i, withdrawal := range pending {
+			if err = w.unconfirmed(ctx, withdrawal, unsettled[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	logger(pending[0]).Error().Str("message_hash", messageHash).Int("withdrawals", len(pending)).Msg("withdrawal message expired")
+	for i, withdrawal := range pending {
+		if err = w.expire(ctx, withdrawal, unsettled[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executed reports whether the wallet executed the expired message: the payout wallet processed the query id
+// of the batch or a transaction of the master wallet carries the message.
+func (w *Worker) executed(ctx context.Context, message *model.WalletMessage) (bool, error) {
+	if message.QueryID != 0 {
+		if w.payouts == nil {
+			return false, errors.Errorf("payout wallet of query id %d is not configured", message.QueryID)
+		}
+
+		processed, err := w.payouts.IsQueryProcessed(ctx, message.QueryID)
+		if err != nil {
+			return false, errors.Wrap(err, "check payout query")
+		}
+		return processed, nil
+	}
+
+	executed, err := w.walletPort.IsMessageExecuted(ctx, message)
+	if err != nil {
+		return false, errors.Wrap(err, "check master message")
+	}
+	return executed, nil
+}
+
+// expire fails the withdrawal and its transfer together, the transaction processor does not fail
+// the transfer whose message was never executed.
+func (w *Worker) expire(ctx context.Context, withdrawal *model.Withdrawal, transfer *model.OutgoingTransfer) error {
+	return w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := w.transition(ctx, withdrawal, model.WithdrawalFailed, "message expired"); err != nil {
+			return err
+		}
+
+		if err := transfer.Transition(model.TransferFailed, w.now().UTC()); err != nil {
+			return err
+		}
+
+		if err := w.transfers.UpdateOutgoingTransferStatus(ctx, transfer, model.TransferSent); err != nil {
+			return errors.Wrap(err, "update transfer to failed")
+		}
+		return nil
+	})
+}
+
+// unconfirmed moves the withdrawal whose message is executed without a confirmation to unconfirmed and publishes
+// its transfer for an operator to reconcile, the transfer stays sent for the transaction processor to confirm it.
+func (w *Worker) unconfirmed(ctx context.Context, withdrawal *model.Withdrawal, transfer *model.OutgoingTransfer) error {
+	return w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		if err := w.transition(ctx, withdrawal, model.WithdrawalUnconfirmed, "withdrawal executed but not confirmed"); err != nil {
+			return err
+		}
+
+		if err := w.events.Publish(ctx, model.TransferUnconfirmedEvent, model.NewTransferPayload(transfer)); err != nil {
+			return errors.Wrapf(err, "publish %s", model.TransferUnconfirmedEvent)
+		}
+		return nil
+	})
+}
+
+// move runs the transition of the withdrawal within a transaction.
+func (w *Worker) move(ctx context.Context, withdrawal *model.Withdrawal, to model.WithdrawalStatus, reason string) error {
+	return w.txPort.WithInTransaction(ctx, func(ctx context.Context) error {
+		return w.transition(ctx, withdrawal, to, reason)
+	})
+}
+
+// transition moves the withdrawal to the status and publishes it within the transaction of the context,
+// the withdrawal is changed only if the transition is stored. A failed withdrawal releases its daily limits.
+func (w *Worker) transition(ctx context.Context, withdrawal *model.Withdrawal, to model.WithdrawalStatus, reason string) error {
+	from := withdrawal.Status
+
+	if to == model.WithdrawalFailed {
+		if err := w.release(ctx, withdrawal); err != nil {
+			return err
+		}
+	}
+
+	next := *withdrawal
+	if err := next.Transition(to, w.now().UTC()); err != nil {
+		return err
+	}
+	next.Error = reason
+
+	if err := w.database.UpdateWithdrawal(ctx, &next, from); err != nil {
+		return errors.Wrapf(err, "update withdrawal to %s", to)
+	}
+
+	if err := publish(ctx, w.events, &next); err != nil {
+		return err
+	}
+
+	*withdrawal = next
+	logger(withdrawal).Debug().Str("from", string(from)).Str("to", string(to)).Msg("withdrawal transition")
+	return nil
+}
+
+// isInFlight reports whether the message of the withdrawal is signed and may still be executed or expire.
+func isInFlight(withdrawal *model.Withdrawal) bool {
+	return withdrawal.Status == model.WithdrawalSigned || withdrawal.Status == model.WithdrawalBroadcast
+}
+
+func logger(withdrawal *model.Withdrawal) *zerolog.Logger {
+	l := log.With().Int64("withdrawal_id", withdrawal.ID).Str("account_id", withdrawal.AccountID).Logger()
+	return &l
+}
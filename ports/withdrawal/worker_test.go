@@ -0,0 +1,536 @@
+package withdrawal
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kriuchkov/tonbeacon/core/model"
+	portsmocks "github.com/kriuchkov/tonbeacon/core/ports/mocks"
+)
+
+type workerMocks struct {
+	wallet      *portsmocks.MockWalletPort
+	payouts     *portsmocks.MockPayoutWalletPort
+	queryIDs    *portsmocks.MockPayoutDatabasePort
+	withdrawals *portsmocks.MockWithdrawalDatabasePort
+	approvals   *portsmocks.MockWithdrawalApprovalDatabasePort
+	limits      *portsmocks.MockWithdrawalLimitDatabasePort
+	transfers   *portsmocks.MockOutgoingTransferDatabasePort
+	events      *portsmocks.MockOutboxMessagePort
+	seqnos      *portsmocks.MockSeqnoDatabasePort
+}
+
+// newWorker returns the worker of the master wallet, or of the payout wallet with the payouts,
+// which applies the withdrawal policies.
+func newWorker(t *testing.T, now time.Time, payouts bool, policies ...model.WithdrawalPolicy) (*Worker, workerMocks) {
+	m := workerMocks{
+		wallet:      portsmocks.NewMockWalletPort(t),
+		payouts:     portsmocks.NewMockPayoutWalletPort(t),
+		queryIDs:    portsmocks.NewMockPayoutDatabasePort(t),
+		withdrawals: portsmocks.NewMockWithdrawalDatabasePort(t),
+		approvals:   portsmocks.NewMockWithdrawalApprovalDatabasePort(t),
+		limits:      portsmocks.NewMockWithdrawalLimitDatabasePort(t),
+		transfers:   portsmocks.NewMockOutgoingTransferDatabasePort(t),
+		events:      portsmocks.NewMockOutboxMessagePort(t),
+		seqnos:      portsmocks.NewMockSeqnoDatabasePort(t),
+	}
+
+	txPort := portsmocks.NewMockDatabaseTransactionPort(t)
+	txPort.On("WithInTransaction", mock.Anything, mock.Anything).Maybe().
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+
+	opts := &WorkerOptions{
+		WalletPort:     m.wallet,
+		Database:       m.withdrawals,
+		TransferPort:   m.transfers,
+		TxPort:         txPort,
+		Events:         m.events,
+		Seqnos:         m.seqnos,
+		Policies:       policies,
+		Approvals:      m.approvals,
+		Limits:         m.limits,
+		ConfirmTimeout: 5 * time.Minute,
+		BatchSize:      10,
+	}
+	if payouts {
+		opts.Payouts, opts.QueryIDs, opts.MaxBatch = m.payouts, m.queryIDs, 2
+	}
+
+	worker := NewWorker(opts)
+	worker.now = func() time.Time { return now }
+	return worker, m
+}
+
+func TestWorker_ProcessWithdrawals(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z"
+	ton := model.Balance{Currency: model.CurrencyTON, Amount: model.NewAmount(700)}
+	message := &model.WalletMessage{
+		From: "0:02", To: "0:40", Currency: model.CurrencyTON, Amount: model.NewAmount(700), Seqno: 12,
+		MessageHash: "hash", BOC: []byte{1}, ExpiresAt: now.Add(3 * time.Minute),
+	}
+
+	withdrawal := func(id int64, status model.WithdrawalStatus) *model.Withdrawal {
+		w := &model.Withdrawal{ID: id, AccountID: "1", To: to, Currency: model.CurrencyTON, Amount: model.NewAmount(700), Status: status}
+		if status == model.WithdrawalSigned || status == model.WithdrawalBroadcast || status == model.WithdrawalUnconfirmed {
+			w.SetMessage(message)
+		}
+		return w
+	}
+
+	transfer := func(id int64, status model.TransferStatus) *model.OutgoingTransfer {
+		return &model.OutgoingTransfer{
+			ID: id, Kind: model.TransferWithdrawal, Reference: strconv.FormatInt(id, 10), Status: status, TxHash: "tx",
+		}
+	}
+
+	published := func(m workerMocks, eventType model.EventType) {
+		m.events.On("Publish", mock.Anything, eventType, mock.AnythingOfType("model.WithdrawalPayload")).Return(nil).Once()
+	}
+
+	tests := []struct {
+		name        string
+		withdrawals []*model.Withdrawal
+		mock        func(m workerMocks)
+	}{
+		{
+			name:        "requested withdrawal is approved, signed and broadcast",
+			withdrawals: []*model.Withdrawal{withdrawal(5, model.WithdrawalRequested)},
+			mock: func(m workerMocks) {
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalApproved && w.ApprovedAt != nil
+				}), model.WithdrawalRequested).Return(nil).Once()
+				published(m, model.WithdrawalApprovedEvent)
+
+				m.wallet.On("PrepareWithdrawal", mock.Anything, to, ton, model.NewAmount(0), "").Return(message, nil).Once()
+				m.seqnos.On("ReserveSeqno", mock.Anything, message).Return(nil).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalSigned && w.MessageHash == "hash" && w.Seqno == 12
+				}), model.WithdrawalApproved).Return(nil).Once()
+				m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Kind == model.TransferWithdrawal && transfer.Reference == "5" && transfer.MessageHash == "hash"
+				})).Return(&model.OutgoingTransfer{ID: 1}, nil).Once()
+				published(m, model.WithdrawalSignedEvent)
+
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.MatchedBy(func(sent *model.WalletMessage) bool {
+					return sent.MessageHash == "hash"
+				})).Return(nil).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalBroadcast && w.Attempts == 1
+				}), model.WithdrawalSigned).Return(nil).Once()
+				published(m, model.WithdrawalBroadcastEvent)
+			},
+		},
+		{
+			name: "approved withdrawal waits for the withdrawal in flight",
+			withdrawals: []*model.Withdrawal{
+				withdrawal(4, model.WithdrawalApproved), withdrawal(5, model.WithdrawalBroadcast),
+			},
+			mock: func(m workerMocks) {
+				m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").
+					Return([]*model.OutgoingTransfer{transfer(5, model.TransferSent)}, nil).Once()
+				m.wallet.On("SendWalletMessage", mock.Anything, mock.Anything).Return(errors.New("liteserver")).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.ID == 5 && w.Error == "liteserver"
+				}), model.WithdrawalBroadcast).Return(nil).Once()
+			},
+		},
+		{
+			name:        "confirmed transfer confirms the withdrawal",
+			withdrawals: []*model.Withdrawal{withdrawal(5, model.WithdrawalBroadcast)},
+			mock: func(m workerMocks) {
+				m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").
+					Return([]*model.OutgoingTransfer{transfer(5, model.TransferConfirmed)}, nil).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalConfirmed && w.TxHash == "tx" && w.ConfirmedAt != nil
+				}), model.WithdrawalBroadcast).Return(nil).Once()
+				published(m, model.WithdrawalConfirmedEvent)
+			},
+		},
+		{
+			name:        "bounced transfer fails the withdrawal",
+			withdrawals: []*model.Withdrawal{withdrawal(5, model.WithdrawalBroadcast)},
+			mock: func(m workerMocks) {
+				m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").
+					Return([]*model.OutgoingTransfer{transfer(5, model.TransferBounced)}, nil).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalFailed && w.Error == "transfer bounced" && w.TxHash == "tx"
+				}), model.WithdrawalBroadcast).Return(nil).Once()
+				published(m, model.WithdrawalFailedEvent)
+			},
+		},
+		{
+			name: "unconfirmed withdrawal waits for its transfer without blocking the master wallet",
+			withdrawals: []*model.Withdrawal{
+				withdrawal(4, model.WithdrawalUnconfirmed), withdrawal(5, model.WithdrawalApproved),
+			},
+			mock: func(m workerMocks) {
+				m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").
+					Return([]*model.OutgoingTransfer{transfer(4, model.TransferSent)}, nil).Once()
+				m.wallet.On("PrepareWithdrawal", mock.Anything, to, ton, model.NewAmount(0), "").Return(message, nil).Once()
+				m.seqnos.On("ReserveSeqno", mock.Anything, message).
+					Return(errors.Wrap(model.ErrSeqnoReserved, "seqno 12 of 0:02")).Once()
+			},
+		},
+		{
+			name:        "confirmed transfer confirms the unconfirmed withdrawal",
+			withdrawals: []*model.Withdrawal{withdrawal(5, model.WithdrawalUnconfirmed)},
+			mock: func(m workerMocks) {
+				m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").
+					Return([]*model.OutgoingTransfer{transfer(5, model.TransferConfirmed)}, nil).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalConfirmed && w.TxHash == "tx"
+				}), model.WithdrawalUnconfirmed).Return(nil).Once()
+				published(m, model.WithdrawalConfirmedEvent)
+			},
+		},
+		{
+			name:        "failed signing keeps the withdrawal approved",
+			withdrawals: []*model.Withdrawal{withdrawal(5, model.WithdrawalApproved)},
+			mock: func(m workerMocks) {
+				m.wallet.On("PrepareWithdrawal", mock.Anything, to, ton, model.NewAmount(0), "").
+					Return(nil, errors.New("insufficient balance in master wallet")).Once()
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalApproved && w.Error == "insufficient balance in master wallet"
+				}), model.WithdrawalApproved).Return(nil).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			worker, m := newWorker(t, now, false)
+			m.withdrawals.On("ListActiveWithdrawals", mock.Anything, int64(0), 10).Return(tt.withdrawals, nil).Once()
+			tt.mock(m)
+
+			require.NoError(t, worker.ProcessWithdrawals(context.Background()))
+		})
+	}
+}
+
+func TestWorker_ExpiredWithdrawal(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		executed bool
+		status   model.WithdrawalStatus
+	}{
+		{name: "message that was not executed fails", status: model.WithdrawalFailed},
+		{name: "executed message is unconfirmed", executed: true, status: model.WithdrawalUnconfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			worker, m := newWorker(t, now, false)
+
+			withdrawal := &model.Withdrawal{ID: 5, AccountID: "1", Status: model.WithdrawalBroadcast}
+			withdrawal.SetMessage(&model.WalletMessage{Seqno: 12, MessageHash: "hash", ExpiresAt: now.Add(-10 * time.Minute)})
+			transfer := &model.OutgoingTransfer{ID: 1, Kind: model.TransferWithdrawal, Reference: "5", Status: model.TransferSent}
+
+			m.withdrawals.On("ListActiveWithdrawals", mock.Anything, int64(0), 10).Return([]*model.Withdrawal{withdrawal}, nil).Once()
+			m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "hash").Return([]*model.OutgoingTransfer{transfer}, nil).Once()
+			m.wallet.On("IsMessageExecuted", mock.Anything, mock.MatchedBy(func(message *model.WalletMessage) bool {
+				return message.MessageHash == "hash"
+			})).Return(tt.executed, nil).Once()
+
+			if tt.status == model.WithdrawalFailed {
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalFailed && w.Error == "message expired"
+				}), model.WithdrawalBroadcast).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.WithdrawalFailedEvent, mock.AnythingOfType("model.WithdrawalPayload")).Return(nil).Once()
+				m.transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Once()
+			} else {
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalUnconfirmed && w.Error == "withdrawal executed but not confirmed"
+				}), model.WithdrawalBroadcast).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.WithdrawalUnconfirmedEvent, mock.AnythingOfType("model.WithdrawalPayload")).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.TransferUnconfirmedEvent, mock.MatchedBy(func(payload model.TransferPayload) bool {
+					return payload.TransferID == 1 && payload.Reference == "5"
+				})).Return(nil).Once()
+			}
+
+			require.NoError(t, worker.ProcessWithdrawals(context.Background()))
+			require.Equal(t, tt.status, withdrawal.Status)
+		})
+	}
+}
+
+func TestWorker_PayoutBatches(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := "EQBAorrBQXpK-NC1bI_k6-eJD2EiN0w-Two9Oyxe4rDmO79Z"
+
+	withdrawal := func(id int64, currency model.Currency) *model.Withdrawal {
+		return &model.Withdrawal{
+			ID: id, AccountID: "1", To: to, Currency: currency, Amount: model.NewAmount(700), Status: model.WithdrawalApproved,
+		}
+	}
+
+	worker, m := newWorker(t, now, true)
+	withdrawals := []*model.Withdrawal{
+		withdrawal(1, model.CurrencyTON), withdrawal(2, model.CurrencyUSDT), withdrawal(3, model.CurrencyTON),
+	}
+	m.withdrawals.On("ListActiveWithdrawals", mock.Anything, int64(0), 10).Return(withdrawals, nil).Once()
+
+	batch := func(queryID uint32, hash string, payouts int) {
+		m.queryIDs.On("NextPayoutQueryID", mock.Anything).Return(queryID, nil).Once()
+		m.payouts.On("PreparePayouts", mock.Anything, queryID, mock.MatchedBy(func(p []model.Payout) bool {
+			return len(p) == payouts
+		})).Return(func(_ context.Context, _ uint32, p []model.Payout) ([]*model.WalletMessage, error) {
+			messages := make([]*model.WalletMessage, 0, len(p))
+			for _, payout := range p {
+				messages = append(messages, &model.WalletMessage{
+					From: "0:03", Currency: payout.Amount.Currency, Amount: payout.Amount.Amount, Gas: payout.Gas,
+					QueryID: queryID, MessageHash: hash, BOC: []byte{1}, ExpiresAt: now.Add(3 * time.Minute),
+				})
+			}
+			return messages, nil
+		}).Once()
+		m.payouts.On("SendWalletMessage", mock.Anything, mock.MatchedBy(func(sent *model.WalletMessage) bool {
+			return sent.QueryID == queryID && sent.MessageHash == hash
+		})).Return(nil).Once()
+	}
+	batch(9, "batch-9", 2)
+	batch(10, "batch-10", 1)
+
+	m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+		return w.Status == model.WithdrawalSigned && w.QueryID != 0 && w.MessageHash == "batch-"+strconv.Itoa(int(w.QueryID))
+	}), model.WithdrawalApproved).Return(nil).Times(3)
+	m.transfers.On("InsertOutgoingTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+		return transfer.Kind == model.TransferWithdrawal && (transfer.Currency == model.CurrencyTON) == (transfer.Gas.Sign() == 0)
+	})).Return(&model.OutgoingTransfer{ID: 1}, nil).Times(3)
+	m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+		return w.Status == model.WithdrawalBroadcast && w.Attempts == 1
+	}), model.WithdrawalSigned).Return(nil).Times(3)
+	m.events.On("Publish", mock.Anything, model.WithdrawalSignedEvent, mock.Anything).Return(nil).Times(3)
+	m.events.On("Publish", mock.Anything, model.WithdrawalBroadcastEvent, mock.Anything).Return(nil).Times(3)
+
+	require.NoError(t, worker.ProcessWithdrawals(context.Background()))
+	require.Equal(t, withdrawals[0].MessageHash, withdrawals[1].MessageHash)
+	require.Equal(t, "batch-10", withdrawals[2].MessageHash)
+}
+
+func TestWorker_ExpiredBatch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		processed bool
+		status    model.WithdrawalStatus
+	}{
+		{name: "batch that was not processed fails", status: model.WithdrawalFailed},
+		{name: "processed batch waits for the confirmation", processed: true, status: model.WithdrawalBroadcast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			worker, m := newWorker(t, now, true)
+			message := &model.WalletMessage{QueryID: 9, MessageHash: "batch", ExpiresAt: now.Add(-time.Second)}
+
+			var (
+				withdrawals []*model.Withdrawal
+				transfers   []*model.OutgoingTransfer
+			)
+			for id := int64(1); id <= 2; id++ {
+				withdrawal := &model.Withdrawal{ID: id, AccountID: "1", Status: model.WithdrawalBroadcast}
+				withdrawal.SetMessage(message)
+				withdrawals = append(withdrawals, withdrawal)
+				transfers = append(transfers, &model.OutgoingTransfer{
+					ID: id, Kind: model.TransferWithdrawal, Reference: strconv.FormatInt(id, 10), Status: model.TransferSent,
+				})
+			}
+
+			m.withdrawals.On("ListActiveWithdrawals", mock.Anything, int64(0), 10).Return(withdrawals, nil).Once()
+			m.transfers.On("ListOutgoingTransfersByMessageHash", mock.Anything, "batch").Return(transfers, nil).Once()
+			m.payouts.On("IsQueryProcessed", mock.Anything, uint32(9)).Return(tt.processed, nil).Once()
+
+			if !tt.processed {
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalFailed && w.Error == "message expired"
+				}), model.WithdrawalBroadcast).Return(nil).Twice()
+				m.events.On("Publish", mock.Anything, model.WithdrawalFailedEvent, mock.Anything).Return(nil).Twice()
+				m.transfers.On("UpdateOutgoingTransferStatus", mock.Anything, mock.MatchedBy(func(transfer *model.OutgoingTransfer) bool {
+					return transfer.Status == model.TransferFailed
+				}), model.TransferSent).Return(nil).Twice()
+			}
+
+			require.NoError(t, worker.ProcessWithdrawals(context.Background()))
+			for _, withdrawal := range withdrawals {
+				require.Equal(t, tt.status, withdrawal.Status)
+			}
+		})
+	}
+}
+
+func TestWorker_ApprovalPolicy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := model.WithdrawalPolicy{
+		Currency:          model.CurrencyTON,
+		Thresholds:        []model.ApprovalThreshold{{Above: model.NewAmount(1000), Approvals: 2}},
+		AccountDailyLimit: model.NewAmount(5000),
+		GlobalDailyLimit:  model.NewAmount(10000),
+	}
+	account := model.WithdrawalLimitCounter{Scope: "1", Currency: model.CurrencyTON, Day: model.LimitDay(now)}
+	global := model.WithdrawalLimitCounter{Scope: model.GlobalLimitScope, Currency: model.CurrencyTON, Day: model.LimitDay(now)}
+
+	approvals := func(decisions ...model.ApprovalDecision) []*model.WithdrawalApproval {
+		result := make([]*model.WithdrawalApproval, 0, len(decisions))
+		for i, decision := range decisions {
+			result = append(result, &model.WithdrawalApproval{WithdrawalID: 5, Operator: "op-" + strconv.Itoa(i), Decision: decision})
+		}
+		return result
+	}
+
+	approved := func(m workerMocks, amount int64) {
+		m.limits.On("AddWithdrawalLimitUsage", mock.Anything, account, model.NewAmount(amount), policy.AccountDailyLimit).Return(nil).Once()
+		m.limits.On("AddWithdrawalLimitUsage", mock.Anything, global, model.NewAmount(amount), policy.GlobalDailyLimit).Return(nil).Once()
+		m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+			return w.Status == model.WithdrawalApproved && w.Error == "" && w.HoldReason == ""
+		}), model.WithdrawalRequested).Return(nil).Once()
+		m.events.On("Publish", mock.Anything, model.WithdrawalApprovedEvent, mock.Anything).Return(nil).Once()
+	}
+
+	held := func(m workerMocks, reason string) {
+		m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+			return w.Status == model.WithdrawalRequested && w.HoldReason == reason && w.Error == ""
+		}), model.WithdrawalRequested).Return(nil).Once()
+	}
+
+	tests := []struct {
+		name     string
+		currency model.Currency
+		amount   int64
+		reason   string
+		mock     func(m workerMocks)
+		status   model.WithdrawalStatus
+	}{
+		{
+			name:   "withdrawal below the threshold is counted and approved",
+			amount: 1000,
+			mock:   func(m workerMocks) { approved(m, 1000) },
+			status: model.WithdrawalApproved,
+		},
+		{
+			name:   "withdrawal above the threshold waits for the approvals",
+			amount: 2000,
+			mock: func(m workerMocks) {
+				m.approvals.On("ListWithdrawalApprovals", mock.Anything, int64(5)).
+					Return(approvals(model.ApprovalApproved), nil).Once()
+				held(m, "awaiting approvals: 1 of 2")
+			},
+			status: model.WithdrawalRequested,
+		},
+		{
+			name:   "unchanged reason is not stored again",
+			amount: 2000,
+			reason: "awaiting approvals: 1 of 2",
+			mock: func(m workerMocks) {
+				m.approvals.On("ListWithdrawalApprovals", mock.Anything, int64(5)).
+					Return(approvals(model.ApprovalApproved), nil).Once()
+			},
+			status: model.WithdrawalRequested,
+		},
+		{
+			name:   "withdrawal with the approvals is counted and approved",
+			amount: 2000,
+			reason: "awaiting approvals: 1 of 2",
+			mock: func(m workerMocks) {
+				m.approvals.On("ListWithdrawalApprovals", mock.Anything, int64(5)).
+					Return(approvals(model.ApprovalApproved, model.ApprovalApproved), nil).Once()
+				approved(m, 2000)
+			},
+			status: model.WithdrawalApproved,
+		},
+		{
+			name:   "withdrawal over the daily limit is held",
+			amount: 700,
+			mock: func(m workerMocks) {
+				m.limits.On("AddWithdrawalLimitUsage", mock.Anything, account, model.NewAmount(700), policy.AccountDailyLimit).
+					Return(errors.Wrap(model.ErrWithdrawalLimitExceeded, "1 of TON")).Once()
+				held(m, "1 of TON: withdrawal limit exceeded")
+			},
+			status: model.WithdrawalRequested,
+		},
+		{
+			name:     "withdrawal without a policy is approved at once",
+			currency: model.CurrencyUSDT,
+			amount:   1_000_000,
+			mock: func(m workerMocks) {
+				m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+					return w.Status == model.WithdrawalApproved
+				}), model.WithdrawalRequested).Return(nil).Once()
+				m.events.On("Publish", mock.Anything, model.WithdrawalApprovedEvent, mock.Anything).Return(nil).Once()
+			},
+			status: model.WithdrawalApproved,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			worker, m := newWorker(t, now, false, policy)
+			tt.mock(m)
+
+			withdrawal := &model.Withdrawal{
+				ID: 5, AccountID: "1", Currency: model.CurrencyTON, Amount: model.NewAmount(tt.amount),
+				Status: model.WithdrawalRequested, HoldReason: tt.reason,
+			}
+			if tt.currency != "" {
+				withdrawal.Currency = tt.currency
+			}
+
+			require.NoError(t, worker.approve(context.Background(), withdrawal))
+			require.Equal(t, tt.status, withdrawal.Status)
+		})
+	}
+}
+
+func TestWorker_FailedWithdrawalReleasesLimits(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 0, 5, 0, 0, time.UTC)
+	approvedAt := now.Add(-time.Hour)
+	policy := model.WithdrawalPolicy{Currency: model.CurrencyTON, GlobalDailyLimit: model.NewAmount(10000)}
+
+	worker, m := newWorker(t, now, false, policy)
+	withdrawal := &model.Withdrawal{
+		ID: 5, AccountID: "1", Currency: model.CurrencyTON, Amount: model.NewAmount(700),
+		Status: model.WithdrawalApproved, ApprovedAt: &approvedAt,
+	}
+
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.limits.On("ReleaseWithdrawalLimitUsage", mock.Anything, model.WithdrawalLimitCounter{
+		Scope: model.GlobalLimitScope, Currency: model.CurrencyTON, Day: day,
+	}, model.NewAmount(700)).Return(nil).Once()
+	m.withdrawals.On("UpdateWithdrawal", mock.Anything, mock.MatchedBy(func(w *model.Withdrawal) bool {
+		return w.Status == model.WithdrawalFailed
+	}), model.WithdrawalApproved).Return(nil).Once()
+	m.events.On("Publish", mock.Anything, model.WithdrawalFailedEvent, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, worker.move(context.Background(), withdrawal, model.WithdrawalFailed, "rejected"))
+}